@@ -1,46 +1,77 @@
-package routes
-
-import (
-	"broker/handler"
-	"broker/middleware"
-	"broker/repository"
-
-	"github.com/gin-contrib/cors"
-	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
-)
-
-func Routes() *gin.Engine {
-	r := gin.Default()
-
-	// CORS middleware - allow all origins for development
-	r.Use(cors.New(cors.Config{
-		AllowAllOrigins:  true,
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: false,
-	}))
-
-	if err := middleware.InitRedis(); err != nil {
-		logrus.Warnf("Failed to start Redis: %v", err)
-	}
-
-	userRepo := repository.NewUserRepository()
-	userHandler := handler.NewUserHandler(userRepo)
-	userHandler.RegisterRoutes(r)
-
-	productRepo := repository.NewProductRepository()
-	productHandler := handler.NewProductHandler(productRepo)
-	productHandler.RegisterRoutes(r)
-
-	orderRepo := repository.NewOrderRepository()
-	orderHandler := handler.NewOrderHandler(userRepo, productRepo, orderRepo)
-	orderHandler.RegisterRoutes(r)
-
-	paymentRepo := repository.NewPaymentRepository()
-	paymentHandler := handler.NewPaymentHandler(paymentRepo)
-	paymentHandler.RegisterRoutes(r)
-
-	return r
-}
+package routes
+
+import (
+	"broker/handler"
+	"broker/middleware"
+	"broker/repository"
+	"broker/storage"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+func Routes() *gin.Engine {
+	r := gin.Default()
+
+	// CORS middleware - allow all origins for development
+	r.Use(cors.New(cors.Config{
+		AllowAllOrigins:  true,
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		ExposeHeaders:    []string{"Content-Length"},
+		AllowCredentials: false,
+	}))
+
+	if err := middleware.InitRedis(); err != nil {
+		logrus.Warnf("Failed to start Redis: %v", err)
+	}
+
+	userRepo := repository.NewUserRepository()
+	userHandler := handler.NewUserHandler(userRepo)
+	userHandler.RegisterRoutes(r)
+
+	customerGroupHandler := handler.NewCustomerGroupHandler(userRepo)
+	customerGroupHandler.RegisterRoutes(r)
+
+	store, err := storage.NewBlobStore()
+	if err != nil {
+		logrus.Fatalf("Failed to set up blob store: %v", err)
+	}
+	if local, ok := store.(*storage.LocalBlobStore); ok {
+		r.Static(storage.LocalMediaRoute, local.Dir())
+	}
+
+	// Files of digital products are private and only served through signed
+	// download links
+	downloads, err := storage.NewDownloadStore()
+	if err != nil {
+		logrus.Fatalf("Failed to set up download store: %v", err)
+	}
+
+	productRepo := repository.NewProductRepository()
+	productHandler := handler.NewProductHandler(productRepo, store, downloads)
+	productHandler.RegisterRoutes(r)
+
+	categoryHandler := handler.NewCategoryHandler(productRepo, store)
+	categoryHandler.RegisterRoutes(r)
+
+	warehouseHandler := handler.NewWarehouseHandler(productRepo)
+	warehouseHandler.RegisterRoutes(r)
+
+	purchaseOrderHandler := handler.NewPurchaseOrderHandler(productRepo)
+	purchaseOrderHandler.RegisterRoutes(r)
+
+	wishlistHandler := handler.NewWishlistHandler(productRepo, store)
+	wishlistHandler.RegisterRoutes(r)
+
+	orderRepo := repository.NewOrderRepository()
+	orderHandler := handler.NewOrderHandler(userRepo, productRepo, orderRepo, downloads)
+	orderHandler.RegisterRoutes(r)
+
+	paymentRepo := repository.NewPaymentRepository()
+	paymentHandler := handler.NewPaymentHandler(paymentRepo)
+	paymentHandler.RegisterRoutes(r)
+
+	return r
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"broker/middleware"
+	"broker/proto"
+	"broker/repository"
+	"broker/storage"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryHandler struct {
+	productRepo repository.ProductRepository
+	store       storage.BlobStore
+}
+
+func NewCategoryHandler(repo repository.ProductRepository, store storage.BlobStore) *CategoryHandler {
+	return &CategoryHandler{
+		productRepo: repo,
+		store:       store,
+	}
+}
+
+func (h *CategoryHandler) RegisterRoutes(r *gin.Engine) {
+	// Public routes for storefront navigation
+	r.GET("/category", h.ListCategories)
+	r.GET("/category/:slug", h.ListCategoryProducts)
+	r.GET("/tag", h.ListTags)
+
+	// Protected routes (auth required)
+	categoryRoutes := r.Group("/category")
+	categoryRoutes.Use(middleware.ProtectedEndpoint())
+	categoryRoutes.Use(middleware.RateLimiterMiddleware(middleware.DefaultRateLimiterConfig()))
+
+	categoryRoutes.POST("", h.CreateCategory)
+	categoryRoutes.PUT("/:id", h.UpdateCategory)
+	categoryRoutes.DELETE("/:id", h.DeleteCategory)
+
+	tagRoutes := r.Group("/tag")
+	tagRoutes.Use(middleware.ProtectedEndpoint())
+	tagRoutes.Use(middleware.RateLimiterMiddleware(middleware.DefaultRateLimiterConfig()))
+
+	tagRoutes.POST("", h.CreateTag)
+	tagRoutes.DELETE("/:id", h.DeleteTag)
+}
+
+// ListCategories returns the category tree
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	response, err := h.productRepo.ListCategories()
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, response)
+}
+
+// ListCategoryProducts lists a category's products with breadcrumbs and subcategories
+func (h *CategoryHandler) ListCategoryProducts(c *gin.Context) {
+	req := &proto.ListCategoryProductsRequest{
+		Slug: c.Param("slug"),
+		Sort: c.Query("sort"),
+	}
+
+	if v := c.Query("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "Invalid page"})
+			return
+		}
+		req.Page = int32(page)
+	}
+	if v := c.Query("page_size"); v != "" {
+		pageSize, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "Invalid page_size"})
+			return
+		}
+		req.PageSize = int32(pageSize)
+	}
+
+	response, err := h.productRepo.ListCategoryProducts(req)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"category":    response.Category,
+		"breadcrumbs": response.Breadcrumbs,
+		"products":    productListDTO(response.Products, h.store),
+	})
+}
+
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var req struct {
+		ParentID int32  `json:"parent_id"`
+		Name     string `json:"name" binding:"required"`
+		Slug     string `json:"slug"`
+		Position int32  `json:"position"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := h.productRepo.CreateCategory(&proto.Category{
+		ParentId: req.ParentID,
+		Name:     req.Name,
+		Slug:     req.Slug,
+		Position: req.Position,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, category)
+}
+
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	var req struct {
+		ParentID int32  `json:"parent_id"`
+		Name     string `json:"name"`
+		Slug     string `json:"slug"`
+		Position int32  `json:"position"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := h.productRepo.UpdateCategory(&proto.Category{
+		Id:       int32(ID),
+		ParentId: req.ParentID,
+		Name:     req.Name,
+		Slug:     req.Slug,
+		Position: req.Position,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, category)
+}
+
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	if err := h.productRepo.DeleteCategory(int32(ID)); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Category deleted successfully"})
+}
+
+func (h *CategoryHandler) ListTags(c *gin.Context) {
+	response, err := h.productRepo.ListTags()
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, response)
+}
+
+func (h *CategoryHandler) CreateTag(c *gin.Context) {
+	var req struct {
+		Name string `json:"name" binding:"required"`
+		Slug string `json:"slug"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag, err := h.productRepo.CreateTag(&proto.Tag{Name: req.Name, Slug: req.Slug})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, tag)
+}
+
+func (h *CategoryHandler) DeleteTag(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	if err := h.productRepo.DeleteTag(int32(ID)); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Tag deleted successfully"})
+}
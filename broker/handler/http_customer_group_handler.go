@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"broker/middleware"
+	"broker/proto"
+	"broker/repository"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CustomerGroupHandler struct {
+	userRepo repository.UserRepository
+}
+
+func NewCustomerGroupHandler(repo repository.UserRepository) *CustomerGroupHandler {
+	return &CustomerGroupHandler{
+		userRepo: repo,
+	}
+}
+
+func (h *CustomerGroupHandler) RegisterRoutes(r *gin.Engine) {
+	groupRoutes := r.Group("/customer-group")
+	groupRoutes.Use(middleware.ProtectedEndpoint())
+	groupRoutes.Use(middleware.RequireRole("admin"))
+	groupRoutes.Use(middleware.RateLimiterMiddleware(middleware.DefaultRateLimiterConfig()))
+
+	groupRoutes.GET("", h.ListCustomerGroups)
+	groupRoutes.POST("", h.CreateCustomerGroup)
+	groupRoutes.POST("/:id/members", h.AddGroupMember)
+	groupRoutes.DELETE("/:id/members/:user_id", h.RemoveGroupMember)
+}
+
+// ListCustomerGroups returns every customer group by name with its member count
+func (h *CustomerGroupHandler) ListCustomerGroups(c *gin.Context) {
+	response, err := h.userRepo.ListCustomerGroups()
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"groups": response.Groups})
+}
+
+// CreateCustomerGroup adds a group price rules can apply to, e.g. {"name": "wholesale"}
+func (h *CustomerGroupHandler) CreateCustomerGroup(c *gin.Context) {
+	var req struct {
+		Name        string `json:"name" binding:"required,max=100"`
+		Description string `json:"description" binding:"max=500"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, err := h.userRepo.CreateCustomerGroup(&proto.CustomerGroup{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, group)
+}
+
+// AddGroupMember puts a user in a group, e.g. {"user_id": 12}
+func (h *CustomerGroupHandler) AddGroupMember(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid customer group ID"})
+		return
+	}
+
+	var req struct {
+		UserID int32 `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.userRepo.AddGroupMember(&proto.GroupMemberRequest{GroupId: int32(ID), UserId: req.UserID}); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "User added to customer group"})
+}
+
+func (h *CustomerGroupHandler) RemoveGroupMember(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid customer group ID"})
+		return
+	}
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.userRepo.RemoveGroupMember(&proto.GroupMemberRequest{GroupId: int32(ID), UserId: int32(userID)}); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "User removed from customer group"})
+}
@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"broker/middleware"
+	"broker/proto"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdjustVariantStock adds or takes stock of a variant in the caller's stock pool of
+// one warehouse (the default one without warehouse_id),
+// e.g. {"delta": 20, "reason": "restock", "reference_type": "purchase_order", "reference_id": "PO-7"}
+func (u *ProductHandler) AdjustVariantStock(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("variant_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid variant ID"})
+		return
+	}
+
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	var req struct {
+		Delta         int32  `json:"delta" binding:"required"`
+		Reason        string `json:"reason" binding:"required,oneof=sale restock reservation_release manual_adjustment return"`
+		ReferenceType string `json:"reference_type" binding:"max=30"`
+		ReferenceID   string `json:"reference_id" binding:"max=64"`
+		Note          string `json:"note"`
+		WarehouseID   int32  `json:"warehouse_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	variant, err := u.productRepo.AdjustVariantStock(&proto.AdjustVariantStockRequest{
+		VariantId:     int32(ID),
+		Delta:         req.Delta,
+		Livemode:      middleware.IsLivemode(c.Request.Context()),
+		Reason:        req.Reason,
+		ReferenceType: req.ReferenceType,
+		ReferenceId:   req.ReferenceID,
+		Actor:         userActor(userID),
+		Note:          req.Note,
+		WarehouseId:   req.WarehouseID,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, variant)
+}
+
+// TransferStock moves stock of a variant between warehouses in the caller's stock pool,
+// e.g. {"from_warehouse_id": 1, "to_warehouse_id": 2, "quantity": 10}
+func (u *ProductHandler) TransferStock(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("variant_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid variant ID"})
+		return
+	}
+
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	var req struct {
+		FromWarehouseID int32  `json:"from_warehouse_id" binding:"required"`
+		ToWarehouseID   int32  `json:"to_warehouse_id" binding:"required"`
+		Quantity        int32  `json:"quantity" binding:"required,gt=0"`
+		Note            string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	variant, err := u.productRepo.TransferStock(&proto.TransferStockRequest{
+		VariantId:       int32(ID),
+		FromWarehouseId: req.FromWarehouseID,
+		ToWarehouseId:   req.ToWarehouseID,
+		Quantity:        req.Quantity,
+		Livemode:        middleware.IsLivemode(c.Request.Context()),
+		Actor:           userActor(userID),
+		Note:            req.Note,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, variant)
+}
+
+// ListInventoryMovements returns a product's stock history in the caller's stock pool, newest first
+func (u *ProductHandler) ListInventoryMovements(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	page := 1
+	if v := c.Query("page"); v != "" {
+		if page, err = strconv.Atoi(v); err != nil {
+			c.JSON(400, gin.H{"error": "Invalid page"})
+			return
+		}
+	}
+
+	response, err := u.productRepo.ListInventoryMovements(&proto.ListInventoryMovementsRequest{
+		ProductId: int32(ID),
+		Livemode:  middleware.IsLivemode(c.Request.Context()),
+		Page:      int32(page),
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"page":       response.Page,
+		"total":      response.Total,
+		"total_page": response.TotalPage,
+		"movements":  response.Movements,
+	})
+}
+
+// SetReorderThreshold sets the live stock at or below which a product is announced as
+// low on stock (product.low_stock), e.g. {"reorder_threshold": 15}; 0 turns it off
+func (u *ProductHandler) SetReorderThreshold(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req struct {
+		ReorderThreshold int32 `json:"reorder_threshold" binding:"min=0"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	threshold, err := u.productRepo.SetReorderThreshold(&proto.ReorderThreshold{
+		ProductId:        int32(ID),
+		ReorderThreshold: req.ReorderThreshold,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, threshold)
+}
+
+// GetReorderReport suggests what to reorder from recent sales velocity,
+// e.g. /product/reorder-report?window_days=30&cover_days=45&reorder_only=true
+func (u *ProductHandler) GetReorderReport(c *gin.Context) {
+	req := &proto.ReorderReportRequest{}
+	if v := c.Query("window_days"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "Invalid window_days"})
+			return
+		}
+		req.WindowDays = int32(days)
+	}
+	if v := c.Query("cover_days"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "Invalid cover_days"})
+			return
+		}
+		req.CoverDays = int32(days)
+	}
+	if v := c.Query("reorder_only"); v != "" {
+		var err error
+		if req.ReorderOnly, err = strconv.ParseBool(v); err != nil {
+			c.JSON(400, gin.H{"error": "Invalid reorder_only"})
+			return
+		}
+	}
+
+	report, err := u.productRepo.GetReorderReport(req)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"window_days": report.WindowDays,
+		"cover_days":  report.CoverDays,
+		"suggestions": report.Suggestions,
+	})
+}
+
+// userActor identifies a signed-in user on stock movements
+func userActor(userID int) string {
+	return fmt.Sprintf("user:%d", userID)
+}
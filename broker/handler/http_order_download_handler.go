@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"broker/middleware"
+	"broker/proto"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Download serves the file of a digital order item from a signed link the order
+// service issued, e.g. GET /download/7?expires=1767225600&signature=..., and
+// counts it against the item's download limit once the file opened
+func (u *OrderHandler) Download(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("delivery_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil || !validDownloadSignature(int32(ID), expires, c.Query("signature")) {
+		c.JSON(403, gin.H{"error": "invalid download link"})
+		return
+	}
+	if time.Now().Unix() > expires {
+		c.JSON(410, gin.H{"error": "download link has expired"})
+		return
+	}
+
+	delivery, err := u.orderRepo.RecordDownload(&proto.RecordDownloadRequest{DeliveryId: int32(ID), CheckOnly: true})
+	if err != nil {
+		downloadError(c, err)
+		return
+	}
+
+	file, err := u.downloads.Open(c.Request.Context(), delivery.FileKey)
+	if err != nil {
+		logrus.Errorf("Failed to open download %s of delivery %d: %v", delivery.FileKey, ID, err)
+		c.JSON(500, gin.H{"error": "failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	// A file that cannot be read does not use up a download; counting can still
+	// refuse it if another download took the last one meanwhile
+	if _, err := u.orderRepo.RecordDownload(&proto.RecordDownloadRequest{DeliveryId: int32(ID)}); err != nil {
+		downloadError(c, err)
+		return
+	}
+
+	contentType := delivery.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.DataFromReader(200, -1, contentType, file, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": delivery.FileName}),
+		"Cache-Control":       "no-store",
+	})
+}
+
+// downloadError answers a refused download with 410 and an unknown one with 404
+func downloadError(c *gin.Context, err error) {
+	switch status.Code(err) {
+	case codes.NotFound:
+		c.JSON(404, gin.H{"error": "download not found"})
+	case codes.FailedPrecondition:
+		c.JSON(410, gin.H{"error": status.Convert(err).Message()})
+	default:
+		c.JSON(500, gin.H{"error": err.Error()})
+	}
+}
+
+// DeliverOrder delivers the digital items of a paid order again, for when they were
+// not delivered at payment time, and returns the order with its deliveries
+func (u *OrderHandler) DeliverOrder(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	order, err := u.orderRepo.GetOrderById(&proto.GetOrderByIdRequest{
+		OrderId: int32(id),
+		UserId:  int32(userID),
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if order.Order.Livemode != middleware.IsLivemode(c.Request.Context()) {
+		c.JSON(404, gin.H{"error": "order not found"})
+		return
+	}
+
+	order, err = u.orderRepo.DeliverOrder(&proto.GetOrderByIdRequest{
+		OrderId: int32(id),
+		UserId:  int32(userID),
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, order)
+}
+
+// validDownloadSignature checks a download link against DOWNLOAD_SIGNING_SECRET, the
+// secret the order service signs links with
+func validDownloadSignature(deliveryID int32, expires int64, signature string) bool {
+	secret := os.Getenv("DOWNLOAD_SIGNING_SECRET")
+	if secret == "" || signature == "" {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d:%d", deliveryID, expires)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
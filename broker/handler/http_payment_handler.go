@@ -1,134 +1,461 @@
-package handler
-
-import (
-	"broker/middleware"
-	"broker/proto"
-	"broker/repository"
-	"strconv"
-
-	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
-)
-
-type PaymentHandler struct {
-	repo repository.PaymentRepository
-}
-
-func NewPaymentHandler(repo repository.PaymentRepository) *PaymentHandler {
-	return &PaymentHandler{
-		repo: repo,
-	}
-}
-
-func (u *PaymentHandler) RegisterRoutes(r *gin.Engine) {
-	// Protected routes (require auth)
-	paymentRoutes := r.Group("/payment")
-	paymentRoutes.Use(middleware.ProtectedEndpoint())
-	paymentRoutes.Use(middleware.RateLimiterMiddleware(middleware.DefaultRateLimiterConfig()))
-
-	paymentRoutes.GET("/order/:order_id", u.GetPaymentByOrderId)
-	paymentRoutes.POST("/initiate", u.InitiatePayment)
-
-	// Webhook route (no auth - called by Midtrans)
-	r.POST("/payment/webhook/midtrans", u.HandleMidtransWebhook)
-}
-
-// GetPaymentByOrderId retrieves payment by order ID
-func (u *PaymentHandler) GetPaymentByOrderId(c *gin.Context) {
-	orderIDStr := c.Param("order_id")
-	orderID, err := strconv.Atoi(orderIDStr)
-	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid order ID"})
-		return
-	}
-
-	payment, err := u.repo.GetPaymentByOrderId(int32(orderID))
-	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(200, payment)
-}
-
-// InitiatePayment creates a Midtrans Snap transaction
-func (u *PaymentHandler) InitiatePayment(c *gin.Context) {
-	var req struct {
-		OrderID        int32  `json:"order_id" binding:"required"`
-		PaymentMethod  string `json:"payment_method"`
-		PaymentChannel string `json:"payment_channel"`
-		CustomerName   string `json:"customer_name" binding:"required"`
-		CustomerEmail  string `json:"customer_email" binding:"required"`
-		CustomerPhone  string `json:"customer_phone"`
-	}
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, gin.H{"error": err.Error()})
-		return
-	}
-
-	logrus.Infof("Initiating payment for order: %d", req.OrderID)
-
-	response, err := u.repo.InitiatePayment(&proto.InitiatePaymentRequest{
-		OrderId:        req.OrderID,
-		PaymentMethod:  req.PaymentMethod,
-		PaymentChannel: req.PaymentChannel,
-		CustomerName:   req.CustomerName,
-		CustomerEmail:  req.CustomerEmail,
-		CustomerPhone:  req.CustomerPhone,
-	})
-	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(200, gin.H{
-		"payment_id":   response.PaymentId,
-		"token":        response.GatewayToken,
-		"redirect_url": response.GatewayRedirectUrl,
-		"va_number":    response.VaNumber,
-		"qr_code_url":  response.QrCodeUrl,
-		"expired_at":   response.ExpiredAt,
-		"status":       response.Status,
-	})
-}
-
-// HandleMidtransWebhook processes webhook notifications from Midtrans
-func (u *PaymentHandler) HandleMidtransWebhook(c *gin.Context) {
-	var req struct {
-		OrderID           string `json:"order_id"`
-		TransactionID     string `json:"transaction_id"`
-		TransactionStatus string `json:"transaction_status"`
-		PaymentType       string `json:"payment_type"`
-		GrossAmount       string `json:"gross_amount"`
-		SignatureKey      string `json:"signature_key"`
-		FraudStatus       string `json:"fraud_status"`
-		StatusCode        string `json:"status_code"`
-	}
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		logrus.Errorf("Invalid webhook payload: %v", err)
-		c.JSON(400, gin.H{"error": err.Error()})
-		return
-	}
-
-	logrus.Infof("Received Midtrans webhook for order: %s, status: %s", req.OrderID, req.TransactionStatus)
-
-	err := u.repo.HandleWebhook(&proto.WebhookRequest{
-		OrderId:           req.OrderID,
-		TransactionId:     req.TransactionID,
-		TransactionStatus: req.TransactionStatus,
-		PaymentType:       req.PaymentType,
-		GrossAmount:       req.GrossAmount,
-		SignatureKey:      req.SignatureKey,
-		FraudStatus:       req.FraudStatus,
-		StatusCode:        req.StatusCode,
-	})
-	if err != nil {
-		logrus.Errorf("Failed to handle webhook: %v", err)
-		c.JSON(500, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(200, gin.H{"status": "ok"})
-}
+package handler
+
+import (
+	"broker/middleware"
+	"broker/proto"
+	"broker/repository"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// refundItemRequest is an order item returned with a refund
+type refundItemRequest struct {
+	OrderItemID int32 `json:"order_item_id" binding:"required"`
+	Quantity    int32 `json:"quantity" binding:"required,gt=0"`
+}
+
+// refundItems converts returned items to their proto form
+func refundItems(items []refundItemRequest) []*proto.RefundItem {
+	result := make([]*proto.RefundItem, 0, len(items))
+	for _, item := range items {
+		result = append(result, &proto.RefundItem{OrderItemId: item.OrderItemID, Quantity: item.Quantity})
+	}
+	return result
+}
+
+type PaymentHandler struct {
+	repo repository.PaymentRepository
+}
+
+func NewPaymentHandler(repo repository.PaymentRepository) *PaymentHandler {
+	return &PaymentHandler{
+		repo: repo,
+	}
+}
+
+func (u *PaymentHandler) RegisterRoutes(r *gin.Engine) {
+	// Protected routes (require auth)
+	paymentRoutes := r.Group("/payment")
+	paymentRoutes.Use(middleware.ProtectedEndpoint())
+	paymentRoutes.Use(middleware.RateLimiterMiddleware(middleware.DefaultRateLimiterConfig()))
+
+	paymentRoutes.GET("/order/:order_id", u.GetPaymentByOrderId)
+	paymentRoutes.POST("/order/:order_id/refund", middleware.RequireRole("admin"), u.RefundPayment)
+	paymentRoutes.POST("/initiate", u.InitiatePayment)
+	paymentRoutes.POST("/intent", u.CreatePaymentIntent)
+	paymentRoutes.GET("/intent/:id", u.GetPaymentIntent)
+	paymentRoutes.POST("/intent/:id/refund", middleware.RequireRole("admin"), u.RefundPaymentIntent)
+	paymentRoutes.POST("/card", u.InitiateCardPayment)
+	paymentRoutes.GET("/methods", u.ListSavedPaymentMethods)
+	paymentRoutes.DELETE("/methods/:id", u.DeleteSavedPaymentMethod)
+	paymentRoutes.GET("/wallet", u.GetWallet)
+	paymentRoutes.POST("/wallet/topup", u.TopUpWallet)
+
+	// Webhook route (no auth - called by Midtrans)
+	r.POST("/payment/webhook/midtrans", u.HandleMidtransWebhook)
+}
+
+// GetPaymentByOrderId retrieves payment by order ID
+func (u *PaymentHandler) GetPaymentByOrderId(c *gin.Context) {
+	orderIDStr := c.Param("order_id")
+	orderID, err := strconv.Atoi(orderIDStr)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	payment, err := u.repo.GetPaymentByOrderId(int32(orderID))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Test-mode credentials only see test-mode payments and vice versa
+	if payment.Livemode != middleware.IsLivemode(c.Request.Context()) {
+		c.JSON(404, gin.H{"error": "payment not found"})
+		return
+	}
+
+	c.JSON(200, payment)
+}
+
+// InitiatePayment creates a Midtrans Snap transaction
+func (u *PaymentHandler) InitiatePayment(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	var req struct {
+		OrderID        int32  `json:"order_id" binding:"required"`
+		PaymentMethod  string `json:"payment_method"`
+		PaymentChannel string `json:"payment_channel"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	logrus.Infof("Initiating payment for order: %d", req.OrderID)
+
+	// Customer details come from the user's profile, not the request body
+	response, err := u.repo.InitiatePayment(&proto.InitiatePaymentRequest{
+		OrderId:        req.OrderID,
+		PaymentMethod:  req.PaymentMethod,
+		PaymentChannel: req.PaymentChannel,
+		UserId:         int32(userID),
+		Livemode:       middleware.IsLivemode(c.Request.Context()),
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"payment_id":     response.PaymentId,
+		"token":          response.GatewayToken,
+		"redirect_url":   response.GatewayRedirectUrl,
+		"va_number":      response.VaNumber,
+		"qr_code_url":    response.QrCodeUrl,
+		"expired_at":     response.ExpiredAt,
+		"status":         response.Status,
+		"wallet_amount":  response.WalletAmount,
+		"gateway_amount": response.GatewayAmount,
+	})
+}
+
+// CreatePaymentIntent creates one Midtrans transaction paying for several orders
+func (u *PaymentHandler) CreatePaymentIntent(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	var req struct {
+		OrderIDs       []int32 `json:"order_ids" binding:"required,min=1"`
+		PaymentMethod  string  `json:"payment_method"`
+		PaymentChannel string  `json:"payment_channel"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	logrus.Infof("Creating payment intent for orders: %v", req.OrderIDs)
+
+	intent, err := u.repo.CreatePaymentIntent(&proto.CreatePaymentIntentRequest{
+		UserId:         int32(userID),
+		OrderIds:       req.OrderIDs,
+		PaymentMethod:  req.PaymentMethod,
+		PaymentChannel: req.PaymentChannel,
+		Livemode:       middleware.IsLivemode(c.Request.Context()),
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, intent)
+}
+
+// GetPaymentIntent retrieves a payment intent owned by the current user
+func (u *PaymentHandler) GetPaymentIntent(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	intentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid payment intent ID"})
+		return
+	}
+
+	intent, err := u.repo.GetPaymentIntent(int32(intentID), int32(userID))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	if intent.Livemode != middleware.IsLivemode(c.Request.Context()) {
+		c.JSON(404, gin.H{"error": "payment intent not found"})
+		return
+	}
+
+	c.JSON(200, intent)
+}
+
+// RefundPaymentIntent refunds part of a settled payment intent to one of its orders
+func (u *PaymentHandler) RefundPaymentIntent(c *gin.Context) {
+	intentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid payment intent ID"})
+		return
+	}
+
+	var req struct {
+		OrderID       int32               `json:"order_id" binding:"required"`
+		Amount        float64             `json:"amount" binding:"gte=0"`
+		Reason        string              `json:"reason"`
+		AsStoreCredit bool                `json:"as_store_credit"`
+		Items         []refundItemRequest `json:"items" binding:"dive"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Without returned items there is nothing to work the amount out from
+	if req.Amount == 0 && len(req.Items) == 0 {
+		c.JSON(400, gin.H{"error": "Provide amount or items"})
+		return
+	}
+
+	logrus.Infof("Refunding payment intent %d to order %d", intentID, req.OrderID)
+
+	refund, err := u.repo.RefundPaymentIntent(&proto.RefundPaymentIntentRequest{
+		IntentId:      int32(intentID),
+		OrderId:       req.OrderID,
+		Amount:        req.Amount,
+		Reason:        req.Reason,
+		AsStoreCredit: req.AsStoreCredit,
+		Items:         refundItems(req.Items),
+		Livemode:      middleware.IsLivemode(c.Request.Context()),
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, refund)
+}
+
+// RefundPayment refunds part of what was paid for an order, however it was paid
+func (u *PaymentHandler) RefundPayment(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("order_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	var req struct {
+		Amount        float64             `json:"amount" binding:"gte=0"`
+		Reason        string              `json:"reason"`
+		AsStoreCredit bool                `json:"as_store_credit"`
+		Items         []refundItemRequest `json:"items" binding:"dive"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Without returned items there is nothing to work the amount out from
+	if req.Amount == 0 && len(req.Items) == 0 {
+		c.JSON(400, gin.H{"error": "Provide amount or items"})
+		return
+	}
+
+	logrus.Infof("Refunding order %d", orderID)
+
+	refund, err := u.repo.RefundPayment(&proto.RefundPaymentRequest{
+		OrderId:       int32(orderID),
+		Amount:        req.Amount,
+		Reason:        req.Reason,
+		AsStoreCredit: req.AsStoreCredit,
+		Items:         refundItems(req.Items),
+		Livemode:      middleware.IsLivemode(c.Request.Context()),
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, refund)
+}
+
+// InitiateCardPayment charges an order to a new card token or a saved card
+func (u *PaymentHandler) InitiateCardPayment(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	var req struct {
+		OrderID              int32  `json:"order_id" binding:"required"`
+		CardToken            string `json:"card_token"`
+		SaveCard             bool   `json:"save_card"`
+		SavedPaymentMethodID int32  `json:"saved_payment_method_id"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	if (req.CardToken == "") == (req.SavedPaymentMethodID == 0) {
+		c.JSON(400, gin.H{"error": "Provide either card_token or saved_payment_method_id"})
+		return
+	}
+
+	logrus.Infof("Initiating card payment for order: %d", req.OrderID)
+
+	response, err := u.repo.InitiateCardPayment(&proto.InitiateCardPaymentRequest{
+		OrderId:              req.OrderID,
+		UserId:               int32(userID),
+		CardToken:            req.CardToken,
+		SaveCard:             req.SaveCard,
+		SavedPaymentMethodId: req.SavedPaymentMethodID,
+		Livemode:             middleware.IsLivemode(c.Request.Context()),
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"payment_id":              response.PaymentId,
+		"redirect_url":            response.GatewayRedirectUrl,
+		"status":                  response.Status,
+		"saved_payment_method_id": response.SavedPaymentMethodId,
+		"wallet_amount":           response.WalletAmount,
+		"gateway_amount":          response.GatewayAmount,
+	})
+}
+
+// ListSavedPaymentMethods lists the current user's saved cards
+func (u *PaymentHandler) ListSavedPaymentMethods(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	response, err := u.repo.ListSavedPaymentMethods(int32(userID), middleware.IsLivemode(c.Request.Context()))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, response)
+}
+
+// DeleteSavedPaymentMethod deletes one of the current user's saved cards
+func (u *PaymentHandler) DeleteSavedPaymentMethod(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid payment method ID"})
+		return
+	}
+
+	if err := u.repo.DeleteSavedPaymentMethod(int32(id), int32(userID)); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Payment method deleted"})
+}
+
+// GetWallet returns the current user's store credit balance and recent transactions
+func (u *PaymentHandler) GetWallet(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	wallet, err := u.repo.GetWallet(int32(userID), middleware.IsLivemode(c.Request.Context()))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, wallet)
+}
+
+// TopUpWallet starts a Midtrans transaction that credits the current user's wallet once paid
+func (u *PaymentHandler) TopUpWallet(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	var req struct {
+		Amount float64 `json:"amount" binding:"required,gt=0"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	logrus.Infof("Topping up wallet of user %d, amount: %f", userID, req.Amount)
+
+	topUp, err := u.repo.TopUpWallet(&proto.TopUpWalletRequest{
+		UserId:   int32(userID),
+		Amount:   req.Amount,
+		Livemode: middleware.IsLivemode(c.Request.Context()),
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, topUp)
+}
+
+// HandleMidtransWebhook processes webhook notifications from Midtrans
+func (u *PaymentHandler) HandleMidtransWebhook(c *gin.Context) {
+	var req struct {
+		OrderID           string `json:"order_id"`
+		TransactionID     string `json:"transaction_id"`
+		TransactionStatus string `json:"transaction_status"`
+		PaymentType       string `json:"payment_type"`
+		GrossAmount       string `json:"gross_amount"`
+		SignatureKey      string `json:"signature_key"`
+		FraudStatus       string `json:"fraud_status"`
+		StatusCode        string `json:"status_code"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logrus.Errorf("Invalid webhook payload: %v", err)
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	logrus.Infof("Received Midtrans webhook for order: %s, status: %s", req.OrderID, req.TransactionStatus)
+
+	err := u.repo.HandleWebhook(&proto.WebhookRequest{
+		OrderId:           req.OrderID,
+		TransactionId:     req.TransactionID,
+		TransactionStatus: req.TransactionStatus,
+		PaymentType:       req.PaymentType,
+		GrossAmount:       req.GrossAmount,
+		SignatureKey:      req.SignatureKey,
+		FraudStatus:       req.FraudStatus,
+		StatusCode:        req.StatusCode,
+	})
+	if err != nil {
+		logrus.Errorf("Failed to handle webhook: %v", err)
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"status": "ok"})
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"broker/proto"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// maxDigitalFileBytes caps the size of a digital product's file
+const maxDigitalFileBytes = 200 << 20
+
+// SetDigitalDelivery makes ?id= a digital product or changes how it is delivered.
+// Downloads take a multipart "file", stored in the private download store; without
+// one the product keeps its current file. Licence key products take JSON or form
+// fields only, e.g. {"delivery": "license_key"}. download_limit and link_ttl_hours
+// default to 5 downloads and 72 hours.
+func (u *ProductHandler) SetDigitalDelivery(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	// Leave room for the multipart envelope around the file itself
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxDigitalFileBytes+1<<20)
+
+	var req struct {
+		Delivery      string `json:"delivery" form:"delivery"`
+		DownloadLimit int32  `json:"download_limit" form:"download_limit"`
+		LinkTTLHours  int32  `json:"link_ttl_hours" form:"link_ttl_hours"`
+	}
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Delivery == "" {
+		req.Delivery = "download"
+	}
+
+	delivery := &proto.DigitalDelivery{
+		ProductId:     int32(ID),
+		Delivery:      req.Delivery,
+		DownloadLimit: req.DownloadLimit,
+		LinkTtlHours:  req.LinkTTLHours,
+	}
+
+	var key string
+	if req.Delivery == "download" {
+		fileHeader, err := c.FormFile("file")
+		switch {
+		case errors.Is(err, http.ErrMissingFile), errors.Is(err, http.ErrNotMultipart):
+			// Only the limits change; the product keeps the file it has
+			product, err := u.productRepo.GetProduct(&proto.GetProductRequest{Id: int32(ID)})
+			if err != nil {
+				c.JSON(500, gin.H{"error": err.Error()})
+				return
+			}
+			if product.Digital == nil || product.Digital.FileKey == "" {
+				c.JSON(400, gin.H{"error": "file is required"})
+				return
+			}
+			delivery.FileKey = product.Digital.FileKey
+			delivery.FileName = product.Digital.FileName
+			delivery.ContentType = product.Digital.ContentType
+		case err != nil:
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		default:
+			if fileHeader.Size > maxDigitalFileBytes {
+				c.JSON(413, gin.H{"error": fmt.Sprintf("file is larger than %d MB", maxDigitalFileBytes>>20)})
+				return
+			}
+
+			file, err := fileHeader.Open()
+			if err != nil {
+				c.JSON(400, gin.H{"error": err.Error()})
+				return
+			}
+			defer file.Close()
+
+			data, err := io.ReadAll(io.LimitReader(file, maxDigitalFileBytes+1))
+			if err != nil {
+				c.JSON(400, gin.H{"error": err.Error()})
+				return
+			}
+
+			contentType := fileHeader.Header.Get("Content-Type")
+			if contentType == "" {
+				contentType = http.DetectContentType(data)
+			}
+
+			name, err := randomName()
+			if err != nil {
+				c.JSON(500, gin.H{"error": err.Error()})
+				return
+			}
+			key = fmt.Sprintf("products/%d/%s%s", ID, name, path.Ext(fileHeader.Filename))
+			if err := u.downloads.Put(c.Request.Context(), key, data, contentType); err != nil {
+				c.JSON(500, gin.H{"error": err.Error()})
+				return
+			}
+
+			delivery.FileKey = key
+			delivery.FileName = path.Base(fileHeader.Filename)
+			delivery.ContentType = contentType
+		}
+	}
+
+	// The previous file is kept, as orders that were delivered it still download it
+	product, err := u.productRepo.SetDigitalDelivery(delivery)
+	if err != nil {
+		if key != "" {
+			if err := u.downloads.Delete(c.Request.Context(), key); err != nil {
+				logrus.Warnf("Failed to delete download %s: %v", key, err)
+			}
+		}
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	signImages(product.Images, u.store)
+	c.JSON(200, product)
+}
+
+// AddLicenseKeys adds keys to the pool of a licence key product ?id=; keys already
+// in the pool are skipped
+func (u *ProductHandler) AddLicenseKeys(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req struct {
+		Keys []string `json:"keys" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	pool, err := u.productRepo.AddLicenseKeys(&proto.AddLicenseKeysRequest{
+		ProductId: int32(ID),
+		Keys:      req.Keys,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, pool)
+}
@@ -1,135 +1,619 @@
-package handler
-
-import (
-	"broker/middleware"
-	"broker/proto"
-	"broker/repository"
-	"broker/types"
-	"strconv"
-
-	"github.com/gin-gonic/gin"
-)
-
-type ProductHandler struct {
-	productRepo repository.ProductRepository
-}
-
-func NewProductHandler(repo repository.ProductRepository) *ProductHandler {
-	return &ProductHandler{
-		productRepo: repo,
-	}
-}
-
-func (p *ProductHandler) RegisterRoutes(r *gin.Engine) {
-	// Public routes (no auth required)
-	r.GET("/product/", p.ListProducts)
-
-	// Protected routes (auth required)
-	productRoutes := r.Group("/product")
-	productRoutes.Use(middleware.ProtectedEndpoint())
-	productRoutes.Use(middleware.RateLimiterMiddleware(middleware.DefaultRateLimiterConfig()))
-
-	productRoutes.POST("/", p.CreateProduct)
-	productRoutes.PUT("/", p.UpdateProduct)
-	productRoutes.DELETE("/", p.DeleteProduct)
-}
-
-func (p *ProductHandler) CreateProduct(c *gin.Context) {
-	var payload proto.ProductPayload
-	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(400, gin.H{"error": err.Error()})
-		return
-	}
-	if _, err := p.productRepo.Create(&proto.ProductRequest{
-		Payload: &payload,
-	}); err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(200, gin.H{"message": "Product created successfully"})
-}
-
-func (u *ProductHandler) ListProducts(c *gin.Context) {
-	query := c.Query("page")
-	page, err := strconv.Atoi(query)
-	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid page"})
-		return
-	}
-
-	response, err := u.productRepo.ListProducts(&proto.Offset{Id: int32(page)})
-	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
-		return
-	}
-
-	items := make([]*types.ProductDTO, 0, len(response.Products))
-	for _, p := range response.Products {
-		items = append(items, &types.ProductDTO{
-			Id:          p.Id,
-			Name:        p.Name,
-			Description: p.Description,
-			Price:       p.Price,
-			Stock:       p.Stock,
-			CreatedAt:   p.CreatedAt,
-			UpdatedAt:   p.UpdatedAt,
-		})
-	}
-
-	c.JSON(200, types.ProductListDTO{
-		Page:      response.Page,
-		Total:     response.Total,
-		TotalPage: response.TotalPage,
-		Products:  items,
-	})
-}
-
-func (u *ProductHandler) UpdateProduct(c *gin.Context) {
-	var product proto.Product
-	if err := c.ShouldBindJSON(&product); err != nil {
-		c.JSON(400, gin.H{"error": err.Error()})
-		return
-	}
-
-	query := c.Query("id")
-	ID, err := strconv.Atoi(query)
-	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid ID"})
-		return
-	}
-	product.Id = uint32(ID)
-
-	response, err := u.productRepo.UpdateProduct(&product)
-	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
-		return
-	}
-	item := &types.ProductDTO{
-		Id:          response.Id,
-		Name:        response.Name,
-		Description: response.Description,
-		Price:       response.Price,
-		Stock:       response.Stock,
-		CreatedAt:   response.CreatedAt,
-		UpdatedAt:   response.UpdatedAt,
-	}
-
-	c.JSON(200, item)
-}
-
-func (u *ProductHandler) DeleteProduct(c *gin.Context) {
-	query := c.Query("id")
-	ID, err := strconv.Atoi(query)
-	if err != nil {
-		c.JSON(400, gin.H{"error": "InvalidID"})
-		return
-	}
-
-	_, err = u.productRepo.DeleteProduct(&proto.GetProductRequest{Id: int32(ID)})
-	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(200, gin.H{"message": "Product deleted successfully"})
-}
+package handler
+
+import (
+	"broker/middleware"
+	"broker/proto"
+	"broker/repository"
+	"broker/storage"
+	"broker/types"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProductHandler struct {
+	productRepo repository.ProductRepository
+	store       storage.BlobStore
+	downloads   storage.BlobStore // Private files of digital products
+}
+
+func NewProductHandler(repo repository.ProductRepository, store storage.BlobStore, downloads storage.BlobStore) *ProductHandler {
+	return &ProductHandler{
+		productRepo: repo,
+		store:       store,
+		downloads:   downloads,
+	}
+}
+
+func (p *ProductHandler) RegisterRoutes(r *gin.Engine) {
+	// Public routes (no auth required)
+	r.GET("/product/", p.ListProducts)
+	r.GET("/product/search", p.SearchProducts)
+	r.GET("/product/slug/:slug", p.GetProductBySlug)
+	r.GET("/product/variants", p.ListVariants)
+	r.GET("/product/images", p.ListProductImages)
+	r.GET("/product/reviews", p.ListReviews)
+	r.GET("/product/recommendations", p.GetRecommendations)
+
+	// Protected routes (auth required)
+	productRoutes := r.Group("/product")
+	productRoutes.Use(middleware.ProtectedEndpoint())
+	productRoutes.Use(middleware.RateLimiterMiddleware(middleware.DefaultRateLimiterConfig()))
+
+	productRoutes.POST("/", p.CreateProduct)
+	productRoutes.PUT("/", p.UpdateProduct)
+	productRoutes.DELETE("/", p.DeleteProduct)
+	productRoutes.POST("/unarchive", p.UnarchiveProduct)
+	productRoutes.DELETE("/purge", middleware.RequireRole("admin"), p.PurgeProduct)
+	productRoutes.PUT("/categories", p.SetProductCategories)
+	productRoutes.PUT("/tags", p.SetProductTags)
+	productRoutes.PUT("/options", p.SetProductOptions)
+	productRoutes.PUT("/bundle", p.SetBundleComponents)
+	productRoutes.POST("/digital", middleware.RequireRole("admin"), p.SetDigitalDelivery)
+	productRoutes.POST("/license-keys", middleware.RequireRole("admin"), p.AddLicenseKeys)
+	productRoutes.GET("/translations", p.ListProductTranslations)
+	productRoutes.PUT("/translations/:locale", p.SetProductTranslation)
+	productRoutes.DELETE("/translations/:locale", p.DeleteProductTranslation)
+	productRoutes.POST("/variants", p.CreateVariant)
+	productRoutes.PUT("/variants/:variant_id", p.UpdateVariant)
+	productRoutes.DELETE("/variants/:variant_id", p.DeleteVariant)
+	productRoutes.POST("/variants/:variant_id/stock", p.AdjustVariantStock)
+	productRoutes.POST("/variants/:variant_id/transfer", p.TransferStock)
+	productRoutes.GET("/inventory", p.ListInventoryMovements)
+	productRoutes.PUT("/reorder-threshold", middleware.RequireRole("admin"), p.SetReorderThreshold)
+	productRoutes.GET("/reorder-report", middleware.RequireRole("admin"), p.GetReorderReport)
+	productRoutes.GET("/prices", p.GetPriceHistory)
+	productRoutes.POST("/prices", p.SchedulePrice)
+	productRoutes.DELETE("/prices/:price_id", p.EndScheduledPrice)
+	productRoutes.GET("/price-rules", p.ListPriceRules)
+	productRoutes.POST("/price-rules", middleware.RequireRole("admin"), p.CreatePriceRule)
+	productRoutes.DELETE("/price-rules/:rule_id", middleware.RequireRole("admin"), p.DeletePriceRule)
+	productRoutes.POST("/quote", p.QuotePrices)
+	productRoutes.POST("/reviews", p.CreateReview)
+	productRoutes.DELETE("/reviews/:review_id", p.DeleteReview)
+	productRoutes.GET("/reviews/moderation", middleware.RequireRole("admin", "moderator"), p.ListReviewsForModeration)
+	productRoutes.PUT("/reviews/:review_id/moderation", middleware.RequireRole("admin", "moderator"), p.ModerateReview)
+	productRoutes.POST("/images", p.UploadProductImage)
+	productRoutes.PUT("/images", p.ReorderProductImages)
+	productRoutes.PUT("/images/:image_id", p.UpdateProductImage)
+	productRoutes.DELETE("/images/:image_id", p.DeleteProductImage)
+	productRoutes.POST("/import", p.ImportProducts)
+	productRoutes.GET("/export", p.ExportProducts)
+}
+
+func (p *ProductHandler) CreateProduct(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	var payload proto.ProductPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := p.productRepo.Create(&proto.ProductRequest{
+		Payload: &payload,
+		Actor:   userActor(userID),
+	}); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"message": "Product created successfully"})
+}
+
+func (u *ProductHandler) ListProducts(c *gin.Context) {
+	query := c.Query("page")
+	page, err := strconv.Atoi(query)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid page"})
+		return
+	}
+
+	response, err := u.productRepo.ListProducts(&proto.Offset{
+		Id:             int32(page),
+		AcceptLanguage: c.GetHeader("Accept-Language"),
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, types.ProductListDTO{
+		Page:      response.Page,
+		Total:     response.Total,
+		TotalPage: response.TotalPage,
+		Products:  productDTOs(response.Products, u.store),
+	})
+}
+
+// SearchProducts runs a full-text search, e.g. /product/search?q=shirt&min_price=50000&in_stock=true&sort=price_asc
+func (u *ProductHandler) SearchProducts(c *gin.Context) {
+	req := &proto.SearchProductsRequest{
+		Query:          c.Query("q"),
+		Sort:           c.Query("sort"),
+		AcceptLanguage: c.GetHeader("Accept-Language"),
+	}
+
+	var err error
+	if v := c.Query("min_price"); v != "" {
+		if req.MinPrice, err = strconv.ParseFloat(v, 64); err != nil {
+			c.JSON(400, gin.H{"error": "Invalid min_price"})
+			return
+		}
+	}
+	if v := c.Query("max_price"); v != "" {
+		if req.MaxPrice, err = strconv.ParseFloat(v, 64); err != nil {
+			c.JSON(400, gin.H{"error": "Invalid max_price"})
+			return
+		}
+	}
+	if v := c.Query("in_stock"); v != "" {
+		if req.InStock, err = strconv.ParseBool(v); err != nil {
+			c.JSON(400, gin.H{"error": "Invalid in_stock"})
+			return
+		}
+	}
+	if v := c.Query("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "Invalid page"})
+			return
+		}
+		req.Page = int32(page)
+	}
+	if v := c.Query("page_size"); v != "" {
+		pageSize, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "Invalid page_size"})
+			return
+		}
+		req.PageSize = int32(pageSize)
+	}
+
+	if v := c.Query("category_id"); v != "" {
+		categoryID, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "Invalid category_id"})
+			return
+		}
+		req.CategoryId = int32(categoryID)
+	}
+
+	response, err := u.productRepo.SearchProducts(req)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, productListDTO(response, u.store))
+}
+
+// GetProductBySlug returns an active product by slug, in the locale the
+// Accept-Language header prefers. A slug the product used to have redirects
+// permanently to its current one.
+func (u *ProductHandler) GetProductBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+	product, err := u.productRepo.GetProductBySlug(&proto.GetProductBySlugRequest{
+		Slug:           slug,
+		AcceptLanguage: c.GetHeader("Accept-Language"),
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if product.ArchivedAt != "" {
+		c.JSON(404, gin.H{"error": "product not found"})
+		return
+	}
+
+	if product.Slug != slug {
+		location := "/product/slug/" + url.PathEscape(product.Slug)
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(301, location)
+		return
+	}
+
+	c.Header("Content-Language", product.Locale)
+	c.Header("Vary", "Accept-Language")
+	c.JSON(200, gin.H{
+		"product":    productDTOs([]*proto.Product{product}, u.store)[0],
+		"categories": product.Categories,
+		"tags":       product.Tags,
+		"options":    product.Options,
+		"variants":   product.Variants,
+		"components": product.Components,
+	})
+}
+
+// SetProductCategories replaces the categories a product is listed under
+func (u *ProductHandler) SetProductCategories(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req struct {
+		CategoryIDs []int32 `json:"category_ids"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := u.productRepo.SetProductCategories(&proto.SetProductCategoriesRequest{
+		ProductId:   int32(ID),
+		CategoryIds: req.CategoryIDs,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	signImages(product.Images, u.store)
+	c.JSON(200, product)
+}
+
+// SetProductTags replaces the tags of a product; unknown tags are created
+func (u *ProductHandler) SetProductTags(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req struct {
+		Tags []string `json:"tags"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := u.productRepo.SetProductTags(&proto.SetProductTagsRequest{
+		ProductId: int32(ID),
+		Tags:      req.Tags,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	signImages(product.Images, u.store)
+	c.JSON(200, product)
+}
+
+// ListVariants returns the options and variants a shopper picks from before ordering
+func (u *ProductHandler) ListVariants(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	product, err := u.productRepo.GetProduct(&proto.GetProductRequest{Id: int32(ID)})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"product_id": product.Id,
+		"price":      product.Price,
+		"options":    product.Options,
+		"variants":   product.Variants,
+	})
+}
+
+// GetRecommendations returns in-stock products frequently bought together with ?id=,
+// topped up with popular products, e.g. /product/recommendations?id=12&limit=4
+func (u *ProductHandler) GetRecommendations(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil {
+			c.JSON(400, gin.H{"error": "Invalid limit"})
+			return
+		}
+	}
+
+	response, err := u.productRepo.GetRecommendations(&proto.GetRecommendationsRequest{
+		ProductId: int32(ID),
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	recommendations := make([]gin.H, 0, len(response.Recommendations))
+	for _, recommendation := range response.Recommendations {
+		recommendations = append(recommendations, gin.H{
+			"product":     productDTOs([]*proto.Product{recommendation.Product}, u.store)[0],
+			"reason":      recommendation.Reason,
+			"order_count": recommendation.OrderCount,
+		})
+	}
+
+	c.JSON(200, gin.H{
+		"product_id":      ID,
+		"recommendations": recommendations,
+	})
+}
+
+// SetProductOptions replaces the option types, such as size or colour, of a product
+func (u *ProductHandler) SetProductOptions(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req struct {
+		Options []*proto.ProductOption `json:"options"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := u.productRepo.SetProductOptions(&proto.SetProductOptionsRequest{
+		ProductId: int32(ID),
+		Options:   req.Options,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	signImages(product.Images, u.store)
+	c.JSON(200, product)
+}
+
+// SetBundleComponents makes a product a bundle of the given variants; an empty list
+// makes it a standard product again
+func (u *ProductHandler) SetBundleComponents(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req struct {
+		Components []*proto.BundleComponent `json:"components"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := u.productRepo.SetBundleComponents(&proto.SetBundleComponentsRequest{
+		ProductId:  int32(ID),
+		Components: req.Components,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	signImages(product.Images, u.store)
+	c.JSON(200, product)
+}
+
+func (u *ProductHandler) CreateVariant(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var variant proto.ProductVariant
+	if err := c.ShouldBindJSON(&variant); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	variant.ProductId = int32(ID)
+
+	created, err := u.productRepo.CreateVariant(&variant)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, created)
+}
+
+func (u *ProductHandler) UpdateVariant(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("variant_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid variant ID"})
+		return
+	}
+
+	var variant proto.ProductVariant
+	if err := c.ShouldBindJSON(&variant); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	variant.Id = int32(ID)
+
+	updated, err := u.productRepo.UpdateVariant(&variant)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, updated)
+}
+
+func (u *ProductHandler) DeleteVariant(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("variant_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid variant ID"})
+		return
+	}
+
+	if err := u.productRepo.DeleteVariant(int32(ID)); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Variant deleted successfully"})
+}
+
+func (u *ProductHandler) UpdateProduct(c *gin.Context) {
+	var product proto.Product
+	if err := c.ShouldBindJSON(&product); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := c.Query("id")
+	ID, err := strconv.Atoi(query)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+	product.Id = uint32(ID)
+
+	response, err := u.productRepo.UpdateProduct(&product)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	item := &types.ProductDTO{
+		Id:            response.Id,
+		Name:          response.Name,
+		Description:   response.Description,
+		Price:         response.Price,
+		ListPrice:     response.ListPrice,
+		RatingAverage: response.RatingAverage,
+		RatingCount:   response.RatingCount,
+		Stock:         response.Stock,
+		CreatedAt:     response.CreatedAt,
+		UpdatedAt:     response.UpdatedAt,
+		ArchivedAt:    response.ArchivedAt,
+	}
+
+	c.JSON(200, item)
+}
+
+// DeleteProduct archives a product: it leaves listings and search but stays
+// readable by ID for the orders that include it
+func (u *ProductHandler) DeleteProduct(c *gin.Context) {
+	query := c.Query("id")
+	ID, err := strconv.Atoi(query)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "InvalidID"})
+		return
+	}
+
+	_, err = u.productRepo.DeleteProduct(&proto.GetProductRequest{Id: int32(ID)})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Product archived successfully"})
+}
+
+func (u *ProductHandler) UnarchiveProduct(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	product, err := u.productRepo.UnarchiveProduct(&proto.GetProductRequest{Id: int32(ID)})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, productDTOs([]*proto.Product{product}, u.store)[0])
+}
+
+// PurgeProduct permanently deletes an archived product that no order refers to,
+// along with its stored images
+func (u *ProductHandler) PurgeProduct(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	// The image records go with the product, so collect the stored files first
+	images, err := u.productRepo.ListProductImages(int32(ID))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := u.productRepo.PurgeProduct(&proto.GetProductRequest{Id: int32(ID)}); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	for _, image := range images.Images {
+		u.deleteBlobs(c, image.StorageKey, image.ThumbnailKey)
+	}
+	c.JSON(200, gin.H{"message": "Product purged successfully"})
+}
+
+func productDTOs(products []*proto.Product, store storage.BlobStore) []*types.ProductDTO {
+	items := make([]*types.ProductDTO, 0, len(products))
+	for _, p := range products {
+		var delivery string
+		if p.Digital != nil {
+			delivery = p.Digital.Delivery
+		}
+		items = append(items, &types.ProductDTO{
+			Id:            p.Id,
+			Slug:          p.Slug,
+			Name:          p.Name,
+			Description:   p.Description,
+			Locale:        p.Locale,
+			Kind:          p.Kind,
+			Delivery:      delivery,
+			Price:         p.Price,
+			ListPrice:     p.ListPrice,
+			RatingAverage: p.RatingAverage,
+			RatingCount:   p.RatingCount,
+			Stock:         p.Stock,
+			CreatedAt:     p.CreatedAt,
+			UpdatedAt:     p.UpdatedAt,
+			ArchivedAt:    p.ArchivedAt,
+			Images:        productImageDTOs(p.Images, store),
+		})
+	}
+	return items
+}
+
+func productListDTO(list *proto.ProductList, store storage.BlobStore) types.ProductListDTO {
+	response := types.ProductListDTO{
+		Page:      list.Page,
+		Total:     list.Total,
+		TotalPage: list.TotalPage,
+		PageSize:  list.PageSize,
+		Products:  productDTOs(list.Products, store),
+	}
+	for _, facet := range list.Facets {
+		response.Facets = append(response.Facets, &types.CategoryFacetDTO{
+			CategoryId: facet.CategoryId,
+			Name:       facet.Name,
+			Slug:       facet.Slug,
+			Count:      facet.Count,
+		})
+	}
+	return response
+}
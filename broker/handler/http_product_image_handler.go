@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"broker/media"
+	"broker/proto"
+	"broker/storage"
+	"broker/types"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ListProductImages returns a product's images in display order
+func (u *ProductHandler) ListProductImages(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	response, err := u.productRepo.ListProductImages(int32(ID))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"images": productImageDTOs(response.Images, u.store)})
+}
+
+// UploadProductImage accepts a multipart "image" file with optional "alt_text" and
+// "position" fields, stores it with a thumbnail and attaches it to the product
+func (u *ProductHandler) UploadProductImage(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	// Leave room for the multipart envelope around the file itself
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, media.MaxImageBytes+1<<20)
+
+	fileHeader, err := c.FormFile("image")
+	if err != nil {
+		c.JSON(400, gin.H{"error": "image file is required"})
+		return
+	}
+	if fileHeader.Size > media.MaxImageBytes {
+		c.JSON(413, gin.H{"error": fmt.Sprintf("image is larger than %d MB", media.MaxImageBytes>>20)})
+		return
+	}
+
+	var position int
+	if v := c.PostForm("position"); v != "" {
+		if position, err = strconv.Atoi(v); err != nil {
+			c.JSON(400, gin.H{"error": "Invalid position"})
+			return
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, media.MaxImageBytes+1))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	img, err := media.Process(data)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	name, err := randomName()
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	key := fmt.Sprintf("products/%d/%s.%s", ID, name, img.Ext)
+	thumbnailKey := fmt.Sprintf("products/%d/%s_thumb.%s", ID, name, img.ThumbnailExt)
+
+	ctx := c.Request.Context()
+	if err := u.store.Put(ctx, key, img.Data, img.ContentType); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err := u.store.Put(ctx, thumbnailKey, img.Thumbnail, img.ThumbnailType); err != nil {
+		u.deleteBlobs(c, key)
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	image, err := u.productRepo.AddProductImage(&proto.ProductImage{
+		ProductId:    int32(ID),
+		StorageKey:   key,
+		ThumbnailKey: thumbnailKey,
+		ContentType:  img.ContentType,
+		SizeBytes:    int64(len(img.Data)),
+		Width:        int32(img.Width),
+		Height:       int32(img.Height),
+		AltText:      c.PostForm("alt_text"),
+		Position:     int32(position),
+	})
+	if err != nil {
+		// The product service never recorded the image, so drop the orphaned files
+		u.deleteBlobs(c, key, thumbnailKey)
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	logrus.Infof("Image %d uploaded for product %d", image.Id, ID)
+	c.JSON(200, productImageDTO(image, u.store))
+}
+
+// ReorderProductImages sets the display order from a list of every image ID of the product
+func (u *ProductHandler) ReorderProductImages(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req struct {
+		ImageIDs []int32 `json:"image_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := u.productRepo.ReorderProductImages(&proto.ReorderProductImagesRequest{
+		ProductId: int32(ID),
+		ImageIds:  req.ImageIDs,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"images": productImageDTOs(response.Images, u.store)})
+}
+
+// UpdateProductImage changes the alt text and position of an image
+func (u *ProductHandler) UpdateProductImage(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("image_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid image ID"})
+		return
+	}
+
+	var req struct {
+		AltText  string `json:"alt_text" binding:"max=255"`
+		Position int32  `json:"position"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	image, err := u.productRepo.UpdateProductImage(&proto.ProductImage{
+		Id:       int32(ID),
+		AltText:  req.AltText,
+		Position: req.Position,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, productImageDTO(image, u.store))
+}
+
+func (u *ProductHandler) DeleteProductImage(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("image_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid image ID"})
+		return
+	}
+
+	image, err := u.productRepo.DeleteProductImage(int32(ID))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	u.deleteBlobs(c, image.StorageKey, image.ThumbnailKey)
+	c.JSON(200, gin.H{"message": "Image deleted successfully"})
+}
+
+// deleteBlobs removes stored files on a best-effort basis; a leftover file is only wasted space
+func (u *ProductHandler) deleteBlobs(c *gin.Context, keys ...string) {
+	for _, key := range keys {
+		if err := u.store.Delete(c.Request.Context(), key); err != nil {
+			logrus.Warnf("Failed to delete blob %s: %v", key, err)
+		}
+	}
+}
+
+func productImageDTOs(images []*proto.ProductImage, store storage.BlobStore) []*types.ProductImageDTO {
+	items := make([]*types.ProductImageDTO, 0, len(images))
+	for _, image := range images {
+		items = append(items, productImageDTO(image, store))
+	}
+	return items
+}
+
+func productImageDTO(image *proto.ProductImage, store storage.BlobStore) *types.ProductImageDTO {
+	signImages([]*proto.ProductImage{image}, store)
+
+	return &types.ProductImageDTO{
+		Id:           image.Id,
+		Url:          image.Url,
+		ThumbnailUrl: image.ThumbnailUrl,
+		AltText:      image.AltText,
+		Position:     image.Position,
+		Width:        image.Width,
+		Height:       image.Height,
+	}
+}
+
+// signImages fills in the download URLs of images from their storage keys. Signed
+// URLs expire, so this runs on every response rather than being cached.
+func signImages(images []*proto.ProductImage, store storage.BlobStore) {
+	for _, image := range images {
+		var err error
+		if image.Url, err = store.URL(image.StorageKey); err != nil {
+			logrus.Warnf("Failed to build URL for image %d: %v", image.Id, err)
+		}
+		if image.ThumbnailUrl, err = store.URL(image.ThumbnailKey); err != nil {
+			logrus.Warnf("Failed to build thumbnail URL for image %d: %v", image.Id, err)
+		}
+	}
+}
+
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
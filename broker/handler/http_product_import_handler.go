@@ -0,0 +1,336 @@
+package handler
+
+import (
+	"broker/middleware"
+	"broker/proto"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// maxImportBytes caps the size of an uploaded import file
+const maxImportBytes = 50 << 20
+
+// exportFlushEvery is how many rows are written between flushes of an export
+const exportFlushEvery = 100
+
+// exportRecord is one row of a JSON lines export
+type exportRecord struct {
+	ProductID   int32             `json:"product_id,omitempty"`
+	SKU         string            `json:"sku"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Price       float64           `json:"price"`
+	Stock       int32             `json:"stock"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+}
+
+var exportColumns = []string{"product_id", "sku", "name", "description", "price", "stock", "attributes"}
+
+// ImportProducts upserts products by SKU from a CSV (default) or JSON lines request
+// body. Rows that fail to parse or validate are listed in the report and the rest
+// are saved; with dry_run=true nothing is saved.
+func (u *ProductHandler) ImportProducts(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+
+	var reader rowReader
+	switch c.DefaultQuery("format", "csv") {
+	case "csv":
+		csvReader, err := newCSVRowReader(c.Request.Body)
+		if err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		reader = csvReader
+	case "jsonl":
+		reader = newJSONRowReader(c.Request.Body)
+	default:
+		c.JSON(400, gin.H{"error": "format must be csv or jsonl"})
+		return
+	}
+
+	// Rows the gateway cannot parse never reach the product service, so their
+	// errors are merged into the report it returns
+	var parseErrors []*proto.ImportRowError
+	next := func() (*proto.ProductRow, error) {
+		for {
+			row, err := reader.Next()
+			var rowErr *importRowError
+			if errors.As(err, &rowErr) {
+				parseErrors = append(parseErrors, &proto.ImportRowError{
+					Line:  int32(rowErr.line),
+					Error: rowErr.err.Error(),
+				})
+				continue
+			}
+			return row, err
+		}
+	}
+
+	report, err := u.productRepo.ImportProducts(&proto.ImportOptions{DryRun: dryRun, Actor: userActor(userID)}, next)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(413, gin.H{"error": fmt.Sprintf("import file is larger than %d MB", maxImportBytes>>20)})
+			return
+		}
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	report.TotalRows += int32(len(parseErrors))
+	report.Failed += int32(len(parseErrors))
+	report.Errors = append(parseErrors, report.Errors...)
+
+	logrus.Infof("Import of %d rows: %d created, %d updated, %d failed (dry run: %v)",
+		report.TotalRows, report.Created, report.Updated, report.Failed, dryRun)
+	c.JSON(200, gin.H{
+		"total_rows": report.TotalRows,
+		"created":    report.Created,
+		"updated":    report.Updated,
+		"failed":     report.Failed,
+		"dry_run":    report.DryRun,
+		"errors":     report.Errors,
+	})
+}
+
+// ExportProducts streams one row per SKU of the catalog as CSV (default) or JSON lines
+func (u *ProductHandler) ExportProducts(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+
+	var write func(row *proto.ProductRow) error
+	var flush func() error
+	switch format {
+	case "csv":
+		writer := csv.NewWriter(c.Writer)
+		write = func(row *proto.ProductRow) error {
+			return writer.Write(csvExportRecord(row))
+		}
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+		c.Header("Content-Type", "text/csv")
+		if err := writer.Write(exportColumns); err != nil {
+			return
+		}
+	case "jsonl":
+		encoder := json.NewEncoder(c.Writer)
+		write = func(row *proto.ProductRow) error {
+			return encoder.Encode(exportRecord{
+				ProductID:   row.ProductId,
+				SKU:         row.Sku,
+				Name:        row.Name,
+				Description: row.Description,
+				Price:       row.Price,
+				Stock:       row.Stock,
+				Attributes:  row.Attributes,
+			})
+		}
+		flush = func() error { return nil }
+		c.Header("Content-Type", "application/x-ndjson")
+	default:
+		c.JSON(400, gin.H{"error": "format must be csv or jsonl"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="products.%s"`, format))
+	c.Status(200)
+
+	var written int
+	err := u.productRepo.ExportProducts(c.Request.Context(), func(row *proto.ProductRow) error {
+		if err := write(row); err != nil {
+			return err
+		}
+		written++
+		if written%exportFlushEvery == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		// The status line has already been sent, so the client only sees a truncated file
+		logrus.Errorf("Product export failed after %d rows: %v", written, err)
+		return
+	}
+	c.Writer.Flush()
+}
+
+func csvExportRecord(row *proto.ProductRow) []string {
+	var attributes string
+	if len(row.Attributes) > 0 {
+		data, _ := json.Marshal(row.Attributes)
+		attributes = string(data)
+	}
+
+	return []string{
+		strconv.Itoa(int(row.ProductId)),
+		row.Sku,
+		row.Name,
+		row.Description,
+		strconv.FormatFloat(row.Price, 'f', -1, 64),
+		strconv.Itoa(int(row.Stock)),
+		attributes,
+	}
+}
+
+// rowReader yields import rows until io.EOF. A row that cannot be parsed is
+// reported as an *importRowError and reading can continue past it.
+type rowReader interface {
+	Next() (*proto.ProductRow, error)
+}
+
+type importRowError struct {
+	line int
+	err  error
+}
+
+func (e *importRowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.line, e.err)
+}
+
+// csvRowReader reads CSV with a header row. Columns are matched by name in any
+// order; sku, name, price and stock are required and unknown columns are ignored,
+// so an export can be edited and imported back.
+type csvRowReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVRowReader(r io.Reader) (*csvRowReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("import file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %v", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range []string{"sku", "name", "price", "stock"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the %s column", name)
+		}
+	}
+
+	return &csvRowReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvRowReader) Next() (*proto.ProductRow, error) {
+	record, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, &importRowError{line: parseErr.StartLine, err: parseErr.Err}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	field := func(name string) string {
+		if i, ok := r.columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	price, err := strconv.ParseFloat(field("price"), 64)
+	if err != nil {
+		return nil, &importRowError{line: line, err: fmt.Errorf("invalid price %q", field("price"))}
+	}
+	stock, err := strconv.Atoi(field("stock"))
+	if err != nil {
+		return nil, &importRowError{line: line, err: fmt.Errorf("invalid stock %q", field("stock"))}
+	}
+
+	return &proto.ProductRow{
+		Line:        int32(line),
+		Sku:         field("sku"),
+		Name:        field("name"),
+		Description: field("description"),
+		Price:       price,
+		Stock:       int32(stock),
+	}, nil
+}
+
+// jsonRowReader reads one JSON object per line, skipping blank lines
+type jsonRowReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newJSONRowReader(r io.Reader) *jsonRowReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	return &jsonRowReader{scanner: scanner}
+}
+
+func (r *jsonRowReader) Next() (*proto.ProductRow, error) {
+	for r.scanner.Scan() {
+		r.line++
+		text := strings.TrimSpace(r.scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var record struct {
+			SKU         string   `json:"sku"`
+			Name        string   `json:"name"`
+			Description string   `json:"description"`
+			Price       *float64 `json:"price"`
+			Stock       *int32   `json:"stock"`
+		}
+		if err := json.Unmarshal([]byte(text), &record); err != nil {
+			return nil, &importRowError{line: r.line, err: fmt.Errorf("invalid JSON: %v", err)}
+		}
+		if record.Price == nil || record.Stock == nil {
+			return nil, &importRowError{line: r.line, err: errors.New("price and stock are required")}
+		}
+
+		return &proto.ProductRow{
+			Line:        int32(r.line),
+			Sku:         record.SKU,
+			Name:        record.Name,
+			Description: record.Description,
+			Price:       *record.Price,
+			Stock:       *record.Stock,
+		}, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"broker/middleware"
+	"broker/proto"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SchedulePrice schedules a price that overrides the list price for a period,
+// e.g. {"price": 79.5, "effective_from": "2026-11-27T00:00:00Z", "effective_to": "2026-11-30T23:59:59Z", "note": "Black Friday"}
+func (u *ProductHandler) SchedulePrice(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req struct {
+		Price         float64 `json:"price" binding:"min=0"`
+		EffectiveFrom string  `json:"effective_from" binding:"required"`
+		EffectiveTo   string  `json:"effective_to"`
+		Note          string  `json:"note" binding:"max=200"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	price, err := u.productRepo.SchedulePrice(&proto.ProductPrice{
+		ProductId:     int32(ID),
+		Price:         req.Price,
+		EffectiveFrom: req.EffectiveFrom,
+		EffectiveTo:   req.EffectiveTo,
+		Note:          req.Note,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, price)
+}
+
+// EndScheduledPrice cancels a scheduled price that has not started or ends an active one now
+func (u *ProductHandler) EndScheduledPrice(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("price_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid price ID"})
+		return
+	}
+
+	price, err := u.productRepo.EndScheduledPrice(int32(ID))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, price)
+}
+
+// GetPriceHistory returns a product's list and scheduled prices, newest first, and
+// with ?at= (RFC 3339) the price it sold for at that time
+func (u *ProductHandler) GetPriceHistory(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	at := c.Query("at")
+	response, err := u.productRepo.GetPriceHistory(&proto.PriceHistoryRequest{
+		ProductId: int32(ID),
+		At:        at,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := gin.H{"prices": response.Prices}
+	if at != "" {
+		result["at"] = at
+		result["price_at"] = response.PriceAt
+	}
+	c.JSON(200, result)
+}
+
+// CreatePriceRule adds a price rule from a minimum quantity, for one customer group or
+// every customer, e.g. {"customer_group_id": 2, "min_quantity": 10, "discount_percent": 15}
+// or {"min_quantity": 50, "price": 42.5}
+func (u *ProductHandler) CreatePriceRule(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req struct {
+		CustomerGroupID int32   `json:"customer_group_id" binding:"min=0"`
+		MinQuantity     int32   `json:"min_quantity" binding:"min=0"`
+		Price           float64 `json:"price" binding:"min=0"`
+		DiscountPercent float64 `json:"discount_percent" binding:"min=0,max=100"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := u.productRepo.CreatePriceRule(&proto.PriceRule{
+		ProductId:       int32(ID),
+		CustomerGroupId: req.CustomerGroupID,
+		MinQuantity:     req.MinQuantity,
+		Price:           req.Price,
+		DiscountPercent: req.DiscountPercent,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, rule)
+}
+
+func (u *ProductHandler) DeletePriceRule(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("rule_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid price rule ID"})
+		return
+	}
+
+	if err := u.productRepo.DeletePriceRule(int32(ID)); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Price rule deleted"})
+}
+
+// ListPriceRules returns a product's price rules by minimum quantity
+func (u *ProductHandler) ListPriceRules(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	response, err := u.productRepo.ListPriceRules(int32(ID))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"rules": response.Rules})
+}
+
+// QuotePrices prices cart lines for the caller the way their order would be priced,
+// e.g. {"lines": [{"product_id": 3, "variant_id": 7, "quantity": 12}]}
+func (u *ProductHandler) QuotePrices(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	var req struct {
+		Lines []struct {
+			ProductID int32 `json:"product_id" binding:"required"`
+			VariantID int32 `json:"variant_id"`
+			Quantity  int32 `json:"quantity" binding:"required,min=1"`
+		} `json:"lines" binding:"required,min=1,dive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	quoteReq := &proto.QuotePricesRequest{UserId: int32(userID)}
+	for _, line := range req.Lines {
+		quoteReq.Lines = append(quoteReq.Lines, &proto.QuoteLine{
+			ProductId: line.ProductID,
+			VariantId: line.VariantID,
+			Quantity:  line.Quantity,
+		})
+	}
+
+	quote, err := u.productRepo.QuotePrices(quoteReq)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	var total float64
+	for _, line := range quote.Lines {
+		total += float64(line.Quantity) * line.UnitPrice
+	}
+	c.JSON(200, gin.H{"lines": quote.Lines, "total": total})
+}
@@ -0,0 +1,321 @@
+package handler
+
+import (
+	"broker/media"
+	"broker/middleware"
+	"broker/proto"
+	"broker/storage"
+	"broker/types"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// maxReviewImages matches the limit of the product service, checked here before
+// anything is stored
+const maxReviewImages = 3
+
+// ListReviews returns a page of a product's approved reviews with its rating
+func (u *ProductHandler) ListReviews(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	page := 1
+	if v := c.Query("page"); v != "" {
+		if page, err = strconv.Atoi(v); err != nil {
+			c.JSON(400, gin.H{"error": "Invalid page"})
+			return
+		}
+	}
+
+	response, err := u.productRepo.ListReviews(&proto.ListReviewsRequest{
+		ProductId: int32(ID),
+		Page:      int32(page),
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	reviews := reviewDTOs(response.Reviews, u.store)
+	// Published reviews are all approved; moderation details stay internal
+	for _, review := range reviews {
+		review.Status = ""
+		review.ModerationNote = ""
+	}
+
+	c.JSON(200, gin.H{
+		"rating_average": response.RatingAverage,
+		"rating_count":   response.RatingCount,
+		"page":           response.Page,
+		"total":          response.Total,
+		"total_page":     response.TotalPage,
+		"reviews":        reviews,
+	})
+}
+
+// ListReviewsForModeration returns a page of reviews in one state, pending by default,
+// across every product or for ?id=
+func (u *ProductHandler) ListReviewsForModeration(c *gin.Context) {
+	var (
+		ID  int
+		err error
+	)
+	if v := c.Query("id"); v != "" {
+		if ID, err = strconv.Atoi(v); err != nil {
+			c.JSON(400, gin.H{"error": "Invalid ID"})
+			return
+		}
+	}
+
+	page := 1
+	if v := c.Query("page"); v != "" {
+		if page, err = strconv.Atoi(v); err != nil {
+			c.JSON(400, gin.H{"error": "Invalid page"})
+			return
+		}
+	}
+
+	response, err := u.productRepo.ListReviews(&proto.ListReviewsRequest{
+		ProductId: int32(ID),
+		Status:    c.DefaultQuery("status", "pending"),
+		Page:      int32(page),
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"page":       response.Page,
+		"total":      response.Total,
+		"total_page": response.TotalPage,
+		"reviews":    reviewDTOs(response.Reviews, u.store),
+	})
+}
+
+// CreateReview accepts a multipart form with "rating", "body" and up to three "images".
+// The product service only accepts reviews from buyers of the product; stored images
+// are removed again when it refuses.
+func (u *ProductHandler) CreateReview(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	// Leave room for the multipart envelope and text fields around the files
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxReviewImages*media.MaxImageBytes+1<<20)
+
+	rating, err := strconv.Atoi(c.PostForm("rating"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "rating is required"})
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil && err != http.ErrNotMultipart {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	var files []*multipart.FileHeader
+	if form != nil {
+		files = form.File["images"]
+	}
+	if len(files) > maxReviewImages {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("a review can have at most %d images", maxReviewImages)})
+		return
+	}
+
+	review := &proto.ProductReview{
+		ProductId: int32(ID),
+		UserId:    int32(userID),
+		Rating:    int32(rating),
+		Body:      c.PostForm("body"),
+	}
+
+	var stored []string
+	for _, file := range files {
+		image, keys, status, err := u.storeReviewImage(c, ID, file)
+		stored = append(stored, keys...)
+		if err != nil {
+			u.deleteBlobs(c, stored...)
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		review.Images = append(review.Images, image)
+	}
+
+	created, err := u.productRepo.CreateReview(review)
+	if err != nil {
+		// The product service never recorded the review, so drop the orphaned files
+		u.deleteBlobs(c, stored...)
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	logrus.Infof("Review %d submitted for product %d", created.Id, ID)
+	c.JSON(200, reviewDTO(created, u.store))
+}
+
+// DeleteReview removes the caller's own review and its stored images
+func (u *ProductHandler) DeleteReview(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	ID, err := strconv.Atoi(c.Param("review_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	review, err := u.productRepo.DeleteReview(&proto.DeleteReviewRequest{
+		Id:     int32(ID),
+		UserId: int32(userID),
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	for _, image := range review.Images {
+		u.deleteBlobs(c, image.StorageKey, image.ThumbnailKey)
+	}
+	c.JSON(200, gin.H{"message": "Review deleted successfully"})
+}
+
+// ModerateReview approves or rejects a review, or sends it back to pending
+func (u *ProductHandler) ModerateReview(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("review_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	var req struct {
+		Status string `json:"status" binding:"required,oneof=pending approved rejected"`
+		Note   string `json:"note" binding:"max=255"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := u.productRepo.ModerateReview(&proto.ModerateReviewRequest{
+		Id:     int32(ID),
+		Status: req.Status,
+		Note:   req.Note,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, reviewDTO(review, u.store))
+}
+
+// storeReviewImage validates one uploaded image and stores it with a thumbnail. It
+// returns the keys it stored, also on failure, and the status to answer with.
+func (u *ProductHandler) storeReviewImage(c *gin.Context, productID int, file *multipart.FileHeader) (*proto.ReviewImage, []string, int, error) {
+	if file.Size > media.MaxImageBytes {
+		return nil, nil, 413, fmt.Errorf("image %s is larger than %d MB", file.Filename, media.MaxImageBytes>>20)
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, nil, 400, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, media.MaxImageBytes+1))
+	if err != nil {
+		return nil, nil, 400, err
+	}
+
+	img, err := media.Process(data)
+	if err != nil {
+		return nil, nil, 400, err
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return nil, nil, 500, err
+	}
+	key := fmt.Sprintf("reviews/%d/%s.%s", productID, name, img.Ext)
+	thumbnailKey := fmt.Sprintf("reviews/%d/%s_thumb.%s", productID, name, img.ThumbnailExt)
+
+	ctx := c.Request.Context()
+	if err := u.store.Put(ctx, key, img.Data, img.ContentType); err != nil {
+		return nil, nil, 500, err
+	}
+	if err := u.store.Put(ctx, thumbnailKey, img.Thumbnail, img.ThumbnailType); err != nil {
+		return nil, []string{key}, 500, err
+	}
+
+	return &proto.ReviewImage{
+		StorageKey:   key,
+		ThumbnailKey: thumbnailKey,
+		ContentType:  img.ContentType,
+		SizeBytes:    int64(len(img.Data)),
+		Width:        int32(img.Width),
+		Height:       int32(img.Height),
+	}, []string{key, thumbnailKey}, 200, nil
+}
+
+func reviewDTOs(reviews []*proto.ProductReview, store storage.BlobStore) []*types.ReviewDTO {
+	items := make([]*types.ReviewDTO, 0, len(reviews))
+	for _, review := range reviews {
+		items = append(items, reviewDTO(review, store))
+	}
+	return items
+}
+
+func reviewDTO(review *proto.ProductReview, store storage.BlobStore) *types.ReviewDTO {
+	images := make([]*types.ReviewImageDTO, 0, len(review.Images))
+	for _, image := range review.Images {
+		dto := &types.ReviewImageDTO{
+			Id:     image.Id,
+			Width:  image.Width,
+			Height: image.Height,
+		}
+
+		var err error
+		if dto.Url, err = store.URL(image.StorageKey); err != nil {
+			logrus.Warnf("Failed to build URL for review image %d: %v", image.Id, err)
+		}
+		if dto.ThumbnailUrl, err = store.URL(image.ThumbnailKey); err != nil {
+			logrus.Warnf("Failed to build thumbnail URL for review image %d: %v", image.Id, err)
+		}
+		images = append(images, dto)
+	}
+
+	return &types.ReviewDTO{
+		Id:             review.Id,
+		ProductId:      review.ProductId,
+		UserId:         review.UserId,
+		Rating:         review.Rating,
+		Body:           review.Body,
+		Status:         review.Status,
+		ModerationNote: review.ModerationNote,
+		Images:         images,
+		CreatedAt:      review.CreatedAt,
+	}
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"broker/proto"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetProductTranslation creates or replaces the name and description of ?id= in a
+// locale, e.g. PUT /product/translations/de?id=12 {"name": "Hemd", "description": "Aus Leinen"}
+func (u *ProductHandler) SetProductTranslation(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req struct {
+		Name        string `json:"name" binding:"required,max=100"`
+		Description string `json:"description"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	translation, err := u.productRepo.SetProductTranslation(&proto.ProductTranslation{
+		ProductId:   int32(ID),
+		Locale:      c.Param("locale"),
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, translation)
+}
+
+func (u *ProductHandler) DeleteProductTranslation(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := u.productRepo.DeleteProductTranslation(&proto.GetProductTranslationRequest{
+		ProductId: int32(ID),
+		Locale:    c.Param("locale"),
+	}); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Translation deleted successfully"})
+}
+
+func (u *ProductHandler) ListProductTranslations(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	response, err := u.productRepo.ListProductTranslations(int32(ID))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"translations": response.Translations})
+}
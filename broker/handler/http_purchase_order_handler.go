@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"broker/middleware"
+	"broker/proto"
+	"broker/repository"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PurchaseOrderHandler struct {
+	productRepo repository.ProductRepository
+}
+
+func NewPurchaseOrderHandler(repo repository.ProductRepository) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{
+		productRepo: repo,
+	}
+}
+
+func (h *PurchaseOrderHandler) RegisterRoutes(r *gin.Engine) {
+	supplierRoutes := r.Group("/supplier")
+	supplierRoutes.Use(middleware.ProtectedEndpoint())
+	supplierRoutes.Use(middleware.RateLimiterMiddleware(middleware.DefaultRateLimiterConfig()))
+
+	supplierRoutes.GET("", h.ListSuppliers)
+	supplierRoutes.POST("", h.CreateSupplier)
+
+	orderRoutes := r.Group("/purchase-order")
+	orderRoutes.Use(middleware.ProtectedEndpoint())
+	orderRoutes.Use(middleware.RateLimiterMiddleware(middleware.DefaultRateLimiterConfig()))
+
+	orderRoutes.GET("", h.ListPurchaseOrders)
+	orderRoutes.POST("", h.CreatePurchaseOrder)
+	orderRoutes.GET("/:id", h.GetPurchaseOrder)
+	orderRoutes.POST("/:id/send", h.SendPurchaseOrder)
+	orderRoutes.POST("/:id/receive", h.ReceivePurchaseOrder)
+	orderRoutes.POST("/:id/cancel", h.CancelPurchaseOrder)
+}
+
+func (h *PurchaseOrderHandler) ListSuppliers(c *gin.Context) {
+	response, err := h.productRepo.ListSuppliers()
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"suppliers": response.Suppliers})
+}
+
+// CreateSupplier adds a supplier, e.g. {"name": "Acme Textiles", "email": "orders@acme.test", "lead_time_days": 14}
+func (h *PurchaseOrderHandler) CreateSupplier(c *gin.Context) {
+	var req struct {
+		Name         string `json:"name" binding:"required,max=100"`
+		Email        string `json:"email" binding:"omitempty,email,max=255"`
+		Phone        string `json:"phone" binding:"max=30"`
+		LeadTimeDays int32  `json:"lead_time_days" binding:"min=0"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	supplier, err := h.productRepo.CreateSupplier(&proto.Supplier{
+		Name:         req.Name,
+		Email:        req.Email,
+		Phone:        req.Phone,
+		LeadTimeDays: req.LeadTimeDays,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, supplier)
+}
+
+// ListPurchaseOrders returns a page of purchase orders, e.g. /purchase-order?status=sent&supplier_id=2&page=1
+func (h *PurchaseOrderHandler) ListPurchaseOrders(c *gin.Context) {
+	req := &proto.ListPurchaseOrdersRequest{Status: c.Query("status")}
+	if v := c.Query("supplier_id"); v != "" {
+		ID, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "Invalid supplier_id"})
+			return
+		}
+		req.SupplierId = int32(ID)
+	}
+	if v := c.Query("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "Invalid page"})
+			return
+		}
+		req.Page = int32(page)
+	}
+
+	response, err := h.productRepo.ListPurchaseOrders(req)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"page":            response.Page,
+		"total":           response.Total,
+		"total_page":      response.TotalPage,
+		"purchase_orders": response.PurchaseOrders,
+	})
+}
+
+// CreatePurchaseOrder drafts a purchase order,
+// e.g. {"supplier_id": 2, "warehouse_id": 1, "expected_at": "2026-11-02T00:00:00Z", "freight_cost": 120,
+// "lines": [{"variant_id": 7, "quantity": 100, "unit_cost": 4.2}]}
+func (h *PurchaseOrderHandler) CreatePurchaseOrder(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	var req struct {
+		SupplierID  int32   `json:"supplier_id" binding:"required"`
+		WarehouseID int32   `json:"warehouse_id"`
+		ExpectedAt  string  `json:"expected_at"`
+		FreightCost float64 `json:"freight_cost" binding:"min=0"`
+		DutyCost    float64 `json:"duty_cost" binding:"min=0"`
+		Note        string  `json:"note"`
+		Lines       []struct {
+			VariantID int32   `json:"variant_id" binding:"required"`
+			Quantity  int32   `json:"quantity" binding:"required,min=1"`
+			UnitCost  float64 `json:"unit_cost" binding:"min=0"`
+		} `json:"lines" binding:"required,min=1,dive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	order := &proto.PurchaseOrder{
+		SupplierId:  req.SupplierID,
+		WarehouseId: req.WarehouseID,
+		ExpectedAt:  req.ExpectedAt,
+		FreightCost: req.FreightCost,
+		DutyCost:    req.DutyCost,
+		Note:        req.Note,
+		CreatedBy:   userActor(userID),
+	}
+	for _, line := range req.Lines {
+		order.Lines = append(order.Lines, &proto.PurchaseOrderLine{
+			VariantId:       line.VariantID,
+			QuantityOrdered: line.Quantity,
+			UnitCost:        line.UnitCost,
+		})
+	}
+
+	order, err := h.productRepo.CreatePurchaseOrder(order)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, order)
+}
+
+func (h *PurchaseOrderHandler) GetPurchaseOrder(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid purchase order ID"})
+		return
+	}
+
+	order, err := h.productRepo.GetPurchaseOrder(int32(ID))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, order)
+}
+
+// SendPurchaseOrder marks a draft sent to its supplier
+func (h *PurchaseOrderHandler) SendPurchaseOrder(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid purchase order ID"})
+		return
+	}
+
+	order, err := h.productRepo.SendPurchaseOrder(int32(ID))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, order)
+}
+
+// ReceivePurchaseOrder restocks what arrived, e.g. {"lines": [{"line_id": 3, "quantity": 40}]};
+// without lines everything outstanding is received
+func (h *PurchaseOrderHandler) ReceivePurchaseOrder(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid purchase order ID"})
+		return
+	}
+
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	var req struct {
+		Lines []struct {
+			LineID   int32 `json:"line_id" binding:"required"`
+			Quantity int32 `json:"quantity" binding:"required,min=1"`
+		} `json:"lines" binding:"dive"`
+		Note string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	receipt := &proto.ReceivePurchaseOrderRequest{
+		Id:    int32(ID),
+		Actor: userActor(userID),
+		Note:  req.Note,
+	}
+	for _, line := range req.Lines {
+		receipt.Lines = append(receipt.Lines, &proto.ReceiveLine{LineId: line.LineID, Quantity: line.Quantity})
+	}
+
+	order, err := h.productRepo.ReceivePurchaseOrder(receipt)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, order)
+}
+
+// CancelPurchaseOrder cancels a purchase order nothing was received of
+func (h *PurchaseOrderHandler) CancelPurchaseOrder(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid purchase order ID"})
+		return
+	}
+
+	order, err := h.productRepo.CancelPurchaseOrder(int32(ID))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, order)
+}
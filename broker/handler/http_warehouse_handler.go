@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"broker/middleware"
+	"broker/proto"
+	"broker/repository"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WarehouseHandler struct {
+	productRepo repository.ProductRepository
+}
+
+func NewWarehouseHandler(repo repository.ProductRepository) *WarehouseHandler {
+	return &WarehouseHandler{
+		productRepo: repo,
+	}
+}
+
+func (h *WarehouseHandler) RegisterRoutes(r *gin.Engine) {
+	warehouseRoutes := r.Group("/warehouse")
+	warehouseRoutes.Use(middleware.ProtectedEndpoint())
+	warehouseRoutes.Use(middleware.RateLimiterMiddleware(middleware.DefaultRateLimiterConfig()))
+
+	warehouseRoutes.GET("", h.ListWarehouses)
+	warehouseRoutes.POST("", h.CreateWarehouse)
+	warehouseRoutes.PUT("/:id", h.UpdateWarehouse)
+}
+
+type warehouseRequest struct {
+	Code      string  `json:"code" binding:"required,max=20"`
+	Name      string  `json:"name" binding:"required,max=100"`
+	Latitude  float64 `json:"latitude" binding:"min=-90,max=90"`
+	Longitude float64 `json:"longitude" binding:"min=-180,max=180"`
+	Priority  int32   `json:"priority"`
+	Active    *bool   `json:"active"`
+}
+
+// ListWarehouses returns every warehouse in allocation order
+func (h *WarehouseHandler) ListWarehouses(c *gin.Context) {
+	response, err := h.productRepo.ListWarehouses()
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, response)
+}
+
+// CreateWarehouse adds a warehouse, active unless "active": false is sent
+func (h *WarehouseHandler) CreateWarehouse(c *gin.Context) {
+	var req warehouseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	warehouse, err := h.productRepo.CreateWarehouse(req.toProto(0))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, warehouse)
+}
+
+// UpdateWarehouse replaces a warehouse's details; without "active" it stays active
+func (h *WarehouseHandler) UpdateWarehouse(c *gin.Context) {
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid warehouse ID"})
+		return
+	}
+
+	var req warehouseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	warehouse, err := h.productRepo.UpdateWarehouse(req.toProto(int32(ID)))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, warehouse)
+}
+
+func (r warehouseRequest) toProto(ID int32) *proto.Warehouse {
+	active := true
+	if r.Active != nil {
+		active = *r.Active
+	}
+
+	return &proto.Warehouse{
+		Id:        ID,
+		Code:      r.Code,
+		Name:      r.Name,
+		Latitude:  r.Latitude,
+		Longitude: r.Longitude,
+		Priority:  r.Priority,
+		Active:    active,
+	}
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"broker/middleware"
+	"broker/proto"
+	"broker/repository"
+	"broker/storage"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WishlistHandler struct {
+	productRepo repository.ProductRepository
+	store       storage.BlobStore
+}
+
+func NewWishlistHandler(repo repository.ProductRepository, store storage.BlobStore) *WishlistHandler {
+	return &WishlistHandler{
+		productRepo: repo,
+		store:       store,
+	}
+}
+
+func (h *WishlistHandler) RegisterRoutes(r *gin.Engine) {
+	wishlistRoutes := r.Group("/wishlist")
+	wishlistRoutes.Use(middleware.ProtectedEndpoint())
+	wishlistRoutes.Use(middleware.RateLimiterMiddleware(middleware.DefaultRateLimiterConfig()))
+
+	wishlistRoutes.GET("", h.GetWishlist)
+	wishlistRoutes.POST("", h.AddToWishlist)
+	wishlistRoutes.DELETE("/:product_id", h.RemoveFromWishlist)
+	wishlistRoutes.GET("/subscriptions", h.ListStockSubscriptions)
+	wishlistRoutes.POST("/subscriptions", h.SubscribeBackInStock)
+	wishlistRoutes.DELETE("/subscriptions/:product_id", h.UnsubscribeBackInStock)
+}
+
+type wishlistRequest struct {
+	ProductID int32 `json:"product_id" binding:"required"`
+}
+
+// GetWishlist returns the caller's saved products, most recently added first
+func (h *WishlistHandler) GetWishlist(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	response, err := h.productRepo.GetWishlist(int32(userID))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	items := make([]gin.H, 0, len(response.Items))
+	for _, item := range response.Items {
+		items = append(items, gin.H{
+			"product":    productDTOs([]*proto.Product{item.Product}, h.store)[0],
+			"added_at":   item.AddedAt,
+			"subscribed": item.Subscribed,
+		})
+	}
+
+	c.JSON(200, gin.H{"items": items})
+}
+
+func (h *WishlistHandler) AddToWishlist(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	var req wishlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.productRepo.AddToWishlist(&proto.WishlistRequest{
+		UserId:    int32(userID),
+		ProductId: req.ProductID,
+	}); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Product added to wishlist"})
+}
+
+func (h *WishlistHandler) RemoveFromWishlist(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	productID, err := strconv.Atoi(c.Param("product_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	if err := h.productRepo.RemoveFromWishlist(&proto.WishlistRequest{
+		UserId:    int32(userID),
+		ProductId: int32(productID),
+	}); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Product removed from wishlist"})
+}
+
+// ListStockSubscriptions returns the products the caller is waiting for
+func (h *WishlistHandler) ListStockSubscriptions(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	response, err := h.productRepo.ListStockSubscriptions(int32(userID))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"subscriptions": response.Subscriptions})
+}
+
+// SubscribeBackInStock asks to be notified each time an out-of-stock product comes back
+func (h *WishlistHandler) SubscribeBackInStock(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	var req wishlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	subscription, err := h.productRepo.SubscribeBackInStock(&proto.WishlistRequest{
+		UserId:    int32(userID),
+		ProductId: req.ProductID,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, subscription)
+}
+
+func (h *WishlistHandler) UnsubscribeBackInStock(c *gin.Context) {
+	userID, ok := c.Request.Context().Value(middleware.UserKey).(int)
+	if !ok {
+		c.JSON(401, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	productID, err := strconv.Atoi(c.Param("product_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	if err := h.productRepo.UnsubscribeBackInStock(&proto.WishlistRequest{
+		UserId:    int32(userID),
+		ProductId: int32(productID),
+	}); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Unsubscribed from back-in-stock notifications"})
+}
@@ -0,0 +1,145 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // Registers the GIF decoder with image.Decode
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	// MaxImageBytes is the largest image accepted for upload
+	MaxImageBytes = 5 << 20
+	// MaxImagePixels guards against small files that decode to huge bitmaps
+	MaxImagePixels = 40_000_000
+	// ThumbnailSize is the longest side of generated thumbnails
+	ThumbnailSize = 320
+)
+
+var ErrUnsupportedImage = errors.New("image must be a JPEG, PNG or GIF")
+
+// Image is a validated upload with its generated thumbnail
+type Image struct {
+	Data          []byte
+	ContentType   string
+	Ext           string
+	Width         int
+	Height        int
+	Thumbnail     []byte
+	ThumbnailType string
+	ThumbnailExt  string
+}
+
+// Process checks an uploaded image by its content rather than its file name or
+// declared type, and renders a thumbnail of it
+func Process(data []byte) (*Image, error) {
+	if len(data) == 0 {
+		return nil, errors.New("image is empty")
+	}
+	if len(data) > MaxImageBytes {
+		return nil, fmt.Errorf("image is larger than %d MB", MaxImageBytes>>20)
+	}
+
+	img := &Image{Data: data, ContentType: http.DetectContentType(data)}
+	switch img.ContentType {
+	case "image/jpeg":
+		img.Ext = "jpg"
+	case "image/png":
+		img.Ext = "png"
+	case "image/gif":
+		img.Ext = "gif"
+	default:
+		return nil, ErrUnsupportedImage
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %v", err)
+	}
+	if config.Width*config.Height > MaxImagePixels {
+		return nil, fmt.Errorf("image is larger than %d megapixels", MaxImagePixels/1_000_000)
+	}
+	img.Width, img.Height = config.Width, config.Height
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %v", err)
+	}
+
+	thumbnail := resize(decoded, ThumbnailSize)
+
+	// JPEG has no transparency, so images that may have it keep a PNG thumbnail
+	var buf bytes.Buffer
+	if img.ContentType == "image/jpeg" {
+		err = jpeg.Encode(&buf, thumbnail, &jpeg.Options{Quality: 80})
+		img.ThumbnailType, img.ThumbnailExt = "image/jpeg", "jpg"
+	} else {
+		err = png.Encode(&buf, thumbnail)
+		img.ThumbnailType, img.ThumbnailExt = "image/png", "png"
+	}
+	if err != nil {
+		return nil, err
+	}
+	img.Thumbnail = buf.Bytes()
+
+	return img, nil
+}
+
+// resize scales an image down so its longest side is at most size, averaging the
+// source pixels under each target pixel. Smaller images are copied as they are.
+func resize(src image.Image, size int) *image.NRGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	rgba := image.NewNRGBA(image.Rect(0, 0, srcW, srcH))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+
+	if srcW <= size && srcH <= size {
+		return rgba
+	}
+
+	dstW, dstH := size, srcH*size/srcW
+	if srcH > srcW {
+		dstW, dstH = srcW*size/srcH, size
+	}
+	if dstW < 1 {
+		dstW = 1
+	}
+	if dstH < 1 {
+		dstH = 1
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0, y1 := y*srcH/dstH, (y+1)*srcH/dstH
+		for x := 0; x < dstW; x++ {
+			x0, x1 := x*srcW/dstW, (x+1)*srcW/dstW
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += int(p[0])
+					g += int(p[1])
+					b += int(p[2])
+					a += int(p[3])
+					n++
+				}
+			}
+
+			i := y*dst.Stride + x*4
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
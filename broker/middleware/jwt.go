@@ -1,72 +1,111 @@
-package middleware
-
-import (
-	"context"
-	"fmt"
-	"net/http"
-	"os"
-	"strconv"
-	"strings"
-	"time"
-
-	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
-)
-
-type contextKey string
-
-var UserKey contextKey = "userID"
-
-func ProtectedEndpoint() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		header := c.GetHeader("Authorization")
-
-		parts := strings.Split(header, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token missing"})
-			c.Abort()
-			return
-		}
-
-		tokenString := parts[1]
-		token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
-			}
-			return []byte(os.Getenv("JWT_SECRET")), nil
-		})
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
-			return
-		}
-
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid claims"})
-			c.Abort()
-			return
-		}
-
-		expiredAt := int64(claims["exp"].(float64))
-		if time.Now().Unix() > expiredAt {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token expired"})
-			c.Abort()
-			return
-		}
-
-		userIDClaims := claims["userID"].(string)
-		userID, err := strconv.Atoi(userIDClaims)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Can't convert userID to int"})
-			c.Abort()
-			return
-		}
-
-		ctx := c.Request.Context()
-		ctx = context.WithValue(ctx, UserKey, userID)
-		c.Request = c.Request.WithContext(ctx)
-
-		c.Next()
-	}
-}
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type contextKey string
+
+var UserKey contextKey = "userID"
+
+// LivemodeKey holds false for test-mode credentials, which only see sandbox data
+var LivemodeKey contextKey = "livemode"
+
+// RoleKey holds the role claim of the token: user, or admin or moderator for staff
+var RoleKey contextKey = "role"
+
+// IsLivemode reports whether the request was made with live credentials
+func IsLivemode(ctx context.Context) bool {
+	livemode, ok := ctx.Value(LivemodeKey).(bool)
+	return !ok || livemode
+}
+
+func ProtectedEndpoint() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+
+		parts := strings.Split(header, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token missing"})
+			c.Abort()
+			return
+		}
+
+		tokenString := parts[1]
+		token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
+			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+			}
+			return []byte(os.Getenv("JWT_SECRET")), nil
+		})
+		if err != nil || !token.Valid {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.Abort()
+			return
+		}
+
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid claims"})
+			c.Abort()
+			return
+		}
+
+		expiredAt := int64(claims["exp"].(float64))
+		if time.Now().Unix() > expiredAt {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token expired"})
+			c.Abort()
+			return
+		}
+
+		userIDClaims := claims["userID"].(string)
+		userID, err := strconv.Atoi(userIDClaims)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Can't convert userID to int"})
+			c.Abort()
+			return
+		}
+
+		// Tokens issued before test mode existed carry no claim and are live
+		livemode, ok := claims["livemode"].(bool)
+		if !ok {
+			livemode = true
+		}
+
+		role, _ := claims["role"].(string)
+
+		ctx := c.Request.Context()
+		ctx = context.WithValue(ctx, UserKey, userID)
+		ctx = context.WithValue(ctx, LivemodeKey, livemode)
+		ctx = context.WithValue(ctx, RoleKey, role)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// RequireRole lets through only tokens carrying one of roles; it runs after
+// ProtectedEndpoint, which reads the role claim
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := c.Request.Context().Value(RoleKey).(string)
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+		c.Abort()
+	}
+}
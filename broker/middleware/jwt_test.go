@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	token := func(claims jwt.MapClaims) string {
+		claims["userID"] = "7"
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		roles  []string
+		want   int
+	}{
+		{"admin", jwt.MapClaims{"role": "admin"}, []string{"admin"}, http.StatusOK},
+		{"customer", jwt.MapClaims{"role": "user"}, []string{"admin"}, http.StatusForbidden},
+		{"token without a role", jwt.MapClaims{}, []string{"admin"}, http.StatusForbidden},
+		{"moderator where moderators are allowed", jwt.MapClaims{"role": "moderator"}, []string{"admin", "moderator"}, http.StatusOK},
+		{"moderator where only admins are allowed", jwt.MapClaims{"role": "moderator"}, []string{"admin"}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/", ProtectedEndpoint(), RequireRole(tt.roles...), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+token(tt.claims))
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -30,6 +30,8 @@ type Order struct {
 	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     string                 `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	OrderItems    []*OrderItem           `protobuf:"bytes,7,rep,name=order_items,json=orderItems,proto3" json:"order_items,omitempty"`
+	Livemode      bool                   `protobuf:"varint,8,opt,name=livemode,proto3" json:"livemode,omitempty"`    // False for orders placed with test-mode credentials
+	Deliveries    []*OrderDelivery       `protobuf:"bytes,9,rep,name=deliveries,proto3" json:"deliveries,omitempty"` // Digital items delivered once the order is paid
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -113,32 +115,833 @@ func (x *Order) GetOrderItems() []*OrderItem {
 	return nil
 }
 
+func (x *Order) GetLivemode() bool {
+	if x != nil {
+		return x.Livemode
+	}
+	return false
+}
+
+func (x *Order) GetDeliveries() []*OrderDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
 type OrderItem struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId          int32                  `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	ProductId        int32                  `protobuf:"varint,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity         int32                  `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price            float64                `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	ProductName      string                 `protobuf:"bytes,6,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	VariantId        int32                  `protobuf:"varint,7,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Sku              string                 `protobuf:"bytes,8,opt,name=sku,proto3" json:"sku,omitempty"`
+	WarehouseId      int32                  `protobuf:"varint,9,opt,name=warehouse_id,json=warehouseId,proto3" json:"warehouse_id,omitempty"` // Warehouse the line ships from
+	WarehouseCode    string                 `protobuf:"bytes,10,opt,name=warehouse_code,json=warehouseCode,proto3" json:"warehouse_code,omitempty"`
+	ParentItemId     int32                  `protobuf:"varint,11,opt,name=parent_item_id,json=parentItemId,proto3" json:"parent_item_id,omitempty"` // Set on the components of a bundle item
+	ReturnedQuantity int32                  `protobuf:"varint,12,opt,name=returned_quantity,json=returnedQuantity,proto3" json:"returned_quantity,omitempty"`
+	Components       []*OrderItem           `protobuf:"bytes,13,rep,name=components,proto3" json:"components,omitempty"`                         // A bundle item's components, each with its share of the bundle price
+	PriceRuleId      int32                  `protobuf:"varint,14,opt,name=price_rule_id,json=priceRuleId,proto3" json:"price_rule_id,omitempty"` // Price rule that priced the line, 0 for the base price
+	Digital          bool                   `protobuf:"varint,15,opt,name=digital,proto3" json:"digital,omitempty"`                              // Delivered as a download or licence keys instead of shipped
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *OrderItem) Reset() {
+	*x = OrderItem{}
+	mi := &file_proto_order_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderItem) ProtoMessage() {}
+
+func (x *OrderItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderItem.ProtoReflect.Descriptor instead.
+func (*OrderItem) Descriptor() ([]byte, []int) {
+	return file_proto_order_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OrderItem) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *OrderItem) GetOrderId() int32 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *OrderItem) GetProductId() int32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *OrderItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderItem) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *OrderItem) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *OrderItem) GetVariantId() int32 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *OrderItem) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *OrderItem) GetWarehouseId() int32 {
+	if x != nil {
+		return x.WarehouseId
+	}
+	return 0
+}
+
+func (x *OrderItem) GetWarehouseCode() string {
+	if x != nil {
+		return x.WarehouseCode
+	}
+	return ""
+}
+
+func (x *OrderItem) GetParentItemId() int32 {
+	if x != nil {
+		return x.ParentItemId
+	}
+	return 0
+}
+
+func (x *OrderItem) GetReturnedQuantity() int32 {
+	if x != nil {
+		return x.ReturnedQuantity
+	}
+	return 0
+}
+
+func (x *OrderItem) GetComponents() []*OrderItem {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
+func (x *OrderItem) GetPriceRuleId() int32 {
+	if x != nil {
+		return x.PriceRuleId
+	}
+	return 0
+}
+
+func (x *OrderItem) GetDigital() bool {
+	if x != nil {
+		return x.Digital
+	}
+	return false
+}
+
+// OrderDelivery is what a digital item of a paid order delivered
+type OrderDelivery struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	OrderId       int32                  `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	ProductId     int32                  `protobuf:"varint,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Quantity      int32                  `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	Price         float64                `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	OrderItemId   int32                  `protobuf:"varint,3,opt,name=order_item_id,json=orderItemId,proto3" json:"order_item_id,omitempty"`
+	ProductId     int32                  `protobuf:"varint,4,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Delivery      string                 `protobuf:"bytes,5,opt,name=delivery,proto3" json:"delivery,omitempty"` // download or license_key
+	FileName      string                 `protobuf:"bytes,6,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	DownloadUrl   string                 `protobuf:"bytes,7,opt,name=download_url,json=downloadUrl,proto3" json:"download_url,omitempty"` // Signed gateway link, valid until expires_at
+	DownloadLimit int32                  `protobuf:"varint,8,opt,name=download_limit,json=downloadLimit,proto3" json:"download_limit,omitempty"`
+	DownloadCount int32                  `protobuf:"varint,9,opt,name=download_count,json=downloadCount,proto3" json:"download_count,omitempty"`
+	ExpiresAt     string                 `protobuf:"bytes,10,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Expired       bool                   `protobuf:"varint,11,opt,name=expired,proto3" json:"expired,omitempty"`
+	LicenseKeys   []string               `protobuf:"bytes,12,rep,name=license_keys,json=licenseKeys,proto3" json:"license_keys,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	FileKey       string                 `protobuf:"bytes,14,opt,name=file_key,json=fileKey,proto3" json:"file_key,omitempty"` // Only set by RecordDownload, for the gateway to serve the file
+	ContentType   string                 `protobuf:"bytes,15,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrderItem) Reset() {
-	*x = OrderItem{}
-	mi := &file_proto_order_proto_msgTypes[1]
+func (x *OrderDelivery) Reset() {
+	*x = OrderDelivery{}
+	mi := &file_proto_order_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderDelivery) ProtoMessage() {}
+
+func (x *OrderDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderDelivery.ProtoReflect.Descriptor instead.
+func (*OrderDelivery) Descriptor() ([]byte, []int) {
+	return file_proto_order_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OrderDelivery) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *OrderDelivery) GetOrderId() int32 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *OrderDelivery) GetOrderItemId() int32 {
+	if x != nil {
+		return x.OrderItemId
+	}
+	return 0
+}
+
+func (x *OrderDelivery) GetProductId() int32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *OrderDelivery) GetDelivery() string {
+	if x != nil {
+		return x.Delivery
+	}
+	return ""
+}
+
+func (x *OrderDelivery) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *OrderDelivery) GetDownloadUrl() string {
+	if x != nil {
+		return x.DownloadUrl
+	}
+	return ""
+}
+
+func (x *OrderDelivery) GetDownloadLimit() int32 {
+	if x != nil {
+		return x.DownloadLimit
+	}
+	return 0
+}
+
+func (x *OrderDelivery) GetDownloadCount() int32 {
+	if x != nil {
+		return x.DownloadCount
+	}
+	return 0
+}
+
+func (x *OrderDelivery) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *OrderDelivery) GetExpired() bool {
+	if x != nil {
+		return x.Expired
+	}
+	return false
+}
+
+func (x *OrderDelivery) GetLicenseKeys() []string {
+	if x != nil {
+		return x.LicenseKeys
+	}
+	return nil
+}
+
+func (x *OrderDelivery) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *OrderDelivery) GetFileKey() string {
+	if x != nil {
+		return x.FileKey
+	}
+	return ""
+}
+
+func (x *OrderDelivery) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+type RecordDownloadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeliveryId    int32                  `protobuf:"varint,1,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	CheckOnly     bool                   `protobuf:"varint,2,opt,name=check_only,json=checkOnly,proto3" json:"check_only,omitempty"` // Checks the download is allowed without counting it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordDownloadRequest) Reset() {
+	*x = RecordDownloadRequest{}
+	mi := &file_proto_order_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordDownloadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordDownloadRequest) ProtoMessage() {}
+
+func (x *RecordDownloadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordDownloadRequest.ProtoReflect.Descriptor instead.
+func (*RecordDownloadRequest) Descriptor() ([]byte, []int) {
+	return file_proto_order_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RecordDownloadRequest) GetDeliveryId() int32 {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return 0
+}
+
+func (x *RecordDownloadRequest) GetCheckOnly() bool {
+	if x != nil {
+		return x.CheckOnly
+	}
+	return false
+}
+
+type CreateOrderRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	UserId            int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TotalPrice        float64                `protobuf:"fixed64,2,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	Items             []*OrderItemRequest    `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	Livemode          bool                   `protobuf:"varint,4,opt,name=livemode,proto3" json:"livemode,omitempty"`
+	ShippingLatitude  float64                `protobuf:"fixed64,5,opt,name=shipping_latitude,json=shippingLatitude,proto3" json:"shipping_latitude,omitempty"` // Where the order ships to, used to pick the nearest warehouse
+	ShippingLongitude float64                `protobuf:"fixed64,6,opt,name=shipping_longitude,json=shippingLongitude,proto3" json:"shipping_longitude,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CreateOrderRequest) Reset() {
+	*x = CreateOrderRequest{}
+	mi := &file_proto_order_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrderRequest) ProtoMessage() {}
+
+func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrderRequest.ProtoReflect.Descriptor instead.
+func (*CreateOrderRequest) Descriptor() ([]byte, []int) {
+	return file_proto_order_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateOrderRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CreateOrderRequest) GetTotalPrice() float64 {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return 0
+}
+
+func (x *CreateOrderRequest) GetItems() []*OrderItemRequest {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *CreateOrderRequest) GetLivemode() bool {
+	if x != nil {
+		return x.Livemode
+	}
+	return false
+}
+
+func (x *CreateOrderRequest) GetShippingLatitude() float64 {
+	if x != nil {
+		return x.ShippingLatitude
+	}
+	return 0
+}
+
+func (x *CreateOrderRequest) GetShippingLongitude() float64 {
+	if x != nil {
+		return x.ShippingLongitude
+	}
+	return 0
+}
+
+type OrderItemRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	OrderId      int32                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	ProductId    int32                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity     int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price        float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	VariantId    int32                  `protobuf:"varint,5,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"` // Required when the product has more than one variant
+	WarehouseId  int32                  `protobuf:"varint,6,opt,name=warehouse_id,json=warehouseId,proto3" json:"warehouse_id,omitempty"`
+	ParentItemId int32                  `protobuf:"varint,7,opt,name=parent_item_id,json=parentItemId,proto3" json:"parent_item_id,omitempty"`
+	PriceRuleId  int32                  `protobuf:"varint,8,opt,name=price_rule_id,json=priceRuleId,proto3" json:"price_rule_id,omitempty"`
+	// Snapshot of the catalog when the order is placed, so the item reads the same
+	// after the product changes
+	ProductName   string `protobuf:"bytes,9,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	Sku           string `protobuf:"bytes,10,opt,name=sku,proto3" json:"sku,omitempty"`
+	WarehouseCode string `protobuf:"bytes,11,opt,name=warehouse_code,json=warehouseCode,proto3" json:"warehouse_code,omitempty"`
+	Digital       bool   `protobuf:"varint,12,opt,name=digital,proto3" json:"digital,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderItemRequest) Reset() {
+	*x = OrderItemRequest{}
+	mi := &file_proto_order_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderItemRequest) ProtoMessage() {}
+
+func (x *OrderItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderItemRequest.ProtoReflect.Descriptor instead.
+func (*OrderItemRequest) Descriptor() ([]byte, []int) {
+	return file_proto_order_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *OrderItemRequest) GetOrderId() int32 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *OrderItemRequest) GetProductId() int32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *OrderItemRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderItemRequest) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *OrderItemRequest) GetVariantId() int32 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *OrderItemRequest) GetWarehouseId() int32 {
+	if x != nil {
+		return x.WarehouseId
+	}
+	return 0
+}
+
+func (x *OrderItemRequest) GetParentItemId() int32 {
+	if x != nil {
+		return x.ParentItemId
+	}
+	return 0
+}
+
+func (x *OrderItemRequest) GetPriceRuleId() int32 {
+	if x != nil {
+		return x.PriceRuleId
+	}
+	return 0
+}
+
+func (x *OrderItemRequest) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *OrderItemRequest) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *OrderItemRequest) GetWarehouseCode() string {
+	if x != nil {
+		return x.WarehouseCode
+	}
+	return ""
+}
+
+func (x *OrderItemRequest) GetDigital() bool {
+	if x != nil {
+		return x.Digital
+	}
+	return false
+}
+
+type GetOrderItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderItemId   int32                  `protobuf:"varint,1,opt,name=order_item_id,json=orderItemId,proto3" json:"order_item_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderItemRequest) Reset() {
+	*x = GetOrderItemRequest{}
+	mi := &file_proto_order_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderItemRequest) ProtoMessage() {}
+
+func (x *GetOrderItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderItemRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderItemRequest) Descriptor() ([]byte, []int) {
+	return file_proto_order_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetOrderItemRequest) GetOrderItemId() int32 {
+	if x != nil {
+		return x.OrderItemId
+	}
+	return 0
+}
+
+type GetOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Livemode      bool                   `protobuf:"varint,3,opt,name=livemode,proto3" json:"livemode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderRequest) Reset() {
+	*x = GetOrderRequest{}
+	mi := &file_proto_order_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderRequest) ProtoMessage() {}
+
+func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderRequest) Descriptor() ([]byte, []int) {
+	return file_proto_order_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetOrderRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *GetOrderRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetOrderRequest) GetLivemode() bool {
+	if x != nil {
+		return x.Livemode
+	}
+	return false
+}
+
+type GetOrderByIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int32                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderByIdRequest) Reset() {
+	*x = GetOrderByIdRequest{}
+	mi := &file_proto_order_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderByIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderByIdRequest) ProtoMessage() {}
+
+func (x *GetOrderByIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderByIdRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderByIdRequest) Descriptor() ([]byte, []int) {
+	return file_proto_order_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetOrderByIdRequest) GetOrderId() int32 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *GetOrderByIdRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type UpdateOrderStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int32                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOrderStatusRequest) Reset() {
+	*x = UpdateOrderStatusRequest{}
+	mi := &file_proto_order_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrderStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderStatusRequest) ProtoMessage() {}
+
+func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateOrderStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_order_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateOrderStatusRequest) GetOrderId() int32 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *UpdateOrderStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type OrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderResponse) Reset() {
+	*x = OrderResponse{}
+	mi := &file_proto_order_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderItem) String() string {
+func (x *OrderResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderItem) ProtoMessage() {}
+func (*OrderResponse) ProtoMessage() {}
 
-func (x *OrderItem) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_order_proto_msgTypes[1]
+func (x *OrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -149,70 +952,40 @@ func (x *OrderItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderItem.ProtoReflect.Descriptor instead.
-func (*OrderItem) Descriptor() ([]byte, []int) {
-	return file_proto_order_proto_rawDescGZIP(), []int{1}
-}
-
-func (x *OrderItem) GetId() int32 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
-}
-
-func (x *OrderItem) GetOrderId() int32 {
-	if x != nil {
-		return x.OrderId
-	}
-	return 0
-}
-
-func (x *OrderItem) GetProductId() int32 {
-	if x != nil {
-		return x.ProductId
-	}
-	return 0
-}
-
-func (x *OrderItem) GetQuantity() int32 {
-	if x != nil {
-		return x.Quantity
-	}
-	return 0
+// Deprecated: Use OrderResponse.ProtoReflect.Descriptor instead.
+func (*OrderResponse) Descriptor() ([]byte, []int) {
+	return file_proto_order_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *OrderItem) GetPrice() float64 {
+func (x *OrderResponse) GetOrder() *Order {
 	if x != nil {
-		return x.Price
+		return x.Order
 	}
-	return 0
+	return nil
 }
 
-type CreateOrderRequest struct {
+type OrdersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	TotalPrice    float64                `protobuf:"fixed64,2,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
-	Items         []*OrderItemRequest    `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateOrderRequest) Reset() {
-	*x = CreateOrderRequest{}
-	mi := &file_proto_order_proto_msgTypes[2]
+func (x *OrdersResponse) Reset() {
+	*x = OrdersResponse{}
+	mi := &file_proto_order_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateOrderRequest) String() string {
+func (x *OrdersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateOrderRequest) ProtoMessage() {}
+func (*OrdersResponse) ProtoMessage() {}
 
-func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_order_proto_msgTypes[2]
+func (x *OrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -223,56 +996,41 @@ func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateOrderRequest.ProtoReflect.Descriptor instead.
-func (*CreateOrderRequest) Descriptor() ([]byte, []int) {
-	return file_proto_order_proto_rawDescGZIP(), []int{2}
-}
-
-func (x *CreateOrderRequest) GetUserId() int32 {
-	if x != nil {
-		return x.UserId
-	}
-	return 0
-}
-
-func (x *CreateOrderRequest) GetTotalPrice() float64 {
-	if x != nil {
-		return x.TotalPrice
-	}
-	return 0
+// Deprecated: Use OrdersResponse.ProtoReflect.Descriptor instead.
+func (*OrdersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_order_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *CreateOrderRequest) GetItems() []*OrderItemRequest {
+func (x *OrdersResponse) GetOrders() []*Order {
 	if x != nil {
-		return x.Items
+		return x.Orders
 	}
 	return nil
 }
 
-type OrderItemRequest struct {
+type ReturnItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       int32                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	ProductId     int32                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	OrderItemId   int32                  `protobuf:"varint,1,opt,name=order_item_id,json=orderItemId,proto3" json:"order_item_id,omitempty"` // A bundle item returns the bundle with all its components
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrderItemRequest) Reset() {
-	*x = OrderItemRequest{}
-	mi := &file_proto_order_proto_msgTypes[3]
+func (x *ReturnItem) Reset() {
+	*x = ReturnItem{}
+	mi := &file_proto_order_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderItemRequest) String() string {
+func (x *ReturnItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderItemRequest) ProtoMessage() {}
+func (*ReturnItem) ProtoMessage() {}
 
-func (x *OrderItemRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_order_proto_msgTypes[3]
+func (x *ReturnItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -283,54 +1041,52 @@ func (x *OrderItemRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderItemRequest.ProtoReflect.Descriptor instead.
-func (*OrderItemRequest) Descriptor() ([]byte, []int) {
-	return file_proto_order_proto_rawDescGZIP(), []int{3}
-}
-
-func (x *OrderItemRequest) GetOrderId() int32 {
-	if x != nil {
-		return x.OrderId
-	}
-	return 0
+// Deprecated: Use ReturnItem.ProtoReflect.Descriptor instead.
+func (*ReturnItem) Descriptor() ([]byte, []int) {
+	return file_proto_order_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *OrderItemRequest) GetProductId() int32 {
+func (x *ReturnItem) GetOrderItemId() int32 {
 	if x != nil {
-		return x.ProductId
+		return x.OrderItemId
 	}
 	return 0
 }
 
-func (x *OrderItemRequest) GetQuantity() int32 {
+func (x *ReturnItem) GetQuantity() int32 {
 	if x != nil {
 		return x.Quantity
 	}
 	return 0
 }
 
-type GetOrderItemRequest struct {
+// ReturnOrderItemsRequest puts returned items back in stock. A dry run only
+// works out what the return is worth.
+type ReturnOrderItemsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderItemId   int32                  `protobuf:"varint,1,opt,name=order_item_id,json=orderItemId,proto3" json:"order_item_id,omitempty"`
+	OrderId       int32                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Items         []*ReturnItem          `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	DryRun        bool                   `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetOrderItemRequest) Reset() {
-	*x = GetOrderItemRequest{}
-	mi := &file_proto_order_proto_msgTypes[4]
+func (x *ReturnOrderItemsRequest) Reset() {
+	*x = ReturnOrderItemsRequest{}
+	mi := &file_proto_order_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOrderItemRequest) String() string {
+func (x *ReturnOrderItemsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOrderItemRequest) ProtoMessage() {}
+func (*ReturnOrderItemsRequest) ProtoMessage() {}
 
-func (x *GetOrderItemRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_order_proto_msgTypes[4]
+func (x *ReturnOrderItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -341,41 +1097,61 @@ func (x *GetOrderItemRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOrderItemRequest.ProtoReflect.Descriptor instead.
-func (*GetOrderItemRequest) Descriptor() ([]byte, []int) {
-	return file_proto_order_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ReturnOrderItemsRequest.ProtoReflect.Descriptor instead.
+func (*ReturnOrderItemsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_order_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *GetOrderItemRequest) GetOrderItemId() int32 {
+func (x *ReturnOrderItemsRequest) GetOrderId() int32 {
 	if x != nil {
-		return x.OrderItemId
+		return x.OrderId
 	}
 	return 0
 }
 
-type GetOrderRequest struct {
+func (x *ReturnOrderItemsRequest) GetItems() []*ReturnItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ReturnOrderItemsRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ReturnOrderItemsRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type ReturnOrderItemsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	RefundAmount  float64                `protobuf:"fixed64,1,opt,name=refund_amount,json=refundAmount,proto3" json:"refund_amount,omitempty"` // What the returned items were paid
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetOrderRequest) Reset() {
-	*x = GetOrderRequest{}
-	mi := &file_proto_order_proto_msgTypes[5]
+func (x *ReturnOrderItemsResponse) Reset() {
+	*x = ReturnOrderItemsResponse{}
+	mi := &file_proto_order_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOrderRequest) String() string {
+func (x *ReturnOrderItemsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOrderRequest) ProtoMessage() {}
+func (*ReturnOrderItemsResponse) ProtoMessage() {}
 
-func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_order_proto_msgTypes[5]
+func (x *ReturnOrderItemsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -386,48 +1162,43 @@ func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
-func (*GetOrderRequest) Descriptor() ([]byte, []int) {
-	return file_proto_order_proto_rawDescGZIP(), []int{5}
-}
-
-func (x *GetOrderRequest) GetUserId() int32 {
-	if x != nil {
-		return x.UserId
-	}
-	return 0
+// Deprecated: Use ReturnOrderItemsResponse.ProtoReflect.Descriptor instead.
+func (*ReturnOrderItemsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_order_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *GetOrderRequest) GetOffset() int32 {
+func (x *ReturnOrderItemsResponse) GetRefundAmount() float64 {
 	if x != nil {
-		return x.Offset
+		return x.RefundAmount
 	}
 	return 0
 }
 
-type GetOrderByIdRequest struct {
+// HasPurchasedRequest asks whether a user bought a product in a paid or fulfilled
+// live order; test-mode orders are not purchases
+type HasPurchasedRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       int32                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	UserId        int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId     int32                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetOrderByIdRequest) Reset() {
-	*x = GetOrderByIdRequest{}
-	mi := &file_proto_order_proto_msgTypes[6]
+func (x *HasPurchasedRequest) Reset() {
+	*x = HasPurchasedRequest{}
+	mi := &file_proto_order_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOrderByIdRequest) String() string {
+func (x *HasPurchasedRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOrderByIdRequest) ProtoMessage() {}
+func (*HasPurchasedRequest) ProtoMessage() {}
 
-func (x *GetOrderByIdRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_order_proto_msgTypes[6]
+func (x *HasPurchasedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -438,48 +1209,47 @@ func (x *GetOrderByIdRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOrderByIdRequest.ProtoReflect.Descriptor instead.
-func (*GetOrderByIdRequest) Descriptor() ([]byte, []int) {
-	return file_proto_order_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use HasPurchasedRequest.ProtoReflect.Descriptor instead.
+func (*HasPurchasedRequest) Descriptor() ([]byte, []int) {
+	return file_proto_order_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *GetOrderByIdRequest) GetOrderId() int32 {
+func (x *HasPurchasedRequest) GetUserId() int32 {
 	if x != nil {
-		return x.OrderId
+		return x.UserId
 	}
 	return 0
 }
 
-func (x *GetOrderByIdRequest) GetUserId() int32 {
+func (x *HasPurchasedRequest) GetProductId() int32 {
 	if x != nil {
-		return x.UserId
+		return x.ProductId
 	}
 	return 0
 }
 
-type UpdateOrderStatusRequest struct {
+type HasPurchasedResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       int32                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Purchased     bool                   `protobuf:"varint,1,opt,name=purchased,proto3" json:"purchased,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateOrderStatusRequest) Reset() {
-	*x = UpdateOrderStatusRequest{}
-	mi := &file_proto_order_proto_msgTypes[7]
+func (x *HasPurchasedResponse) Reset() {
+	*x = HasPurchasedResponse{}
+	mi := &file_proto_order_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateOrderStatusRequest) String() string {
+func (x *HasPurchasedResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateOrderStatusRequest) ProtoMessage() {}
+func (*HasPurchasedResponse) ProtoMessage() {}
 
-func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_order_proto_msgTypes[7]
+func (x *HasPurchasedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -490,47 +1260,42 @@ func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateOrderStatusRequest.ProtoReflect.Descriptor instead.
-func (*UpdateOrderStatusRequest) Descriptor() ([]byte, []int) {
-	return file_proto_order_proto_rawDescGZIP(), []int{7}
-}
-
-func (x *UpdateOrderStatusRequest) GetOrderId() int32 {
-	if x != nil {
-		return x.OrderId
-	}
-	return 0
+// Deprecated: Use HasPurchasedResponse.ProtoReflect.Descriptor instead.
+func (*HasPurchasedResponse) Descriptor() ([]byte, []int) {
+	return file_proto_order_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *UpdateOrderStatusRequest) GetStatus() string {
+func (x *HasPurchasedResponse) GetPurchased() bool {
 	if x != nil {
-		return x.Status
+		return x.Purchased
 	}
-	return ""
+	return false
 }
 
-type OrderResponse struct {
+// HasOrdersRequest asks whether any order of either mode has a line of a product,
+// including lines that are components of a bundle
+type HasOrdersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	ProductId     int32                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrderResponse) Reset() {
-	*x = OrderResponse{}
-	mi := &file_proto_order_proto_msgTypes[8]
+func (x *HasOrdersRequest) Reset() {
+	*x = HasOrdersRequest{}
+	mi := &file_proto_order_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderResponse) String() string {
+func (x *HasOrdersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderResponse) ProtoMessage() {}
+func (*HasOrdersRequest) ProtoMessage() {}
 
-func (x *OrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_order_proto_msgTypes[8]
+func (x *HasOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -541,40 +1306,40 @@ func (x *OrderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderResponse.ProtoReflect.Descriptor instead.
-func (*OrderResponse) Descriptor() ([]byte, []int) {
-	return file_proto_order_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use HasOrdersRequest.ProtoReflect.Descriptor instead.
+func (*HasOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_order_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *OrderResponse) GetOrder() *Order {
+func (x *HasOrdersRequest) GetProductId() int32 {
 	if x != nil {
-		return x.Order
+		return x.ProductId
 	}
-	return nil
+	return 0
 }
 
-type OrdersResponse struct {
+type HasOrdersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	Ordered       bool                   `protobuf:"varint,1,opt,name=ordered,proto3" json:"ordered,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrdersResponse) Reset() {
-	*x = OrdersResponse{}
-	mi := &file_proto_order_proto_msgTypes[9]
+func (x *HasOrdersResponse) Reset() {
+	*x = HasOrdersResponse{}
+	mi := &file_proto_order_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrdersResponse) String() string {
+func (x *HasOrdersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrdersResponse) ProtoMessage() {}
+func (*HasOrdersResponse) ProtoMessage() {}
 
-func (x *OrdersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_order_proto_msgTypes[9]
+func (x *HasOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -585,16 +1350,16 @@ func (x *OrdersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrdersResponse.ProtoReflect.Descriptor instead.
-func (*OrdersResponse) Descriptor() ([]byte, []int) {
-	return file_proto_order_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use HasOrdersResponse.ProtoReflect.Descriptor instead.
+func (*HasOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_order_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *OrdersResponse) GetOrders() []*Order {
+func (x *HasOrdersResponse) GetOrdered() bool {
 	if x != nil {
-		return x.Orders
+		return x.Ordered
 	}
-	return nil
+	return false
 }
 
 type EmptyOrder struct {
@@ -605,7 +1370,7 @@ type EmptyOrder struct {
 
 func (x *EmptyOrder) Reset() {
 	*x = EmptyOrder{}
-	mi := &file_proto_order_proto_msgTypes[10]
+	mi := &file_proto_order_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -617,7 +1382,7 @@ func (x *EmptyOrder) String() string {
 func (*EmptyOrder) ProtoMessage() {}
 
 func (x *EmptyOrder) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_order_proto_msgTypes[10]
+	mi := &file_proto_order_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -630,14 +1395,14 @@ func (x *EmptyOrder) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EmptyOrder.ProtoReflect.Descriptor instead.
 func (*EmptyOrder) Descriptor() ([]byte, []int) {
-	return file_proto_order_proto_rawDescGZIP(), []int{10}
+	return file_proto_order_proto_rawDescGZIP(), []int{19}
 }
 
 var File_proto_order_proto protoreflect.FileDescriptor
 
 const file_proto_order_proto_rawDesc = "" +
 	"\n" +
-	"\x11proto/order.proto\x12\x06orders\"\xdb\x01\n" +
+	"\x11proto/order.proto\x12\x06orders\"\xae\x02\n" +
 	"\x05Order\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\x05R\x06userId\x12\x1f\n" +
@@ -649,29 +1414,87 @@ const file_proto_order_proto_rawDesc = "" +
 	"\n" +
 	"updated_at\x18\x06 \x01(\tR\tupdatedAt\x122\n" +
 	"\vorder_items\x18\a \x03(\v2\x11.orders.OrderItemR\n" +
-	"orderItems\"\x87\x01\n" +
+	"orderItems\x12\x1a\n" +
+	"\blivemode\x18\b \x01(\bR\blivemode\x125\n" +
+	"\n" +
+	"deliveries\x18\t \x03(\v2\x15.orders.OrderDeliveryR\n" +
+	"deliveries\"\xe9\x03\n" +
 	"\tOrderItem\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x19\n" +
 	"\border_id\x18\x02 \x01(\x05R\aorderId\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x03 \x01(\x05R\tproductId\x12\x1a\n" +
 	"\bquantity\x18\x04 \x01(\x05R\bquantity\x12\x14\n" +
-	"\x05price\x18\x05 \x01(\x01R\x05price\"~\n" +
+	"\x05price\x18\x05 \x01(\x01R\x05price\x12!\n" +
+	"\fproduct_name\x18\x06 \x01(\tR\vproductName\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\a \x01(\x05R\tvariantId\x12\x10\n" +
+	"\x03sku\x18\b \x01(\tR\x03sku\x12!\n" +
+	"\fwarehouse_id\x18\t \x01(\x05R\vwarehouseId\x12%\n" +
+	"\x0ewarehouse_code\x18\n" +
+	" \x01(\tR\rwarehouseCode\x12$\n" +
+	"\x0eparent_item_id\x18\v \x01(\x05R\fparentItemId\x12+\n" +
+	"\x11returned_quantity\x18\f \x01(\x05R\x10returnedQuantity\x121\n" +
+	"\n" +
+	"components\x18\r \x03(\v2\x11.orders.OrderItemR\n" +
+	"components\x12\"\n" +
+	"\rprice_rule_id\x18\x0e \x01(\x05R\vpriceRuleId\x12\x18\n" +
+	"\adigital\x18\x0f \x01(\bR\adigital\"\xe0\x03\n" +
+	"\rOrderDelivery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\x05R\aorderId\x12\"\n" +
+	"\rorder_item_id\x18\x03 \x01(\x05R\vorderItemId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x04 \x01(\x05R\tproductId\x12\x1a\n" +
+	"\bdelivery\x18\x05 \x01(\tR\bdelivery\x12\x1b\n" +
+	"\tfile_name\x18\x06 \x01(\tR\bfileName\x12!\n" +
+	"\fdownload_url\x18\a \x01(\tR\vdownloadUrl\x12%\n" +
+	"\x0edownload_limit\x18\b \x01(\x05R\rdownloadLimit\x12%\n" +
+	"\x0edownload_count\x18\t \x01(\x05R\rdownloadCount\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\n" +
+	" \x01(\tR\texpiresAt\x12\x18\n" +
+	"\aexpired\x18\v \x01(\bR\aexpired\x12!\n" +
+	"\flicense_keys\x18\f \x03(\tR\vlicenseKeys\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\r \x01(\tR\tcreatedAt\x12\x19\n" +
+	"\bfile_key\x18\x0e \x01(\tR\afileKey\x12!\n" +
+	"\fcontent_type\x18\x0f \x01(\tR\vcontentType\"W\n" +
+	"\x15RecordDownloadRequest\x12\x1f\n" +
+	"\vdelivery_id\x18\x01 \x01(\x05R\n" +
+	"deliveryId\x12\x1d\n" +
+	"\n" +
+	"check_only\x18\x02 \x01(\bR\tcheckOnly\"\xf6\x01\n" +
 	"\x12CreateOrderRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12\x1f\n" +
 	"\vtotal_price\x18\x02 \x01(\x01R\n" +
 	"totalPrice\x12.\n" +
-	"\x05items\x18\x03 \x03(\v2\x18.orders.OrderItemRequestR\x05items\"h\n" +
+	"\x05items\x18\x03 \x03(\v2\x18.orders.OrderItemRequestR\x05items\x12\x1a\n" +
+	"\blivemode\x18\x04 \x01(\bR\blivemode\x12+\n" +
+	"\x11shipping_latitude\x18\x05 \x01(\x01R\x10shippingLatitude\x12-\n" +
+	"\x12shipping_longitude\x18\x06 \x01(\x01R\x11shippingLongitude\"\x80\x03\n" +
 	"\x10OrderItemRequest\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\x05R\aorderId\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x02 \x01(\x05R\tproductId\x12\x1a\n" +
-	"\bquantity\x18\x03 \x01(\x05R\bquantity\"9\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x05 \x01(\x05R\tvariantId\x12!\n" +
+	"\fwarehouse_id\x18\x06 \x01(\x05R\vwarehouseId\x12$\n" +
+	"\x0eparent_item_id\x18\a \x01(\x05R\fparentItemId\x12\"\n" +
+	"\rprice_rule_id\x18\b \x01(\x05R\vpriceRuleId\x12!\n" +
+	"\fproduct_name\x18\t \x01(\tR\vproductName\x12\x10\n" +
+	"\x03sku\x18\n" +
+	" \x01(\tR\x03sku\x12%\n" +
+	"\x0ewarehouse_code\x18\v \x01(\tR\rwarehouseCode\x12\x18\n" +
+	"\adigital\x18\f \x01(\bR\adigital\"9\n" +
 	"\x13GetOrderItemRequest\x12\"\n" +
-	"\rorder_item_id\x18\x01 \x01(\x05R\vorderItemId\"B\n" +
+	"\rorder_item_id\x18\x01 \x01(\x05R\vorderItemId\"^\n" +
 	"\x0fGetOrderRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x05R\x06offset\"I\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x1a\n" +
+	"\blivemode\x18\x03 \x01(\bR\blivemode\"I\n" +
 	"\x13GetOrderByIdRequest\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\x05R\aorderId\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\x05R\x06userId\"M\n" +
@@ -681,14 +1504,41 @@ const file_proto_order_proto_rawDesc = "" +
 	"\rOrderResponse\x12#\n" +
 	"\x05order\x18\x01 \x01(\v2\r.orders.OrderR\x05order\"7\n" +
 	"\x0eOrdersResponse\x12%\n" +
-	"\x06orders\x18\x01 \x03(\v2\r.orders.OrderR\x06orders\"\f\n" +
+	"\x06orders\x18\x01 \x03(\v2\r.orders.OrderR\x06orders\"L\n" +
+	"\n" +
+	"ReturnItem\x12\"\n" +
+	"\rorder_item_id\x18\x01 \x01(\x05R\vorderItemId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"\x8f\x01\n" +
+	"\x17ReturnOrderItemsRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x05R\aorderId\x12(\n" +
+	"\x05items\x18\x02 \x03(\v2\x12.orders.ReturnItemR\x05items\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\"?\n" +
+	"\x18ReturnOrderItemsResponse\x12#\n" +
+	"\rrefund_amount\x18\x01 \x01(\x01R\frefundAmount\"M\n" +
+	"\x13HasPurchasedRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x05R\tproductId\"4\n" +
+	"\x14HasPurchasedResponse\x12\x1c\n" +
+	"\tpurchased\x18\x01 \x01(\bR\tpurchased\"1\n" +
+	"\x10HasOrdersRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x05R\tproductId\"-\n" +
+	"\x11HasOrdersResponse\x12\x18\n" +
+	"\aordered\x18\x01 \x01(\bR\aordered\"\f\n" +
 	"\n" +
-	"EmptyOrder2\x9c\x02\n" +
+	"EmptyOrder2\x8c\x05\n" +
 	"\fOrderService\x12@\n" +
 	"\vCreateOrder\x12\x1a.orders.CreateOrderRequest\x1a\x15.orders.OrderResponse\x12;\n" +
 	"\bGetOrder\x12\x17.orders.GetOrderRequest\x1a\x16.orders.OrdersResponse\x12B\n" +
 	"\fGetOrderById\x12\x1b.orders.GetOrderByIdRequest\x1a\x15.orders.OrderResponse\x12I\n" +
-	"\x11UpdateOrderStatus\x12 .orders.UpdateOrderStatusRequest\x1a\x12.orders.EmptyOrderB\n" +
+	"\x11UpdateOrderStatus\x12 .orders.UpdateOrderStatusRequest\x1a\x12.orders.EmptyOrder\x12U\n" +
+	"\x10ReturnOrderItems\x12\x1f.orders.ReturnOrderItemsRequest\x1a .orders.ReturnOrderItemsResponse\x12B\n" +
+	"\fDeliverOrder\x12\x1b.orders.GetOrderByIdRequest\x1a\x15.orders.OrderResponse\x12F\n" +
+	"\x0eRecordDownload\x12\x1d.orders.RecordDownloadRequest\x1a\x15.orders.OrderDelivery\x12I\n" +
+	"\fHasPurchased\x12\x1b.orders.HasPurchasedRequest\x1a\x1c.orders.HasPurchasedResponse\x12@\n" +
+	"\tHasOrders\x12\x18.orders.HasOrdersRequest\x1a\x19.orders.HasOrdersResponseB\n" +
 	"Z\b../protob\x06proto3"
 
 var (
@@ -703,38 +1553,60 @@ func file_proto_order_proto_rawDescGZIP() []byte {
 	return file_proto_order_proto_rawDescData
 }
 
-var file_proto_order_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_proto_order_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_proto_order_proto_goTypes = []any{
 	(*Order)(nil),                    // 0: orders.Order
 	(*OrderItem)(nil),                // 1: orders.OrderItem
-	(*CreateOrderRequest)(nil),       // 2: orders.CreateOrderRequest
-	(*OrderItemRequest)(nil),         // 3: orders.OrderItemRequest
-	(*GetOrderItemRequest)(nil),      // 4: orders.GetOrderItemRequest
-	(*GetOrderRequest)(nil),          // 5: orders.GetOrderRequest
-	(*GetOrderByIdRequest)(nil),      // 6: orders.GetOrderByIdRequest
-	(*UpdateOrderStatusRequest)(nil), // 7: orders.UpdateOrderStatusRequest
-	(*OrderResponse)(nil),            // 8: orders.OrderResponse
-	(*OrdersResponse)(nil),           // 9: orders.OrdersResponse
-	(*EmptyOrder)(nil),               // 10: orders.EmptyOrder
+	(*OrderDelivery)(nil),            // 2: orders.OrderDelivery
+	(*RecordDownloadRequest)(nil),    // 3: orders.RecordDownloadRequest
+	(*CreateOrderRequest)(nil),       // 4: orders.CreateOrderRequest
+	(*OrderItemRequest)(nil),         // 5: orders.OrderItemRequest
+	(*GetOrderItemRequest)(nil),      // 6: orders.GetOrderItemRequest
+	(*GetOrderRequest)(nil),          // 7: orders.GetOrderRequest
+	(*GetOrderByIdRequest)(nil),      // 8: orders.GetOrderByIdRequest
+	(*UpdateOrderStatusRequest)(nil), // 9: orders.UpdateOrderStatusRequest
+	(*OrderResponse)(nil),            // 10: orders.OrderResponse
+	(*OrdersResponse)(nil),           // 11: orders.OrdersResponse
+	(*ReturnItem)(nil),               // 12: orders.ReturnItem
+	(*ReturnOrderItemsRequest)(nil),  // 13: orders.ReturnOrderItemsRequest
+	(*ReturnOrderItemsResponse)(nil), // 14: orders.ReturnOrderItemsResponse
+	(*HasPurchasedRequest)(nil),      // 15: orders.HasPurchasedRequest
+	(*HasPurchasedResponse)(nil),     // 16: orders.HasPurchasedResponse
+	(*HasOrdersRequest)(nil),         // 17: orders.HasOrdersRequest
+	(*HasOrdersResponse)(nil),        // 18: orders.HasOrdersResponse
+	(*EmptyOrder)(nil),               // 19: orders.EmptyOrder
 }
 var file_proto_order_proto_depIdxs = []int32{
 	1,  // 0: orders.Order.order_items:type_name -> orders.OrderItem
-	3,  // 1: orders.CreateOrderRequest.items:type_name -> orders.OrderItemRequest
-	0,  // 2: orders.OrderResponse.order:type_name -> orders.Order
-	0,  // 3: orders.OrdersResponse.orders:type_name -> orders.Order
-	2,  // 4: orders.OrderService.CreateOrder:input_type -> orders.CreateOrderRequest
-	5,  // 5: orders.OrderService.GetOrder:input_type -> orders.GetOrderRequest
-	6,  // 6: orders.OrderService.GetOrderById:input_type -> orders.GetOrderByIdRequest
-	7,  // 7: orders.OrderService.UpdateOrderStatus:input_type -> orders.UpdateOrderStatusRequest
-	8,  // 8: orders.OrderService.CreateOrder:output_type -> orders.OrderResponse
-	9,  // 9: orders.OrderService.GetOrder:output_type -> orders.OrdersResponse
-	8,  // 10: orders.OrderService.GetOrderById:output_type -> orders.OrderResponse
-	10, // 11: orders.OrderService.UpdateOrderStatus:output_type -> orders.EmptyOrder
-	8,  // [8:12] is the sub-list for method output_type
-	4,  // [4:8] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	2,  // 1: orders.Order.deliveries:type_name -> orders.OrderDelivery
+	1,  // 2: orders.OrderItem.components:type_name -> orders.OrderItem
+	5,  // 3: orders.CreateOrderRequest.items:type_name -> orders.OrderItemRequest
+	0,  // 4: orders.OrderResponse.order:type_name -> orders.Order
+	0,  // 5: orders.OrdersResponse.orders:type_name -> orders.Order
+	12, // 6: orders.ReturnOrderItemsRequest.items:type_name -> orders.ReturnItem
+	4,  // 7: orders.OrderService.CreateOrder:input_type -> orders.CreateOrderRequest
+	7,  // 8: orders.OrderService.GetOrder:input_type -> orders.GetOrderRequest
+	8,  // 9: orders.OrderService.GetOrderById:input_type -> orders.GetOrderByIdRequest
+	9,  // 10: orders.OrderService.UpdateOrderStatus:input_type -> orders.UpdateOrderStatusRequest
+	13, // 11: orders.OrderService.ReturnOrderItems:input_type -> orders.ReturnOrderItemsRequest
+	8,  // 12: orders.OrderService.DeliverOrder:input_type -> orders.GetOrderByIdRequest
+	3,  // 13: orders.OrderService.RecordDownload:input_type -> orders.RecordDownloadRequest
+	15, // 14: orders.OrderService.HasPurchased:input_type -> orders.HasPurchasedRequest
+	17, // 15: orders.OrderService.HasOrders:input_type -> orders.HasOrdersRequest
+	10, // 16: orders.OrderService.CreateOrder:output_type -> orders.OrderResponse
+	11, // 17: orders.OrderService.GetOrder:output_type -> orders.OrdersResponse
+	10, // 18: orders.OrderService.GetOrderById:output_type -> orders.OrderResponse
+	19, // 19: orders.OrderService.UpdateOrderStatus:output_type -> orders.EmptyOrder
+	14, // 20: orders.OrderService.ReturnOrderItems:output_type -> orders.ReturnOrderItemsResponse
+	10, // 21: orders.OrderService.DeliverOrder:output_type -> orders.OrderResponse
+	2,  // 22: orders.OrderService.RecordDownload:output_type -> orders.OrderDelivery
+	16, // 23: orders.OrderService.HasPurchased:output_type -> orders.HasPurchasedResponse
+	18, // 24: orders.OrderService.HasOrders:output_type -> orders.HasOrdersResponse
+	16, // [16:25] is the sub-list for method output_type
+	7,  // [7:16] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_proto_order_proto_init() }
@@ -748,7 +1620,7 @@ func file_proto_order_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_order_proto_rawDesc), len(file_proto_order_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
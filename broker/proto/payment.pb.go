@@ -41,6 +41,11 @@ type PaymentResponse struct {
 	CreatedAt            string                 `protobuf:"bytes,15,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	PaidAt               string                 `protobuf:"bytes,16,opt,name=paid_at,json=paidAt,proto3" json:"paid_at,omitempty"`
 	ExpiredAt            string                 `protobuf:"bytes,17,opt,name=expired_at,json=expiredAt,proto3" json:"expired_at,omitempty"`
+	PaymentIntentId      int32                  `protobuf:"varint,18,opt,name=payment_intent_id,json=paymentIntentId,proto3" json:"payment_intent_id,omitempty"`
+	RefundedAmount       float64                `protobuf:"fixed64,19,opt,name=refunded_amount,json=refundedAmount,proto3" json:"refunded_amount,omitempty"`
+	Livemode             bool                   `protobuf:"varint,20,opt,name=livemode,proto3" json:"livemode,omitempty"`                              // False for payments of test-mode orders
+	WalletAmount         float64                `protobuf:"fixed64,21,opt,name=wallet_amount,json=walletAmount,proto3" json:"wallet_amount,omitempty"` // Portion covered by store credit
+	UserId               int32                  `protobuf:"varint,22,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields        protoimpl.UnknownFields
 	sizeCache            protoimpl.SizeCache
 }
@@ -194,11 +199,48 @@ func (x *PaymentResponse) GetExpiredAt() string {
 	return ""
 }
 
+func (x *PaymentResponse) GetPaymentIntentId() int32 {
+	if x != nil {
+		return x.PaymentIntentId
+	}
+	return 0
+}
+
+func (x *PaymentResponse) GetRefundedAmount() float64 {
+	if x != nil {
+		return x.RefundedAmount
+	}
+	return 0
+}
+
+func (x *PaymentResponse) GetLivemode() bool {
+	if x != nil {
+		return x.Livemode
+	}
+	return false
+}
+
+func (x *PaymentResponse) GetWalletAmount() float64 {
+	if x != nil {
+		return x.WalletAmount
+	}
+	return 0
+}
+
+func (x *PaymentResponse) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
 // Request to create payment when order is created (via Kafka)
 type CreatePaymentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OrderId       int32                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
 	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Livemode      bool                   `protobuf:"varint,3,opt,name=livemode,proto3" json:"livemode,omitempty"`
+	UserId        int32                  `protobuf:"varint,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -247,6 +289,20 @@ func (x *CreatePaymentRequest) GetAmount() float64 {
 	return 0
 }
 
+func (x *CreatePaymentRequest) GetLivemode() bool {
+	if x != nil {
+		return x.Livemode
+	}
+	return false
+}
+
+func (x *CreatePaymentRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
 // Request to get payment by order ID
 type GetPaymentByOrderIdRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -296,14 +352,12 @@ func (x *GetPaymentByOrderIdRequest) GetOrderId() int32 {
 type InitiatePaymentRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	OrderId        int32                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	PaymentMethod  string                 `protobuf:"bytes,2,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`    // gopay, bank_transfer, credit_card, shopeepay, qris
+	PaymentMethod  string                 `protobuf:"bytes,2,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`    // gopay, bank_transfer, credit_card, shopeepay, qris, wallet
 	PaymentChannel string                 `protobuf:"bytes,3,opt,name=payment_channel,json=paymentChannel,proto3" json:"payment_channel,omitempty"` // bca, bni, mandiri (optional, for bank_transfer)
-	// Customer info for Midtrans
-	CustomerName  string `protobuf:"bytes,4,opt,name=customer_name,json=customerName,proto3" json:"customer_name,omitempty"`
-	CustomerEmail string `protobuf:"bytes,5,opt,name=customer_email,json=customerEmail,proto3" json:"customer_email,omitempty"`
-	CustomerPhone string `protobuf:"bytes,6,opt,name=customer_phone,json=customerPhone,proto3" json:"customer_phone,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	UserId         int32                  `protobuf:"varint,7,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                        // Set by the broker from the JWT; used to load order and customer profile
+	Livemode       bool                   `protobuf:"varint,8,opt,name=livemode,proto3" json:"livemode,omitempty"`                                  // Mode of the credential; must match the payment's mode
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *InitiatePaymentRequest) Reset() {
@@ -357,39 +411,35 @@ func (x *InitiatePaymentRequest) GetPaymentChannel() string {
 	return ""
 }
 
-func (x *InitiatePaymentRequest) GetCustomerName() string {
-	if x != nil {
-		return x.CustomerName
-	}
-	return ""
-}
-
-func (x *InitiatePaymentRequest) GetCustomerEmail() string {
+func (x *InitiatePaymentRequest) GetUserId() int32 {
 	if x != nil {
-		return x.CustomerEmail
+		return x.UserId
 	}
-	return ""
+	return 0
 }
 
-func (x *InitiatePaymentRequest) GetCustomerPhone() string {
+func (x *InitiatePaymentRequest) GetLivemode() bool {
 	if x != nil {
-		return x.CustomerPhone
+		return x.Livemode
 	}
-	return ""
+	return false
 }
 
 // Response after initiating payment
 type InitiatePaymentResponse struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	PaymentId          int32                  `protobuf:"varint,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
-	GatewayToken       string                 `protobuf:"bytes,2,opt,name=gateway_token,json=gatewayToken,proto3" json:"gateway_token,omitempty"`
-	GatewayRedirectUrl string                 `protobuf:"bytes,3,opt,name=gateway_redirect_url,json=gatewayRedirectUrl,proto3" json:"gateway_redirect_url,omitempty"`
-	VaNumber           string                 `protobuf:"bytes,4,opt,name=va_number,json=vaNumber,proto3" json:"va_number,omitempty"`
-	QrCodeUrl          string                 `protobuf:"bytes,5,opt,name=qr_code_url,json=qrCodeUrl,proto3" json:"qr_code_url,omitempty"`
-	ExpiredAt          string                 `protobuf:"bytes,6,opt,name=expired_at,json=expiredAt,proto3" json:"expired_at,omitempty"`
-	Status             string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId            int32                  `protobuf:"varint,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	GatewayToken         string                 `protobuf:"bytes,2,opt,name=gateway_token,json=gatewayToken,proto3" json:"gateway_token,omitempty"`
+	GatewayRedirectUrl   string                 `protobuf:"bytes,3,opt,name=gateway_redirect_url,json=gatewayRedirectUrl,proto3" json:"gateway_redirect_url,omitempty"`
+	VaNumber             string                 `protobuf:"bytes,4,opt,name=va_number,json=vaNumber,proto3" json:"va_number,omitempty"`
+	QrCodeUrl            string                 `protobuf:"bytes,5,opt,name=qr_code_url,json=qrCodeUrl,proto3" json:"qr_code_url,omitempty"`
+	ExpiredAt            string                 `protobuf:"bytes,6,opt,name=expired_at,json=expiredAt,proto3" json:"expired_at,omitempty"`
+	Status               string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	SavedPaymentMethodId int32                  `protobuf:"varint,8,opt,name=saved_payment_method_id,json=savedPaymentMethodId,proto3" json:"saved_payment_method_id,omitempty"` // Set when the card used was saved for later
+	WalletAmount         float64                `protobuf:"fixed64,9,opt,name=wallet_amount,json=walletAmount,proto3" json:"wallet_amount,omitempty"`                            // Paid from the wallet; the rest goes through the gateway
+	GatewayAmount        float64                `protobuf:"fixed64,10,opt,name=gateway_amount,json=gatewayAmount,proto3" json:"gateway_amount,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *InitiatePaymentResponse) Reset() {
@@ -471,35 +521,54 @@ func (x *InitiatePaymentResponse) GetStatus() string {
 	return ""
 }
 
-// Webhook request from Midtrans
-type WebhookRequest struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	OrderId           string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	TransactionId     string                 `protobuf:"bytes,2,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
-	TransactionStatus string                 `protobuf:"bytes,3,opt,name=transaction_status,json=transactionStatus,proto3" json:"transaction_status,omitempty"`
-	PaymentType       string                 `protobuf:"bytes,4,opt,name=payment_type,json=paymentType,proto3" json:"payment_type,omitempty"`
-	GrossAmount       string                 `protobuf:"bytes,5,opt,name=gross_amount,json=grossAmount,proto3" json:"gross_amount,omitempty"`
-	SignatureKey      string                 `protobuf:"bytes,6,opt,name=signature_key,json=signatureKey,proto3" json:"signature_key,omitempty"`
-	FraudStatus       string                 `protobuf:"bytes,7,opt,name=fraud_status,json=fraudStatus,proto3" json:"fraud_status,omitempty"`
-	StatusCode        string                 `protobuf:"bytes,8,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+func (x *InitiatePaymentResponse) GetSavedPaymentMethodId() int32 {
+	if x != nil {
+		return x.SavedPaymentMethodId
+	}
+	return 0
 }
 
-func (x *WebhookRequest) Reset() {
-	*x = WebhookRequest{}
+func (x *InitiatePaymentResponse) GetWalletAmount() float64 {
+	if x != nil {
+		return x.WalletAmount
+	}
+	return 0
+}
+
+func (x *InitiatePaymentResponse) GetGatewayAmount() float64 {
+	if x != nil {
+		return x.GatewayAmount
+	}
+	return 0
+}
+
+// Request to charge an order to a card, either a one-time token or a saved card
+type InitiateCardPaymentRequest struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	OrderId              int32                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId               int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CardToken            string                 `protobuf:"bytes,3,opt,name=card_token,json=cardToken,proto3" json:"card_token,omitempty"`                                       // One-time token from Midtrans.js; card details never reach us
+	SaveCard             bool                   `protobuf:"varint,4,opt,name=save_card,json=saveCard,proto3" json:"save_card,omitempty"`                                         // Keep the gateway-issued token for one-click checkout
+	SavedPaymentMethodId int32                  `protobuf:"varint,5,opt,name=saved_payment_method_id,json=savedPaymentMethodId,proto3" json:"saved_payment_method_id,omitempty"` // Charge a previously saved card instead of card_token
+	Livemode             bool                   `protobuf:"varint,6,opt,name=livemode,proto3" json:"livemode,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *InitiateCardPaymentRequest) Reset() {
+	*x = InitiateCardPaymentRequest{}
 	mi := &file_proto_payment_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WebhookRequest) String() string {
+func (x *InitiateCardPaymentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WebhookRequest) ProtoMessage() {}
+func (*InitiateCardPaymentRequest) ProtoMessage() {}
 
-func (x *WebhookRequest) ProtoReflect() protoreflect.Message {
+func (x *InitiateCardPaymentRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_payment_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -511,89 +580,186 @@ func (x *WebhookRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WebhookRequest.ProtoReflect.Descriptor instead.
-func (*WebhookRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use InitiateCardPaymentRequest.ProtoReflect.Descriptor instead.
+func (*InitiateCardPaymentRequest) Descriptor() ([]byte, []int) {
 	return file_proto_payment_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *WebhookRequest) GetOrderId() string {
+func (x *InitiateCardPaymentRequest) GetOrderId() int32 {
 	if x != nil {
 		return x.OrderId
 	}
-	return ""
+	return 0
 }
 
-func (x *WebhookRequest) GetTransactionId() string {
+func (x *InitiateCardPaymentRequest) GetUserId() int32 {
 	if x != nil {
-		return x.TransactionId
+		return x.UserId
 	}
-	return ""
+	return 0
 }
 
-func (x *WebhookRequest) GetTransactionStatus() string {
+func (x *InitiateCardPaymentRequest) GetCardToken() string {
 	if x != nil {
-		return x.TransactionStatus
+		return x.CardToken
 	}
 	return ""
 }
 
-func (x *WebhookRequest) GetPaymentType() string {
+func (x *InitiateCardPaymentRequest) GetSaveCard() bool {
 	if x != nil {
-		return x.PaymentType
+		return x.SaveCard
 	}
-	return ""
+	return false
 }
 
-func (x *WebhookRequest) GetGrossAmount() string {
+func (x *InitiateCardPaymentRequest) GetSavedPaymentMethodId() int32 {
 	if x != nil {
-		return x.GrossAmount
+		return x.SavedPaymentMethodId
 	}
-	return ""
+	return 0
 }
 
-func (x *WebhookRequest) GetSignatureKey() string {
+func (x *InitiateCardPaymentRequest) GetLivemode() bool {
 	if x != nil {
-		return x.SignatureKey
+		return x.Livemode
+	}
+	return false
+}
+
+// Saved card - a gateway token plus display details, never the raw card
+type SavedPaymentMethod struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MaskedPan     string                 `protobuf:"bytes,3,opt,name=masked_pan,json=maskedPan,proto3" json:"masked_pan,omitempty"`
+	Brand         string                 `protobuf:"bytes,4,opt,name=brand,proto3" json:"brand,omitempty"`
+	ExpiryMonth   int32                  `protobuf:"varint,5,opt,name=expiry_month,json=expiryMonth,proto3" json:"expiry_month,omitempty"`
+	ExpiryYear    int32                  `protobuf:"varint,6,opt,name=expiry_year,json=expiryYear,proto3" json:"expiry_year,omitempty"`
+	IsDefault     bool                   `protobuf:"varint,7,opt,name=is_default,json=isDefault,proto3" json:"is_default,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Livemode      bool                   `protobuf:"varint,9,opt,name=livemode,proto3" json:"livemode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SavedPaymentMethod) Reset() {
+	*x = SavedPaymentMethod{}
+	mi := &file_proto_payment_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SavedPaymentMethod) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SavedPaymentMethod) ProtoMessage() {}
+
+func (x *SavedPaymentMethod) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SavedPaymentMethod.ProtoReflect.Descriptor instead.
+func (*SavedPaymentMethod) Descriptor() ([]byte, []int) {
+	return file_proto_payment_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SavedPaymentMethod) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SavedPaymentMethod) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *SavedPaymentMethod) GetMaskedPan() string {
+	if x != nil {
+		return x.MaskedPan
 	}
 	return ""
 }
 
-func (x *WebhookRequest) GetFraudStatus() string {
+func (x *SavedPaymentMethod) GetBrand() string {
 	if x != nil {
-		return x.FraudStatus
+		return x.Brand
 	}
 	return ""
 }
 
-func (x *WebhookRequest) GetStatusCode() string {
+func (x *SavedPaymentMethod) GetExpiryMonth() int32 {
 	if x != nil {
-		return x.StatusCode
+		return x.ExpiryMonth
+	}
+	return 0
+}
+
+func (x *SavedPaymentMethod) GetExpiryYear() int32 {
+	if x != nil {
+		return x.ExpiryYear
+	}
+	return 0
+}
+
+func (x *SavedPaymentMethod) GetIsDefault() bool {
+	if x != nil {
+		return x.IsDefault
+	}
+	return false
+}
+
+func (x *SavedPaymentMethod) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
 	}
 	return ""
 }
 
-// Generic empty response
-type EmptyPayment struct {
+func (x *SavedPaymentMethod) GetLivemode() bool {
+	if x != nil {
+		return x.Livemode
+	}
+	return false
+}
+
+// Request to list a user's saved cards
+type ListSavedPaymentMethodsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Livemode      bool                   `protobuf:"varint,2,opt,name=livemode,proto3" json:"livemode,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *EmptyPayment) Reset() {
-	*x = EmptyPayment{}
-	mi := &file_proto_payment_proto_msgTypes[6]
+func (x *ListSavedPaymentMethodsRequest) Reset() {
+	*x = ListSavedPaymentMethodsRequest{}
+	mi := &file_proto_payment_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *EmptyPayment) String() string {
+func (x *ListSavedPaymentMethodsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EmptyPayment) ProtoMessage() {}
+func (*ListSavedPaymentMethodsRequest) ProtoMessage() {}
 
-func (x *EmptyPayment) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_proto_msgTypes[6]
+func (x *ListSavedPaymentMethodsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -604,75 +770,1507 @@ func (x *EmptyPayment) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EmptyPayment.ProtoReflect.Descriptor instead.
-func (*EmptyPayment) Descriptor() ([]byte, []int) {
-	return file_proto_payment_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ListSavedPaymentMethodsRequest.ProtoReflect.Descriptor instead.
+func (*ListSavedPaymentMethodsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_payment_proto_rawDescGZIP(), []int{7}
 }
 
-var File_proto_payment_proto protoreflect.FileDescriptor
+func (x *ListSavedPaymentMethodsRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
 
-const file_proto_payment_proto_rawDesc = "" +
-	"\n" +
-	"\x13proto/payment.proto\x12\apayment\"\xc6\x04\n" +
-	"\x0fPaymentResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x19\n" +
-	"\border_id\x18\x02 \x01(\x05R\aorderId\x12\x16\n" +
-	"\x06amount\x18\x03 \x01(\x01R\x06amount\x12\x1a\n" +
-	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12%\n" +
-	"\x0epayment_method\x18\x05 \x01(\tR\rpaymentMethod\x12'\n" +
-	"\x0fpayment_channel\x18\x06 \x01(\tR\x0epaymentChannel\x12!\n" +
-	"\fgateway_name\x18\a \x01(\tR\vgatewayName\x124\n" +
-	"\x16gateway_transaction_id\x18\b \x01(\tR\x14gatewayTransactionId\x12(\n" +
-	"\x10gateway_order_id\x18\t \x01(\tR\x0egatewayOrderId\x12#\n" +
-	"\rgateway_token\x18\n" +
-	" \x01(\tR\fgatewayToken\x120\n" +
-	"\x14gateway_redirect_url\x18\v \x01(\tR\x12gatewayRedirectUrl\x12\x1b\n" +
-	"\tva_number\x18\f \x01(\tR\bvaNumber\x12\x1e\n" +
-	"\vqr_code_url\x18\r \x01(\tR\tqrCodeUrl\x12\x16\n" +
-	"\x06status\x18\x0e \x01(\tR\x06status\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\x0f \x01(\tR\tcreatedAt\x12\x17\n" +
-	"\apaid_at\x18\x10 \x01(\tR\x06paidAt\x12\x1d\n" +
-	"\n" +
-	"expired_at\x18\x11 \x01(\tR\texpiredAt\"I\n" +
-	"\x14CreatePaymentRequest\x12\x19\n" +
-	"\border_id\x18\x01 \x01(\x05R\aorderId\x12\x16\n" +
-	"\x06amount\x18\x02 \x01(\x01R\x06amount\"7\n" +
-	"\x1aGetPaymentByOrderIdRequest\x12\x19\n" +
-	"\border_id\x18\x01 \x01(\x05R\aorderId\"\xf6\x01\n" +
-	"\x16InitiatePaymentRequest\x12\x19\n" +
-	"\border_id\x18\x01 \x01(\x05R\aorderId\x12%\n" +
-	"\x0epayment_method\x18\x02 \x01(\tR\rpaymentMethod\x12'\n" +
-	"\x0fpayment_channel\x18\x03 \x01(\tR\x0epaymentChannel\x12#\n" +
-	"\rcustomer_name\x18\x04 \x01(\tR\fcustomerName\x12%\n" +
-	"\x0ecustomer_email\x18\x05 \x01(\tR\rcustomerEmail\x12%\n" +
-	"\x0ecustomer_phone\x18\x06 \x01(\tR\rcustomerPhone\"\x83\x02\n" +
-	"\x17InitiatePaymentResponse\x12\x1d\n" +
-	"\n" +
-	"payment_id\x18\x01 \x01(\x05R\tpaymentId\x12#\n" +
-	"\rgateway_token\x18\x02 \x01(\tR\fgatewayToken\x120\n" +
-	"\x14gateway_redirect_url\x18\x03 \x01(\tR\x12gatewayRedirectUrl\x12\x1b\n" +
-	"\tva_number\x18\x04 \x01(\tR\bvaNumber\x12\x1e\n" +
-	"\vqr_code_url\x18\x05 \x01(\tR\tqrCodeUrl\x12\x1d\n" +
-	"\n" +
-	"expired_at\x18\x06 \x01(\tR\texpiredAt\x12\x16\n" +
-	"\x06status\x18\a \x01(\tR\x06status\"\xb0\x02\n" +
-	"\x0eWebhookRequest\x12\x19\n" +
-	"\border_id\x18\x01 \x01(\tR\aorderId\x12%\n" +
-	"\x0etransaction_id\x18\x02 \x01(\tR\rtransactionId\x12-\n" +
-	"\x12transaction_status\x18\x03 \x01(\tR\x11transactionStatus\x12!\n" +
-	"\fpayment_type\x18\x04 \x01(\tR\vpaymentType\x12!\n" +
-	"\fgross_amount\x18\x05 \x01(\tR\vgrossAmount\x12#\n" +
-	"\rsignature_key\x18\x06 \x01(\tR\fsignatureKey\x12!\n" +
-	"\ffraud_status\x18\a \x01(\tR\vfraudStatus\x12\x1f\n" +
-	"\vstatus_code\x18\b \x01(\tR\n" +
-	"statusCode\"\x0e\n" +
-	"\fEmptyPayment2\xc7\x02\n" +
+func (x *ListSavedPaymentMethodsRequest) GetLivemode() bool {
+	if x != nil {
+		return x.Livemode
+	}
+	return false
+}
+
+// Saved cards of a user, default first
+type ListSavedPaymentMethodsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Methods       []*SavedPaymentMethod  `protobuf:"bytes,1,rep,name=methods,proto3" json:"methods,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSavedPaymentMethodsResponse) Reset() {
+	*x = ListSavedPaymentMethodsResponse{}
+	mi := &file_proto_payment_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSavedPaymentMethodsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSavedPaymentMethodsResponse) ProtoMessage() {}
+
+func (x *ListSavedPaymentMethodsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSavedPaymentMethodsResponse.ProtoReflect.Descriptor instead.
+func (*ListSavedPaymentMethodsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_payment_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListSavedPaymentMethodsResponse) GetMethods() []*SavedPaymentMethod {
+	if x != nil {
+		return x.Methods
+	}
+	return nil
+}
+
+// Request to delete a saved card owned by a user
+type DeleteSavedPaymentMethodRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSavedPaymentMethodRequest) Reset() {
+	*x = DeleteSavedPaymentMethodRequest{}
+	mi := &file_proto_payment_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSavedPaymentMethodRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSavedPaymentMethodRequest) ProtoMessage() {}
+
+func (x *DeleteSavedPaymentMethodRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSavedPaymentMethodRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSavedPaymentMethodRequest) Descriptor() ([]byte, []int) {
+	return file_proto_payment_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteSavedPaymentMethodRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeleteSavedPaymentMethodRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+// Webhook request from Midtrans
+type WebhookRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	OrderId           string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	TransactionId     string                 `protobuf:"bytes,2,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	TransactionStatus string                 `protobuf:"bytes,3,opt,name=transaction_status,json=transactionStatus,proto3" json:"transaction_status,omitempty"`
+	PaymentType       string                 `protobuf:"bytes,4,opt,name=payment_type,json=paymentType,proto3" json:"payment_type,omitempty"`
+	GrossAmount       string                 `protobuf:"bytes,5,opt,name=gross_amount,json=grossAmount,proto3" json:"gross_amount,omitempty"`
+	SignatureKey      string                 `protobuf:"bytes,6,opt,name=signature_key,json=signatureKey,proto3" json:"signature_key,omitempty"`
+	FraudStatus       string                 `protobuf:"bytes,7,opt,name=fraud_status,json=fraudStatus,proto3" json:"fraud_status,omitempty"`
+	StatusCode        string                 `protobuf:"bytes,8,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *WebhookRequest) Reset() {
+	*x = WebhookRequest{}
+	mi := &file_proto_payment_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookRequest) ProtoMessage() {}
+
+func (x *WebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookRequest.ProtoReflect.Descriptor instead.
+func (*WebhookRequest) Descriptor() ([]byte, []int) {
+	return file_proto_payment_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WebhookRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *WebhookRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *WebhookRequest) GetTransactionStatus() string {
+	if x != nil {
+		return x.TransactionStatus
+	}
+	return ""
+}
+
+func (x *WebhookRequest) GetPaymentType() string {
+	if x != nil {
+		return x.PaymentType
+	}
+	return ""
+}
+
+func (x *WebhookRequest) GetGrossAmount() string {
+	if x != nil {
+		return x.GrossAmount
+	}
+	return ""
+}
+
+func (x *WebhookRequest) GetSignatureKey() string {
+	if x != nil {
+		return x.SignatureKey
+	}
+	return ""
+}
+
+func (x *WebhookRequest) GetFraudStatus() string {
+	if x != nil {
+		return x.FraudStatus
+	}
+	return ""
+}
+
+func (x *WebhookRequest) GetStatusCode() string {
+	if x != nil {
+		return x.StatusCode
+	}
+	return ""
+}
+
+// Payment intent - one gateway transaction covering several orders
+type PaymentIntentResponse struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Id                   int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId               int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Amount               float64                `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	RefundedAmount       float64                `protobuf:"fixed64,4,opt,name=refunded_amount,json=refundedAmount,proto3" json:"refunded_amount,omitempty"`
+	Currency             string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	PaymentMethod        string                 `protobuf:"bytes,6,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	PaymentChannel       string                 `protobuf:"bytes,7,opt,name=payment_channel,json=paymentChannel,proto3" json:"payment_channel,omitempty"`
+	GatewayTransactionId string                 `protobuf:"bytes,8,opt,name=gateway_transaction_id,json=gatewayTransactionId,proto3" json:"gateway_transaction_id,omitempty"`
+	GatewayOrderId       string                 `protobuf:"bytes,9,opt,name=gateway_order_id,json=gatewayOrderId,proto3" json:"gateway_order_id,omitempty"`
+	GatewayToken         string                 `protobuf:"bytes,10,opt,name=gateway_token,json=gatewayToken,proto3" json:"gateway_token,omitempty"`
+	GatewayRedirectUrl   string                 `protobuf:"bytes,11,opt,name=gateway_redirect_url,json=gatewayRedirectUrl,proto3" json:"gateway_redirect_url,omitempty"`
+	Status               string                 `protobuf:"bytes,12,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt            string                 `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	PaidAt               string                 `protobuf:"bytes,14,opt,name=paid_at,json=paidAt,proto3" json:"paid_at,omitempty"`
+	ExpiredAt            string                 `protobuf:"bytes,15,opt,name=expired_at,json=expiredAt,proto3" json:"expired_at,omitempty"`
+	Payments             []*PaymentResponse     `protobuf:"bytes,16,rep,name=payments,proto3" json:"payments,omitempty"` // One per grouped order
+	Livemode             bool                   `protobuf:"varint,17,opt,name=livemode,proto3" json:"livemode,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *PaymentIntentResponse) Reset() {
+	*x = PaymentIntentResponse{}
+	mi := &file_proto_payment_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentIntentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentIntentResponse) ProtoMessage() {}
+
+func (x *PaymentIntentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentIntentResponse.ProtoReflect.Descriptor instead.
+func (*PaymentIntentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_payment_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PaymentIntentResponse) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PaymentIntentResponse) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *PaymentIntentResponse) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PaymentIntentResponse) GetRefundedAmount() float64 {
+	if x != nil {
+		return x.RefundedAmount
+	}
+	return 0
+}
+
+func (x *PaymentIntentResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PaymentIntentResponse) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
+func (x *PaymentIntentResponse) GetPaymentChannel() string {
+	if x != nil {
+		return x.PaymentChannel
+	}
+	return ""
+}
+
+func (x *PaymentIntentResponse) GetGatewayTransactionId() string {
+	if x != nil {
+		return x.GatewayTransactionId
+	}
+	return ""
+}
+
+func (x *PaymentIntentResponse) GetGatewayOrderId() string {
+	if x != nil {
+		return x.GatewayOrderId
+	}
+	return ""
+}
+
+func (x *PaymentIntentResponse) GetGatewayToken() string {
+	if x != nil {
+		return x.GatewayToken
+	}
+	return ""
+}
+
+func (x *PaymentIntentResponse) GetGatewayRedirectUrl() string {
+	if x != nil {
+		return x.GatewayRedirectUrl
+	}
+	return ""
+}
+
+func (x *PaymentIntentResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PaymentIntentResponse) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *PaymentIntentResponse) GetPaidAt() string {
+	if x != nil {
+		return x.PaidAt
+	}
+	return ""
+}
+
+func (x *PaymentIntentResponse) GetExpiredAt() string {
+	if x != nil {
+		return x.ExpiredAt
+	}
+	return ""
+}
+
+func (x *PaymentIntentResponse) GetPayments() []*PaymentResponse {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+func (x *PaymentIntentResponse) GetLivemode() bool {
+	if x != nil {
+		return x.Livemode
+	}
+	return false
+}
+
+// Request to pay several orders in one gateway transaction
+type CreatePaymentIntentRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderIds       []int32                `protobuf:"varint,2,rep,packed,name=order_ids,json=orderIds,proto3" json:"order_ids,omitempty"`
+	PaymentMethod  string                 `protobuf:"bytes,3,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	PaymentChannel string                 `protobuf:"bytes,4,opt,name=payment_channel,json=paymentChannel,proto3" json:"payment_channel,omitempty"`
+	Livemode       bool                   `protobuf:"varint,5,opt,name=livemode,proto3" json:"livemode,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreatePaymentIntentRequest) Reset() {
+	*x = CreatePaymentIntentRequest{}
+	mi := &file_proto_payment_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePaymentIntentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePaymentIntentRequest) ProtoMessage() {}
+
+func (x *CreatePaymentIntentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePaymentIntentRequest.ProtoReflect.Descriptor instead.
+func (*CreatePaymentIntentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_payment_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CreatePaymentIntentRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CreatePaymentIntentRequest) GetOrderIds() []int32 {
+	if x != nil {
+		return x.OrderIds
+	}
+	return nil
+}
+
+func (x *CreatePaymentIntentRequest) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
+func (x *CreatePaymentIntentRequest) GetPaymentChannel() string {
+	if x != nil {
+		return x.PaymentChannel
+	}
+	return ""
+}
+
+func (x *CreatePaymentIntentRequest) GetLivemode() bool {
+	if x != nil {
+		return x.Livemode
+	}
+	return false
+}
+
+// Request to get a payment intent owned by a user
+type GetPaymentIntentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IntentId      int32                  `protobuf:"varint,1,opt,name=intent_id,json=intentId,proto3" json:"intent_id,omitempty"`
+	UserId        int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPaymentIntentRequest) Reset() {
+	*x = GetPaymentIntentRequest{}
+	mi := &file_proto_payment_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentIntentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentIntentRequest) ProtoMessage() {}
+
+func (x *GetPaymentIntentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentIntentRequest.ProtoReflect.Descriptor instead.
+func (*GetPaymentIntentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_payment_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetPaymentIntentRequest) GetIntentId() int32 {
+	if x != nil {
+		return x.IntentId
+	}
+	return 0
+}
+
+func (x *GetPaymentIntentRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+// Request to refund part of an intent back to one of its orders
+type RefundPaymentIntentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IntentId      int32                  `protobuf:"varint,1,opt,name=intent_id,json=intentId,proto3" json:"intent_id,omitempty"`
+	OrderId       int32                  `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Amount        float64                `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	AsStoreCredit bool                   `protobuf:"varint,5,opt,name=as_store_credit,json=asStoreCredit,proto3" json:"as_store_credit,omitempty"` // Credit the customer's wallet instead of refunding through the gateway
+	Items         []*RefundItem          `protobuf:"bytes,6,rep,name=items,proto3" json:"items,omitempty"`                                         // Returned order items, put back in stock; amount defaults to what they were paid
+	Livemode      bool                   `protobuf:"varint,7,opt,name=livemode,proto3" json:"livemode,omitempty"`                                  // Mode of the credential; must match the intent's mode
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundPaymentIntentRequest) Reset() {
+	*x = RefundPaymentIntentRequest{}
+	mi := &file_proto_payment_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundPaymentIntentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundPaymentIntentRequest) ProtoMessage() {}
+
+func (x *RefundPaymentIntentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundPaymentIntentRequest.ProtoReflect.Descriptor instead.
+func (*RefundPaymentIntentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_payment_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RefundPaymentIntentRequest) GetIntentId() int32 {
+	if x != nil {
+		return x.IntentId
+	}
+	return 0
+}
+
+func (x *RefundPaymentIntentRequest) GetOrderId() int32 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *RefundPaymentIntentRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *RefundPaymentIntentRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RefundPaymentIntentRequest) GetAsStoreCredit() bool {
+	if x != nil {
+		return x.AsStoreCredit
+	}
+	return false
+}
+
+func (x *RefundPaymentIntentRequest) GetItems() []*RefundItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *RefundPaymentIntentRequest) GetLivemode() bool {
+	if x != nil {
+		return x.Livemode
+	}
+	return false
+}
+
+// Request to refund part of what was paid for an order, whether it was paid on its
+// own by Snap or card, from the wallet or as part of a payment intent
+type RefundPaymentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int32                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	AsStoreCredit bool                   `protobuf:"varint,4,opt,name=as_store_credit,json=asStoreCredit,proto3" json:"as_store_credit,omitempty"` // Credit the customer's wallet instead of refunding through the gateway
+	Livemode      bool                   `protobuf:"varint,5,opt,name=livemode,proto3" json:"livemode,omitempty"`                                  // Mode of the credential; must match the payment's mode
+	Items         []*RefundItem          `protobuf:"bytes,6,rep,name=items,proto3" json:"items,omitempty"`                                         // Returned order items, put back in stock; amount defaults to what they were paid
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundPaymentRequest) Reset() {
+	*x = RefundPaymentRequest{}
+	mi := &file_proto_payment_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundPaymentRequest) ProtoMessage() {}
+
+func (x *RefundPaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundPaymentRequest.ProtoReflect.Descriptor instead.
+func (*RefundPaymentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_payment_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RefundPaymentRequest) GetOrderId() int32 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *RefundPaymentRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *RefundPaymentRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RefundPaymentRequest) GetAsStoreCredit() bool {
+	if x != nil {
+		return x.AsStoreCredit
+	}
+	return false
+}
+
+func (x *RefundPaymentRequest) GetLivemode() bool {
+	if x != nil {
+		return x.Livemode
+	}
+	return false
+}
+
+func (x *RefundPaymentRequest) GetItems() []*RefundItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// RefundItem is an order item returned with a refund. A bundle item returns the
+// whole bundle; its component items can be returned on their own.
+type RefundItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderItemId   int32                  `protobuf:"varint,1,opt,name=order_item_id,json=orderItemId,proto3" json:"order_item_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundItem) Reset() {
+	*x = RefundItem{}
+	mi := &file_proto_payment_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundItem) ProtoMessage() {}
+
+func (x *RefundItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundItem.ProtoReflect.Descriptor instead.
+func (*RefundItem) Descriptor() ([]byte, []int) {
+	return file_proto_payment_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RefundItem) GetOrderItemId() int32 {
+	if x != nil {
+		return x.OrderItemId
+	}
+	return 0
+}
+
+func (x *RefundItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+// Refund allocated to a single order, of an intent or paid on its own
+type RefundResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	PaymentIntentId int32                  `protobuf:"varint,2,opt,name=payment_intent_id,json=paymentIntentId,proto3" json:"payment_intent_id,omitempty"` // 0 when the order was paid on its own
+	PaymentId       int32                  `protobuf:"varint,3,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	OrderId         int32                  `protobuf:"varint,4,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Amount          float64                `protobuf:"fixed64,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	Reason          string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	RefundKey       string                 `protobuf:"bytes,7,opt,name=refund_key,json=refundKey,proto3" json:"refund_key,omitempty"`
+	Status          string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"` // pending while the gateway refunds it, then refunded or failed; credited for store credit
+	CreatedAt       string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RefundResponse) Reset() {
+	*x = RefundResponse{}
+	mi := &file_proto_payment_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundResponse) ProtoMessage() {}
+
+func (x *RefundResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundResponse.ProtoReflect.Descriptor instead.
+func (*RefundResponse) Descriptor() ([]byte, []int) {
+	return file_proto_payment_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RefundResponse) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RefundResponse) GetPaymentIntentId() int32 {
+	if x != nil {
+		return x.PaymentIntentId
+	}
+	return 0
+}
+
+func (x *RefundResponse) GetPaymentId() int32 {
+	if x != nil {
+		return x.PaymentId
+	}
+	return 0
+}
+
+func (x *RefundResponse) GetOrderId() int32 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *RefundResponse) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *RefundResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RefundResponse) GetRefundKey() string {
+	if x != nil {
+		return x.RefundKey
+	}
+	return ""
+}
+
+func (x *RefundResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RefundResponse) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+// Wallet balance change; the log is append-only
+type WalletTransaction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	WalletId      int32                  `protobuf:"varint,2,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`       // topup, payment, payment_reversal, refund_credit
+	Amount        float64                `protobuf:"fixed64,4,opt,name=amount,proto3" json:"amount,omitempty"` // Positive credits, negative debits
+	BalanceAfter  float64                `protobuf:"fixed64,5,opt,name=balance_after,json=balanceAfter,proto3" json:"balance_after,omitempty"`
+	ReferenceType string                 `protobuf:"bytes,6,opt,name=reference_type,json=referenceType,proto3" json:"reference_type,omitempty"` // wallet_topup, payment, payment_refund
+	ReferenceId   int32                  `protobuf:"varint,7,opt,name=reference_id,json=referenceId,proto3" json:"reference_id,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WalletTransaction) Reset() {
+	*x = WalletTransaction{}
+	mi := &file_proto_payment_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletTransaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletTransaction) ProtoMessage() {}
+
+func (x *WalletTransaction) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletTransaction.ProtoReflect.Descriptor instead.
+func (*WalletTransaction) Descriptor() ([]byte, []int) {
+	return file_proto_payment_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *WalletTransaction) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *WalletTransaction) GetWalletId() int32 {
+	if x != nil {
+		return x.WalletId
+	}
+	return 0
+}
+
+func (x *WalletTransaction) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *WalletTransaction) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *WalletTransaction) GetBalanceAfter() float64 {
+	if x != nil {
+		return x.BalanceAfter
+	}
+	return 0
+}
+
+func (x *WalletTransaction) GetReferenceType() string {
+	if x != nil {
+		return x.ReferenceType
+	}
+	return ""
+}
+
+func (x *WalletTransaction) GetReferenceId() int32 {
+	if x != nil {
+		return x.ReferenceId
+	}
+	return 0
+}
+
+func (x *WalletTransaction) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+// Store credit wallet of a user in one mode
+type WalletResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Balance       float64                `protobuf:"fixed64,3,opt,name=balance,proto3" json:"balance,omitempty"`
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	Livemode      bool                   `protobuf:"varint,5,opt,name=livemode,proto3" json:"livemode,omitempty"`
+	Transactions  []*WalletTransaction   `protobuf:"bytes,6,rep,name=transactions,proto3" json:"transactions,omitempty"` // Most recent first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WalletResponse) Reset() {
+	*x = WalletResponse{}
+	mi := &file_proto_payment_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletResponse) ProtoMessage() {}
+
+func (x *WalletResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletResponse.ProtoReflect.Descriptor instead.
+func (*WalletResponse) Descriptor() ([]byte, []int) {
+	return file_proto_payment_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WalletResponse) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *WalletResponse) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *WalletResponse) GetBalance() float64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *WalletResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *WalletResponse) GetLivemode() bool {
+	if x != nil {
+		return x.Livemode
+	}
+	return false
+}
+
+func (x *WalletResponse) GetTransactions() []*WalletTransaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+// Request to get a user's wallet
+type GetWalletRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Livemode      bool                   `protobuf:"varint,2,opt,name=livemode,proto3" json:"livemode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWalletRequest) Reset() {
+	*x = GetWalletRequest{}
+	mi := &file_proto_payment_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWalletRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWalletRequest) ProtoMessage() {}
+
+func (x *GetWalletRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWalletRequest.ProtoReflect.Descriptor instead.
+func (*GetWalletRequest) Descriptor() ([]byte, []int) {
+	return file_proto_payment_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetWalletRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *GetWalletRequest) GetLivemode() bool {
+	if x != nil {
+		return x.Livemode
+	}
+	return false
+}
+
+// Request to top up a wallet through the gateway
+type TopUpWalletRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Livemode      bool                   `protobuf:"varint,3,opt,name=livemode,proto3" json:"livemode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TopUpWalletRequest) Reset() {
+	*x = TopUpWalletRequest{}
+	mi := &file_proto_payment_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TopUpWalletRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopUpWalletRequest) ProtoMessage() {}
+
+func (x *TopUpWalletRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopUpWalletRequest.ProtoReflect.Descriptor instead.
+func (*TopUpWalletRequest) Descriptor() ([]byte, []int) {
+	return file_proto_payment_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *TopUpWalletRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *TopUpWalletRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *TopUpWalletRequest) GetLivemode() bool {
+	if x != nil {
+		return x.Livemode
+	}
+	return false
+}
+
+// Pending top-up; the wallet is credited once the gateway confirms payment
+type WalletTopUpResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	WalletId           int32                  `protobuf:"varint,2,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
+	Amount             float64                `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	GatewayToken       string                 `protobuf:"bytes,4,opt,name=gateway_token,json=gatewayToken,proto3" json:"gateway_token,omitempty"`
+	GatewayRedirectUrl string                 `protobuf:"bytes,5,opt,name=gateway_redirect_url,json=gatewayRedirectUrl,proto3" json:"gateway_redirect_url,omitempty"`
+	Status             string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	ExpiredAt          string                 `protobuf:"bytes,7,opt,name=expired_at,json=expiredAt,proto3" json:"expired_at,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *WalletTopUpResponse) Reset() {
+	*x = WalletTopUpResponse{}
+	mi := &file_proto_payment_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletTopUpResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletTopUpResponse) ProtoMessage() {}
+
+func (x *WalletTopUpResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletTopUpResponse.ProtoReflect.Descriptor instead.
+func (*WalletTopUpResponse) Descriptor() ([]byte, []int) {
+	return file_proto_payment_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *WalletTopUpResponse) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *WalletTopUpResponse) GetWalletId() int32 {
+	if x != nil {
+		return x.WalletId
+	}
+	return 0
+}
+
+func (x *WalletTopUpResponse) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *WalletTopUpResponse) GetGatewayToken() string {
+	if x != nil {
+		return x.GatewayToken
+	}
+	return ""
+}
+
+func (x *WalletTopUpResponse) GetGatewayRedirectUrl() string {
+	if x != nil {
+		return x.GatewayRedirectUrl
+	}
+	return ""
+}
+
+func (x *WalletTopUpResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WalletTopUpResponse) GetExpiredAt() string {
+	if x != nil {
+		return x.ExpiredAt
+	}
+	return ""
+}
+
+// Generic empty response
+type EmptyPayment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmptyPayment) Reset() {
+	*x = EmptyPayment{}
+	mi := &file_proto_payment_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmptyPayment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmptyPayment) ProtoMessage() {}
+
+func (x *EmptyPayment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmptyPayment.ProtoReflect.Descriptor instead.
+func (*EmptyPayment) Descriptor() ([]byte, []int) {
+	return file_proto_payment_proto_rawDescGZIP(), []int{23}
+}
+
+var File_proto_payment_proto protoreflect.FileDescriptor
+
+const file_proto_payment_proto_rawDesc = "" +
+	"\n" +
+	"\x13proto/payment.proto\x12\apayment\"\xf5\x05\n" +
+	"\x0fPaymentResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\x05R\aorderId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x01R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12%\n" +
+	"\x0epayment_method\x18\x05 \x01(\tR\rpaymentMethod\x12'\n" +
+	"\x0fpayment_channel\x18\x06 \x01(\tR\x0epaymentChannel\x12!\n" +
+	"\fgateway_name\x18\a \x01(\tR\vgatewayName\x124\n" +
+	"\x16gateway_transaction_id\x18\b \x01(\tR\x14gatewayTransactionId\x12(\n" +
+	"\x10gateway_order_id\x18\t \x01(\tR\x0egatewayOrderId\x12#\n" +
+	"\rgateway_token\x18\n" +
+	" \x01(\tR\fgatewayToken\x120\n" +
+	"\x14gateway_redirect_url\x18\v \x01(\tR\x12gatewayRedirectUrl\x12\x1b\n" +
+	"\tva_number\x18\f \x01(\tR\bvaNumber\x12\x1e\n" +
+	"\vqr_code_url\x18\r \x01(\tR\tqrCodeUrl\x12\x16\n" +
+	"\x06status\x18\x0e \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x0f \x01(\tR\tcreatedAt\x12\x17\n" +
+	"\apaid_at\x18\x10 \x01(\tR\x06paidAt\x12\x1d\n" +
+	"\n" +
+	"expired_at\x18\x11 \x01(\tR\texpiredAt\x12*\n" +
+	"\x11payment_intent_id\x18\x12 \x01(\x05R\x0fpaymentIntentId\x12'\n" +
+	"\x0frefunded_amount\x18\x13 \x01(\x01R\x0erefundedAmount\x12\x1a\n" +
+	"\blivemode\x18\x14 \x01(\bR\blivemode\x12#\n" +
+	"\rwallet_amount\x18\x15 \x01(\x01R\fwalletAmount\x12\x17\n" +
+	"\auser_id\x18\x16 \x01(\x05R\x06userId\"~\n" +
+	"\x14CreatePaymentRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x05R\aorderId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12\x1a\n" +
+	"\blivemode\x18\x03 \x01(\bR\blivemode\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\x05R\x06userId\"7\n" +
+	"\x1aGetPaymentByOrderIdRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x05R\aorderId\"\xf9\x01\n" +
+	"\x16InitiatePaymentRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x05R\aorderId\x12%\n" +
+	"\x0epayment_method\x18\x02 \x01(\tR\rpaymentMethod\x12'\n" +
+	"\x0fpayment_channel\x18\x03 \x01(\tR\x0epaymentChannel\x12\x17\n" +
+	"\auser_id\x18\a \x01(\x05R\x06userId\x12\x1a\n" +
+	"\blivemode\x18\b \x01(\bR\blivemodeJ\x04\b\x04\x10\x05J\x04\b\x05\x10\x06J\x04\b\x06\x10\aR\rcustomer_nameR\x0ecustomer_emailR\x0ecustomer_phone\"\x86\x03\n" +
+	"\x17InitiatePaymentResponse\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\x05R\tpaymentId\x12#\n" +
+	"\rgateway_token\x18\x02 \x01(\tR\fgatewayToken\x120\n" +
+	"\x14gateway_redirect_url\x18\x03 \x01(\tR\x12gatewayRedirectUrl\x12\x1b\n" +
+	"\tva_number\x18\x04 \x01(\tR\bvaNumber\x12\x1e\n" +
+	"\vqr_code_url\x18\x05 \x01(\tR\tqrCodeUrl\x12\x1d\n" +
+	"\n" +
+	"expired_at\x18\x06 \x01(\tR\texpiredAt\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x125\n" +
+	"\x17saved_payment_method_id\x18\b \x01(\x05R\x14savedPaymentMethodId\x12#\n" +
+	"\rwallet_amount\x18\t \x01(\x01R\fwalletAmount\x12%\n" +
+	"\x0egateway_amount\x18\n" +
+	" \x01(\x01R\rgatewayAmount\"\xdf\x01\n" +
+	"\x1aInitiateCardPaymentRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x05R\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x05R\x06userId\x12\x1d\n" +
+	"\n" +
+	"card_token\x18\x03 \x01(\tR\tcardToken\x12\x1b\n" +
+	"\tsave_card\x18\x04 \x01(\bR\bsaveCard\x125\n" +
+	"\x17saved_payment_method_id\x18\x05 \x01(\x05R\x14savedPaymentMethodId\x12\x1a\n" +
+	"\blivemode\x18\x06 \x01(\bR\blivemode\"\x90\x02\n" +
+	"\x12SavedPaymentMethod\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x05R\x06userId\x12\x1d\n" +
+	"\n" +
+	"masked_pan\x18\x03 \x01(\tR\tmaskedPan\x12\x14\n" +
+	"\x05brand\x18\x04 \x01(\tR\x05brand\x12!\n" +
+	"\fexpiry_month\x18\x05 \x01(\x05R\vexpiryMonth\x12\x1f\n" +
+	"\vexpiry_year\x18\x06 \x01(\x05R\n" +
+	"expiryYear\x12\x1d\n" +
+	"\n" +
+	"is_default\x18\a \x01(\bR\tisDefault\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\x12\x1a\n" +
+	"\blivemode\x18\t \x01(\bR\blivemode\"U\n" +
+	"\x1eListSavedPaymentMethodsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12\x1a\n" +
+	"\blivemode\x18\x02 \x01(\bR\blivemode\"X\n" +
+	"\x1fListSavedPaymentMethodsResponse\x125\n" +
+	"\amethods\x18\x01 \x03(\v2\x1b.payment.SavedPaymentMethodR\amethods\"J\n" +
+	"\x1fDeleteSavedPaymentMethodRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x05R\x06userId\"\xb0\x02\n" +
+	"\x0eWebhookRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12%\n" +
+	"\x0etransaction_id\x18\x02 \x01(\tR\rtransactionId\x12-\n" +
+	"\x12transaction_status\x18\x03 \x01(\tR\x11transactionStatus\x12!\n" +
+	"\fpayment_type\x18\x04 \x01(\tR\vpaymentType\x12!\n" +
+	"\fgross_amount\x18\x05 \x01(\tR\vgrossAmount\x12#\n" +
+	"\rsignature_key\x18\x06 \x01(\tR\fsignatureKey\x12!\n" +
+	"\ffraud_status\x18\a \x01(\tR\vfraudStatus\x12\x1f\n" +
+	"\vstatus_code\x18\b \x01(\tR\n" +
+	"statusCode\"\xe5\x04\n" +
+	"\x15PaymentIntentResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x05R\x06userId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x01R\x06amount\x12'\n" +
+	"\x0frefunded_amount\x18\x04 \x01(\x01R\x0erefundedAmount\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12%\n" +
+	"\x0epayment_method\x18\x06 \x01(\tR\rpaymentMethod\x12'\n" +
+	"\x0fpayment_channel\x18\a \x01(\tR\x0epaymentChannel\x124\n" +
+	"\x16gateway_transaction_id\x18\b \x01(\tR\x14gatewayTransactionId\x12(\n" +
+	"\x10gateway_order_id\x18\t \x01(\tR\x0egatewayOrderId\x12#\n" +
+	"\rgateway_token\x18\n" +
+	" \x01(\tR\fgatewayToken\x120\n" +
+	"\x14gateway_redirect_url\x18\v \x01(\tR\x12gatewayRedirectUrl\x12\x16\n" +
+	"\x06status\x18\f \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\r \x01(\tR\tcreatedAt\x12\x17\n" +
+	"\apaid_at\x18\x0e \x01(\tR\x06paidAt\x12\x1d\n" +
+	"\n" +
+	"expired_at\x18\x0f \x01(\tR\texpiredAt\x124\n" +
+	"\bpayments\x18\x10 \x03(\v2\x18.payment.PaymentResponseR\bpayments\x12\x1a\n" +
+	"\blivemode\x18\x11 \x01(\bR\blivemode\"\xbe\x01\n" +
+	"\x1aCreatePaymentIntentRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12\x1b\n" +
+	"\torder_ids\x18\x02 \x03(\x05R\borderIds\x12%\n" +
+	"\x0epayment_method\x18\x03 \x01(\tR\rpaymentMethod\x12'\n" +
+	"\x0fpayment_channel\x18\x04 \x01(\tR\x0epaymentChannel\x12\x1a\n" +
+	"\blivemode\x18\x05 \x01(\bR\blivemode\"O\n" +
+	"\x17GetPaymentIntentRequest\x12\x1b\n" +
+	"\tintent_id\x18\x01 \x01(\x05R\bintentId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x05R\x06userId\"\xf3\x01\n" +
+	"\x1aRefundPaymentIntentRequest\x12\x1b\n" +
+	"\tintent_id\x18\x01 \x01(\x05R\bintentId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\x05R\aorderId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x01R\x06amount\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12&\n" +
+	"\x0fas_store_credit\x18\x05 \x01(\bR\rasStoreCredit\x12)\n" +
+	"\x05items\x18\x06 \x03(\v2\x13.payment.RefundItemR\x05items\x12\x1a\n" +
+	"\blivemode\x18\a \x01(\bR\blivemode\"\xd0\x01\n" +
+	"\x14RefundPaymentRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x05R\aorderId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12&\n" +
+	"\x0fas_store_credit\x18\x04 \x01(\bR\rasStoreCredit\x12\x1a\n" +
+	"\blivemode\x18\x05 \x01(\bR\blivemode\x12)\n" +
+	"\x05items\x18\x06 \x03(\v2\x13.payment.RefundItemR\x05items\"L\n" +
+	"\n" +
+	"RefundItem\x12\"\n" +
+	"\rorder_item_id\x18\x01 \x01(\x05R\vorderItemId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"\x8c\x02\n" +
+	"\x0eRefundResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12*\n" +
+	"\x11payment_intent_id\x18\x02 \x01(\x05R\x0fpaymentIntentId\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x03 \x01(\x05R\tpaymentId\x12\x19\n" +
+	"\border_id\x18\x04 \x01(\x05R\aorderId\x12\x16\n" +
+	"\x06amount\x18\x05 \x01(\x01R\x06amount\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"refund_key\x18\a \x01(\tR\trefundKey\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\"\xfa\x01\n" +
+	"\x11WalletTransaction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1b\n" +
+	"\twallet_id\x18\x02 \x01(\x05R\bwalletId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x01R\x06amount\x12#\n" +
+	"\rbalance_after\x18\x05 \x01(\x01R\fbalanceAfter\x12%\n" +
+	"\x0ereference_type\x18\x06 \x01(\tR\rreferenceType\x12!\n" +
+	"\freference_id\x18\a \x01(\x05R\vreferenceId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\"\xcb\x01\n" +
+	"\x0eWalletResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x05R\x06userId\x12\x18\n" +
+	"\abalance\x18\x03 \x01(\x01R\abalance\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x1a\n" +
+	"\blivemode\x18\x05 \x01(\bR\blivemode\x12>\n" +
+	"\ftransactions\x18\x06 \x03(\v2\x1a.payment.WalletTransactionR\ftransactions\"G\n" +
+	"\x10GetWalletRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12\x1a\n" +
+	"\blivemode\x18\x02 \x01(\bR\blivemode\"a\n" +
+	"\x12TopUpWalletRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12\x1a\n" +
+	"\blivemode\x18\x03 \x01(\bR\blivemode\"\xe8\x01\n" +
+	"\x13WalletTopUpResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1b\n" +
+	"\twallet_id\x18\x02 \x01(\x05R\bwalletId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x01R\x06amount\x12#\n" +
+	"\rgateway_token\x18\x04 \x01(\tR\fgatewayToken\x120\n" +
+	"\x14gateway_redirect_url\x18\x05 \x01(\tR\x12gatewayRedirectUrl\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"expired_at\x18\a \x01(\tR\texpiredAt\"\x0e\n" +
+	"\fEmptyPayment2\xcb\b\n" +
 	"\x0ePaymentService\x12H\n" +
 	"\rCreatePayment\x12\x1d.payment.CreatePaymentRequest\x1a\x18.payment.PaymentResponse\x12T\n" +
 	"\x13GetPaymentByOrderId\x12#.payment.GetPaymentByOrderIdRequest\x1a\x18.payment.PaymentResponse\x12T\n" +
 	"\x0fInitiatePayment\x12\x1f.payment.InitiatePaymentRequest\x1a .payment.InitiatePaymentResponse\x12?\n" +
-	"\rHandleWebhook\x12\x17.payment.WebhookRequest\x1a\x15.payment.EmptyPaymentB\n" +
+	"\rHandleWebhook\x12\x17.payment.WebhookRequest\x1a\x15.payment.EmptyPayment\x12Z\n" +
+	"\x13CreatePaymentIntent\x12#.payment.CreatePaymentIntentRequest\x1a\x1e.payment.PaymentIntentResponse\x12T\n" +
+	"\x10GetPaymentIntent\x12 .payment.GetPaymentIntentRequest\x1a\x1e.payment.PaymentIntentResponse\x12S\n" +
+	"\x13RefundPaymentIntent\x12#.payment.RefundPaymentIntentRequest\x1a\x17.payment.RefundResponse\x12G\n" +
+	"\rRefundPayment\x12\x1d.payment.RefundPaymentRequest\x1a\x17.payment.RefundResponse\x12\\\n" +
+	"\x13InitiateCardPayment\x12#.payment.InitiateCardPaymentRequest\x1a .payment.InitiatePaymentResponse\x12l\n" +
+	"\x17ListSavedPaymentMethods\x12'.payment.ListSavedPaymentMethodsRequest\x1a(.payment.ListSavedPaymentMethodsResponse\x12[\n" +
+	"\x18DeleteSavedPaymentMethod\x12(.payment.DeleteSavedPaymentMethodRequest\x1a\x15.payment.EmptyPayment\x12?\n" +
+	"\tGetWallet\x12\x19.payment.GetWalletRequest\x1a\x17.payment.WalletResponse\x12H\n" +
+	"\vTopUpWallet\x12\x1b.payment.TopUpWalletRequest\x1a\x1c.payment.WalletTopUpResponseB\n" +
 	"Z\b../protob\x06proto3"
 
 var (
@@ -687,30 +2285,70 @@ func file_proto_payment_proto_rawDescGZIP() []byte {
 	return file_proto_payment_proto_rawDescData
 }
 
-var file_proto_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_proto_payment_proto_goTypes = []any{
-	(*PaymentResponse)(nil),            // 0: payment.PaymentResponse
-	(*CreatePaymentRequest)(nil),       // 1: payment.CreatePaymentRequest
-	(*GetPaymentByOrderIdRequest)(nil), // 2: payment.GetPaymentByOrderIdRequest
-	(*InitiatePaymentRequest)(nil),     // 3: payment.InitiatePaymentRequest
-	(*InitiatePaymentResponse)(nil),    // 4: payment.InitiatePaymentResponse
-	(*WebhookRequest)(nil),             // 5: payment.WebhookRequest
-	(*EmptyPayment)(nil),               // 6: payment.EmptyPayment
+	(*PaymentResponse)(nil),                 // 0: payment.PaymentResponse
+	(*CreatePaymentRequest)(nil),            // 1: payment.CreatePaymentRequest
+	(*GetPaymentByOrderIdRequest)(nil),      // 2: payment.GetPaymentByOrderIdRequest
+	(*InitiatePaymentRequest)(nil),          // 3: payment.InitiatePaymentRequest
+	(*InitiatePaymentResponse)(nil),         // 4: payment.InitiatePaymentResponse
+	(*InitiateCardPaymentRequest)(nil),      // 5: payment.InitiateCardPaymentRequest
+	(*SavedPaymentMethod)(nil),              // 6: payment.SavedPaymentMethod
+	(*ListSavedPaymentMethodsRequest)(nil),  // 7: payment.ListSavedPaymentMethodsRequest
+	(*ListSavedPaymentMethodsResponse)(nil), // 8: payment.ListSavedPaymentMethodsResponse
+	(*DeleteSavedPaymentMethodRequest)(nil), // 9: payment.DeleteSavedPaymentMethodRequest
+	(*WebhookRequest)(nil),                  // 10: payment.WebhookRequest
+	(*PaymentIntentResponse)(nil),           // 11: payment.PaymentIntentResponse
+	(*CreatePaymentIntentRequest)(nil),      // 12: payment.CreatePaymentIntentRequest
+	(*GetPaymentIntentRequest)(nil),         // 13: payment.GetPaymentIntentRequest
+	(*RefundPaymentIntentRequest)(nil),      // 14: payment.RefundPaymentIntentRequest
+	(*RefundPaymentRequest)(nil),            // 15: payment.RefundPaymentRequest
+	(*RefundItem)(nil),                      // 16: payment.RefundItem
+	(*RefundResponse)(nil),                  // 17: payment.RefundResponse
+	(*WalletTransaction)(nil),               // 18: payment.WalletTransaction
+	(*WalletResponse)(nil),                  // 19: payment.WalletResponse
+	(*GetWalletRequest)(nil),                // 20: payment.GetWalletRequest
+	(*TopUpWalletRequest)(nil),              // 21: payment.TopUpWalletRequest
+	(*WalletTopUpResponse)(nil),             // 22: payment.WalletTopUpResponse
+	(*EmptyPayment)(nil),                    // 23: payment.EmptyPayment
 }
 var file_proto_payment_proto_depIdxs = []int32{
-	1, // 0: payment.PaymentService.CreatePayment:input_type -> payment.CreatePaymentRequest
-	2, // 1: payment.PaymentService.GetPaymentByOrderId:input_type -> payment.GetPaymentByOrderIdRequest
-	3, // 2: payment.PaymentService.InitiatePayment:input_type -> payment.InitiatePaymentRequest
-	5, // 3: payment.PaymentService.HandleWebhook:input_type -> payment.WebhookRequest
-	0, // 4: payment.PaymentService.CreatePayment:output_type -> payment.PaymentResponse
-	0, // 5: payment.PaymentService.GetPaymentByOrderId:output_type -> payment.PaymentResponse
-	4, // 6: payment.PaymentService.InitiatePayment:output_type -> payment.InitiatePaymentResponse
-	6, // 7: payment.PaymentService.HandleWebhook:output_type -> payment.EmptyPayment
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	6,  // 0: payment.ListSavedPaymentMethodsResponse.methods:type_name -> payment.SavedPaymentMethod
+	0,  // 1: payment.PaymentIntentResponse.payments:type_name -> payment.PaymentResponse
+	16, // 2: payment.RefundPaymentIntentRequest.items:type_name -> payment.RefundItem
+	16, // 3: payment.RefundPaymentRequest.items:type_name -> payment.RefundItem
+	18, // 4: payment.WalletResponse.transactions:type_name -> payment.WalletTransaction
+	1,  // 5: payment.PaymentService.CreatePayment:input_type -> payment.CreatePaymentRequest
+	2,  // 6: payment.PaymentService.GetPaymentByOrderId:input_type -> payment.GetPaymentByOrderIdRequest
+	3,  // 7: payment.PaymentService.InitiatePayment:input_type -> payment.InitiatePaymentRequest
+	10, // 8: payment.PaymentService.HandleWebhook:input_type -> payment.WebhookRequest
+	12, // 9: payment.PaymentService.CreatePaymentIntent:input_type -> payment.CreatePaymentIntentRequest
+	13, // 10: payment.PaymentService.GetPaymentIntent:input_type -> payment.GetPaymentIntentRequest
+	14, // 11: payment.PaymentService.RefundPaymentIntent:input_type -> payment.RefundPaymentIntentRequest
+	15, // 12: payment.PaymentService.RefundPayment:input_type -> payment.RefundPaymentRequest
+	5,  // 13: payment.PaymentService.InitiateCardPayment:input_type -> payment.InitiateCardPaymentRequest
+	7,  // 14: payment.PaymentService.ListSavedPaymentMethods:input_type -> payment.ListSavedPaymentMethodsRequest
+	9,  // 15: payment.PaymentService.DeleteSavedPaymentMethod:input_type -> payment.DeleteSavedPaymentMethodRequest
+	20, // 16: payment.PaymentService.GetWallet:input_type -> payment.GetWalletRequest
+	21, // 17: payment.PaymentService.TopUpWallet:input_type -> payment.TopUpWalletRequest
+	0,  // 18: payment.PaymentService.CreatePayment:output_type -> payment.PaymentResponse
+	0,  // 19: payment.PaymentService.GetPaymentByOrderId:output_type -> payment.PaymentResponse
+	4,  // 20: payment.PaymentService.InitiatePayment:output_type -> payment.InitiatePaymentResponse
+	23, // 21: payment.PaymentService.HandleWebhook:output_type -> payment.EmptyPayment
+	11, // 22: payment.PaymentService.CreatePaymentIntent:output_type -> payment.PaymentIntentResponse
+	11, // 23: payment.PaymentService.GetPaymentIntent:output_type -> payment.PaymentIntentResponse
+	17, // 24: payment.PaymentService.RefundPaymentIntent:output_type -> payment.RefundResponse
+	17, // 25: payment.PaymentService.RefundPayment:output_type -> payment.RefundResponse
+	4,  // 26: payment.PaymentService.InitiateCardPayment:output_type -> payment.InitiatePaymentResponse
+	8,  // 27: payment.PaymentService.ListSavedPaymentMethods:output_type -> payment.ListSavedPaymentMethodsResponse
+	23, // 28: payment.PaymentService.DeleteSavedPaymentMethod:output_type -> payment.EmptyPayment
+	19, // 29: payment.PaymentService.GetWallet:output_type -> payment.WalletResponse
+	22, // 30: payment.PaymentService.TopUpWallet:output_type -> payment.WalletTopUpResponse
+	18, // [18:31] is the sub-list for method output_type
+	5,  // [5:18] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_proto_payment_proto_init() }
@@ -724,7 +2362,7 @@ func file_proto_payment_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_payment_proto_rawDesc), len(file_proto_payment_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
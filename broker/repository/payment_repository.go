@@ -1,61 +1,134 @@
-package repository
-
-import (
-	"broker/proto"
-	"context"
-	"os"
-	"time"
-
-	"github.com/sirupsen/logrus"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-)
-
-type PaymentRepository interface {
-	GetPaymentByOrderId(orderID int32) (*proto.PaymentResponse, error)
-	InitiatePayment(req *proto.InitiatePaymentRequest) (*proto.InitiatePaymentResponse, error)
-	HandleWebhook(req *proto.WebhookRequest) error
-}
-
-type PaymentRepositoryImpl struct {
-	client proto.PaymentServiceClient
-}
-
-func NewPaymentRepository() *PaymentRepositoryImpl {
-	addr := os.Getenv("PAYMENT_SERVICE_ADDR")
-	if addr == "" {
-		addr = "payment-service:60001"
-	}
-
-	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		logrus.Fatalf("Failed to connect to payment service: %v", err)
-	}
-
-	logrus.Infof("Connected to payment service at %s", addr)
-	client := proto.NewPaymentServiceClient(conn)
-
-	return &PaymentRepositoryImpl{client: client}
-}
-
-func (u *PaymentRepositoryImpl) GetPaymentByOrderId(orderID int32) (*proto.PaymentResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	return u.client.GetPaymentByOrderId(ctx, &proto.GetPaymentByOrderIdRequest{OrderId: orderID})
-}
-
-func (u *PaymentRepositoryImpl) InitiatePayment(req *proto.InitiatePaymentRequest) (*proto.InitiatePaymentResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	return u.client.InitiatePayment(ctx, req)
-}
-
-func (u *PaymentRepositoryImpl) HandleWebhook(req *proto.WebhookRequest) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	_, err := u.client.HandleWebhook(ctx, req)
-	return err
-}
+package repository
+
+import (
+	"broker/proto"
+	"context"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type PaymentRepository interface {
+	GetPaymentByOrderId(orderID int32) (*proto.PaymentResponse, error)
+	InitiatePayment(req *proto.InitiatePaymentRequest) (*proto.InitiatePaymentResponse, error)
+	HandleWebhook(req *proto.WebhookRequest) error
+	CreatePaymentIntent(req *proto.CreatePaymentIntentRequest) (*proto.PaymentIntentResponse, error)
+	GetPaymentIntent(intentID int32, userID int32) (*proto.PaymentIntentResponse, error)
+	RefundPaymentIntent(req *proto.RefundPaymentIntentRequest) (*proto.RefundResponse, error)
+	RefundPayment(req *proto.RefundPaymentRequest) (*proto.RefundResponse, error)
+	InitiateCardPayment(req *proto.InitiateCardPaymentRequest) (*proto.InitiatePaymentResponse, error)
+	ListSavedPaymentMethods(userID int32, livemode bool) (*proto.ListSavedPaymentMethodsResponse, error)
+	DeleteSavedPaymentMethod(id int32, userID int32) error
+	GetWallet(userID int32, livemode bool) (*proto.WalletResponse, error)
+	TopUpWallet(req *proto.TopUpWalletRequest) (*proto.WalletTopUpResponse, error)
+}
+
+type PaymentRepositoryImpl struct {
+	client proto.PaymentServiceClient
+}
+
+func NewPaymentRepository() *PaymentRepositoryImpl {
+	addr := os.Getenv("PAYMENT_SERVICE_ADDR")
+	if addr == "" {
+		addr = "payment-service:60001"
+	}
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		logrus.Fatalf("Failed to connect to payment service: %v", err)
+	}
+
+	logrus.Infof("Connected to payment service at %s", addr)
+	client := proto.NewPaymentServiceClient(conn)
+
+	return &PaymentRepositoryImpl{client: client}
+}
+
+func (u *PaymentRepositoryImpl) GetPaymentByOrderId(orderID int32) (*proto.PaymentResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.GetPaymentByOrderId(ctx, &proto.GetPaymentByOrderIdRequest{OrderId: orderID})
+}
+
+func (u *PaymentRepositoryImpl) InitiatePayment(req *proto.InitiatePaymentRequest) (*proto.InitiatePaymentResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return u.client.InitiatePayment(ctx, req)
+}
+
+func (u *PaymentRepositoryImpl) HandleWebhook(req *proto.WebhookRequest) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := u.client.HandleWebhook(ctx, req)
+	return err
+}
+
+func (u *PaymentRepositoryImpl) CreatePaymentIntent(req *proto.CreatePaymentIntentRequest) (*proto.PaymentIntentResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return u.client.CreatePaymentIntent(ctx, req)
+}
+
+func (u *PaymentRepositoryImpl) GetPaymentIntent(intentID int32, userID int32) (*proto.PaymentIntentResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.GetPaymentIntent(ctx, &proto.GetPaymentIntentRequest{IntentId: intentID, UserId: userID})
+}
+
+func (u *PaymentRepositoryImpl) RefundPaymentIntent(req *proto.RefundPaymentIntentRequest) (*proto.RefundResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return u.client.RefundPaymentIntent(ctx, req)
+}
+
+func (u *PaymentRepositoryImpl) RefundPayment(req *proto.RefundPaymentRequest) (*proto.RefundResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return u.client.RefundPayment(ctx, req)
+}
+
+func (u *PaymentRepositoryImpl) InitiateCardPayment(req *proto.InitiateCardPaymentRequest) (*proto.InitiatePaymentResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return u.client.InitiateCardPayment(ctx, req)
+}
+
+func (u *PaymentRepositoryImpl) ListSavedPaymentMethods(userID int32, livemode bool) (*proto.ListSavedPaymentMethodsResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ListSavedPaymentMethods(ctx, &proto.ListSavedPaymentMethodsRequest{UserId: userID, Livemode: livemode})
+}
+
+func (u *PaymentRepositoryImpl) DeleteSavedPaymentMethod(id int32, userID int32) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := u.client.DeleteSavedPaymentMethod(ctx, &proto.DeleteSavedPaymentMethodRequest{Id: id, UserId: userID})
+	return err
+}
+
+func (u *PaymentRepositoryImpl) GetWallet(userID int32, livemode bool) (*proto.WalletResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.GetWallet(ctx, &proto.GetWalletRequest{UserId: userID, Livemode: livemode})
+}
+
+func (u *PaymentRepositoryImpl) TopUpWallet(req *proto.TopUpWalletRequest) (*proto.WalletTopUpResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return u.client.TopUpWallet(ctx, req)
+}
@@ -1,75 +1,649 @@
-package repository
-
-import (
-	"broker/proto"
-	"context"
-	"os"
-	"time"
-
-	"github.com/sirupsen/logrus"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-)
-
-type ProductRepository interface {
-	Create(payload *proto.ProductRequest) (*proto.Empty, error)
-	GetProduct(payload *proto.GetProductRequest) (*proto.Product, error)
-	ListProducts(offset *proto.Offset) (*proto.ProductList, error)
-	UpdateProduct(payload *proto.Product) (*proto.Product, error)
-	DeleteProduct(ID *proto.GetProductRequest) (*proto.Empty, error)
-}
-
-type ProductRepositoryImpl struct {
-	client proto.ProductServiceClient
-}
-
-func NewProductRepository() *ProductRepositoryImpl {
-	addr := os.Getenv("PRODUCT_SERVICE_ADDR")
-	if addr == "" {
-		addr = "product-service:40001"
-	}
-
-	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		logrus.Fatalf("Failed to connect to product service: %v", err)
-	}
-	logrus.Infof("Connected to product service at %s", addr)
-
-	client := proto.NewProductServiceClient(conn)
-	return &ProductRepositoryImpl{client: client}
-}
-
-func (u *ProductRepositoryImpl) Create(payload *proto.ProductRequest) (*proto.Empty, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	return u.client.CreateProduct(ctx, payload)
-}
-
-func (u *ProductRepositoryImpl) GetProduct(payload *proto.GetProductRequest) (*proto.Product, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	return u.client.GetProduct(ctx, payload)
-}
-
-func (u *ProductRepositoryImpl) ListProducts(offset *proto.Offset) (*proto.ProductList, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	return u.client.ListProducts(ctx, offset)
-}
-
-func (u *ProductRepositoryImpl) UpdateProduct(payload *proto.Product) (*proto.Product, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	return u.client.UpdateProduct(ctx, payload)
-}
-
-func (u *ProductRepositoryImpl) DeleteProduct(ID *proto.GetProductRequest) (*proto.Empty, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	return u.client.DeleteProduct(ctx, ID)
-}
+package repository
+
+import (
+	"broker/proto"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type ProductRepository interface {
+	Create(payload *proto.ProductRequest) (*proto.Empty, error)
+	GetProduct(payload *proto.GetProductRequest) (*proto.Product, error)
+	GetProductBySlug(req *proto.GetProductBySlugRequest) (*proto.Product, error)
+	ListProducts(offset *proto.Offset) (*proto.ProductList, error)
+	UpdateProduct(payload *proto.Product) (*proto.Product, error)
+	DeleteProduct(ID *proto.GetProductRequest) (*proto.Empty, error)
+	UnarchiveProduct(ID *proto.GetProductRequest) (*proto.Product, error)
+	PurgeProduct(ID *proto.GetProductRequest) (*proto.Empty, error)
+	SearchProducts(req *proto.SearchProductsRequest) (*proto.ProductList, error)
+	SetProductTranslation(translation *proto.ProductTranslation) (*proto.ProductTranslation, error)
+	DeleteProductTranslation(req *proto.GetProductTranslationRequest) error
+	ListProductTranslations(productID int32) (*proto.ProductTranslationList, error)
+	CreateCategory(category *proto.Category) (*proto.Category, error)
+	UpdateCategory(category *proto.Category) (*proto.Category, error)
+	DeleteCategory(ID int32) error
+	ListCategories() (*proto.CategoryList, error)
+	ListCategoryProducts(req *proto.ListCategoryProductsRequest) (*proto.CategoryProductList, error)
+	SetProductCategories(req *proto.SetProductCategoriesRequest) (*proto.Product, error)
+	CreateTag(tag *proto.Tag) (*proto.Tag, error)
+	DeleteTag(ID int32) error
+	ListTags() (*proto.TagList, error)
+	SetProductTags(req *proto.SetProductTagsRequest) (*proto.Product, error)
+	SetProductOptions(req *proto.SetProductOptionsRequest) (*proto.Product, error)
+	SetBundleComponents(req *proto.SetBundleComponentsRequest) (*proto.Product, error)
+	SetDigitalDelivery(req *proto.DigitalDelivery) (*proto.Product, error)
+	AddLicenseKeys(req *proto.AddLicenseKeysRequest) (*proto.LicenseKeyPool, error)
+	CreateVariant(variant *proto.ProductVariant) (*proto.ProductVariant, error)
+	UpdateVariant(variant *proto.ProductVariant) (*proto.ProductVariant, error)
+	DeleteVariant(ID int32) error
+	AdjustVariantStock(req *proto.AdjustVariantStockRequest) (*proto.ProductVariant, error)
+	ListInventoryMovements(req *proto.ListInventoryMovementsRequest) (*proto.InventoryMovementList, error)
+	SetReorderThreshold(req *proto.ReorderThreshold) (*proto.ReorderThreshold, error)
+	CreateSupplier(supplier *proto.Supplier) (*proto.Supplier, error)
+	ListSuppliers() (*proto.SupplierList, error)
+	CreatePurchaseOrder(order *proto.PurchaseOrder) (*proto.PurchaseOrder, error)
+	GetPurchaseOrder(ID int32) (*proto.PurchaseOrder, error)
+	ListPurchaseOrders(req *proto.ListPurchaseOrdersRequest) (*proto.PurchaseOrderList, error)
+	SendPurchaseOrder(ID int32) (*proto.PurchaseOrder, error)
+	ReceivePurchaseOrder(req *proto.ReceivePurchaseOrderRequest) (*proto.PurchaseOrder, error)
+	CancelPurchaseOrder(ID int32) (*proto.PurchaseOrder, error)
+	GetReorderReport(req *proto.ReorderReportRequest) (*proto.ReorderReport, error)
+	CreateWarehouse(warehouse *proto.Warehouse) (*proto.Warehouse, error)
+	UpdateWarehouse(warehouse *proto.Warehouse) (*proto.Warehouse, error)
+	ListWarehouses() (*proto.WarehouseList, error)
+	TransferStock(req *proto.TransferStockRequest) (*proto.ProductVariant, error)
+	SchedulePrice(price *proto.ProductPrice) (*proto.ProductPrice, error)
+	EndScheduledPrice(ID int32) (*proto.ProductPrice, error)
+	GetPriceHistory(req *proto.PriceHistoryRequest) (*proto.PriceHistory, error)
+	CreatePriceRule(rule *proto.PriceRule) (*proto.PriceRule, error)
+	DeletePriceRule(ID int32) error
+	ListPriceRules(productID int32) (*proto.PriceRuleList, error)
+	QuotePrices(req *proto.QuotePricesRequest) (*proto.PriceQuote, error)
+	CreateReview(review *proto.ProductReview) (*proto.ProductReview, error)
+	DeleteReview(req *proto.DeleteReviewRequest) (*proto.ProductReview, error)
+	ModerateReview(req *proto.ModerateReviewRequest) (*proto.ProductReview, error)
+	ListReviews(req *proto.ListReviewsRequest) (*proto.ReviewList, error)
+	AddToWishlist(req *proto.WishlistRequest) error
+	RemoveFromWishlist(req *proto.WishlistRequest) error
+	GetWishlist(userID int32) (*proto.Wishlist, error)
+	SubscribeBackInStock(req *proto.WishlistRequest) (*proto.StockSubscription, error)
+	UnsubscribeBackInStock(req *proto.WishlistRequest) error
+	ListStockSubscriptions(userID int32) (*proto.StockSubscriptionList, error)
+	GetRecommendations(req *proto.GetRecommendationsRequest) (*proto.RecommendationList, error)
+	AddProductImage(image *proto.ProductImage) (*proto.ProductImage, error)
+	UpdateProductImage(image *proto.ProductImage) (*proto.ProductImage, error)
+	DeleteProductImage(ID int32) (*proto.ProductImage, error)
+	ListProductImages(productID int32) (*proto.ProductImageList, error)
+	ReorderProductImages(req *proto.ReorderProductImagesRequest) (*proto.ProductImageList, error)
+	ImportProducts(options *proto.ImportOptions, next func() (*proto.ProductRow, error)) (*proto.ImportReport, error)
+	ExportProducts(ctx context.Context, send func(row *proto.ProductRow) error) error
+}
+
+type ProductRepositoryImpl struct {
+	client proto.ProductServiceClient
+}
+
+func NewProductRepository() *ProductRepositoryImpl {
+	addr := os.Getenv("PRODUCT_SERVICE_ADDR")
+	if addr == "" {
+		addr = "product-service:40001"
+	}
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		logrus.Fatalf("Failed to connect to product service: %v", err)
+	}
+	logrus.Infof("Connected to product service at %s", addr)
+
+	client := proto.NewProductServiceClient(conn)
+	return &ProductRepositoryImpl{client: client}
+}
+
+func (u *ProductRepositoryImpl) Create(payload *proto.ProductRequest) (*proto.Empty, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.CreateProduct(ctx, payload)
+}
+
+func (u *ProductRepositoryImpl) GetProduct(payload *proto.GetProductRequest) (*proto.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.GetProduct(ctx, payload)
+}
+
+func (u *ProductRepositoryImpl) GetProductBySlug(req *proto.GetProductBySlugRequest) (*proto.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.GetProductBySlug(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) ListProducts(offset *proto.Offset) (*proto.ProductList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ListProducts(ctx, offset)
+}
+
+func (u *ProductRepositoryImpl) UpdateProduct(payload *proto.Product) (*proto.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.UpdateProduct(ctx, payload)
+}
+
+func (u *ProductRepositoryImpl) DeleteProduct(ID *proto.GetProductRequest) (*proto.Empty, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.DeleteProduct(ctx, ID)
+}
+
+func (u *ProductRepositoryImpl) UnarchiveProduct(ID *proto.GetProductRequest) (*proto.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.UnarchiveProduct(ctx, ID)
+}
+
+func (u *ProductRepositoryImpl) PurgeProduct(ID *proto.GetProductRequest) (*proto.Empty, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.PurgeProduct(ctx, ID)
+}
+
+func (u *ProductRepositoryImpl) SearchProducts(req *proto.SearchProductsRequest) (*proto.ProductList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.SearchProducts(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) SetProductTranslation(translation *proto.ProductTranslation) (*proto.ProductTranslation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.SetProductTranslation(ctx, translation)
+}
+
+func (u *ProductRepositoryImpl) DeleteProductTranslation(req *proto.GetProductTranslationRequest) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := u.client.DeleteProductTranslation(ctx, req)
+	return err
+}
+
+func (u *ProductRepositoryImpl) ListProductTranslations(productID int32) (*proto.ProductTranslationList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ListProductTranslations(ctx, &proto.GetProductRequest{Id: productID})
+}
+
+func (u *ProductRepositoryImpl) CreateCategory(category *proto.Category) (*proto.Category, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.CreateCategory(ctx, category)
+}
+
+func (u *ProductRepositoryImpl) UpdateCategory(category *proto.Category) (*proto.Category, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.UpdateCategory(ctx, category)
+}
+
+func (u *ProductRepositoryImpl) DeleteCategory(ID int32) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := u.client.DeleteCategory(ctx, &proto.GetCategoryRequest{Id: ID})
+	return err
+}
+
+func (u *ProductRepositoryImpl) ListCategories() (*proto.CategoryList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ListCategories(ctx, &proto.Empty{})
+}
+
+func (u *ProductRepositoryImpl) ListCategoryProducts(req *proto.ListCategoryProductsRequest) (*proto.CategoryProductList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ListCategoryProducts(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) SetProductCategories(req *proto.SetProductCategoriesRequest) (*proto.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.SetProductCategories(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) CreateTag(tag *proto.Tag) (*proto.Tag, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.CreateTag(ctx, tag)
+}
+
+func (u *ProductRepositoryImpl) DeleteTag(ID int32) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := u.client.DeleteTag(ctx, &proto.GetTagRequest{Id: ID})
+	return err
+}
+
+func (u *ProductRepositoryImpl) ListTags() (*proto.TagList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ListTags(ctx, &proto.Empty{})
+}
+
+func (u *ProductRepositoryImpl) SetProductTags(req *proto.SetProductTagsRequest) (*proto.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.SetProductTags(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) SetProductOptions(req *proto.SetProductOptionsRequest) (*proto.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.SetProductOptions(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) SetBundleComponents(req *proto.SetBundleComponentsRequest) (*proto.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.SetBundleComponents(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) SetDigitalDelivery(req *proto.DigitalDelivery) (*proto.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.SetDigitalDelivery(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) AddLicenseKeys(req *proto.AddLicenseKeysRequest) (*proto.LicenseKeyPool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.AddLicenseKeys(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) CreateVariant(variant *proto.ProductVariant) (*proto.ProductVariant, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.CreateVariant(ctx, variant)
+}
+
+func (u *ProductRepositoryImpl) UpdateVariant(variant *proto.ProductVariant) (*proto.ProductVariant, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.UpdateVariant(ctx, variant)
+}
+
+func (u *ProductRepositoryImpl) DeleteVariant(ID int32) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := u.client.DeleteVariant(ctx, &proto.GetVariantRequest{Id: ID})
+	return err
+}
+
+func (u *ProductRepositoryImpl) AdjustVariantStock(req *proto.AdjustVariantStockRequest) (*proto.ProductVariant, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.AdjustVariantStock(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) ListInventoryMovements(req *proto.ListInventoryMovementsRequest) (*proto.InventoryMovementList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ListInventoryMovements(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) SetReorderThreshold(req *proto.ReorderThreshold) (*proto.ReorderThreshold, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.SetReorderThreshold(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) GetReorderReport(req *proto.ReorderReportRequest) (*proto.ReorderReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.GetReorderReport(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) CreateSupplier(supplier *proto.Supplier) (*proto.Supplier, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.CreateSupplier(ctx, supplier)
+}
+
+func (u *ProductRepositoryImpl) ListSuppliers() (*proto.SupplierList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ListSuppliers(ctx, &proto.Empty{})
+}
+
+func (u *ProductRepositoryImpl) CreatePurchaseOrder(order *proto.PurchaseOrder) (*proto.PurchaseOrder, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.CreatePurchaseOrder(ctx, order)
+}
+
+func (u *ProductRepositoryImpl) GetPurchaseOrder(ID int32) (*proto.PurchaseOrder, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.GetPurchaseOrder(ctx, &proto.GetPurchaseOrderRequest{Id: ID})
+}
+
+func (u *ProductRepositoryImpl) ListPurchaseOrders(req *proto.ListPurchaseOrdersRequest) (*proto.PurchaseOrderList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ListPurchaseOrders(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) SendPurchaseOrder(ID int32) (*proto.PurchaseOrder, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.SendPurchaseOrder(ctx, &proto.GetPurchaseOrderRequest{Id: ID})
+}
+
+func (u *ProductRepositoryImpl) ReceivePurchaseOrder(req *proto.ReceivePurchaseOrderRequest) (*proto.PurchaseOrder, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ReceivePurchaseOrder(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) CancelPurchaseOrder(ID int32) (*proto.PurchaseOrder, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.CancelPurchaseOrder(ctx, &proto.GetPurchaseOrderRequest{Id: ID})
+}
+
+func (u *ProductRepositoryImpl) CreateWarehouse(warehouse *proto.Warehouse) (*proto.Warehouse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.CreateWarehouse(ctx, warehouse)
+}
+
+func (u *ProductRepositoryImpl) UpdateWarehouse(warehouse *proto.Warehouse) (*proto.Warehouse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.UpdateWarehouse(ctx, warehouse)
+}
+
+func (u *ProductRepositoryImpl) ListWarehouses() (*proto.WarehouseList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ListWarehouses(ctx, &proto.Empty{})
+}
+
+func (u *ProductRepositoryImpl) TransferStock(req *proto.TransferStockRequest) (*proto.ProductVariant, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.TransferStock(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) SchedulePrice(price *proto.ProductPrice) (*proto.ProductPrice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.SchedulePrice(ctx, price)
+}
+
+func (u *ProductRepositoryImpl) EndScheduledPrice(ID int32) (*proto.ProductPrice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.EndScheduledPrice(ctx, &proto.GetProductPriceRequest{Id: ID})
+}
+
+func (u *ProductRepositoryImpl) GetPriceHistory(req *proto.PriceHistoryRequest) (*proto.PriceHistory, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.GetPriceHistory(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) CreatePriceRule(rule *proto.PriceRule) (*proto.PriceRule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.CreatePriceRule(ctx, rule)
+}
+
+func (u *ProductRepositoryImpl) DeletePriceRule(ID int32) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := u.client.DeletePriceRule(ctx, &proto.GetPriceRuleRequest{Id: ID})
+	return err
+}
+
+func (u *ProductRepositoryImpl) ListPriceRules(productID int32) (*proto.PriceRuleList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ListPriceRules(ctx, &proto.GetProductRequest{Id: productID})
+}
+
+func (u *ProductRepositoryImpl) QuotePrices(req *proto.QuotePricesRequest) (*proto.PriceQuote, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.QuotePrices(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) CreateReview(review *proto.ProductReview) (*proto.ProductReview, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.CreateReview(ctx, review)
+}
+
+func (u *ProductRepositoryImpl) DeleteReview(req *proto.DeleteReviewRequest) (*proto.ProductReview, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.DeleteReview(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) ModerateReview(req *proto.ModerateReviewRequest) (*proto.ProductReview, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ModerateReview(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) ListReviews(req *proto.ListReviewsRequest) (*proto.ReviewList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ListReviews(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) GetRecommendations(req *proto.GetRecommendationsRequest) (*proto.RecommendationList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.GetRecommendations(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) AddToWishlist(req *proto.WishlistRequest) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := u.client.AddToWishlist(ctx, req)
+	return err
+}
+
+func (u *ProductRepositoryImpl) RemoveFromWishlist(req *proto.WishlistRequest) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := u.client.RemoveFromWishlist(ctx, req)
+	return err
+}
+
+func (u *ProductRepositoryImpl) GetWishlist(userID int32) (*proto.Wishlist, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.GetWishlist(ctx, &proto.GetWishlistRequest{UserId: userID})
+}
+
+func (u *ProductRepositoryImpl) SubscribeBackInStock(req *proto.WishlistRequest) (*proto.StockSubscription, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.SubscribeBackInStock(ctx, req)
+}
+
+func (u *ProductRepositoryImpl) UnsubscribeBackInStock(req *proto.WishlistRequest) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := u.client.UnsubscribeBackInStock(ctx, req)
+	return err
+}
+
+func (u *ProductRepositoryImpl) ListStockSubscriptions(userID int32) (*proto.StockSubscriptionList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ListStockSubscriptions(ctx, &proto.GetWishlistRequest{UserId: userID})
+}
+
+func (u *ProductRepositoryImpl) AddProductImage(image *proto.ProductImage) (*proto.ProductImage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.AddProductImage(ctx, image)
+}
+
+func (u *ProductRepositoryImpl) UpdateProductImage(image *proto.ProductImage) (*proto.ProductImage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.UpdateProductImage(ctx, image)
+}
+
+func (u *ProductRepositoryImpl) DeleteProductImage(ID int32) (*proto.ProductImage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.DeleteProductImage(ctx, &proto.GetProductImageRequest{Id: ID})
+}
+
+func (u *ProductRepositoryImpl) ListProductImages(productID int32) (*proto.ProductImageList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ListProductImages(ctx, &proto.GetProductRequest{Id: productID})
+}
+
+func (u *ProductRepositoryImpl) ReorderProductImages(req *proto.ReorderProductImagesRequest) (*proto.ProductImageList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ReorderProductImages(ctx, req)
+}
+
+// ImportProducts streams rows from next to the product service until next returns
+// io.EOF, then waits for the import report
+func (u *ProductRepositoryImpl) ImportProducts(options *proto.ImportOptions, next func() (*proto.ProductRow, error)) (*proto.ImportReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	stream, err := u.client.ImportProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := stream.Send(&proto.ImportProductsRequest{Item: &proto.ImportProductsRequest_Options{Options: options}}); err != nil {
+		return nil, err
+	}
+
+	for {
+		row, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		err = stream.Send(&proto.ImportProductsRequest{Item: &proto.ImportProductsRequest_Row{Row: row}})
+		if errors.Is(err, io.EOF) {
+			// The service ended the stream early; CloseAndRecv returns its error
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return stream.CloseAndRecv()
+}
+
+// ExportProducts passes every catalog row to send as it arrives from the product service
+func (u *ProductRepositoryImpl) ExportProducts(ctx context.Context, send func(row *proto.ProductRow) error) error {
+	stream, err := u.client.ExportProducts(ctx, &proto.Empty{})
+	if err != nil {
+		return err
+	}
+
+	for {
+		row, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := send(row); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalMediaRoute is where the gateway serves files of the local store
+const LocalMediaRoute = "/media"
+
+// LocalBlobStore keeps files on the gateway's filesystem and serves them publicly
+type LocalBlobStore struct {
+	dir     string
+	baseURL string
+}
+
+func NewLocalBlobStore(dir string, baseURL string) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &LocalBlobStore{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}, nil
+}
+
+// Dir is the directory to serve under LocalMediaRoute
+func (s *LocalBlobStore) Dir() string {
+	return s.dir
+}
+
+func (s *LocalBlobStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see a partial image
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalBlobStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (s *LocalBlobStore) URL(key string) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	return s.baseURL + "/" + key, nil
+}
+
+// path maps a key into the store directory, refusing keys that would escape it
+func (s *LocalBlobStore) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if key == "" || clean != "/"+key {
+		return "", errors.New("invalid blob key")
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
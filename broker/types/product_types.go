@@ -1,18 +1,66 @@
 package types
 
 type ProductDTO struct {
-	Id          uint32  `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Stock       int32   `json:"stock"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
+	Id            uint32             `json:"id"`
+	Slug          string             `json:"slug"`
+	Name          string             `json:"name"`
+	Description   string             `json:"description"`
+	Locale        string             `json:"locale,omitempty"`
+	Kind          string             `json:"kind"`
+	Delivery      string             `json:"delivery,omitempty"` // download or license_key, for digital products
+	Price         float64            `json:"price"`
+	ListPrice     float64            `json:"list_price"`
+	Stock         int32              `json:"stock"`
+	RatingAverage float64            `json:"rating_average"`
+	RatingCount   int32              `json:"rating_count"`
+	CreatedAt     string             `json:"created_at"`
+	UpdatedAt     string             `json:"updated_at"`
+	ArchivedAt    string             `json:"archived_at,omitempty"`
+	Images        []*ProductImageDTO `json:"images"`
+}
+
+type ProductImageDTO struct {
+	Id           int32  `json:"id"`
+	Url          string `json:"url"`
+	ThumbnailUrl string `json:"thumbnail_url"`
+	AltText      string `json:"alt_text"`
+	Position     int32  `json:"position"`
+	Width        int32  `json:"width"`
+	Height       int32  `json:"height"`
+}
+
+type ReviewDTO struct {
+	Id             int32             `json:"id"`
+	ProductId      int32             `json:"product_id"`
+	UserId         int32             `json:"user_id"`
+	Rating         int32             `json:"rating"`
+	Body           string            `json:"body"`
+	Status         string            `json:"status,omitempty"`
+	ModerationNote string            `json:"moderation_note,omitempty"`
+	Images         []*ReviewImageDTO `json:"images"`
+	CreatedAt      string            `json:"created_at"`
+}
+
+type ReviewImageDTO struct {
+	Id           int32  `json:"id"`
+	Url          string `json:"url"`
+	ThumbnailUrl string `json:"thumbnail_url"`
+	Width        int32  `json:"width"`
+	Height       int32  `json:"height"`
 }
 
 type ProductListDTO struct {
-	Total     int64         `json:"total"`
-	Page      int32         `json:"page"`
-	TotalPage int32         `json:"total_page"`
-	Products  []*ProductDTO `json:"products"`
+	Total     int64               `json:"total"`
+	Page      int32               `json:"page"`
+	TotalPage int32               `json:"total_page"`
+	Products  []*ProductDTO       `json:"products"`
+	PageSize  int32               `json:"page_size,omitempty"`
+	Facets    []*CategoryFacetDTO `json:"facets,omitempty"`
+}
+
+type CategoryFacetDTO struct {
+	CategoryId int32  `json:"category_id"`
+	Name       string `json:"name"`
+	Slug       string `json:"slug"`
+	Count      int64  `json:"count"`
 }
@@ -8,7 +8,6 @@ import toast from 'react-hot-toast';
 import { CreditCard, CheckCircle2, ArrowLeft, Clock, XCircle, Loader2 } from 'lucide-react';
 import { Button } from '@/components/ui/button';
 import { Loading } from '@/components/ui/loading';
-import { useAuthStore } from '@/store';
 import { orderService } from '@/services/order-service';
 import { paymentService } from '@/services/payment-service';
 import { formatPrice, formatDate } from '@/lib/utils';
@@ -21,9 +20,6 @@ export default function OrderPaymentPage() {
   const [isPaymentLoading, setIsPaymentLoading] = useState(false);
   const [isSnapOpen, setIsSnapOpen] = useState(false);
   
-  // Get user info from auth store
-  const user = useAuthStore((state) => state.user);
-  
   const { data: orderData, isLoading, error, refetch } = useQuery({
     queryKey: ['order', orderId],
     queryFn: () => orderService.getOrderById(orderId),
@@ -62,15 +58,6 @@ export default function OrderPaymentPage() {
       }
     }
 
-    // Get user info - use stored user or fallback
-    const customerName = user?.full_name || user?.email?.split('@')[0] || 'Customer';
-    const customerEmail = user?.email || '';
-    
-    if (!customerEmail) {
-      toast.error('User information not available. Please login again.');
-      return;
-    }
-    
     setIsPaymentLoading(true);
     
     // Get current payment data (might have been refetched)
@@ -82,8 +69,6 @@ export default function OrderPaymentPage() {
       if (!token) {
         const response = await paymentService.initiatePayment({ 
           order_id: orderId,
-          customer_name: customerName,
-          customer_email: customerEmail,
         });
         token = response.token;
         
@@ -116,9 +116,6 @@ export interface InitiatePaymentRequest {
   order_id: number;
   payment_method?: string;
   payment_channel?: string;
-  customer_name: string;
-  customer_email: string;
-  customer_phone?: string;
 }
 
 export interface InitiatePaymentResponse {
@@ -132,10 +132,15 @@ func DeleteCacheByPattern(ctx context.Context, pattern string) error {
 	return nil
 }
 
-func RedisOrderKey(userID, page int) string {
+func RedisOrderKey(userID, page int, livemode bool) string {
+	if !livemode {
+		return fmt.Sprintf("orders:user%d:page%d:test", userID, page)
+	}
 	return fmt.Sprintf("orders:user%d:page%d", userID, page)
 }
 
-func RedisOrderByIdKey(orderID int) string {
-	return fmt.Sprintf("order:id:%d", orderID)
+// RedisOrderByIdKey names the cached order of a user; a cached order is only ever
+// served to the user it was read for
+func RedisOrderByIdKey(orderID, userID int) string {
+	return fmt.Sprintf("order:id:%d:user%d", orderID, userID)
 }
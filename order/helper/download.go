@@ -0,0 +1,30 @@
+package helper
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SignDownload signs a download link of a delivery valid until expires, a Unix
+// time. The broker checks the same signature before it serves the file.
+func SignDownload(secret string, deliveryID int32, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d:%d", deliveryID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// DownloadURL is the signed link a download delivery is fetched with, or empty
+// when DOWNLOAD_SIGNING_SECRET is not set
+func DownloadURL(deliveryID int32, expires int64) string {
+	secret := os.Getenv("DOWNLOAD_SIGNING_SECRET")
+	if secret == "" {
+		return ""
+	}
+
+	base := strings.TrimSuffix(os.Getenv("DOWNLOAD_BASE_URL"), "/")
+	return fmt.Sprintf("%s/download/%d?expires=%d&signature=%s", base, deliveryID, expires, SignDownload(secret, deliveryID, expires))
+}
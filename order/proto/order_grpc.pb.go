@@ -23,6 +23,11 @@ const (
 	OrderService_GetOrder_FullMethodName          = "/orders.OrderService/GetOrder"
 	OrderService_GetOrderById_FullMethodName      = "/orders.OrderService/GetOrderById"
 	OrderService_UpdateOrderStatus_FullMethodName = "/orders.OrderService/UpdateOrderStatus"
+	OrderService_ReturnOrderItems_FullMethodName  = "/orders.OrderService/ReturnOrderItems"
+	OrderService_DeliverOrder_FullMethodName      = "/orders.OrderService/DeliverOrder"
+	OrderService_RecordDownload_FullMethodName    = "/orders.OrderService/RecordDownload"
+	OrderService_HasPurchased_FullMethodName      = "/orders.OrderService/HasPurchased"
+	OrderService_HasOrders_FullMethodName         = "/orders.OrderService/HasOrders"
 )
 
 // OrderServiceClient is the client API for OrderService service.
@@ -33,6 +38,11 @@ type OrderServiceClient interface {
 	GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*OrdersResponse, error)
 	GetOrderById(ctx context.Context, in *GetOrderByIdRequest, opts ...grpc.CallOption) (*OrderResponse, error)
 	UpdateOrderStatus(ctx context.Context, in *UpdateOrderStatusRequest, opts ...grpc.CallOption) (*EmptyOrder, error)
+	ReturnOrderItems(ctx context.Context, in *ReturnOrderItemsRequest, opts ...grpc.CallOption) (*ReturnOrderItemsResponse, error)
+	DeliverOrder(ctx context.Context, in *GetOrderByIdRequest, opts ...grpc.CallOption) (*OrderResponse, error)
+	RecordDownload(ctx context.Context, in *RecordDownloadRequest, opts ...grpc.CallOption) (*OrderDelivery, error)
+	HasPurchased(ctx context.Context, in *HasPurchasedRequest, opts ...grpc.CallOption) (*HasPurchasedResponse, error)
+	HasOrders(ctx context.Context, in *HasOrdersRequest, opts ...grpc.CallOption) (*HasOrdersResponse, error)
 }
 
 type orderServiceClient struct {
@@ -83,6 +93,56 @@ func (c *orderServiceClient) UpdateOrderStatus(ctx context.Context, in *UpdateOr
 	return out, nil
 }
 
+func (c *orderServiceClient) ReturnOrderItems(ctx context.Context, in *ReturnOrderItemsRequest, opts ...grpc.CallOption) (*ReturnOrderItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReturnOrderItemsResponse)
+	err := c.cc.Invoke(ctx, OrderService_ReturnOrderItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) DeliverOrder(ctx context.Context, in *GetOrderByIdRequest, opts ...grpc.CallOption) (*OrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_DeliverOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) RecordDownload(ctx context.Context, in *RecordDownloadRequest, opts ...grpc.CallOption) (*OrderDelivery, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderDelivery)
+	err := c.cc.Invoke(ctx, OrderService_RecordDownload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) HasPurchased(ctx context.Context, in *HasPurchasedRequest, opts ...grpc.CallOption) (*HasPurchasedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HasPurchasedResponse)
+	err := c.cc.Invoke(ctx, OrderService_HasPurchased_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) HasOrders(ctx context.Context, in *HasOrdersRequest, opts ...grpc.CallOption) (*HasOrdersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HasOrdersResponse)
+	err := c.cc.Invoke(ctx, OrderService_HasOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrderServiceServer is the server API for OrderService service.
 // All implementations must embed UnimplementedOrderServiceServer
 // for forward compatibility.
@@ -91,6 +151,11 @@ type OrderServiceServer interface {
 	GetOrder(context.Context, *GetOrderRequest) (*OrdersResponse, error)
 	GetOrderById(context.Context, *GetOrderByIdRequest) (*OrderResponse, error)
 	UpdateOrderStatus(context.Context, *UpdateOrderStatusRequest) (*EmptyOrder, error)
+	ReturnOrderItems(context.Context, *ReturnOrderItemsRequest) (*ReturnOrderItemsResponse, error)
+	DeliverOrder(context.Context, *GetOrderByIdRequest) (*OrderResponse, error)
+	RecordDownload(context.Context, *RecordDownloadRequest) (*OrderDelivery, error)
+	HasPurchased(context.Context, *HasPurchasedRequest) (*HasPurchasedResponse, error)
+	HasOrders(context.Context, *HasOrdersRequest) (*HasOrdersResponse, error)
 	mustEmbedUnimplementedOrderServiceServer()
 }
 
@@ -113,6 +178,21 @@ func (UnimplementedOrderServiceServer) GetOrderById(context.Context, *GetOrderBy
 func (UnimplementedOrderServiceServer) UpdateOrderStatus(context.Context, *UpdateOrderStatusRequest) (*EmptyOrder, error) {
 	return nil, status.Error(codes.Unimplemented, "method UpdateOrderStatus not implemented")
 }
+func (UnimplementedOrderServiceServer) ReturnOrderItems(context.Context, *ReturnOrderItemsRequest) (*ReturnOrderItemsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReturnOrderItems not implemented")
+}
+func (UnimplementedOrderServiceServer) DeliverOrder(context.Context, *GetOrderByIdRequest) (*OrderResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeliverOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) RecordDownload(context.Context, *RecordDownloadRequest) (*OrderDelivery, error) {
+	return nil, status.Error(codes.Unimplemented, "method RecordDownload not implemented")
+}
+func (UnimplementedOrderServiceServer) HasPurchased(context.Context, *HasPurchasedRequest) (*HasPurchasedResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method HasPurchased not implemented")
+}
+func (UnimplementedOrderServiceServer) HasOrders(context.Context, *HasOrdersRequest) (*HasOrdersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method HasOrders not implemented")
+}
 func (UnimplementedOrderServiceServer) mustEmbedUnimplementedOrderServiceServer() {}
 func (UnimplementedOrderServiceServer) testEmbeddedByValue()                      {}
 
@@ -206,6 +286,96 @@ func _OrderService_UpdateOrderStatus_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_ReturnOrderItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReturnOrderItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ReturnOrderItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ReturnOrderItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ReturnOrderItems(ctx, req.(*ReturnOrderItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_DeliverOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderByIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).DeliverOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_DeliverOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).DeliverOrder(ctx, req.(*GetOrderByIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_RecordDownload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordDownloadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).RecordDownload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_RecordDownload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).RecordDownload(ctx, req.(*RecordDownloadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_HasPurchased_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HasPurchasedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).HasPurchased(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_HasPurchased_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).HasPurchased(ctx, req.(*HasPurchasedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_HasOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HasOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).HasOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_HasOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).HasOrders(ctx, req.(*HasOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // OrderService_ServiceDesc is the grpc.ServiceDesc for OrderService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -229,6 +399,26 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateOrderStatus",
 			Handler:    _OrderService_UpdateOrderStatus_Handler,
 		},
+		{
+			MethodName: "ReturnOrderItems",
+			Handler:    _OrderService_ReturnOrderItems_Handler,
+		},
+		{
+			MethodName: "DeliverOrder",
+			Handler:    _OrderService_DeliverOrder_Handler,
+		},
+		{
+			MethodName: "RecordDownload",
+			Handler:    _OrderService_RecordDownload_Handler,
+		},
+		{
+			MethodName: "HasPurchased",
+			Handler:    _OrderService_HasPurchased_Handler,
+		},
+		{
+			MethodName: "HasOrders",
+			Handler:    _OrderService_HasOrders_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/order.proto",
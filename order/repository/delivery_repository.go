@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"order/proto"
+)
+
+var ErrDeliveryNotFound = errors.New("delivery not found")
+
+type DeliveryRepository interface {
+	CreateDelivery(delivery *proto.OrderDelivery, linkTTLHours int32, tx *sql.Tx) error
+	GetDeliveriesByOrderID(orderID int, db *sql.DB) ([]*proto.OrderDelivery, error)
+	GetDelivery(deliveryID int, db *sql.DB) (*proto.OrderDelivery, error)
+	CountDownload(deliveryID int, tx *sql.Tx) (*proto.OrderDelivery, error)
+}
+
+type DeliveryRepositoryImpl struct{}
+
+func NewDeliveryRepositoryImpl() *DeliveryRepositoryImpl {
+	return &DeliveryRepositoryImpl{}
+}
+
+// deliveryColumns reads expires_at as RFC 3339 in UTC, as download links are signed
+// with its Unix time
+const deliveryColumns = `id, order_id, order_item_id, product_id, delivery, file_name, download_limit, download_count,
+			COALESCE(to_char(expires_at::timestamptz AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"'), ''), COALESCE(expires_at <= NOW(), FALSE), array_to_json(license_keys), created_at, file_key, content_type`
+
+// CreateDelivery records what an order item delivered. Downloads expire
+// linkTTLHours from now; an item that is already delivered keeps its delivery.
+func (u *DeliveryRepositoryImpl) CreateDelivery(delivery *proto.OrderDelivery, linkTTLHours int32, tx *sql.Tx) error {
+	SQL := `INSERT INTO order_deliveries(order_id, order_item_id, product_id, delivery, file_key, file_name, content_type, license_keys, download_limit, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, CASE WHEN $4 = 'download' THEN NOW() + make_interval(hours => $10) END)
+			ON CONFLICT (order_item_id) DO NOTHING`
+	keys := delivery.LicenseKeys
+	if keys == nil {
+		keys = []string{}
+	}
+	if _, err := tx.Exec(SQL, delivery.OrderId, delivery.OrderItemId, delivery.ProductId, delivery.Delivery, delivery.FileKey, delivery.FileName, delivery.ContentType, keys, delivery.DownloadLimit, linkTTLHours); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *DeliveryRepositoryImpl) GetDeliveriesByOrderID(orderID int, db *sql.DB) ([]*proto.OrderDelivery, error) {
+	rows, err := db.Query("SELECT "+deliveryColumns+" FROM order_deliveries WHERE order_id = $1 ORDER BY id ASC", orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*proto.OrderDelivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func (u *DeliveryRepositoryImpl) GetDelivery(deliveryID int, db *sql.DB) (*proto.OrderDelivery, error) {
+	return scanDelivery(db.QueryRow("SELECT "+deliveryColumns+" FROM order_deliveries WHERE id = $1", deliveryID))
+}
+
+// CountDownload counts one more download of a delivery that has not expired or
+// reached its limit, and returns sql.ErrNoRows otherwise
+func (u *DeliveryRepositoryImpl) CountDownload(deliveryID int, tx *sql.Tx) (*proto.OrderDelivery, error) {
+	SQL := `UPDATE order_deliveries
+			SET download_count = download_count + 1, last_downloaded_at = NOW()
+			WHERE id = $1 AND delivery = 'download' AND expires_at > NOW() AND download_count < download_limit
+			RETURNING ` + deliveryColumns
+	delivery, err := scanDelivery(tx.QueryRow(SQL, deliveryID))
+	if errors.Is(err, ErrDeliveryNotFound) {
+		return nil, sql.ErrNoRows
+	}
+	return delivery, err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanDelivery(row rowScanner) (*proto.OrderDelivery, error) {
+	delivery := &proto.OrderDelivery{}
+	var keys []byte
+	if err := row.Scan(
+		&delivery.Id,
+		&delivery.OrderId,
+		&delivery.OrderItemId,
+		&delivery.ProductId,
+		&delivery.Delivery,
+		&delivery.FileName,
+		&delivery.DownloadLimit,
+		&delivery.DownloadCount,
+		&delivery.ExpiresAt,
+		&delivery.Expired,
+		&keys,
+		&delivery.CreatedAt,
+		&delivery.FileKey,
+		&delivery.ContentType,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(keys, &delivery.LicenseKeys); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
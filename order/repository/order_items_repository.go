@@ -1,35 +1,111 @@
-package repository
-
-import (
-	"database/sql"
-	"order/proto"
-)
-
-type OrderItemsRepository interface {
-	CreateOrderItems(payload *proto.OrderItemRequest, tx *sql.Tx) error
-	DeleteOrderItems(payload *proto.GetOrderItemRequest, tx *sql.Tx) error
-}
-
-type OrderItemsRepositoryImpl struct{}
-
-func NewOrderItemsRepositoryImpl() *OrderItemsRepositoryImpl {
-	return &OrderItemsRepositoryImpl{}
-}
-
-func (u *OrderItemsRepositoryImpl) CreateOrderItems(payload *proto.OrderItemRequest, tx *sql.Tx) error {
-	SQL := "INSERT INTO order_items(order_id, product_id, quantity) VALUES ($1, $2, $3)"
-	if _, err := tx.Exec(SQL, payload.OrderId, payload.ProductId, payload.Quantity); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func (u *OrderItemsRepositoryImpl) DeleteOrderItems(payload *proto.GetOrderItemRequest, tx *sql.Tx) error {
-	SQL := "DELETE FROM order_items WHERE id = $1"
-	if _, err := tx.Exec(SQL, payload.OrderItemId); err != nil {
-		return err
-	}
-
-	return nil
-}
+package repository
+
+import (
+	"database/sql"
+	"order/proto"
+)
+
+type OrderItemsRepository interface {
+	CreateOrderItems(payload *proto.OrderItemRequest, tx *sql.Tx) (int, error)
+	DeleteOrderItems(payload *proto.GetOrderItemRequest, tx *sql.Tx) error
+	GetOrderItemsByOrderID(orderID int, db *sql.DB) ([]*proto.OrderItem, error)
+	LockOrderItems(orderID int, tx *sql.Tx) ([]*proto.OrderItem, error)
+	AddReturnedQuantity(orderItemID int, quantity int32, tx *sql.Tx) error
+}
+
+type OrderItemsRepositoryImpl struct{}
+
+func NewOrderItemsRepositoryImpl() *OrderItemsRepositoryImpl {
+	return &OrderItemsRepositoryImpl{}
+}
+
+func (u *OrderItemsRepositoryImpl) CreateOrderItems(payload *proto.OrderItemRequest, tx *sql.Tx) (int, error) {
+	SQL := `INSERT INTO order_items(order_id, product_id, variant_id, quantity, price, warehouse_id, parent_item_id, price_rule_id,
+				product_name, sku, warehouse_code, digital)
+			VALUES ($1, $2, NULLIF($3, 0), $4, $5, NULLIF($6, 0), NULLIF($7, 0), NULLIF($8, 0), $9, $10, $11, $12) RETURNING id`
+	var ID int
+	if err := tx.QueryRow(SQL, payload.OrderId, payload.ProductId, payload.VariantId, payload.Quantity, payload.Price, payload.WarehouseId, payload.ParentItemId, payload.PriceRuleId,
+		payload.ProductName, payload.Sku, payload.WarehouseCode, payload.Digital).Scan(&ID); err != nil {
+		return 0, err
+	}
+
+	return ID, nil
+}
+
+func (u *OrderItemsRepositoryImpl) DeleteOrderItems(payload *proto.GetOrderItemRequest, tx *sql.Tx) error {
+	SQL := "DELETE FROM order_items WHERE id = $1"
+	if _, err := tx.Exec(SQL, payload.OrderItemId); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// orderItemsSQL reads the product details an item was ordered with from the item
+// itself; the catalog belongs to the product service
+const orderItemsSQL = `SELECT oi.id, oi.order_id, oi.product_id, COALESCE(oi.variant_id, 0), oi.sku, oi.quantity, oi.price, oi.product_name,
+				COALESCE(oi.warehouse_id, 0), oi.warehouse_code, COALESCE(oi.parent_item_id, 0), oi.returned_quantity,
+				COALESCE(oi.price_rule_id, 0), oi.digital
+			FROM order_items oi
+			WHERE oi.order_id = $1
+			ORDER BY oi.id ASC`
+
+// GetOrderItemsByOrderID returns every item of an order, the components of bundles
+// included, in the order they were added
+func (u *OrderItemsRepositoryImpl) GetOrderItemsByOrderID(orderID int, db *sql.DB) ([]*proto.OrderItem, error) {
+	rows, err := db.Query(orderItemsSQL, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return scanOrderItems(rows)
+}
+
+// LockOrderItems returns every item of an order like GetOrderItemsByOrderID, locked
+// until tx ends
+func (u *OrderItemsRepositoryImpl) LockOrderItems(orderID int, tx *sql.Tx) ([]*proto.OrderItem, error) {
+	rows, err := tx.Query(orderItemsSQL+" FOR UPDATE OF oi", orderID)
+	if err != nil {
+		return nil, err
+	}
+	return scanOrderItems(rows)
+}
+
+// AddReturnedQuantity counts quantity more of an item as returned; more than was
+// ordered is refused by the database
+func (u *OrderItemsRepositoryImpl) AddReturnedQuantity(orderItemID int, quantity int32, tx *sql.Tx) error {
+	SQL := "UPDATE order_items SET returned_quantity = returned_quantity + $1, updated_at = NOW() WHERE id = $2"
+	if _, err := tx.Exec(SQL, quantity, orderItemID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func scanOrderItems(rows *sql.Rows) ([]*proto.OrderItem, error) {
+	defer rows.Close()
+
+	var items []*proto.OrderItem
+	for rows.Next() {
+		item := &proto.OrderItem{}
+		if err := rows.Scan(
+			&item.Id,
+			&item.OrderId,
+			&item.ProductId,
+			&item.VariantId,
+			&item.Sku,
+			&item.Quantity,
+			&item.Price,
+			&item.ProductName,
+			&item.WarehouseId,
+			&item.WarehouseCode,
+			&item.ParentItemId,
+			&item.ReturnedQuantity,
+			&item.PriceRuleId,
+			&item.Digital,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -1,88 +1,144 @@
-package repository
-
-import (
-	"database/sql"
-	"order/proto"
-	"time"
-)
-
-type OrderRepository interface {
-	CreateOrder(payload *proto.CreateOrderRequest, price float64, tx *sql.Tx) (int, error)
-	GetOrderByUserID(payload *proto.GetOrderRequest, db *sql.DB) ([]*proto.Order, error)
-	GetOrderById(orderID int, userID int, db *sql.DB) (*proto.Order, error)
-	UpdateOrderStatus(status string, orderID int, tx *sql.Tx) error
-}
-
-type OrderRepositoryImpl struct{}
-
-func NewOrderRepositoryImpl() *OrderRepositoryImpl {
-	return &OrderRepositoryImpl{}
-}
-
-func (u *OrderRepositoryImpl) CreateOrder(payload *proto.CreateOrderRequest, price float64, tx *sql.Tx) (int, error) {
-	SQL := "INSERT INTO orders(user_id, total_price, status) VALUES ($1, $2, $3) RETURNING id"
-	var orderID int
-	err := tx.QueryRow(SQL, payload.UserId, price, "pending").Scan(&orderID)
-	if err != nil {
-		return 0, err
-	}
-	return orderID, nil
-}
-
-func (u *OrderRepositoryImpl) GetOrderByUserID(payload *proto.GetOrderRequest, db *sql.DB) ([]*proto.Order, error) {
-	SQL := "SELECT id, user_id, status, total_price, created_at, updated_at FROM orders WHERE user_id = $1 ORDER BY id ASC LIMIT 15 OFFSET $2"
-	rows, err := db.Query(SQL, payload.UserId, payload.Offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var orders []*proto.Order
-	for rows.Next() {
-		order := &proto.Order{}
-		if err := rows.Scan(
-			&order.Id,
-			&order.UserId,
-			&order.Status,
-			&order.TotalPrice,
-			&order.CreatedAt,
-			&order.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		orders = append(orders, order)
-	}
-	return orders, nil
-}
-
-func (u *OrderRepositoryImpl) GetOrderById(orderID int, userID int, db *sql.DB) (*proto.Order, error) {
-	SQL := "SELECT id, user_id, status, total_price, created_at, updated_at FROM orders WHERE id = $1 AND user_id = $2"
-	row := db.QueryRow(SQL, orderID, userID)
-
-	order := &proto.Order{}
-	if err := row.Scan(
-		&order.Id,
-		&order.UserId,
-		&order.Status,
-		&order.TotalPrice,
-		&order.CreatedAt,
-		&order.UpdatedAt,
-	); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil // Order not found
-		}
-		return nil, err
-	}
-
-	return order, nil
-}
-
-func (u *OrderRepositoryImpl) UpdateOrderStatus(status string, orderID int, tx *sql.Tx) error {
-	loc := time.FixedZone("WIB", 7*60*60)
-	SQL := `UPDATE orders SET status = $1, updated_at = $2 WHERE id = $3`
-	now := time.Now().In(loc)
-	if _, err := tx.Exec(SQL, status, now, orderID); err != nil {
-		return err
-	}
-	return nil
-}
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"order/proto"
+	"time"
+)
+
+type OrderRepository interface {
+	CreateOrder(payload *proto.CreateOrderRequest, price float64, tx *sql.Tx) (int, error)
+	GetOrderByUserID(payload *proto.GetOrderRequest, db *sql.DB) ([]*proto.Order, error)
+	GetOrderById(orderID int, userID int, db *sql.DB) (*proto.Order, error)
+	UpdateOrderStatus(status string, orderID int, tx *sql.Tx) error
+	LockOrder(orderID int, tx *sql.Tx) (*proto.Order, error)
+	HasPurchased(userID int, productID int, db *sql.DB) (bool, error)
+	HasOrders(productID int, db *sql.DB) (bool, error)
+}
+
+type OrderRepositoryImpl struct{}
+
+func NewOrderRepositoryImpl() *OrderRepositoryImpl {
+	return &OrderRepositoryImpl{}
+}
+
+func (u *OrderRepositoryImpl) CreateOrder(payload *proto.CreateOrderRequest, price float64, tx *sql.Tx) (int, error) {
+	SQL := "INSERT INTO orders(user_id, total_price, status, livemode) VALUES ($1, $2, $3, $4) RETURNING id"
+	var orderID int
+	err := tx.QueryRow(SQL, payload.UserId, price, "pending", payload.Livemode).Scan(&orderID)
+	if err != nil {
+		return 0, err
+	}
+	return orderID, nil
+}
+
+func (u *OrderRepositoryImpl) GetOrderByUserID(payload *proto.GetOrderRequest, db *sql.DB) ([]*proto.Order, error) {
+	SQL := "SELECT id, user_id, status, total_price, created_at, updated_at, livemode FROM orders WHERE user_id = $1 AND livemode = $2 ORDER BY id ASC LIMIT 15 OFFSET $3"
+	rows, err := db.Query(SQL, payload.UserId, payload.Livemode, payload.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*proto.Order
+	for rows.Next() {
+		order := &proto.Order{}
+		if err := rows.Scan(
+			&order.Id,
+			&order.UserId,
+			&order.Status,
+			&order.TotalPrice,
+			&order.CreatedAt,
+			&order.UpdatedAt,
+			&order.Livemode,
+		); err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+func (u *OrderRepositoryImpl) GetOrderById(orderID int, userID int, db *sql.DB) (*proto.Order, error) {
+	SQL := "SELECT id, user_id, status, total_price, created_at, updated_at, livemode FROM orders WHERE id = $1 AND user_id = $2"
+	row := db.QueryRow(SQL, orderID, userID)
+
+	order := &proto.Order{}
+	if err := row.Scan(
+		&order.Id,
+		&order.UserId,
+		&order.Status,
+		&order.TotalPrice,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+		&order.Livemode,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Order not found
+		}
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// LockOrder returns an order of any user, locked until tx ends
+func (u *OrderRepositoryImpl) LockOrder(orderID int, tx *sql.Tx) (*proto.Order, error) {
+	SQL := "SELECT id, user_id, status, total_price, created_at, updated_at, livemode FROM orders WHERE id = $1 FOR UPDATE"
+	order := &proto.Order{}
+	if err := tx.QueryRow(SQL, orderID).Scan(
+		&order.Id,
+		&order.UserId,
+		&order.Status,
+		&order.TotalPrice,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+		&order.Livemode,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("order not found")
+		}
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// HasPurchased reports whether the user has a paid or fulfilled live order containing
+// the product. Test-mode orders are not purchases.
+func (u *OrderRepositoryImpl) HasPurchased(userID int, productID int, db *sql.DB) (bool, error) {
+	SQL := `SELECT EXISTS (
+				SELECT 1 FROM orders o
+				JOIN order_items oi ON oi.order_id = o.id
+				WHERE o.user_id = $1 AND oi.product_id = $2 AND o.livemode
+					AND o.status IN ('paid', 'fulfilled')
+			)`
+	var purchased bool
+	if err := db.QueryRow(SQL, userID, productID).Scan(&purchased); err != nil {
+		return false, err
+	}
+	return purchased, nil
+}
+
+// HasOrders reports whether any order line refers to the product, in either mode.
+// Bundle components are lines of their own, so a component is ordered with its
+// bundle.
+func (u *OrderRepositoryImpl) HasOrders(productID int, db *sql.DB) (bool, error) {
+	SQL := "SELECT EXISTS (SELECT 1 FROM order_items WHERE product_id = $1)"
+	var ordered bool
+	if err := db.QueryRow(SQL, productID).Scan(&ordered); err != nil {
+		return false, err
+	}
+	return ordered, nil
+}
+
+func (u *OrderRepositoryImpl) UpdateOrderStatus(status string, orderID int, tx *sql.Tx) error {
+	loc := time.FixedZone("WIB", 7*60*60)
+	SQL := `UPDATE orders SET status = $1, updated_at = $2 WHERE id = $3`
+	now := time.Now().In(loc)
+	if _, err := tx.Exec(SQL, status, now, orderID); err != nil {
+		return err
+	}
+	return nil
+}
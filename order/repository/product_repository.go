@@ -1,69 +1,125 @@
-package repository
-
-import (
-	"context"
-	"order/proto"
-	"time"
-
-	"github.com/sirupsen/logrus"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-)
-
-type ProductRepository interface {
-	Create(payload *proto.ProductRequest) (*proto.Empty, error)
-	GetProduct(payload *proto.GetProductRequest) (*proto.Product, error)
-	ListProducts(offset *proto.Offset) (*proto.ProductList, error)
-	UpdateProduct(payload *proto.Product) (*proto.Product, error)
-	DeleteProduct(ID *proto.GetProductRequest) (*proto.Empty, error)
-}
-
-type ProductRepositoryImpl struct {
-	client proto.ProductServiceClient
-}
-
-func NewProductRepositoryImpl() *ProductRepositoryImpl {
-	conn, err := grpc.NewClient("product-service:40001", grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		logrus.Fatalf("Failed to connect: %v", err)
-	}
-	logrus.Info("Connected to product service")
-
-	client := proto.NewProductServiceClient(conn)
-	return &ProductRepositoryImpl{client: client}
-}
-
-func (u *ProductRepositoryImpl) Create(payload *proto.ProductRequest) (*proto.Empty, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	return u.client.CreateProduct(ctx, payload)
-}
-
-func (u *ProductRepositoryImpl) GetProduct(payload *proto.GetProductRequest) (*proto.Product, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	return u.client.GetProduct(ctx, payload)
-}
-
-func (u *ProductRepositoryImpl) ListProducts(offset *proto.Offset) (*proto.ProductList, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	return u.client.ListProducts(ctx, offset)
-}
-
-func (u *ProductRepositoryImpl) UpdateProduct(payload *proto.Product) (*proto.Product, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	return u.client.UpdateProduct(ctx, payload)
-}
-
-func (u *ProductRepositoryImpl) DeleteProduct(ID *proto.GetProductRequest) (*proto.Empty, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	return u.client.DeleteProduct(ctx, ID)
-}
+package repository
+
+import (
+	"context"
+	"order/proto"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type ProductRepository interface {
+	Create(payload *proto.ProductRequest) (*proto.Empty, error)
+	GetProduct(payload *proto.GetProductRequest) (*proto.Product, error)
+	BatchGetProducts(payload *proto.BatchGetProductsRequest) (*proto.BatchGetProductsResponse, error)
+	ListProducts(offset *proto.Offset) (*proto.ProductList, error)
+	UpdateProduct(payload *proto.Product) (*proto.Product, error)
+	DeleteProduct(ID *proto.GetProductRequest) (*proto.Empty, error)
+	UpdateTestStock(payload *proto.UpdateTestStockRequest) (*proto.Empty, error)
+	AdjustVariantStock(payload *proto.AdjustVariantStockRequest) (*proto.ProductVariant, error)
+	AdjustStockBatch(payload *proto.AdjustStockBatchRequest) (*proto.ProductVariantList, error)
+	AllocateStock(payload *proto.AllocateStockRequest) (*proto.AllocateStockResponse, error)
+	QuotePrices(payload *proto.QuotePricesRequest) (*proto.PriceQuote, error)
+	DeliverDigitalItems(payload *proto.DeliverDigitalItemsRequest) (*proto.DigitalItemDeliveryList, error)
+}
+
+type ProductRepositoryImpl struct {
+	client proto.ProductServiceClient
+}
+
+func NewProductRepositoryImpl() *ProductRepositoryImpl {
+	conn, err := grpc.NewClient("product-service:40001", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		logrus.Fatalf("Failed to connect: %v", err)
+	}
+	logrus.Info("Connected to product service")
+
+	client := proto.NewProductServiceClient(conn)
+	return &ProductRepositoryImpl{client: client}
+}
+
+func (u *ProductRepositoryImpl) Create(payload *proto.ProductRequest) (*proto.Empty, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.CreateProduct(ctx, payload)
+}
+
+func (u *ProductRepositoryImpl) GetProduct(payload *proto.GetProductRequest) (*proto.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.GetProduct(ctx, payload)
+}
+
+func (u *ProductRepositoryImpl) BatchGetProducts(payload *proto.BatchGetProductsRequest) (*proto.BatchGetProductsResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.BatchGetProducts(ctx, payload)
+}
+
+func (u *ProductRepositoryImpl) ListProducts(offset *proto.Offset) (*proto.ProductList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ListProducts(ctx, offset)
+}
+
+func (u *ProductRepositoryImpl) UpdateProduct(payload *proto.Product) (*proto.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.UpdateProduct(ctx, payload)
+}
+
+func (u *ProductRepositoryImpl) DeleteProduct(ID *proto.GetProductRequest) (*proto.Empty, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.DeleteProduct(ctx, ID)
+}
+
+func (u *ProductRepositoryImpl) UpdateTestStock(payload *proto.UpdateTestStockRequest) (*proto.Empty, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.UpdateTestStock(ctx, payload)
+}
+
+func (u *ProductRepositoryImpl) AdjustVariantStock(payload *proto.AdjustVariantStockRequest) (*proto.ProductVariant, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.AdjustVariantStock(ctx, payload)
+}
+
+func (u *ProductRepositoryImpl) AdjustStockBatch(payload *proto.AdjustStockBatchRequest) (*proto.ProductVariantList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.AdjustStockBatch(ctx, payload)
+}
+
+func (u *ProductRepositoryImpl) AllocateStock(payload *proto.AllocateStockRequest) (*proto.AllocateStockResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.AllocateStock(ctx, payload)
+}
+
+func (u *ProductRepositoryImpl) QuotePrices(payload *proto.QuotePricesRequest) (*proto.PriceQuote, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.QuotePrices(ctx, payload)
+}
+
+func (u *ProductRepositoryImpl) DeliverDigitalItems(payload *proto.DeliverDigitalItemsRequest) (*proto.DigitalItemDeliveryList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.DeliverDigitalItems(ctx, payload)
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"order/helper"
+	"order/proto"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrDownloadNotAvailable = errors.New("delivery is not a download")
+	ErrDownloadExpired      = errors.New("download link has expired")
+	ErrDownloadLimitReached = errors.New("download limit reached")
+	ErrOrderNotDownloadable = errors.New("downloads of the order are not available")
+)
+
+// deliverDigitalItems delivers the digital items of a paid order that have not been
+// delivered yet: download items get a download that expires after the product's
+// link TTL, licence key items get keys from the product's pool. Running it again
+// only delivers what is missing.
+func (u *OrderService) deliverDigitalItems(orderID int) error {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	// The order stays locked while it is delivered, so two deliveries of it do not
+	// overlap
+	order, err := u.orderRepo.LockOrder(orderID, tx)
+	if err != nil {
+		return err
+	}
+	if order.Status != "paid" && order.Status != "fulfilled" {
+		return fmt.Errorf("a %s order cannot be delivered", order.Status)
+	}
+
+	items, err := u.orderItemRepo.LockOrderItems(orderID, tx)
+	if err != nil {
+		return err
+	}
+	existing, err := u.deliveryRepo.GetDeliveriesByOrderID(orderID, u.DB)
+	if err != nil {
+		return err
+	}
+	delivered := make(map[int32]bool, len(existing))
+	for _, delivery := range existing {
+		delivered[delivery.OrderItemId] = true
+	}
+
+	var digital []*proto.DigitalItem
+	for _, item := range items {
+		if item.Digital && !delivered[item.Id] {
+			digital = append(digital, &proto.DigitalItem{
+				OrderItemId: item.Id,
+				ProductId:   item.ProductId,
+				Quantity:    item.Quantity,
+			})
+		}
+	}
+	if len(digital) == 0 {
+		return nil
+	}
+
+	result, err := u.productRepo.DeliverDigitalItems(&proto.DeliverDigitalItemsRequest{
+		OrderId:  int32(orderID),
+		Livemode: order.Livemode,
+		Items:    digital,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, d := range result.Deliveries {
+		if err := u.deliveryRepo.CreateDelivery(&proto.OrderDelivery{
+			OrderId:       int32(orderID),
+			OrderItemId:   d.OrderItemId,
+			ProductId:     d.Digital.ProductId,
+			Delivery:      d.Digital.Delivery,
+			FileKey:       d.Digital.FileKey,
+			FileName:      d.Digital.FileName,
+			ContentType:   d.Digital.ContentType,
+			LicenseKeys:   d.LicenseKeys,
+			DownloadLimit: d.Digital.DownloadLimit,
+		}, d.Digital.LinkTtlHours, tx); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rollback = false
+
+	logrus.Infof("Delivered %d digital items of order %d", len(result.Deliveries), orderID)
+	return nil
+}
+
+// DeliverOrder delivers the digital items of a user's paid order again, for when
+// delivery failed at payment time, and returns the order with its deliveries
+func (u *OrderService) DeliverOrder(payload *proto.GetOrderByIdRequest) (*proto.Order, error) {
+	order, err := u.orderRepo.GetOrderById(int(payload.OrderId), int(payload.UserId), u.DB)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, errors.New("order not found")
+	}
+
+	if err := u.deliverDigitalItems(int(payload.OrderId)); err != nil {
+		return nil, err
+	}
+	return u.GetOrderById(payload)
+}
+
+// RecordDownload counts a download of a delivery, refusing it once the link has
+// expired, its downloads are used up or the order was refunded or cancelled. The
+// returned delivery has the file key and content type the gateway serves the file
+// with; with check_only the download is only checked, so the gateway can count it
+// once the file opened.
+func (u *OrderService) RecordDownload(req *proto.RecordDownloadRequest) (*proto.OrderDelivery, error) {
+	delivery, err := u.deliveryRepo.GetDelivery(int(req.DeliveryId), u.DB)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Delivery != "download" {
+		return nil, ErrDownloadNotAvailable
+	}
+	if delivery.Expired {
+		return nil, ErrDownloadExpired
+	}
+	if delivery.DownloadCount >= delivery.DownloadLimit {
+		return nil, ErrDownloadLimitReached
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	// Downloads stop with a refund or cancellation of the order
+	order, err := u.orderRepo.LockOrder(int(delivery.OrderId), tx)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != "paid" && order.Status != "fulfilled" {
+		return nil, fmt.Errorf("%w: it is %s", ErrOrderNotDownloadable, order.Status)
+	}
+	if req.CheckOnly {
+		return delivery, nil
+	}
+
+	counted, err := u.deliveryRepo.CountDownload(int(req.DeliveryId), tx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Another download took the last one, or the link expired meanwhile
+			return nil, ErrDownloadLimitReached
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	return counted, nil
+}
+
+// publicDeliveries signs the download links of deliveries and leaves out where the
+// files are kept
+func publicDeliveries(deliveries []*proto.OrderDelivery) []*proto.OrderDelivery {
+	for _, delivery := range deliveries {
+		if delivery.Delivery == "download" && !delivery.Expired && delivery.DownloadCount < delivery.DownloadLimit {
+			expires, err := time.Parse(time.RFC3339, delivery.ExpiresAt)
+			if err != nil {
+				logrus.Warnf("Delivery %d has an invalid expiry %q: %v", delivery.Id, delivery.ExpiresAt, err)
+			} else {
+				delivery.DownloadUrl = helper.DownloadURL(delivery.Id, expires.Unix())
+			}
+		}
+		delivery.FileKey = ""
+		delivery.ContentType = ""
+	}
+	return deliveries
+}
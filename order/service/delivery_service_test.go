@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"order/proto"
+	"order/repository"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// testDB connects to TEST_DATABASE_URL, a migrated database the test may write to,
+// and skips the test when it is not set
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	DB, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := DB.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { DB.Close() })
+	return DB
+}
+
+// keyAssigningProducts assigns licence keys the way the product service does: in
+// its own transaction, recording the keys' order and order item while the order
+// service waits on the call with the order locked
+type keyAssigningProducts struct {
+	repository.ProductRepository
+	DB *sql.DB
+}
+
+func (f *keyAssigningProducts) DeliverDigitalItems(payload *proto.DeliverDigitalItemsRequest) (*proto.DigitalItemDeliveryList, error) {
+	tx, err := f.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Fail rather than hang if assigning the keys waits on the order service's locks
+	if _, err := tx.Exec("SET LOCAL lock_timeout = '2s'"); err != nil {
+		return nil, err
+	}
+
+	result := &proto.DigitalItemDeliveryList{}
+	for _, item := range payload.Items {
+		rows, err := tx.Query(`UPDATE license_keys SET order_id = $1, order_item_id = $2, assigned_at = NOW()
+			WHERE id IN (SELECT id FROM license_keys WHERE product_id = $3 AND order_item_id IS NULL ORDER BY id LIMIT $4)
+			RETURNING license_key`, payload.OrderId, item.OrderItemId, item.ProductId, item.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		var keys []string
+		for rows.Next() {
+			var key string
+			if err := rows.Scan(&key); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			keys = append(keys, key)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		result.Deliveries = append(result.Deliveries, &proto.DigitalItemDelivery{
+			OrderItemId: item.OrderItemId,
+			Digital:     &proto.DigitalDelivery{ProductId: item.ProductId, Delivery: "license_key"},
+			LicenseKeys: keys,
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func TestDeliverDigitalItemsAssignsLicenseKeys(t *testing.T) {
+	DB := testDB(t)
+
+	suffix := time.Now().UnixNano()
+	var productID, orderID, itemID int
+	if err := DB.QueryRow(`INSERT INTO products(name, price, stock, slug, kind) VALUES ($1, 10, 0, $2, 'digital') RETURNING id`,
+		fmt.Sprintf("Test licence %d", suffix), fmt.Sprintf("test-licence-%d", suffix)).Scan(&productID); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		DB.Exec("DELETE FROM order_deliveries WHERE product_id = $1", productID)
+		DB.Exec("DELETE FROM license_keys WHERE product_id = $1", productID)
+		DB.Exec("DELETE FROM orders WHERE id = $1", orderID)
+		DB.Exec("DELETE FROM products WHERE id = $1", productID)
+	})
+	if _, err := DB.Exec(`INSERT INTO license_keys(product_id, license_key) VALUES ($1, 'KEY-1'), ($1, 'KEY-2')`, productID); err != nil {
+		t.Fatal(err)
+	}
+	if err := DB.QueryRow(`INSERT INTO orders(user_id, total_price, status, livemode) VALUES (1, 20, 'paid', true) RETURNING id`).Scan(&orderID); err != nil {
+		t.Fatal(err)
+	}
+	if err := DB.QueryRow(`INSERT INTO order_items(order_id, product_id, quantity, price, product_name, digital) VALUES ($1, $2, 2, 10, 'Test licence', true) RETURNING id`,
+		orderID, productID).Scan(&itemID); err != nil {
+		t.Fatal(err)
+	}
+
+	deliveryRepo := repository.NewDeliveryRepositoryImpl()
+	svc := NewOrderItemService(DB, repository.NewOrderRepositoryImpl(), repository.NewOrderItemsRepositoryImpl(),
+		&keyAssigningProducts{DB: DB}, deliveryRepo, context.Background())
+
+	done := make(chan error, 1)
+	go func() { done <- svc.deliverDigitalItems(orderID) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("deliverDigitalItems() error = %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("deliverDigitalItems() did not return")
+	}
+
+	deliveries, err := deliveryRepo.GetDeliveriesByOrderID(orderID, DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 {
+		t.Fatalf("got %d deliveries, want 1", len(deliveries))
+	}
+	if d := deliveries[0]; d.OrderItemId != int32(itemID) || len(d.LicenseKeys) != 2 {
+		t.Errorf("delivery = item %d with keys %v, want item %d with 2 keys", d.OrderItemId, d.LicenseKeys, itemID)
+	}
+
+	// Delivering again finds nothing missing
+	if err := svc.deliverDigitalItems(orderID); err != nil {
+		t.Fatalf("second deliverDigitalItems() error = %v", err)
+	}
+	var assigned int
+	if err := DB.QueryRow("SELECT COUNT(*) FROM license_keys WHERE order_item_id = $1", itemID).Scan(&assigned); err != nil {
+		t.Fatal(err)
+	}
+	if assigned != 2 {
+		t.Errorf("%d keys assigned, want 2", assigned)
+	}
+}
@@ -1,227 +1,600 @@
-package service
-
-import (
-	"context"
-	"database/sql"
-	"errors"
-	"order/cmd/db"
-	"order/helper"
-	"order/proto"
-	"order/repository"
-	"order/transport/kafka"
-	"os"
-	"time"
-
-	"github.com/sirupsen/logrus"
-)
-
-const (
-	orderCacheTTL     = 10 * time.Minute
-	orderListCacheTTL = 5 * time.Minute
-)
-
-type OrderService struct {
-	DB            *sql.DB
-	orderRepo     repository.OrderRepository
-	orderItemRepo repository.OrderItemsRepository
-	productRepo   repository.ProductRepository
-	ctx           context.Context
-}
-
-func NewOrderItemService(DB *sql.DB, orderRepo repository.OrderRepository, orderItemRepo repository.OrderItemsRepository, productRepo repository.ProductRepository, ctx context.Context) *OrderService {
-	return &OrderService{
-		DB:            DB,
-		orderRepo:     orderRepo,
-		orderItemRepo: orderItemRepo,
-		productRepo:   productRepo,
-		ctx:           ctx,
-	}
-}
-
-func (u *OrderService) CreateOrder(payload *proto.CreateOrderRequest) (*proto.OrderResponse, error) {
-	var totalPrices float64
-
-	addr := []string{os.Getenv("KAFKA_BROKER_URL")}
-	topic := os.Getenv("KAFKA_ORDER_TOPIC")
-
-	var products []*proto.Product
-
-	logrus.Info("Calculating total price")
-	for _, v := range payload.Items {
-		product, err := u.productRepo.GetProduct(&proto.GetProductRequest{Id: v.ProductId})
-		if err != nil {
-			return nil, err
-		}
-		products = append(products, product)
-
-		if product.Stock < v.Quantity {
-			return nil, errors.New("stock is not enough")
-		}
-		totalPrice := float64(v.Quantity) * product.Price
-		totalPrices += totalPrice
-	}
-	payload.TotalPrice = totalPrices
-
-	tx, err := u.DB.Begin()
-	if err != nil {
-		return nil, err
-	}
-
-	rollback := true
-	defer func() {
-		if rollback {
-			if rErr := tx.Rollback(); rErr != nil {
-				logrus.Errorf("Rollback error: %v", rErr)
-			}
-		}
-	}()
-
-	logrus.Info("Create order")
-	orderID, err := u.orderRepo.CreateOrder(payload, payload.TotalPrice, tx)
-	if err != nil {
-		return nil, err
-	}
-
-	logrus.Info("Saving order items")
-	for _, v := range payload.Items {
-		orderItemPayload := &proto.OrderItemRequest{
-			OrderId:   int32(orderID),
-			ProductId: v.ProductId,
-			Quantity:  v.Quantity,
-		}
-		err := u.orderItemRepo.CreateOrderItems(orderItemPayload, tx)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	logrus.Info("Updating products")
-	for i, v := range payload.Items {
-		if _, err := u.productRepo.UpdateProduct(&proto.Product{
-			Id:          products[i].Id,
-			Name:        products[i].Name,
-			Description: products[i].Description,
-			Price:       products[i].Price,
-			Stock:       products[i].Stock - v.Quantity,
-			CreatedAt:   products[i].CreatedAt,
-			UpdatedAt:   products[i].UpdatedAt,
-		}); err != nil {
-			return nil, err
-		}
-	}
-
-	// Commit transaction sebelum publish Kafka
-	logrus.Info("Committing transaction")
-	if err := tx.Commit(); err != nil {
-		return nil, err
-	}
-	rollback = false
-
-	logrus.Info("Sending message to kafka")
-	partition, offset, err := kafka.SendMessage(addr, topic, &proto.Order{
-		Id:         int32(orderID),
-		UserId:     payload.UserId,
-		Status:     "Pending",
-		TotalPrice: payload.TotalPrice,
-	})
-	if err != nil {
-		logrus.Errorf("Failed to send message to Kafka: %v", err)
-	} else {
-		logrus.Infof("Message sent to topic: %s partition: %d, offset: %d", topic, partition, offset)
-	}
-
-	if err := db.DeleteCacheByPattern(u.ctx, "orders:user*"); err != nil {
-		logrus.Warnf("Failed to invalidate product list cache: %v", err)
-	}
-
-	return &proto.OrderResponse{
-		Order: &proto.Order{
-			Id:         int32(orderID),
-			TotalPrice: payload.TotalPrice,
-			CreatedAt:  time.Now().Format("2006-01-02 15:04:05"),
-			UpdatedAt:  time.Now().Format("2006-01-02 15:04:05"),
-		},
-	}, nil
-}
-
-func (u *OrderService) GetOrderByUserID(payload *proto.GetOrderRequest) ([]*proto.Order, error) {
-	page := (payload.Offset / 15) + 1
-	key := db.RedisOrderKey(int(payload.UserId), int(page))
-
-	cachedList, err := db.GetCacheOrderList(u.ctx, key)
-	if err == nil {
-		logrus.Infof("Cache HIT for order list page: %d", page)
-		return cachedList, nil
-	}
-	logrus.Infof("Cache MISS for order list page: %d", page)
-
-	orderResponse, err := u.orderRepo.GetOrderByUserID(payload, u.DB)
-	if err != nil {
-		return nil, err
-	}
-
-	if err := db.SetCache(u.ctx, key, orderResponse, orderListCacheTTL); err != nil {
-		logrus.Warnf("Failed to cache product list: %v", err)
-	} else {
-		logrus.Debugf("Product list page %d cached successfully", page)
-	}
-	return orderResponse, nil
-}
-
-func (u *OrderService) GetOrderById(payload *proto.GetOrderByIdRequest) (*proto.Order, error) {
-	// Try to get from cache first
-	key := db.RedisOrderByIdKey(int(payload.OrderId))
-
-	cachedOrder, err := db.GetCacheOrder(u.ctx, key)
-	if err == nil && cachedOrder != nil {
-		logrus.Infof("Cache HIT for order ID: %d", payload.OrderId)
-		return cachedOrder, nil
-	}
-	logrus.Infof("Cache MISS for order ID: %d", payload.OrderId)
-
-	// Get from database
-	order, err := u.orderRepo.GetOrderById(int(payload.OrderId), int(payload.UserId), u.DB)
-	if err != nil {
-		return nil, err
-	}
-
-	if order == nil {
-		return nil, errors.New("order not found")
-	}
-
-	// Cache the result
-	if err := db.SetCache(u.ctx, key, order, orderCacheTTL); err != nil {
-		logrus.Warnf("Failed to cache order: %v", err)
-	} else {
-		logrus.Debugf("Order ID %d cached successfully", payload.OrderId)
-	}
-
-	return order, nil
-}
-
-func (u *OrderService) UpdateOrderStatus(status string, orderID int) error {
-	tx, err := u.DB.Begin()
-	if err != nil {
-		return err
-	}
-	defer helper.CommitOrRollback(tx)
-
-	if err := u.orderRepo.UpdateOrderStatus(status, orderID, tx); err != nil {
-		return err
-	}
-
-	// Invalidate order list cache (all users)
-	if err := db.DeleteCacheByPattern(u.ctx, "orders:user*"); err != nil {
-		logrus.Warnf("Failed to invalidate order list cache: %v", err)
-	}
-
-	// Invalidate single order cache
-	orderCacheKey := db.RedisOrderByIdKey(orderID)
-	if err := db.DeleteCache(u.ctx, orderCacheKey); err != nil {
-		logrus.Warnf("Failed to invalidate order cache for ID %d: %v", orderID, err)
-	} else {
-		logrus.Infof("Successfully invalidated cache for order ID: %d", orderID)
-	}
-
-	return nil
-}
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"order/cmd/db"
+	"order/proto"
+	"order/repository"
+	"order/transport/kafka"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	orderCacheTTL     = 10 * time.Minute
+	orderListCacheTTL = 5 * time.Minute
+
+	// stockActor is recorded on the stock movements made for orders
+	stockActor = "order-service"
+)
+
+type OrderService struct {
+	DB            *sql.DB
+	orderRepo     repository.OrderRepository
+	orderItemRepo repository.OrderItemsRepository
+	productRepo   repository.ProductRepository
+	deliveryRepo  repository.DeliveryRepository
+	ctx           context.Context
+}
+
+func NewOrderItemService(DB *sql.DB, orderRepo repository.OrderRepository, orderItemRepo repository.OrderItemsRepository, productRepo repository.ProductRepository, deliveryRepo repository.DeliveryRepository, ctx context.Context) *OrderService {
+	return &OrderService{
+		DB:            DB,
+		orderRepo:     orderRepo,
+		orderItemRepo: orderItemRepo,
+		productRepo:   productRepo,
+		deliveryRepo:  deliveryRepo,
+		ctx:           ctx,
+	}
+}
+
+func (u *OrderService) CreateOrder(payload *proto.CreateOrderRequest) (*proto.OrderResponse, error) {
+	var totalPrices float64
+
+	addr := []string{os.Getenv("KAFKA_BROKER_URL")}
+	topic := os.Getenv("KAFKA_ORDER_TOPIC")
+
+	var lines []*orderLine
+	var lineProducts []*proto.Product
+
+	// All products of the cart are fetched in one call, however many lines it has
+	productIDs := make([]int32, 0, len(payload.Items))
+	for _, v := range payload.Items {
+		productIDs = append(productIDs, v.ProductId)
+	}
+	batch, err := u.productRepo.BatchGetProducts(&proto.BatchGetProductsRequest{Ids: productIDs})
+	if err != nil {
+		return nil, err
+	}
+	products := make(map[int32]*proto.Product, len(batch.Products))
+	for _, product := range batch.Products {
+		products[int32(product.Id)] = product
+	}
+
+	logrus.Info("Checking stock")
+	for _, v := range payload.Items {
+		product, ok := products[v.ProductId]
+		if !ok {
+			return nil, fmt.Errorf("product %d not found", v.ProductId)
+		}
+		if product.ArchivedAt != "" {
+			return nil, fmt.Errorf("product %d is no longer available", product.Id)
+		}
+
+		variant, err := selectVariant(product, v.VariantId)
+		if err != nil {
+			return nil, err
+		}
+
+		// Digital products have no stock. Licence keys come from a pool, which live
+		// orders check here; test orders get generated keys.
+		if product.Kind == "digital" {
+			if product.Digital == nil {
+				return nil, fmt.Errorf("digital product %d has no delivery set up", product.Id)
+			}
+			if payload.Livemode && product.Digital.Delivery == "license_key" && product.Digital.AvailableKeys < v.Quantity {
+				return nil, fmt.Errorf("not enough licence keys for product %d", product.Id)
+			}
+			lines = append(lines, &orderLine{variant: variant, digital: true})
+			lineProducts = append(lineProducts, product)
+			continue
+		}
+
+		// Test-mode orders draw from the separate test stock pool
+		stock := variant.Stock
+		if !payload.Livemode {
+			stock = variant.TestStock
+		}
+		if stock < v.Quantity {
+			return nil, errors.New("stock is not enough")
+		}
+
+		if product.Kind == "bundle" && len(product.Components) == 0 {
+			return nil, fmt.Errorf("bundle %d has no components", product.Id)
+		}
+		lines = append(lines, &orderLine{variant: variant})
+		lineProducts = append(lineProducts, product)
+	}
+
+	// The product service prices every line, applying the best price rule of the
+	// user's customer groups and quantity breaks
+	logrus.Info("Calculating total price")
+	quoteLines := make([]*proto.QuoteLine, 0, len(payload.Items))
+	for i, v := range payload.Items {
+		quoteLines = append(quoteLines, &proto.QuoteLine{
+			ProductId: v.ProductId,
+			VariantId: lines[i].variant.Id,
+			Quantity:  v.Quantity,
+		})
+	}
+	quote, err := u.productRepo.QuotePrices(&proto.QuotePricesRequest{UserId: payload.UserId, Lines: quoteLines})
+	if err != nil {
+		return nil, err
+	}
+	if len(quote.Lines) != len(lines) {
+		return nil, fmt.Errorf("quote has %d lines for %d order lines", len(quote.Lines), len(lines))
+	}
+	for i, quoted := range quote.Lines {
+		line := lines[i]
+		line.price = quoted.UnitPrice
+		line.priceRuleID = quoted.PriceRuleId
+		if lineProducts[i].Kind == "bundle" {
+			line.components = bundleShares(lineProducts[i].Components, line.price)
+		}
+
+		totalPrice := float64(quoted.Quantity) * line.price
+		totalPrices += totalPrice
+	}
+	payload.TotalPrice = totalPrices
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	// Stock taken from the product service is outside this transaction, so it is
+	// handed back to the warehouses it came from if the order is not committed
+	var taken []*proto.StockAllocation
+	var reference string
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+			if len(taken) > 0 {
+				releases := make([]*proto.AdjustVariantStockRequest, 0, len(taken))
+				for _, t := range taken {
+					releases = append(releases, &proto.AdjustVariantStockRequest{
+						VariantId:     t.VariantId,
+						Delta:         t.Quantity,
+						Livemode:      payload.Livemode,
+						Reason:        "reservation_release",
+						ReferenceType: "order",
+						ReferenceId:   reference,
+						Actor:         stockActor,
+						Note:          "Order was not saved",
+						WarehouseId:   t.WarehouseId,
+					})
+				}
+				if _, err := u.productRepo.AdjustStockBatch(&proto.AdjustStockBatchRequest{Adjustments: releases}); err != nil {
+					logrus.Errorf("Failed to restore stock of %d order lines: %v", len(releases), err)
+				}
+			}
+		}
+	}()
+
+	logrus.Info("Create order")
+	orderID, err := u.orderRepo.CreateOrder(payload, payload.TotalPrice, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Digital lines are not shipped, so they are saved without a warehouse and
+	// delivered once the order is paid
+	logrus.Info("Saving digital order items")
+	for i, v := range payload.Items {
+		if !lines[i].digital {
+			continue
+		}
+		if _, err := u.orderItemRepo.CreateOrderItems(&proto.OrderItemRequest{
+			OrderId:     int32(orderID),
+			ProductId:   v.ProductId,
+			VariantId:   lines[i].variant.Id,
+			Quantity:    v.Quantity,
+			Price:       lines[i].price,
+			PriceRuleId: lines[i].priceRuleID,
+			ProductName: lineProducts[i].Name,
+			Sku:         lines[i].variant.Sku,
+			Digital:     true,
+		}, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	// The product service picks the warehouses every other line ships from and takes
+	// their stock in one all-or-nothing call. allocated maps the lines it is sent
+	// back to the lines of the order.
+	logrus.Info("Allocating stock")
+	reference = strconv.Itoa(orderID)
+	allocationLines := make([]*proto.AllocationLine, 0, len(payload.Items))
+	var allocated []int
+	for i, v := range payload.Items {
+		if lines[i].digital {
+			continue
+		}
+		allocationLines = append(allocationLines, &proto.AllocationLine{
+			VariantId: lines[i].variant.Id,
+			Quantity:  v.Quantity,
+		})
+		allocated = append(allocated, i)
+	}
+	allocation := &proto.AllocateStockResponse{}
+	if len(allocationLines) > 0 {
+		allocation, err = u.productRepo.AllocateStock(&proto.AllocateStockRequest{
+			Lines:         allocationLines,
+			Livemode:      payload.Livemode,
+			Strategy:      os.Getenv("STOCK_ALLOCATION_STRATEGY"),
+			Latitude:      payload.ShippingLatitude,
+			Longitude:     payload.ShippingLongitude,
+			ReferenceType: "order",
+			ReferenceId:   reference,
+			Actor:         stockActor,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	taken = allocation.Allocations
+
+	// A line split across warehouses is saved as one order item per warehouse. A
+	// bundle is saved as an item of its own, with the components it ships as under it.
+	logrus.Info("Saving order items")
+	bundleItems := make(map[int]int)
+	for _, a := range allocation.Allocations {
+		if int(a.Line) >= len(allocated) {
+			return nil, fmt.Errorf("allocation refers to unknown line %d", a.Line)
+		}
+		line := allocated[a.Line]
+		v := payload.Items[line]
+		orderItemPayload := &proto.OrderItemRequest{
+			OrderId:       int32(orderID),
+			ProductId:     v.ProductId,
+			VariantId:     a.VariantId,
+			Quantity:      a.Quantity,
+			Price:         lines[line].price,
+			WarehouseId:   a.WarehouseId,
+			PriceRuleId:   lines[line].priceRuleID,
+			ProductName:   lineProducts[line].Name,
+			Sku:           lines[line].variant.Sku,
+			WarehouseCode: a.WarehouseCode,
+		}
+
+		if a.BundleVariantId != 0 {
+			parentID, ok := bundleItems[line]
+			if !ok {
+				parentID, err = u.orderItemRepo.CreateOrderItems(&proto.OrderItemRequest{
+					OrderId:     int32(orderID),
+					ProductId:   v.ProductId,
+					VariantId:   a.BundleVariantId,
+					Quantity:    v.Quantity,
+					Price:       lines[line].price,
+					PriceRuleId: lines[line].priceRuleID,
+					ProductName: lineProducts[line].Name,
+					Sku:         lines[line].variant.Sku,
+				}, tx)
+				if err != nil {
+					return nil, err
+				}
+				bundleItems[line] = parentID
+			}
+
+			component, ok := lines[line].components[a.VariantId]
+			if !ok {
+				return nil, fmt.Errorf("variant %d is not a component of product %d", a.VariantId, v.ProductId)
+			}
+			orderItemPayload.ProductId = component.productID
+			orderItemPayload.ProductName = component.productName
+			orderItemPayload.Sku = component.sku
+			orderItemPayload.Price = component.price
+			orderItemPayload.ParentItemId = int32(parentID)
+			orderItemPayload.PriceRuleId = 0
+		}
+
+		if _, err := u.orderItemRepo.CreateOrderItems(orderItemPayload, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	// Commit transaction sebelum publish Kafka
+	logrus.Info("Committing transaction")
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Info("Sending message to kafka")
+	partition, offset, err := kafka.SendMessage(addr, topic, &proto.Order{
+		Id:         int32(orderID),
+		UserId:     payload.UserId,
+		Status:     "Pending",
+		TotalPrice: payload.TotalPrice,
+		Livemode:   payload.Livemode,
+	})
+	if err != nil {
+		logrus.Errorf("Failed to send message to Kafka: %v", err)
+	} else {
+		logrus.Infof("Message sent to topic: %s partition: %d, offset: %d", topic, partition, offset)
+	}
+
+	if err := db.DeleteCacheByPattern(u.ctx, "orders:user*"); err != nil {
+		logrus.Warnf("Failed to invalidate product list cache: %v", err)
+	}
+
+	return &proto.OrderResponse{
+		Order: &proto.Order{
+			Id:         int32(orderID),
+			TotalPrice: payload.TotalPrice,
+			Livemode:   payload.Livemode,
+			CreatedAt:  time.Now().Format("2006-01-02 15:04:05"),
+			UpdatedAt:  time.Now().Format("2006-01-02 15:04:05"),
+		},
+	}, nil
+}
+
+func (u *OrderService) GetOrderByUserID(payload *proto.GetOrderRequest) ([]*proto.Order, error) {
+	page := (payload.Offset / 15) + 1
+	key := db.RedisOrderKey(int(payload.UserId), int(page), payload.Livemode)
+
+	cachedList, err := db.GetCacheOrderList(u.ctx, key)
+	if err == nil {
+		logrus.Infof("Cache HIT for order list page: %d", page)
+		return cachedList, nil
+	}
+	logrus.Infof("Cache MISS for order list page: %d", page)
+
+	orderResponse, err := u.orderRepo.GetOrderByUserID(payload, u.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.SetCache(u.ctx, key, orderResponse, orderListCacheTTL); err != nil {
+		logrus.Warnf("Failed to cache product list: %v", err)
+	} else {
+		logrus.Debugf("Product list page %d cached successfully", page)
+	}
+	return orderResponse, nil
+}
+
+// GetOrderById returns an order of the user with its items and deliveries. Deliveries
+// are read every time rather than cached: they hold licence keys and download links
+// that expire.
+func (u *OrderService) GetOrderById(payload *proto.GetOrderByIdRequest) (*proto.Order, error) {
+	order, err := u.getOrder(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := u.deliveryRepo.GetDeliveriesByOrderID(int(payload.OrderId), u.DB)
+	if err != nil {
+		return nil, err
+	}
+	order.Deliveries = publicDeliveries(deliveries)
+
+	return order, nil
+}
+
+// getOrder returns an order of the user with its items, from the cache when it can
+func (u *OrderService) getOrder(payload *proto.GetOrderByIdRequest) (*proto.Order, error) {
+	// Try to get from cache first
+	key := db.RedisOrderByIdKey(int(payload.OrderId), int(payload.UserId))
+
+	cachedOrder, err := db.GetCacheOrder(u.ctx, key)
+	if err == nil && cachedOrder != nil {
+		logrus.Infof("Cache HIT for order ID: %d", payload.OrderId)
+		return cachedOrder, nil
+	}
+	logrus.Infof("Cache MISS for order ID: %d", payload.OrderId)
+
+	// Get from database
+	order, err := u.orderRepo.GetOrderById(int(payload.OrderId), int(payload.UserId), u.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	if order == nil {
+		return nil, errors.New("order not found")
+	}
+
+	items, err := u.orderItemRepo.GetOrderItemsByOrderID(int(payload.OrderId), u.DB)
+	if err != nil {
+		return nil, err
+	}
+	order.OrderItems = nestComponents(items)
+
+	// Cache the result
+	if err := db.SetCache(u.ctx, key, order, orderCacheTTL); err != nil {
+		logrus.Warnf("Failed to cache order: %v", err)
+	} else {
+		logrus.Debugf("Order ID %d cached successfully", payload.OrderId)
+	}
+
+	return order, nil
+}
+
+func (u *OrderService) UpdateOrderStatus(status string, orderID int) error {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	order, err := u.orderRepo.LockOrder(orderID, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := u.orderRepo.UpdateOrderStatus(status, orderID, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rollback = false
+
+	// Digital items are delivered once the order is paid. A failed delivery does not
+	// fail the payment; the customer can ask for it again.
+	if status == "paid" {
+		if err := u.deliverDigitalItems(orderID); err != nil {
+			logrus.Errorf("Failed to deliver digital items of order %d: %v", orderID, err)
+		}
+		u.publishOrderPaid(order)
+	}
+
+	// Invalidate order list cache (all users)
+	if err := db.DeleteCacheByPattern(u.ctx, "orders:user*"); err != nil {
+		logrus.Warnf("Failed to invalidate order list cache: %v", err)
+	}
+
+	// Invalidate single order cache
+	orderCacheKey := db.RedisOrderByIdKey(orderID, int(order.UserId))
+	if err := db.DeleteCache(u.ctx, orderCacheKey); err != nil {
+		logrus.Warnf("Failed to invalidate order cache for ID %d: %v", orderID, err)
+	} else {
+		logrus.Infof("Successfully invalidated cache for order ID: %d", orderID)
+	}
+
+	return nil
+}
+
+// publishOrderPaid sends a paid order with its items to KAFKA_ORDER_PAID_TOPIC, where
+// the product service counts what was bought together. Like order.created it is sent
+// once the change is committed; a failed send is only logged.
+func (u *OrderService) publishOrderPaid(order *proto.Order) {
+	topic := os.Getenv("KAFKA_ORDER_PAID_TOPIC")
+	if topic == "" {
+		return
+	}
+
+	items, err := u.orderItemRepo.GetOrderItemsByOrderID(int(order.Id), u.DB)
+	if err != nil {
+		logrus.Errorf("Failed to read items of paid order %d: %v", order.Id, err)
+		return
+	}
+	order.Status = "paid"
+	order.OrderItems = items
+
+	partition, offset, err := kafka.SendMessage([]string{os.Getenv("KAFKA_BROKER_URL")}, topic, order)
+	if err != nil {
+		logrus.Errorf("Failed to send paid order %d to Kafka: %v", order.Id, err)
+		return
+	}
+	logrus.Infof("Message sent to topic: %s partition: %d, offset: %d", topic, partition, offset)
+}
+
+// HasPurchased tells the product service whether a user may review a product
+func (u *OrderService) HasPurchased(req *proto.HasPurchasedRequest) (*proto.HasPurchasedResponse, error) {
+	purchased, err := u.orderRepo.HasPurchased(int(req.UserId), int(req.ProductId), u.DB)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.HasPurchasedResponse{Purchased: purchased}, nil
+}
+
+// HasOrders tells the product service whether a product is in order history, which
+// it then may not purge or turn digital
+func (u *OrderService) HasOrders(req *proto.HasOrdersRequest) (*proto.HasOrdersResponse, error) {
+	ordered, err := u.orderRepo.HasOrders(int(req.ProductId), u.DB)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.HasOrdersResponse{Ordered: ordered}, nil
+}
+
+// orderLine is the variant chosen for an order item, the unit price it sells at and
+// the price rule that set it. A bundle also has the components it is made of, by
+// variant ID. A digital line is delivered once the order is paid instead of shipped.
+type orderLine struct {
+	variant     *proto.ProductVariant
+	digital     bool
+	price       float64
+	priceRuleID int32
+	components  map[int32]bundleShare
+}
+
+// bundleShare is a bundle component and the unit price it sells at within the bundle,
+// with the name and SKU its order items are saved with
+type bundleShare struct {
+	productID   int32
+	productName string
+	sku         string
+	price       float64
+}
+
+// bundleShares splits the price of a bundle between its components in proportion to
+// what they sell for on their own, so returning a component refunds its share.
+// Components without prices share the bundle price equally.
+func bundleShares(components []*proto.BundleComponent, price float64) map[int32]bundleShare {
+	var total float64
+	var units int32
+	for _, component := range components {
+		total += component.UnitPrice * float64(component.Quantity)
+		units += component.Quantity
+	}
+
+	shares := make(map[int32]bundleShare, len(components))
+	for _, component := range components {
+		share := price / float64(units)
+		if total > 0 {
+			share = price * component.UnitPrice / total
+		}
+		shares[component.VariantId] = bundleShare{
+			productID:   component.ProductId,
+			productName: component.ProductName,
+			sku:         component.Sku,
+			price:       share,
+		}
+	}
+	return shares
+}
+
+// nestComponents moves the component items of bundles under their bundle item
+func nestComponents(items []*proto.OrderItem) []*proto.OrderItem {
+	byID := make(map[int32]*proto.OrderItem, len(items))
+	for _, item := range items {
+		byID[item.Id] = item
+	}
+
+	nested := make([]*proto.OrderItem, 0, len(items))
+	for _, item := range items {
+		if parent, ok := byID[item.ParentItemId]; ok {
+			parent.Components = append(parent.Components, item)
+			continue
+		}
+		nested = append(nested, item)
+	}
+	return nested
+}
+
+// selectVariant returns the requested variant of a product. Without a variant_id
+// the product's only variant is used, which covers products without options.
+func selectVariant(product *proto.Product, variantID int32) (*proto.ProductVariant, error) {
+	if variantID == 0 {
+		if len(product.Variants) == 1 {
+			return product.Variants[0], nil
+		}
+		return nil, fmt.Errorf("variant_id is required for product %d", product.Id)
+	}
+
+	for _, variant := range product.Variants {
+		if variant.Id == variantID {
+			return variant, nil
+		}
+	}
+	return nil, fmt.Errorf("variant %d not found for product %d", variantID, product.Id)
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"order/cmd/db"
+	"order/proto"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ReturnOrderItems puts returned items of a paid order back in stock and counts them
+// as returned. Returning a bundle item returns its components with it; a component
+// can also be returned on its own, for its share of the bundle price. The response
+// holds what the returned items were paid, which a dry run works out without
+// returning anything. Digital items cannot be returned. The payment service returns
+// items with a refund of their order, whether it was paid on its own or in an intent.
+func (u *OrderService) ReturnOrderItems(req *proto.ReturnOrderItemsRequest) (*proto.ReturnOrderItemsResponse, error) {
+	if len(req.Items) == 0 {
+		return nil, errors.New("items are required")
+	}
+	for _, item := range req.Items {
+		if item.Quantity <= 0 {
+			return nil, errors.New("quantity must be positive")
+		}
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	// Stock is put back by the product service outside this transaction, so it is
+	// taken again if the return is not committed
+	var restocked []*proto.AdjustVariantStockRequest
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+			if len(restocked) > 0 {
+				reverts := make([]*proto.AdjustVariantStockRequest, 0, len(restocked))
+				for _, r := range restocked {
+					reverts = append(reverts, &proto.AdjustVariantStockRequest{
+						VariantId:     r.VariantId,
+						Delta:         -r.Delta,
+						Livemode:      r.Livemode,
+						Reason:        "manual_adjustment",
+						ReferenceType: r.ReferenceType,
+						ReferenceId:   r.ReferenceId,
+						Actor:         stockActor,
+						Note:          "Return was not saved",
+						WarehouseId:   r.WarehouseId,
+					})
+				}
+				if _, err := u.productRepo.AdjustStockBatch(&proto.AdjustStockBatchRequest{Adjustments: reverts}); err != nil {
+					logrus.Errorf("Failed to take back the stock of %d returned items: %v", len(reverts), err)
+				}
+			}
+		}
+	}()
+
+	order, err := u.orderRepo.LockOrder(int(req.OrderId), tx)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != "paid" && order.Status != "fulfilled" {
+		return nil, fmt.Errorf("items of a %s order cannot be returned", order.Status)
+	}
+
+	items, err := u.orderItemRepo.LockOrderItems(int(req.OrderId), tx)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int32]*proto.OrderItem, len(items))
+	for _, item := range items {
+		byID[item.Id] = item
+	}
+	for _, item := range items {
+		if parent, ok := byID[item.ParentItemId]; ok {
+			parent.Components = append(parent.Components, item)
+		}
+	}
+
+	// returned is what each item has returned with this request, on top of earlier returns
+	returned := make(map[int32]int32)
+	returnable := func(item *proto.OrderItem) int32 {
+		return item.Quantity - item.ReturnedQuantity - returned[item.Id]
+	}
+
+	var refund float64
+	var stock []*proto.OrderItem
+	for _, r := range req.Items {
+		item, ok := byID[r.OrderItemId]
+		if !ok {
+			return nil, fmt.Errorf("order item %d is not part of order %d", r.OrderItemId, req.OrderId)
+		}
+		if item.Digital {
+			return nil, fmt.Errorf("order item %d was delivered digitally and cannot be returned", item.Id)
+		}
+		if r.Quantity > returnable(item) {
+			return nil, fmt.Errorf("only %d of order item %d can be returned", returnable(item), item.Id)
+		}
+
+		if len(item.Components) > 0 {
+			// Every component of a bundle comes back with it, from whichever of its
+			// items still has them
+			perBundle := make(map[int32]int32)
+			for _, component := range item.Components {
+				perBundle[component.VariantId] += component.Quantity
+			}
+			for variantID, total := range perBundle {
+				wanted := total / item.Quantity * r.Quantity
+				for _, component := range item.Components {
+					if component.VariantId != variantID || wanted == 0 {
+						continue
+					}
+					take := returnable(component)
+					if take > wanted {
+						take = wanted
+					}
+					if take > 0 {
+						returned[component.Id] += take
+						stock = append(stock, &proto.OrderItem{VariantId: component.VariantId, WarehouseId: component.WarehouseId, Quantity: take})
+						wanted -= take
+					}
+				}
+				if wanted > 0 {
+					return nil, fmt.Errorf("components of order item %d were already returned on their own", item.Id)
+				}
+			}
+		} else {
+			stock = append(stock, &proto.OrderItem{VariantId: item.VariantId, WarehouseId: item.WarehouseId, Quantity: r.Quantity})
+		}
+
+		returned[item.Id] += r.Quantity
+		refund += float64(r.Quantity) * item.Price
+	}
+
+	response := &proto.ReturnOrderItemsResponse{RefundAmount: refund}
+	if req.DryRun {
+		return response, nil
+	}
+
+	for itemID, quantity := range returned {
+		if err := u.orderItemRepo.AddReturnedQuantity(int(itemID), quantity, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	reference := strconv.Itoa(int(req.OrderId))
+	adjustments := make([]*proto.AdjustVariantStockRequest, 0, len(stock))
+	for _, s := range stock {
+		// Items whose variant was deleted since have nowhere to go back to
+		if s.VariantId == 0 {
+			continue
+		}
+		adjustments = append(adjustments, &proto.AdjustVariantStockRequest{
+			VariantId:     s.VariantId,
+			Delta:         s.Quantity,
+			Livemode:      order.Livemode,
+			Reason:        "return",
+			ReferenceType: "order",
+			ReferenceId:   reference,
+			Actor:         stockActor,
+			Note:          req.Reason,
+			WarehouseId:   s.WarehouseId,
+		})
+	}
+	if len(adjustments) > 0 {
+		if _, err := u.productRepo.AdjustStockBatch(&proto.AdjustStockBatchRequest{Adjustments: adjustments}); err != nil {
+			return nil, err
+		}
+		restocked = adjustments
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+	logrus.Infof("Returned %d items of order %d worth %f", len(req.Items), req.OrderId, refund)
+
+	if err := db.DeleteCache(u.ctx, db.RedisOrderByIdKey(int(req.OrderId), int(order.UserId))); err != nil {
+		logrus.Warnf("Failed to invalidate order cache for ID %d: %v", req.OrderId, err)
+	}
+
+	return response, nil
+}
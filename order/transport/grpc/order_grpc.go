@@ -1,100 +1,149 @@
-package grpc
-
-import (
-	"context"
-	"net"
-	"order/cmd/db"
-	"order/proto"
-	"order/repository"
-	"order/service"
-	"order/transport/kafka"
-	"os"
-
-	"github.com/sirupsen/logrus"
-	"google.golang.org/grpc"
-)
-
-type OrderGRPCServer struct {
-	service *service.OrderService
-	proto.UnimplementedOrderServiceServer
-}
-
-func NewOrderGRPCServer(service *service.OrderService) *OrderGRPCServer {
-	return &OrderGRPCServer{
-		service: service,
-	}
-}
-
-func (u *OrderGRPCServer) CreateOrder(ctx context.Context, req *proto.CreateOrderRequest) (*proto.OrderResponse, error) {
-	order, err := u.service.CreateOrder(req)
-	if err != nil {
-		return nil, err
-	}
-
-	return order, nil
-}
-
-func (u *OrderGRPCServer) GetOrder(ctx context.Context, req *proto.GetOrderRequest) (*proto.OrdersResponse, error) {
-	orders, err := u.service.GetOrderByUserID(req)
-	if err != nil {
-		return nil, err
-	}
-
-	return &proto.OrdersResponse{
-		Orders: orders,
-	}, nil
-}
-
-func (u *OrderGRPCServer) GetOrderById(ctx context.Context, req *proto.GetOrderByIdRequest) (*proto.OrderResponse, error) {
-	order, err := u.service.GetOrderById(req)
-	if err != nil {
-		return nil, err
-	}
-
-	return &proto.OrderResponse{
-		Order: order,
-	}, nil
-}
-
-func (u *OrderGRPCServer) UpdateOrderStatus(ctx context.Context, req *proto.UpdateOrderStatusRequest) (*proto.EmptyOrder, error) {
-	if err := u.service.UpdateOrderStatus(req.Status, int(req.OrderId)); err != nil {
-		return nil, err
-	}
-
-	return &proto.EmptyOrder{}, nil
-}
-
-func GRPCListen() {
-	DB, err := db.Connect()
-	if err != nil {
-		logrus.Fatalf("failed to connect to database: %v", err)
-	}
-
-	addr := []string{os.Getenv("KAFKA_BROKER_URL")}
-
-	ctx := context.Background()
-
-	orderRepo := repository.NewOrderRepositoryImpl()
-	orderItemRepo := repository.NewOrderItemsRepositoryImpl()
-	productRepo := repository.NewProductRepositoryImpl()
-
-	orderService := service.NewOrderItemService(DB, orderRepo, orderItemRepo, productRepo, ctx)
-	orderGRPC := NewOrderGRPCServer(orderService)
-
-	if err := kafka.ConnectProducer(addr); err != nil {
-		logrus.Fatalf("failed to connect to kafka: %v", err)
-	}
-
-	conn, err := net.Listen("tcp", ":30001")
-	if err != nil {
-		logrus.Fatalf("failed to listen for gRPC: %v", err)
-	}
-
-	srv := grpc.NewServer()
-	proto.RegisterOrderServiceServer(srv, orderGRPC)
-	logrus.Infof("gRPC Server started on port 30001")
-
-	if err := srv.Serve(conn); err != nil {
-		logrus.Fatalf("error when connect to gRPC Server  %v", err)
-	}
-}
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"order/cmd/db"
+	"order/proto"
+	"order/repository"
+	"order/service"
+	"order/transport/kafka"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type OrderGRPCServer struct {
+	service *service.OrderService
+	proto.UnimplementedOrderServiceServer
+}
+
+func NewOrderGRPCServer(service *service.OrderService) *OrderGRPCServer {
+	return &OrderGRPCServer{
+		service: service,
+	}
+}
+
+func (u *OrderGRPCServer) CreateOrder(ctx context.Context, req *proto.CreateOrderRequest) (*proto.OrderResponse, error) {
+	order, err := u.service.CreateOrder(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (u *OrderGRPCServer) GetOrder(ctx context.Context, req *proto.GetOrderRequest) (*proto.OrdersResponse, error) {
+	orders, err := u.service.GetOrderByUserID(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.OrdersResponse{
+		Orders: orders,
+	}, nil
+}
+
+func (u *OrderGRPCServer) GetOrderById(ctx context.Context, req *proto.GetOrderByIdRequest) (*proto.OrderResponse, error) {
+	order, err := u.service.GetOrderById(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.OrderResponse{
+		Order: order,
+	}, nil
+}
+
+func (u *OrderGRPCServer) UpdateOrderStatus(ctx context.Context, req *proto.UpdateOrderStatusRequest) (*proto.EmptyOrder, error) {
+	if err := u.service.UpdateOrderStatus(req.Status, int(req.OrderId)); err != nil {
+		return nil, err
+	}
+
+	return &proto.EmptyOrder{}, nil
+}
+
+func (u *OrderGRPCServer) ReturnOrderItems(ctx context.Context, req *proto.ReturnOrderItemsRequest) (*proto.ReturnOrderItemsResponse, error) {
+	return u.service.ReturnOrderItems(req)
+}
+
+func (u *OrderGRPCServer) DeliverOrder(ctx context.Context, req *proto.GetOrderByIdRequest) (*proto.OrderResponse, error) {
+	order, err := u.service.DeliverOrder(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.OrderResponse{
+		Order: order,
+	}, nil
+}
+
+// RecordDownload reports refused downloads as FailedPrecondition and unknown
+// deliveries as NotFound, for the gateway to answer with the matching HTTP status
+func (u *OrderGRPCServer) RecordDownload(ctx context.Context, req *proto.RecordDownloadRequest) (*proto.OrderDelivery, error) {
+	delivery, err := u.service.RecordDownload(req)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDeliveryNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, service.ErrDownloadExpired), errors.Is(err, service.ErrDownloadLimitReached), errors.Is(err, service.ErrDownloadNotAvailable),
+			errors.Is(err, service.ErrOrderNotDownloadable):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (u *OrderGRPCServer) HasPurchased(ctx context.Context, req *proto.HasPurchasedRequest) (*proto.HasPurchasedResponse, error) {
+	return u.service.HasPurchased(req)
+}
+
+func (u *OrderGRPCServer) HasOrders(ctx context.Context, req *proto.HasOrdersRequest) (*proto.HasOrdersResponse, error) {
+	return u.service.HasOrders(req)
+}
+
+func GRPCListen() {
+	DB, err := db.Connect()
+	if err != nil {
+		logrus.Fatalf("failed to connect to database: %v", err)
+	}
+
+	addr := []string{os.Getenv("KAFKA_BROKER_URL")}
+
+	ctx := context.Background()
+
+	orderRepo := repository.NewOrderRepositoryImpl()
+	orderItemRepo := repository.NewOrderItemsRepositoryImpl()
+	productRepo := repository.NewProductRepositoryImpl()
+	deliveryRepo := repository.NewDeliveryRepositoryImpl()
+
+	if os.Getenv("DOWNLOAD_SIGNING_SECRET") == "" {
+		logrus.Warn("DOWNLOAD_SIGNING_SECRET is not set, digital downloads get no download links")
+	}
+
+	orderService := service.NewOrderItemService(DB, orderRepo, orderItemRepo, productRepo, deliveryRepo, ctx)
+	orderGRPC := NewOrderGRPCServer(orderService)
+
+	if err := kafka.ConnectProducer(addr); err != nil {
+		logrus.Fatalf("failed to connect to kafka: %v", err)
+	}
+
+	conn, err := net.Listen("tcp", ":30001")
+	if err != nil {
+		logrus.Fatalf("failed to listen for gRPC: %v", err)
+	}
+
+	srv := grpc.NewServer()
+	proto.RegisterOrderServiceServer(srv, orderGRPC)
+	logrus.Infof("gRPC Server started on port 30001")
+
+	if err := srv.Serve(conn); err != nil {
+		logrus.Fatalf("error when connect to gRPC Server  %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLocalCardGatewayChargeCard(t *testing.T) {
+	tests := []struct {
+		name         string
+		charge       CardCharge
+		wantStatus   string
+		wantRedirect bool
+		wantAuth     bool // RequiresAuthentication
+		wantSaved    bool
+		wantErr      bool
+	}{
+		{
+			name:    "missing token",
+			charge:  CardCharge{OrderID: "T-PAY-1"},
+			wantErr: true,
+		},
+		{
+			name:       "one-click charge on a saved token",
+			charge:     CardCharge{OrderID: "T-PAY-1", TokenID: "local-saved-abc"},
+			wantStatus: "capture",
+		},
+		{
+			name:         "new card goes through 3DS",
+			charge:       CardCharge{OrderID: "T-PAY-1", TokenID: "tok-new", Authenticate: true},
+			wantStatus:   "pending",
+			wantRedirect: true,
+			wantAuth:     true,
+		},
+		{
+			name:       "3DS card without authentication",
+			charge:     CardCharge{OrderID: "T-PAY-1", TokenID: "tok-3ds"},
+			wantStatus: "deny",
+			wantAuth:   true,
+		},
+		{
+			name:         "3DS card with authentication",
+			charge:       CardCharge{OrderID: "T-PAY-1-3DS", TokenID: "tok-3ds", Authenticate: true},
+			wantStatus:   "pending",
+			wantRedirect: true,
+			wantAuth:     true,
+		},
+		{
+			name:       "declined card",
+			charge:     CardCharge{OrderID: "T-PAY-1", TokenID: "tok-deny", Authenticate: true},
+			wantStatus: "deny",
+			wantAuth:   true,
+		},
+		{
+			name:       "saving the card",
+			charge:     CardCharge{OrderID: "T-PAY-1", TokenID: "tok-new", SaveToken: true},
+			wantStatus: "capture",
+			wantSaved:  true,
+		},
+		{
+			name:       "declined card is not saved",
+			charge:     CardCharge{OrderID: "T-PAY-1", TokenID: "tok-deny", SaveToken: true},
+			wantStatus: "deny",
+			wantAuth:   true,
+		},
+	}
+
+	gateway := NewLocalCardGateway()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			charge := tt.charge
+			result, err := gateway.ChargeCard(&charge)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ChargeCard() = %+v, want an error", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ChargeCard() error = %v", err)
+			}
+
+			if result.TransactionStatus != tt.wantStatus {
+				t.Errorf("TransactionStatus = %q, want %q", result.TransactionStatus, tt.wantStatus)
+			}
+			if (result.RedirectURL != "") != tt.wantRedirect {
+				t.Errorf("RedirectURL = %q, want redirect %t", result.RedirectURL, tt.wantRedirect)
+			}
+			if got := result.RequiresAuthentication(); got != tt.wantAuth {
+				t.Errorf("RequiresAuthentication() = %t, want %t", got, tt.wantAuth)
+			}
+			if (result.SavedTokenID != "") != tt.wantSaved {
+				t.Errorf("SavedTokenID = %q, want saved %t", result.SavedTokenID, tt.wantSaved)
+			}
+			if tt.wantSaved && result.SavedTokenExpiry.IsZero() {
+				t.Error("SavedTokenExpiry is not set")
+			}
+			if !strings.HasSuffix(result.TransactionID, charge.OrderID) {
+				t.Errorf("TransactionID = %q, want it to name order %q", result.TransactionID, charge.OrderID)
+			}
+		})
+	}
+}
+
+func TestCardBrand(t *testing.T) {
+	tests := map[string]string{
+		"481111-1114": "visa",
+		"521111-1117": "mastercard",
+		"222300-0010": "mastercard",
+		"371111-1114": "amex",
+		"341111-1111": "amex",
+		"353011-1111": "jcb",
+		"601111-1111": "unknown",
+	}
+	for masked, want := range tests {
+		if got := CardBrand(masked); got != want {
+			t.Errorf("CardBrand(%q) = %q, want %q", masked, got, want)
+		}
+	}
+}
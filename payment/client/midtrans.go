@@ -1,20 +1,62 @@
 package client
 
 import (
+	"bytes"
 	"crypto/sha512"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
 	"github.com/midtrans/midtrans-go/snap"
 	"github.com/sirupsen/logrus"
 )
 
+// Live clients use MIDTRANS_ENVIRONMENT; test-mode traffic always goes to the sandbox
 var SnapClient snap.Client
+var CoreClient coreapi.Client
 var ServerKey string
 
+var TestSnapClient snap.Client
+var TestCoreClient coreapi.Client
+var TestServerKey string
+
+// TestOrderIDPrefix marks gateway order IDs of test-mode payments so webhooks can be
+// verified against the sandbox key before anything is looked up
+const TestOrderIDPrefix = "TEST-"
+
+// callbackURLs are the pages Snap redirects the customer to once checkout ends
+var callbackURLs *Callbacks
+
+// Callbacks extends snap.Callbacks with the unfinish and error redirects,
+// which the Snap API accepts but the SDK does not model
+type Callbacks struct {
+	Finish   string `json:"finish,omitempty"`
+	Unfinish string `json:"unfinish,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Customer is the customer profile sent as billing and shipping details
+type Customer struct {
+	Name        string
+	Email       string
+	Phone       string
+	Address     string
+	City        string
+	PostalCode  string
+	CountryCode string
+}
+
+type snapRequest struct {
+	*snap.Request
+	Callbacks *Callbacks `json:"callbacks,omitempty"`
+}
+
 func InitMidtransClient() {
 	ServerKey = os.Getenv("MIDTRANS_SERVER_KEY")
 	clientKey := os.Getenv("MIDTRANS_CLIENT_KEY")
@@ -24,13 +66,57 @@ func InitMidtransClient() {
 		ServerKey = "SB-Mid-server-YOUR_SERVER_KEY"
 	}
 
-	SnapClient.New(ServerKey, midtrans.Sandbox)
+	env := midtrans.Sandbox
+	if os.Getenv("MIDTRANS_ENVIRONMENT") == "production" {
+		env = midtrans.Production
+	}
+
+	TestServerKey = os.Getenv("MIDTRANS_SANDBOX_SERVER_KEY")
+	if TestServerKey == "" {
+		if env == midtrans.Production {
+			logrus.Warn("MIDTRANS_SANDBOX_SERVER_KEY not set, test-mode payments will be rejected by Midtrans")
+		}
+		TestServerKey = ServerKey
+	}
+
+	SnapClient.New(ServerKey, env)
+	CoreClient.New(ServerKey, env)
+	TestSnapClient.New(TestServerKey, midtrans.Sandbox)
+	TestCoreClient.New(TestServerKey, midtrans.Sandbox)
+
+	// PAYMENT_GATEWAY=local swaps card charges for the in-process stand-in
+	if os.Getenv("PAYMENT_GATEWAY") == "local" {
+		logrus.Warn("PAYMENT_GATEWAY=local, card charges use the local gateway stand-in")
+		Cards = NewLocalCardGateway()
+		TestCards = Cards
+	} else {
+		Cards = &midtransCardGateway{core: &CoreClient}
+		TestCards = &midtransCardGateway{core: &TestCoreClient}
+	}
+
+	callbackURLs = &Callbacks{
+		Finish:   os.Getenv("MIDTRANS_FINISH_URL"),
+		Unfinish: os.Getenv("MIDTRANS_UNFINISH_URL"),
+		Error:    os.Getenv("MIDTRANS_ERROR_URL"),
+	}
+	if *callbackURLs == (Callbacks{}) {
+		callbackURLs = nil
+	}
 
 	logrus.Infof("Midtrans initialized with client key: %s", clientKey)
 }
 
 // CreateSnapTransaction creates a Snap transaction and returns the token
-func CreateSnapTransaction(orderID string, amount int64, customerName, customerEmail, customerPhone string) (*snap.Response, error) {
+func CreateSnapTransaction(livemode bool, orderID string, amount int64, customer *Customer, items []midtrans.ItemDetails) (*snap.Response, error) {
+	address := &midtrans.CustomerAddress{
+		FName:       customer.Name,
+		Phone:       customer.Phone,
+		Address:     customer.Address,
+		City:        customer.City,
+		Postcode:    customer.PostalCode,
+		CountryCode: customer.CountryCode,
+	}
+
 	// Create Snap request
 	req := &snap.Request{
 		TransactionDetails: midtrans.TransactionDetails{
@@ -38,9 +124,11 @@ func CreateSnapTransaction(orderID string, amount int64, customerName, customerE
 			GrossAmt: amount,
 		},
 		CustomerDetail: &midtrans.CustomerDetails{
-			FName: customerName,
-			Email: customerEmail,
-			Phone: customerPhone,
+			FName:    customer.Name,
+			Email:    customer.Email,
+			Phone:    customer.Phone,
+			BillAddr: address,
+			ShipAddr: address,
 		},
 		EnabledPayments: snap.AllSnapPaymentType,
 		Expiry: &snap.ExpiryDetails{
@@ -50,10 +138,46 @@ func CreateSnapTransaction(orderID string, amount int64, customerName, customerE
 		},
 	}
 
-	logrus.Infof("Creating Snap transaction for order: %s, amount: %d", orderID, amount)
+	// Midtrans rejects item details that don't add up to the gross amount. Item
+	// prices are rounded one by one, so a difference left over (rounding, or a
+	// discount or fee not itemized) goes on an adjustment line of its own.
+	if len(items) > 0 {
+		var itemsTotal int64
+		for _, item := range items {
+			itemsTotal += item.Price * int64(item.Qty)
+		}
+		if diff := amount - itemsTotal; diff != 0 {
+			name := "Fee"
+			if diff < 0 {
+				name = "Discount"
+			}
+			logrus.Infof("Item details total %d differs from gross amount %d for order %s, adding a %d adjustment", itemsTotal, amount, orderID, diff)
+			items = append(items, midtrans.ItemDetails{ID: "ADJUSTMENT", Name: name, Price: diff, Qty: 1})
+		}
+		req.Items = &items
+	}
 
-	snapResp, err := SnapClient.CreateTransaction(req)
+	logrus.Infof("Creating Snap transaction for order: %s, amount: %d, items: %d, livemode: %t", orderID, amount, len(items), livemode)
+
+	jsonReq, err := json.Marshal(&snapRequest{Request: req, Callbacks: callbackURLs})
 	if err != nil {
+		return nil, err
+	}
+
+	snapClient := &SnapClient
+	if !livemode {
+		snapClient = &TestSnapClient
+	}
+
+	snapResp := &snap.Response{}
+	if err := snapClient.HttpClient.Call(
+		http.MethodPost,
+		fmt.Sprintf("%s/snap/v1/transactions", snapClient.Env.SnapURL()),
+		&snapClient.ServerKey,
+		snapClient.Options,
+		bytes.NewBuffer(jsonReq),
+		snapResp,
+	); err != nil {
 		logrus.Errorf("Failed to create Snap transaction: %v", err)
 		return nil, err
 	}
@@ -62,10 +186,37 @@ func CreateSnapTransaction(orderID string, amount int64, customerName, customerE
 	return snapResp, nil
 }
 
+// RefundTransaction refunds part or all of a settled transaction
+func RefundTransaction(livemode bool, gatewayOrderID, refundKey string, amount int64, reason string) (*coreapi.RefundResponse, error) {
+	logrus.Infof("Refunding %d from transaction %s (key: %s)", amount, gatewayOrderID, refundKey)
+
+	coreClient := &CoreClient
+	if !livemode {
+		coreClient = &TestCoreClient
+	}
+
+	refundResp, err := coreClient.RefundTransaction(gatewayOrderID, &coreapi.RefundReq{
+		RefundKey: refundKey,
+		Amount:    amount,
+		Reason:    reason,
+	})
+	if err != nil {
+		logrus.Errorf("Failed to refund transaction %s: %v", gatewayOrderID, err)
+		return nil, err
+	}
+
+	return refundResp, nil
+}
+
 // VerifySignature verifies the webhook signature from Midtrans
-func VerifySignature(orderID, statusCode, grossAmount, signatureKey string) bool {
+func VerifySignature(livemode bool, orderID, statusCode, grossAmount, signatureKey string) bool {
+	key := ServerKey
+	if !livemode {
+		key = TestServerKey
+	}
+
 	// Signature = SHA512(order_id + status_code + gross_amount + ServerKey)
-	data := orderID + statusCode + grossAmount + ServerKey
+	data := orderID + statusCode + grossAmount + key
 	hash := sha512.Sum512([]byte(data))
 	calculatedSignature := hex.EncodeToString(hash[:])
 
@@ -99,6 +250,37 @@ func MapTransactionStatus(transactionStatus, fraudStatus string) string {
 }
 
 // GenerateOrderID generates a unique order ID for Midtrans
-func GenerateOrderID(paymentID int32) string {
-	return fmt.Sprintf("PAY-%d-%d", paymentID, time.Now().Unix())
+func GenerateOrderID(paymentID int32, livemode bool) string {
+	return modePrefix(livemode) + fmt.Sprintf("PAY-%d-%d", paymentID, time.Now().Unix())
+}
+
+// GenerateIntentOrderID generates a unique Midtrans order ID for a payment intent
+func GenerateIntentOrderID(intentID int32, livemode bool) string {
+	return modePrefix(livemode) + fmt.Sprintf("INT-%d-%d", intentID, time.Now().Unix())
+}
+
+// GenerateTopUpOrderID generates a unique Midtrans order ID for a wallet top-up
+func GenerateTopUpOrderID(topUpID int32, livemode bool) string {
+	return modePrefix(livemode) + fmt.Sprintf("TOP-%d-%d", topUpID, time.Now().Unix())
+}
+
+// SplitOrderIDMode strips the test-mode prefix from a gateway order ID and reports its mode
+func SplitOrderIDMode(orderID string) (string, bool) {
+	if strings.HasPrefix(orderID, TestOrderIDPrefix) {
+		return strings.TrimPrefix(orderID, TestOrderIDPrefix), false
+	}
+	return orderID, true
+}
+
+func modePrefix(livemode bool) string {
+	if livemode {
+		return ""
+	}
+	return TestOrderIDPrefix
+}
+
+// GenerateRefundKey generates an idempotency key for a refund of one order, in an
+// intent or, with intentID 0, paid on its own
+func GenerateRefundKey(intentID, orderID int32) string {
+	return fmt.Sprintf("RFD-%d-%d-%d", intentID, orderID, time.Now().Unix())
 }
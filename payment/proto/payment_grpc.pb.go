@@ -19,10 +19,19 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PaymentService_CreatePayment_FullMethodName       = "/payment.PaymentService/CreatePayment"
-	PaymentService_GetPaymentByOrderId_FullMethodName = "/payment.PaymentService/GetPaymentByOrderId"
-	PaymentService_InitiatePayment_FullMethodName     = "/payment.PaymentService/InitiatePayment"
-	PaymentService_HandleWebhook_FullMethodName       = "/payment.PaymentService/HandleWebhook"
+	PaymentService_CreatePayment_FullMethodName            = "/payment.PaymentService/CreatePayment"
+	PaymentService_GetPaymentByOrderId_FullMethodName      = "/payment.PaymentService/GetPaymentByOrderId"
+	PaymentService_InitiatePayment_FullMethodName          = "/payment.PaymentService/InitiatePayment"
+	PaymentService_HandleWebhook_FullMethodName            = "/payment.PaymentService/HandleWebhook"
+	PaymentService_CreatePaymentIntent_FullMethodName      = "/payment.PaymentService/CreatePaymentIntent"
+	PaymentService_GetPaymentIntent_FullMethodName         = "/payment.PaymentService/GetPaymentIntent"
+	PaymentService_RefundPaymentIntent_FullMethodName      = "/payment.PaymentService/RefundPaymentIntent"
+	PaymentService_RefundPayment_FullMethodName            = "/payment.PaymentService/RefundPayment"
+	PaymentService_InitiateCardPayment_FullMethodName      = "/payment.PaymentService/InitiateCardPayment"
+	PaymentService_ListSavedPaymentMethods_FullMethodName  = "/payment.PaymentService/ListSavedPaymentMethods"
+	PaymentService_DeleteSavedPaymentMethod_FullMethodName = "/payment.PaymentService/DeleteSavedPaymentMethod"
+	PaymentService_GetWallet_FullMethodName                = "/payment.PaymentService/GetWallet"
+	PaymentService_TopUpWallet_FullMethodName              = "/payment.PaymentService/TopUpWallet"
 )
 
 // PaymentServiceClient is the client API for PaymentService service.
@@ -39,6 +48,24 @@ type PaymentServiceClient interface {
 	InitiatePayment(ctx context.Context, in *InitiatePaymentRequest, opts ...grpc.CallOption) (*InitiatePaymentResponse, error)
 	// Handle webhook from Midtrans
 	HandleWebhook(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*EmptyPayment, error)
+	// Pay several orders with a single Midtrans transaction
+	CreatePaymentIntent(ctx context.Context, in *CreatePaymentIntentRequest, opts ...grpc.CallOption) (*PaymentIntentResponse, error)
+	// Get payment intent with its grouped payments
+	GetPaymentIntent(ctx context.Context, in *GetPaymentIntentRequest, opts ...grpc.CallOption) (*PaymentIntentResponse, error)
+	// Refund part of a settled intent, allocated to one order
+	RefundPaymentIntent(ctx context.Context, in *RefundPaymentIntentRequest, opts ...grpc.CallOption) (*RefundResponse, error)
+	// Refund part of what was paid for an order, however it was paid
+	RefundPayment(ctx context.Context, in *RefundPaymentRequest, opts ...grpc.CallOption) (*RefundResponse, error)
+	// Charge an order to a new or saved card, falling back to 3DS when required
+	InitiateCardPayment(ctx context.Context, in *InitiateCardPaymentRequest, opts ...grpc.CallOption) (*InitiatePaymentResponse, error)
+	// List the user's saved cards
+	ListSavedPaymentMethods(ctx context.Context, in *ListSavedPaymentMethodsRequest, opts ...grpc.CallOption) (*ListSavedPaymentMethodsResponse, error)
+	// Delete one of the user's saved cards
+	DeleteSavedPaymentMethod(ctx context.Context, in *DeleteSavedPaymentMethodRequest, opts ...grpc.CallOption) (*EmptyPayment, error)
+	// Get the user's wallet balance and recent transactions
+	GetWallet(ctx context.Context, in *GetWalletRequest, opts ...grpc.CallOption) (*WalletResponse, error)
+	// Start a wallet top-up paid through Midtrans
+	TopUpWallet(ctx context.Context, in *TopUpWalletRequest, opts ...grpc.CallOption) (*WalletTopUpResponse, error)
 }
 
 type paymentServiceClient struct {
@@ -89,6 +116,96 @@ func (c *paymentServiceClient) HandleWebhook(ctx context.Context, in *WebhookReq
 	return out, nil
 }
 
+func (c *paymentServiceClient) CreatePaymentIntent(ctx context.Context, in *CreatePaymentIntentRequest, opts ...grpc.CallOption) (*PaymentIntentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PaymentIntentResponse)
+	err := c.cc.Invoke(ctx, PaymentService_CreatePaymentIntent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) GetPaymentIntent(ctx context.Context, in *GetPaymentIntentRequest, opts ...grpc.CallOption) (*PaymentIntentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PaymentIntentResponse)
+	err := c.cc.Invoke(ctx, PaymentService_GetPaymentIntent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) RefundPaymentIntent(ctx context.Context, in *RefundPaymentIntentRequest, opts ...grpc.CallOption) (*RefundResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RefundResponse)
+	err := c.cc.Invoke(ctx, PaymentService_RefundPaymentIntent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) RefundPayment(ctx context.Context, in *RefundPaymentRequest, opts ...grpc.CallOption) (*RefundResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RefundResponse)
+	err := c.cc.Invoke(ctx, PaymentService_RefundPayment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) InitiateCardPayment(ctx context.Context, in *InitiateCardPaymentRequest, opts ...grpc.CallOption) (*InitiatePaymentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InitiatePaymentResponse)
+	err := c.cc.Invoke(ctx, PaymentService_InitiateCardPayment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ListSavedPaymentMethods(ctx context.Context, in *ListSavedPaymentMethodsRequest, opts ...grpc.CallOption) (*ListSavedPaymentMethodsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSavedPaymentMethodsResponse)
+	err := c.cc.Invoke(ctx, PaymentService_ListSavedPaymentMethods_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) DeleteSavedPaymentMethod(ctx context.Context, in *DeleteSavedPaymentMethodRequest, opts ...grpc.CallOption) (*EmptyPayment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmptyPayment)
+	err := c.cc.Invoke(ctx, PaymentService_DeleteSavedPaymentMethod_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) GetWallet(ctx context.Context, in *GetWalletRequest, opts ...grpc.CallOption) (*WalletResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WalletResponse)
+	err := c.cc.Invoke(ctx, PaymentService_GetWallet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) TopUpWallet(ctx context.Context, in *TopUpWalletRequest, opts ...grpc.CallOption) (*WalletTopUpResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WalletTopUpResponse)
+	err := c.cc.Invoke(ctx, PaymentService_TopUpWallet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PaymentServiceServer is the server API for PaymentService service.
 // All implementations must embed UnimplementedPaymentServiceServer
 // for forward compatibility.
@@ -103,6 +220,24 @@ type PaymentServiceServer interface {
 	InitiatePayment(context.Context, *InitiatePaymentRequest) (*InitiatePaymentResponse, error)
 	// Handle webhook from Midtrans
 	HandleWebhook(context.Context, *WebhookRequest) (*EmptyPayment, error)
+	// Pay several orders with a single Midtrans transaction
+	CreatePaymentIntent(context.Context, *CreatePaymentIntentRequest) (*PaymentIntentResponse, error)
+	// Get payment intent with its grouped payments
+	GetPaymentIntent(context.Context, *GetPaymentIntentRequest) (*PaymentIntentResponse, error)
+	// Refund part of a settled intent, allocated to one order
+	RefundPaymentIntent(context.Context, *RefundPaymentIntentRequest) (*RefundResponse, error)
+	// Refund part of what was paid for an order, however it was paid
+	RefundPayment(context.Context, *RefundPaymentRequest) (*RefundResponse, error)
+	// Charge an order to a new or saved card, falling back to 3DS when required
+	InitiateCardPayment(context.Context, *InitiateCardPaymentRequest) (*InitiatePaymentResponse, error)
+	// List the user's saved cards
+	ListSavedPaymentMethods(context.Context, *ListSavedPaymentMethodsRequest) (*ListSavedPaymentMethodsResponse, error)
+	// Delete one of the user's saved cards
+	DeleteSavedPaymentMethod(context.Context, *DeleteSavedPaymentMethodRequest) (*EmptyPayment, error)
+	// Get the user's wallet balance and recent transactions
+	GetWallet(context.Context, *GetWalletRequest) (*WalletResponse, error)
+	// Start a wallet top-up paid through Midtrans
+	TopUpWallet(context.Context, *TopUpWalletRequest) (*WalletTopUpResponse, error)
 	mustEmbedUnimplementedPaymentServiceServer()
 }
 
@@ -125,6 +260,33 @@ func (UnimplementedPaymentServiceServer) InitiatePayment(context.Context, *Initi
 func (UnimplementedPaymentServiceServer) HandleWebhook(context.Context, *WebhookRequest) (*EmptyPayment, error) {
 	return nil, status.Error(codes.Unimplemented, "method HandleWebhook not implemented")
 }
+func (UnimplementedPaymentServiceServer) CreatePaymentIntent(context.Context, *CreatePaymentIntentRequest) (*PaymentIntentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreatePaymentIntent not implemented")
+}
+func (UnimplementedPaymentServiceServer) GetPaymentIntent(context.Context, *GetPaymentIntentRequest) (*PaymentIntentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPaymentIntent not implemented")
+}
+func (UnimplementedPaymentServiceServer) RefundPaymentIntent(context.Context, *RefundPaymentIntentRequest) (*RefundResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RefundPaymentIntent not implemented")
+}
+func (UnimplementedPaymentServiceServer) RefundPayment(context.Context, *RefundPaymentRequest) (*RefundResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RefundPayment not implemented")
+}
+func (UnimplementedPaymentServiceServer) InitiateCardPayment(context.Context, *InitiateCardPaymentRequest) (*InitiatePaymentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method InitiateCardPayment not implemented")
+}
+func (UnimplementedPaymentServiceServer) ListSavedPaymentMethods(context.Context, *ListSavedPaymentMethodsRequest) (*ListSavedPaymentMethodsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListSavedPaymentMethods not implemented")
+}
+func (UnimplementedPaymentServiceServer) DeleteSavedPaymentMethod(context.Context, *DeleteSavedPaymentMethodRequest) (*EmptyPayment, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteSavedPaymentMethod not implemented")
+}
+func (UnimplementedPaymentServiceServer) GetWallet(context.Context, *GetWalletRequest) (*WalletResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetWallet not implemented")
+}
+func (UnimplementedPaymentServiceServer) TopUpWallet(context.Context, *TopUpWalletRequest) (*WalletTopUpResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method TopUpWallet not implemented")
+}
 func (UnimplementedPaymentServiceServer) mustEmbedUnimplementedPaymentServiceServer() {}
 func (UnimplementedPaymentServiceServer) testEmbeddedByValue()                        {}
 
@@ -218,6 +380,168 @@ func _PaymentService_HandleWebhook_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PaymentService_CreatePaymentIntent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePaymentIntentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).CreatePaymentIntent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_CreatePaymentIntent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).CreatePaymentIntent(ctx, req.(*CreatePaymentIntentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_GetPaymentIntent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPaymentIntentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetPaymentIntent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_GetPaymentIntent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetPaymentIntent(ctx, req.(*GetPaymentIntentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_RefundPaymentIntent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundPaymentIntentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).RefundPaymentIntent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_RefundPaymentIntent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).RefundPaymentIntent(ctx, req.(*RefundPaymentIntentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_RefundPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).RefundPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_RefundPayment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).RefundPayment(ctx, req.(*RefundPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_InitiateCardPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InitiateCardPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).InitiateCardPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_InitiateCardPayment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).InitiateCardPayment(ctx, req.(*InitiateCardPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ListSavedPaymentMethods_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSavedPaymentMethodsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ListSavedPaymentMethods(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ListSavedPaymentMethods_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ListSavedPaymentMethods(ctx, req.(*ListSavedPaymentMethodsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_DeleteSavedPaymentMethod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSavedPaymentMethodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).DeleteSavedPaymentMethod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_DeleteSavedPaymentMethod_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).DeleteSavedPaymentMethod(ctx, req.(*DeleteSavedPaymentMethodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_GetWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWalletRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetWallet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_GetWallet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetWallet(ctx, req.(*GetWalletRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_TopUpWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopUpWalletRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).TopUpWallet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_TopUpWallet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).TopUpWallet(ctx, req.(*TopUpWalletRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PaymentService_ServiceDesc is the grpc.ServiceDesc for PaymentService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -241,6 +565,42 @@ var PaymentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "HandleWebhook",
 			Handler:    _PaymentService_HandleWebhook_Handler,
 		},
+		{
+			MethodName: "CreatePaymentIntent",
+			Handler:    _PaymentService_CreatePaymentIntent_Handler,
+		},
+		{
+			MethodName: "GetPaymentIntent",
+			Handler:    _PaymentService_GetPaymentIntent_Handler,
+		},
+		{
+			MethodName: "RefundPaymentIntent",
+			Handler:    _PaymentService_RefundPaymentIntent_Handler,
+		},
+		{
+			MethodName: "RefundPayment",
+			Handler:    _PaymentService_RefundPayment_Handler,
+		},
+		{
+			MethodName: "InitiateCardPayment",
+			Handler:    _PaymentService_InitiateCardPayment_Handler,
+		},
+		{
+			MethodName: "ListSavedPaymentMethods",
+			Handler:    _PaymentService_ListSavedPaymentMethods_Handler,
+		},
+		{
+			MethodName: "DeleteSavedPaymentMethod",
+			Handler:    _PaymentService_DeleteSavedPaymentMethod_Handler,
+		},
+		{
+			MethodName: "GetWallet",
+			Handler:    _PaymentService_GetWallet_Handler,
+		},
+		{
+			MethodName: "TopUpWallet",
+			Handler:    _PaymentService_TopUpWallet_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/payment.proto",
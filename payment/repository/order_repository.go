@@ -1,38 +1,54 @@
-package repository
-
-import (
-	"context"
-	"payment/proto"
-	"time"
-
-	"github.com/sirupsen/logrus"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-)
-
-type OrderRepository interface {
-	UpdateOrderStatus(ctx context.Context, req *proto.UpdateOrderStatusRequest) (*proto.EmptyOrder, error)
-}
-
-type OrderRepositoryImpl struct {
-	client proto.OrderServiceClient
-}
-
-func NewOrderRepository() *OrderRepositoryImpl {
-	conn, err := grpc.NewClient("order-service:30001", grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		logrus.Fatalf("Failed to connect: %v", err)
-	}
-
-	logrus.Info("Connected to product service")
-	client := proto.NewOrderServiceClient(conn)
-
-	return &OrderRepositoryImpl{client: client}
-}
-
-func (u *OrderRepositoryImpl) UpdateOrderStatus(ctx context.Context, payload *proto.UpdateOrderStatusRequest) (*proto.EmptyOrder, error) {
-	ctxRepo, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	return u.client.UpdateOrderStatus(ctxRepo, payload)
-}
+package repository
+
+import (
+	"context"
+	"payment/proto"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type OrderRepository interface {
+	UpdateOrderStatus(ctx context.Context, req *proto.UpdateOrderStatusRequest) (*proto.EmptyOrder, error)
+	GetOrderById(ctx context.Context, req *proto.GetOrderByIdRequest) (*proto.OrderResponse, error)
+	ReturnOrderItems(ctx context.Context, req *proto.ReturnOrderItemsRequest) (*proto.ReturnOrderItemsResponse, error)
+}
+
+type OrderRepositoryImpl struct {
+	client proto.OrderServiceClient
+}
+
+func NewOrderRepository() *OrderRepositoryImpl {
+	conn, err := grpc.NewClient("order-service:30001", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		logrus.Fatalf("Failed to connect: %v", err)
+	}
+
+	logrus.Info("Connected to product service")
+	client := proto.NewOrderServiceClient(conn)
+
+	return &OrderRepositoryImpl{client: client}
+}
+
+func (u *OrderRepositoryImpl) UpdateOrderStatus(ctx context.Context, payload *proto.UpdateOrderStatusRequest) (*proto.EmptyOrder, error) {
+	ctxRepo, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.UpdateOrderStatus(ctxRepo, payload)
+}
+
+func (u *OrderRepositoryImpl) GetOrderById(ctx context.Context, payload *proto.GetOrderByIdRequest) (*proto.OrderResponse, error) {
+	ctxRepo, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.GetOrderById(ctxRepo, payload)
+}
+
+func (u *OrderRepositoryImpl) ReturnOrderItems(ctx context.Context, payload *proto.ReturnOrderItemsRequest) (*proto.ReturnOrderItemsResponse, error) {
+	ctxRepo, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.ReturnOrderItems(ctxRepo, payload)
+}
@@ -0,0 +1,366 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"payment/proto"
+	"time"
+)
+
+var ErrPaymentsNotAttachable = errors.New("an order is no longer available for a payment intent")
+
+type PaymentIntentRepository interface {
+	CreatePaymentIntent(ctx context.Context, userID int, amount float64, livemode bool, tx *sql.Tx) (*proto.PaymentIntentResponse, error)
+	AttachPayments(ctx context.Context, intentID int, paymentIDs []int32, tx *sql.Tx) error
+	GetByID(ctx context.Context, intentID int, db *sql.DB) (*proto.PaymentIntentResponse, error)
+	GetByGatewayOrderID(ctx context.Context, gatewayOrderID string, db *sql.DB) (*proto.PaymentIntentResponse, error)
+	GetPayments(ctx context.Context, intentID int, db *sql.DB) ([]*proto.PaymentResponse, error)
+	UpdateIntentGateway(ctx context.Context, intent *proto.PaymentIntentResponse, db *sql.DB) error
+	UpdateIntentStatus(ctx context.Context, intentID int, status string, transactionID string, db *sql.DB) error
+	LockIntent(ctx context.Context, intentID int, tx *sql.Tx) (*proto.PaymentIntentResponse, error)
+	AddRefund(ctx context.Context, intentID int, amount float64, tx *sql.Tx) error
+	CreateRefund(ctx context.Context, refund *proto.RefundResponse, tx *sql.Tx) (*proto.RefundResponse, error)
+	CompleteRefund(ctx context.Context, refundID int, tx *sql.Tx) error
+	FailRefund(ctx context.Context, refundID int, db *sql.DB) error
+}
+
+type PaymentIntentRepositoryImpl struct{}
+
+func NewPaymentIntentRepository() *PaymentIntentRepositoryImpl {
+	return &PaymentIntentRepositoryImpl{}
+}
+
+func (u *PaymentIntentRepositoryImpl) CreatePaymentIntent(ctx context.Context, userID int, amount float64, livemode bool, tx *sql.Tx) (*proto.PaymentIntentResponse, error) {
+	SQL := `INSERT INTO payment_intents(user_id, amount, status, livemode)
+			VALUES ($1, $2, 'pending', $3)
+			RETURNING id, user_id, amount, currency, status, created_at, livemode`
+
+	intent := &proto.PaymentIntentResponse{}
+	var createdAt time.Time
+	var currency sql.NullString
+
+	if err := tx.QueryRowContext(ctx, SQL, userID, amount, livemode).Scan(
+		&intent.Id,
+		&intent.UserId,
+		&intent.Amount,
+		&currency,
+		&intent.Status,
+		&createdAt,
+		&intent.Livemode,
+	); err != nil {
+		return nil, err
+	}
+
+	intent.Currency = currency.String
+	if intent.Currency == "" {
+		intent.Currency = "IDR"
+	}
+	intent.CreatedAt = createdAt.Format(time.RFC3339)
+
+	return intent, nil
+}
+
+// AttachPayments groups payments under an intent. Only pending payments without a
+// checkout of their own or a pending or paid intent are attached, and
+// ErrPaymentsNotAttachable is returned unless every payment was.
+func (u *PaymentIntentRepositoryImpl) AttachPayments(ctx context.Context, intentID int, paymentIDs []int32, tx *sql.Tx) error {
+	SQL := `UPDATE payments SET payment_intent_id = $1
+			WHERE id = ANY($2)
+			AND status = 'pending'
+			AND COALESCE(gateway_token, '') = ''
+			AND wallet_amount = 0
+			AND (payment_intent_id IS NULL OR payment_intent_id IN (
+				SELECT id FROM payment_intents WHERE status NOT IN ('pending', 'paid')
+			))`
+	result, err := tx.ExecContext(ctx, SQL, intentID, paymentIDs)
+	if err != nil {
+		return err
+	}
+
+	attached, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if attached != int64(len(paymentIDs)) {
+		return ErrPaymentsNotAttachable
+	}
+	return nil
+}
+
+func (u *PaymentIntentRepositoryImpl) GetByID(ctx context.Context, intentID int, db *sql.DB) (*proto.PaymentIntentResponse, error) {
+	SQL := `SELECT id, user_id, amount, refunded_amount, currency, payment_method, payment_channel,
+			gateway_transaction_id, gateway_order_id, gateway_token, gateway_redirect_url,
+			status, created_at, paid_at, expired_at, livemode
+			FROM payment_intents WHERE id = $1`
+
+	intent, err := scanPaymentIntent(db.QueryRowContext(ctx, SQL, intentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("payment intent not found")
+		}
+		return nil, err
+	}
+
+	return intent, nil
+}
+
+func (u *PaymentIntentRepositoryImpl) GetByGatewayOrderID(ctx context.Context, gatewayOrderID string, db *sql.DB) (*proto.PaymentIntentResponse, error) {
+	SQL := `SELECT id, user_id, amount, refunded_amount, currency, payment_method, payment_channel,
+			gateway_transaction_id, gateway_order_id, gateway_token, gateway_redirect_url,
+			status, created_at, paid_at, expired_at, livemode
+			FROM payment_intents WHERE gateway_order_id = $1`
+
+	intent, err := scanPaymentIntent(db.QueryRowContext(ctx, SQL, gatewayOrderID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("payment intent not found by gateway order ID")
+		}
+		return nil, err
+	}
+
+	return intent, nil
+}
+
+func (u *PaymentIntentRepositoryImpl) GetPayments(ctx context.Context, intentID int, db *sql.DB) ([]*proto.PaymentResponse, error) {
+	SQL := `SELECT id, order_id, amount, refunded_amount, currency, status, created_at, paid_at, livemode
+			FROM payments WHERE payment_intent_id = $1 ORDER BY id ASC`
+
+	rows, err := db.QueryContext(ctx, SQL, intentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []*proto.PaymentResponse
+	for rows.Next() {
+		payment := &proto.PaymentResponse{PaymentIntentId: int32(intentID)}
+		var (
+			currency  sql.NullString
+			createdAt time.Time
+			paidAt    sql.NullTime
+		)
+
+		if err := rows.Scan(
+			&payment.Id,
+			&payment.OrderId,
+			&payment.Amount,
+			&payment.RefundedAmount,
+			&currency,
+			&payment.Status,
+			&createdAt,
+			&paidAt,
+			&payment.Livemode,
+		); err != nil {
+			return nil, err
+		}
+
+		payment.Currency = currency.String
+		payment.CreatedAt = createdAt.Format(time.RFC3339)
+		if paidAt.Valid {
+			payment.PaidAt = paidAt.Time.Format(time.RFC3339)
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, rows.Err()
+}
+
+func (u *PaymentIntentRepositoryImpl) UpdateIntentGateway(ctx context.Context, intent *proto.PaymentIntentResponse, db *sql.DB) error {
+	SQL := `UPDATE payment_intents SET
+			payment_method = $1,
+			payment_channel = $2,
+			gateway_order_id = $3,
+			gateway_token = $4,
+			gateway_redirect_url = $5,
+			expired_at = $6,
+			status = $7
+			WHERE id = $8`
+
+	var expiredAt interface{}
+	if intent.ExpiredAt != "" {
+		t, err := time.Parse(time.RFC3339, intent.ExpiredAt)
+		if err == nil {
+			expiredAt = t
+		}
+	}
+
+	_, err := db.ExecContext(ctx, SQL,
+		intent.PaymentMethod,
+		intent.PaymentChannel,
+		intent.GatewayOrderId,
+		intent.GatewayToken,
+		intent.GatewayRedirectUrl,
+		expiredAt,
+		intent.Status,
+		intent.Id,
+	)
+
+	return err
+}
+
+func (u *PaymentIntentRepositoryImpl) UpdateIntentStatus(ctx context.Context, intentID int, status string, transactionID string, db *sql.DB) error {
+	loc := time.FixedZone("WIB", 7*60*60)
+	now := time.Now().In(loc)
+
+	var SQL string
+	var args []interface{}
+
+	if status == "paid" {
+		SQL = `UPDATE payment_intents SET status = $1, gateway_transaction_id = $2, paid_at = $3 WHERE id = $4`
+		args = []interface{}{status, transactionID, now, intentID}
+	} else {
+		SQL = `UPDATE payment_intents SET status = $1, gateway_transaction_id = $2 WHERE id = $3`
+		args = []interface{}{status, transactionID, intentID}
+	}
+
+	_, err := db.ExecContext(ctx, SQL, args...)
+	return err
+}
+
+// LockIntent returns an intent like GetByID, locked until tx ends so its refunds
+// are made one at a time
+func (u *PaymentIntentRepositoryImpl) LockIntent(ctx context.Context, intentID int, tx *sql.Tx) (*proto.PaymentIntentResponse, error) {
+	SQL := `SELECT id, user_id, amount, refunded_amount, currency, payment_method, payment_channel,
+			gateway_transaction_id, gateway_order_id, gateway_token, gateway_redirect_url,
+			status, created_at, paid_at, expired_at, livemode
+			FROM payment_intents WHERE id = $1 FOR UPDATE`
+
+	intent, err := scanPaymentIntent(tx.QueryRowContext(ctx, SQL, intentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("payment intent not found")
+		}
+		return nil, err
+	}
+
+	return intent, nil
+}
+
+// AddRefund adds to the refunded amount of an intent, failing with ErrRefundTooLarge
+// rather than refunding more than was paid
+func (u *PaymentIntentRepositoryImpl) AddRefund(ctx context.Context, intentID int, amount float64, tx *sql.Tx) error {
+	SQL := `UPDATE payment_intents SET
+			refunded_amount = refunded_amount + $1,
+			status = CASE WHEN refunded_amount + $1 >= amount THEN 'refunded' ELSE 'partially_refunded' END
+			WHERE id = $2 AND refunded_amount + $1 <= amount`
+
+	result, err := tx.ExecContext(ctx, SQL, amount, intentID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrRefundTooLarge
+	}
+	return nil
+}
+
+func (u *PaymentIntentRepositoryImpl) CreateRefund(ctx context.Context, refund *proto.RefundResponse, tx *sql.Tx) (*proto.RefundResponse, error) {
+	SQL := `INSERT INTO payment_refunds(payment_intent_id, payment_id, order_id, amount, reason, refund_key, status)
+			VALUES (NULLIF($1, 0), $2, $3, $4, $5, $6, $7)
+			RETURNING id, created_at`
+
+	var createdAt time.Time
+	if err := tx.QueryRowContext(ctx, SQL,
+		refund.PaymentIntentId,
+		refund.PaymentId,
+		refund.OrderId,
+		refund.Amount,
+		refund.Reason,
+		refund.RefundKey,
+		refund.Status,
+	).Scan(&refund.Id, &createdAt); err != nil {
+		return nil, err
+	}
+	refund.CreatedAt = createdAt.Format(time.RFC3339)
+
+	return refund, nil
+}
+
+// CompleteRefund marks a pending refund as refunded once the gateway has taken it
+func (u *PaymentIntentRepositoryImpl) CompleteRefund(ctx context.Context, refundID int, tx *sql.Tx) error {
+	SQL := `UPDATE payment_refunds SET status = 'refunded' WHERE id = $1 AND status = 'pending'`
+
+	result, err := tx.ExecContext(ctx, SQL, refundID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("refund is not pending")
+	}
+	return nil
+}
+
+// FailRefund marks a pending refund the gateway turned down as failed, so its amount
+// can be refunded again
+func (u *PaymentIntentRepositoryImpl) FailRefund(ctx context.Context, refundID int, db *sql.DB) error {
+	SQL := `UPDATE payment_refunds SET status = 'failed' WHERE id = $1 AND status = 'pending'`
+
+	_, err := db.ExecContext(ctx, SQL, refundID)
+	return err
+}
+
+func scanPaymentIntent(row *sql.Row) (*proto.PaymentIntentResponse, error) {
+	intent := &proto.PaymentIntentResponse{}
+	var (
+		currency             sql.NullString
+		paymentMethod        sql.NullString
+		paymentChannel       sql.NullString
+		gatewayTransactionID sql.NullString
+		gatewayOrderID       sql.NullString
+		gatewayToken         sql.NullString
+		gatewayRedirectURL   sql.NullString
+		createdAt            time.Time
+		paidAt               sql.NullTime
+		expiredAt            sql.NullTime
+	)
+
+	if err := row.Scan(
+		&intent.Id,
+		&intent.UserId,
+		&intent.Amount,
+		&intent.RefundedAmount,
+		&currency,
+		&paymentMethod,
+		&paymentChannel,
+		&gatewayTransactionID,
+		&gatewayOrderID,
+		&gatewayToken,
+		&gatewayRedirectURL,
+		&intent.Status,
+		&createdAt,
+		&paidAt,
+		&expiredAt,
+		&intent.Livemode,
+	); err != nil {
+		return nil, err
+	}
+
+	intent.Currency = currency.String
+	intent.PaymentMethod = paymentMethod.String
+	intent.PaymentChannel = paymentChannel.String
+	intent.GatewayTransactionId = gatewayTransactionID.String
+	intent.GatewayOrderId = gatewayOrderID.String
+	intent.GatewayToken = gatewayToken.String
+	intent.GatewayRedirectUrl = gatewayRedirectURL.String
+	intent.CreatedAt = createdAt.Format(time.RFC3339)
+
+	if paidAt.Valid {
+		intent.PaidAt = paidAt.Time.Format(time.RFC3339)
+	}
+	if expiredAt.Valid {
+		intent.ExpiredAt = expiredAt.Time.Format(time.RFC3339)
+	}
+
+	return intent, nil
+}
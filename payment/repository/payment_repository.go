@@ -1,327 +1,464 @@
-package repository
-
-import (
-	"context"
-	"database/sql"
-	"errors"
-	"payment/proto"
-	"time"
-)
-
-type PaymentRepository interface {
-	CreatePayment(ctx context.Context, payload *proto.CreatePaymentRequest, db *sql.DB) (*proto.PaymentResponse, error)
-	GetByID(ctx context.Context, paymentID int, db *sql.DB) (*proto.PaymentResponse, error)
-	GetByOrderID(ctx context.Context, orderID int, db *sql.DB) (*proto.PaymentResponse, error)
-	GetByGatewayOrderID(ctx context.Context, gatewayOrderID string, db *sql.DB) (*proto.PaymentResponse, error)
-	UpdatePaymentGateway(ctx context.Context, payment *proto.PaymentResponse, db *sql.DB) error
-	UpdatePaymentStatus(ctx context.Context, orderID int, status string, transactionID string, db *sql.DB) error
-}
-
-type PaymentRepositoryImpl struct{}
-
-func NewPaymentRepository() *PaymentRepositoryImpl {
-	return &PaymentRepositoryImpl{}
-}
-
-func (u *PaymentRepositoryImpl) CreatePayment(ctx context.Context, payload *proto.CreatePaymentRequest, db *sql.DB) (*proto.PaymentResponse, error) {
-	SQL := `INSERT INTO payments(order_id, amount, status) 
-			VALUES ($1, $2, 'pending') 
-			RETURNING id, order_id, amount, currency, status, created_at`
-
-	row := db.QueryRowContext(ctx, SQL, payload.OrderId, payload.Amount)
-
-	payment := &proto.PaymentResponse{}
-	var createdAt time.Time
-	var currency sql.NullString
-
-	if err := row.Scan(
-		&payment.Id,
-		&payment.OrderId,
-		&payment.Amount,
-		&currency,
-		&payment.Status,
-		&createdAt,
-	); err != nil {
-		return nil, err
-	}
-
-	payment.Currency = currency.String
-	if payment.Currency == "" {
-		payment.Currency = "IDR"
-	}
-	payment.CreatedAt = createdAt.Format(time.RFC3339)
-
-	return payment, nil
-}
-
-func (u *PaymentRepositoryImpl) GetByID(ctx context.Context, paymentID int, db *sql.DB) (*proto.PaymentResponse, error) {
-	SQL := `SELECT id, order_id, amount, currency, payment_method, payment_channel, 
-			gateway_name, gateway_transaction_id, gateway_order_id, gateway_token, 
-			gateway_redirect_url, va_number, qr_code_url, status, created_at, paid_at, expired_at 
-			FROM payments WHERE id = $1`
-
-	row := db.QueryRowContext(ctx, SQL, paymentID)
-
-	payment := &proto.PaymentResponse{}
-	var (
-		currency             sql.NullString
-		paymentMethod        sql.NullString
-		paymentChannel       sql.NullString
-		gatewayName          sql.NullString
-		gatewayTransactionID sql.NullString
-		gatewayOrderID       sql.NullString
-		gatewayToken         sql.NullString
-		gatewayRedirectURL   sql.NullString
-		vaNumber             sql.NullString
-		qrCodeURL            sql.NullString
-		createdAt            time.Time
-		paidAt               sql.NullTime
-		expiredAt            sql.NullTime
-	)
-
-	if err := row.Scan(
-		&payment.Id,
-		&payment.OrderId,
-		&payment.Amount,
-		&currency,
-		&paymentMethod,
-		&paymentChannel,
-		&gatewayName,
-		&gatewayTransactionID,
-		&gatewayOrderID,
-		&gatewayToken,
-		&gatewayRedirectURL,
-		&vaNumber,
-		&qrCodeURL,
-		&payment.Status,
-		&createdAt,
-		&paidAt,
-		&expiredAt,
-	); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("payment not found")
-		}
-		return nil, err
-	}
-
-	payment.Currency = currency.String
-	payment.PaymentMethod = paymentMethod.String
-	payment.PaymentChannel = paymentChannel.String
-	payment.GatewayName = gatewayName.String
-	payment.GatewayTransactionId = gatewayTransactionID.String
-	payment.GatewayOrderId = gatewayOrderID.String
-	payment.GatewayToken = gatewayToken.String
-	payment.GatewayRedirectUrl = gatewayRedirectURL.String
-	payment.VaNumber = vaNumber.String
-	payment.QrCodeUrl = qrCodeURL.String
-	payment.CreatedAt = createdAt.Format(time.RFC3339)
-
-	if paidAt.Valid {
-		payment.PaidAt = paidAt.Time.Format(time.RFC3339)
-	}
-	if expiredAt.Valid {
-		payment.ExpiredAt = expiredAt.Time.Format(time.RFC3339)
-	}
-
-	return payment, nil
-}
-
-func (u *PaymentRepositoryImpl) GetByOrderID(ctx context.Context, orderID int, db *sql.DB) (*proto.PaymentResponse, error) {
-	SQL := `SELECT id, order_id, amount, currency, payment_method, payment_channel, 
-			gateway_name, gateway_transaction_id, gateway_order_id, gateway_token, 
-			gateway_redirect_url, va_number, qr_code_url, status, created_at, paid_at, expired_at 
-			FROM payments WHERE order_id = $1`
-
-	row := db.QueryRowContext(ctx, SQL, orderID)
-
-	payment := &proto.PaymentResponse{}
-	var (
-		currency             sql.NullString
-		paymentMethod        sql.NullString
-		paymentChannel       sql.NullString
-		gatewayName          sql.NullString
-		gatewayTransactionID sql.NullString
-		gatewayOrderID       sql.NullString
-		gatewayToken         sql.NullString
-		gatewayRedirectURL   sql.NullString
-		vaNumber             sql.NullString
-		qrCodeURL            sql.NullString
-		createdAt            time.Time
-		paidAt               sql.NullTime
-		expiredAt            sql.NullTime
-	)
-
-	if err := row.Scan(
-		&payment.Id,
-		&payment.OrderId,
-		&payment.Amount,
-		&currency,
-		&paymentMethod,
-		&paymentChannel,
-		&gatewayName,
-		&gatewayTransactionID,
-		&gatewayOrderID,
-		&gatewayToken,
-		&gatewayRedirectURL,
-		&vaNumber,
-		&qrCodeURL,
-		&payment.Status,
-		&createdAt,
-		&paidAt,
-		&expiredAt,
-	); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("payment not found for this order")
-		}
-		return nil, err
-	}
-
-	payment.Currency = currency.String
-	payment.PaymentMethod = paymentMethod.String
-	payment.PaymentChannel = paymentChannel.String
-	payment.GatewayName = gatewayName.String
-	payment.GatewayTransactionId = gatewayTransactionID.String
-	payment.GatewayOrderId = gatewayOrderID.String
-	payment.GatewayToken = gatewayToken.String
-	payment.GatewayRedirectUrl = gatewayRedirectURL.String
-	payment.VaNumber = vaNumber.String
-	payment.QrCodeUrl = qrCodeURL.String
-	payment.CreatedAt = createdAt.Format(time.RFC3339)
-
-	if paidAt.Valid {
-		payment.PaidAt = paidAt.Time.Format(time.RFC3339)
-	}
-	if expiredAt.Valid {
-		payment.ExpiredAt = expiredAt.Time.Format(time.RFC3339)
-	}
-
-	return payment, nil
-}
-
-func (u *PaymentRepositoryImpl) GetByGatewayOrderID(ctx context.Context, gatewayOrderID string, db *sql.DB) (*proto.PaymentResponse, error) {
-	SQL := `SELECT id, order_id, amount, currency, payment_method, payment_channel, 
-			gateway_name, gateway_transaction_id, gateway_order_id, gateway_token, 
-			gateway_redirect_url, va_number, qr_code_url, status, created_at, paid_at, expired_at 
-			FROM payments WHERE gateway_order_id = $1`
-
-	row := db.QueryRowContext(ctx, SQL, gatewayOrderID)
-
-	payment := &proto.PaymentResponse{}
-	var (
-		currency             sql.NullString
-		paymentMethod        sql.NullString
-		paymentChannel       sql.NullString
-		gatewayName          sql.NullString
-		gatewayTransactionID sql.NullString
-		gatewayOrderIDVal    sql.NullString
-		gatewayToken         sql.NullString
-		gatewayRedirectURL   sql.NullString
-		vaNumber             sql.NullString
-		qrCodeURL            sql.NullString
-		createdAt            time.Time
-		paidAt               sql.NullTime
-		expiredAt            sql.NullTime
-	)
-
-	if err := row.Scan(
-		&payment.Id,
-		&payment.OrderId,
-		&payment.Amount,
-		&currency,
-		&paymentMethod,
-		&paymentChannel,
-		&gatewayName,
-		&gatewayTransactionID,
-		&gatewayOrderIDVal,
-		&gatewayToken,
-		&gatewayRedirectURL,
-		&vaNumber,
-		&qrCodeURL,
-		&payment.Status,
-		&createdAt,
-		&paidAt,
-		&expiredAt,
-	); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("payment not found by gateway order ID")
-		}
-		return nil, err
-	}
-
-	payment.Currency = currency.String
-	payment.PaymentMethod = paymentMethod.String
-	payment.PaymentChannel = paymentChannel.String
-	payment.GatewayName = gatewayName.String
-	payment.GatewayTransactionId = gatewayTransactionID.String
-	payment.GatewayOrderId = gatewayOrderIDVal.String
-	payment.GatewayToken = gatewayToken.String
-	payment.GatewayRedirectUrl = gatewayRedirectURL.String
-	payment.VaNumber = vaNumber.String
-	payment.QrCodeUrl = qrCodeURL.String
-	payment.CreatedAt = createdAt.Format(time.RFC3339)
-
-	if paidAt.Valid {
-		payment.PaidAt = paidAt.Time.Format(time.RFC3339)
-	}
-	if expiredAt.Valid {
-		payment.ExpiredAt = expiredAt.Time.Format(time.RFC3339)
-	}
-
-	return payment, nil
-}
-
-func (u *PaymentRepositoryImpl) UpdatePaymentGateway(ctx context.Context, payment *proto.PaymentResponse, db *sql.DB) error {
-	SQL := `UPDATE payments SET 
-			payment_method = $1, 
-			payment_channel = $2,
-			gateway_order_id = $3,
-			gateway_token = $4,
-			gateway_redirect_url = $5,
-			va_number = $6,
-			qr_code_url = $7,
-			expired_at = $8,
-			status = $9
-			WHERE order_id = $10`
-
-	var expiredAt interface{}
-	if payment.ExpiredAt != "" {
-		t, err := time.Parse(time.RFC3339, payment.ExpiredAt)
-		if err == nil {
-			expiredAt = t
-		}
-	}
-
-	_, err := db.ExecContext(ctx, SQL,
-		payment.PaymentMethod,
-		payment.PaymentChannel,
-		payment.GatewayOrderId,
-		payment.GatewayToken,
-		payment.GatewayRedirectUrl,
-		payment.VaNumber,
-		payment.QrCodeUrl,
-		expiredAt,
-		payment.Status,
-		payment.OrderId,
-	)
-
-	return err
-}
-
-func (u *PaymentRepositoryImpl) UpdatePaymentStatus(ctx context.Context, orderID int, status string, transactionID string, db *sql.DB) error {
-	loc := time.FixedZone("WIB", 7*60*60)
-	now := time.Now().In(loc)
-
-	var SQL string
-	var args []interface{}
-
-	if status == "success" || status == "paid" {
-		SQL = `UPDATE payments SET status = $1, gateway_transaction_id = $2, paid_at = $3 WHERE order_id = $4`
-		args = []interface{}{status, transactionID, now, orderID}
-	} else {
-		SQL = `UPDATE payments SET status = $1, gateway_transaction_id = $2 WHERE order_id = $3`
-		args = []interface{}{status, transactionID, orderID}
-	}
-
-	_, err := db.ExecContext(ctx, SQL, args...)
-	return err
-}
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"payment/proto"
+	"time"
+)
+
+var ErrRefundTooLarge = errors.New("refund exceeds the amount left to refund")
+
+type PaymentRepository interface {
+	CreatePayment(ctx context.Context, payload *proto.CreatePaymentRequest, db *sql.DB) (*proto.PaymentResponse, error)
+	GetByID(ctx context.Context, paymentID int, db *sql.DB) (*proto.PaymentResponse, error)
+	GetByOrderID(ctx context.Context, orderID int, db *sql.DB) (*proto.PaymentResponse, error)
+	GetByGatewayOrderID(ctx context.Context, gatewayOrderID string, db *sql.DB) (*proto.PaymentResponse, error)
+	UpdatePaymentGateway(ctx context.Context, payment *proto.PaymentResponse, db *sql.DB) error
+	UpdatePaymentStatus(ctx context.Context, orderID int, status string, transactionID string, db *sql.DB) error
+	LockRefundable(ctx context.Context, paymentID int, tx *sql.Tx) (float64, error)
+	GatewayRefunded(ctx context.Context, paymentID int, tx *sql.Tx) (float64, error)
+	AddRefund(ctx context.Context, paymentID int, amount float64, tx *sql.Tx) (string, error)
+	SetWalletAmount(ctx context.Context, paymentID int, amount float64, tx *sql.Tx) error
+	ReleaseWalletAmount(ctx context.Context, paymentID int, tx *sql.Tx) (float64, error)
+}
+
+type PaymentRepositoryImpl struct{}
+
+func NewPaymentRepository() *PaymentRepositoryImpl {
+	return &PaymentRepositoryImpl{}
+}
+
+func (u *PaymentRepositoryImpl) CreatePayment(ctx context.Context, payload *proto.CreatePaymentRequest, db *sql.DB) (*proto.PaymentResponse, error) {
+	SQL := `INSERT INTO payments(order_id, amount, status, livemode, user_id) 
+			VALUES ($1, $2, 'pending', $3, NULLIF($4, 0)) 
+			RETURNING id, order_id, amount, currency, status, created_at, livemode`
+
+	row := db.QueryRowContext(ctx, SQL, payload.OrderId, payload.Amount, payload.Livemode, payload.UserId)
+
+	payment := &proto.PaymentResponse{}
+	var createdAt time.Time
+	var currency sql.NullString
+
+	if err := row.Scan(
+		&payment.Id,
+		&payment.OrderId,
+		&payment.Amount,
+		&currency,
+		&payment.Status,
+		&createdAt,
+		&payment.Livemode,
+	); err != nil {
+		return nil, err
+	}
+	payment.UserId = payload.UserId
+
+	payment.Currency = currency.String
+	if payment.Currency == "" {
+		payment.Currency = "IDR"
+	}
+	payment.CreatedAt = createdAt.Format(time.RFC3339)
+
+	return payment, nil
+}
+
+func (u *PaymentRepositoryImpl) GetByID(ctx context.Context, paymentID int, db *sql.DB) (*proto.PaymentResponse, error) {
+	SQL := `SELECT id, order_id, amount, currency, payment_method, payment_channel, 
+			gateway_name, gateway_transaction_id, gateway_order_id, gateway_token, 
+			gateway_redirect_url, va_number, qr_code_url, status, created_at, paid_at, expired_at,
+			payment_intent_id, refunded_amount, livemode, wallet_amount, user_id
+			FROM payments WHERE id = $1`
+
+	row := db.QueryRowContext(ctx, SQL, paymentID)
+
+	payment := &proto.PaymentResponse{}
+	var (
+		currency             sql.NullString
+		paymentMethod        sql.NullString
+		paymentChannel       sql.NullString
+		gatewayName          sql.NullString
+		gatewayTransactionID sql.NullString
+		gatewayOrderID       sql.NullString
+		gatewayToken         sql.NullString
+		gatewayRedirectURL   sql.NullString
+		vaNumber             sql.NullString
+		qrCodeURL            sql.NullString
+		createdAt            time.Time
+		paidAt               sql.NullTime
+		expiredAt            sql.NullTime
+		paymentIntentID      sql.NullInt32
+		userID               sql.NullInt32
+	)
+
+	if err := row.Scan(
+		&payment.Id,
+		&payment.OrderId,
+		&payment.Amount,
+		&currency,
+		&paymentMethod,
+		&paymentChannel,
+		&gatewayName,
+		&gatewayTransactionID,
+		&gatewayOrderID,
+		&gatewayToken,
+		&gatewayRedirectURL,
+		&vaNumber,
+		&qrCodeURL,
+		&payment.Status,
+		&createdAt,
+		&paidAt,
+		&expiredAt,
+		&paymentIntentID,
+		&payment.RefundedAmount,
+		&payment.Livemode,
+		&payment.WalletAmount,
+		&userID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("payment not found")
+		}
+		return nil, err
+	}
+
+	payment.Currency = currency.String
+	payment.PaymentMethod = paymentMethod.String
+	payment.PaymentChannel = paymentChannel.String
+	payment.GatewayName = gatewayName.String
+	payment.GatewayTransactionId = gatewayTransactionID.String
+	payment.GatewayOrderId = gatewayOrderID.String
+	payment.GatewayToken = gatewayToken.String
+	payment.GatewayRedirectUrl = gatewayRedirectURL.String
+	payment.VaNumber = vaNumber.String
+	payment.QrCodeUrl = qrCodeURL.String
+	payment.CreatedAt = createdAt.Format(time.RFC3339)
+
+	if paidAt.Valid {
+		payment.PaidAt = paidAt.Time.Format(time.RFC3339)
+	}
+	if expiredAt.Valid {
+		payment.ExpiredAt = expiredAt.Time.Format(time.RFC3339)
+	}
+	payment.PaymentIntentId = paymentIntentID.Int32
+	payment.UserId = userID.Int32
+
+	return payment, nil
+}
+
+func (u *PaymentRepositoryImpl) GetByOrderID(ctx context.Context, orderID int, db *sql.DB) (*proto.PaymentResponse, error) {
+	SQL := `SELECT id, order_id, amount, currency, payment_method, payment_channel, 
+			gateway_name, gateway_transaction_id, gateway_order_id, gateway_token, 
+			gateway_redirect_url, va_number, qr_code_url, status, created_at, paid_at, expired_at,
+			payment_intent_id, refunded_amount, livemode, wallet_amount, user_id
+			FROM payments WHERE order_id = $1`
+
+	row := db.QueryRowContext(ctx, SQL, orderID)
+
+	payment := &proto.PaymentResponse{}
+	var (
+		currency             sql.NullString
+		paymentMethod        sql.NullString
+		paymentChannel       sql.NullString
+		gatewayName          sql.NullString
+		gatewayTransactionID sql.NullString
+		gatewayOrderID       sql.NullString
+		gatewayToken         sql.NullString
+		gatewayRedirectURL   sql.NullString
+		vaNumber             sql.NullString
+		qrCodeURL            sql.NullString
+		createdAt            time.Time
+		paidAt               sql.NullTime
+		expiredAt            sql.NullTime
+		paymentIntentID      sql.NullInt32
+		userID               sql.NullInt32
+	)
+
+	if err := row.Scan(
+		&payment.Id,
+		&payment.OrderId,
+		&payment.Amount,
+		&currency,
+		&paymentMethod,
+		&paymentChannel,
+		&gatewayName,
+		&gatewayTransactionID,
+		&gatewayOrderID,
+		&gatewayToken,
+		&gatewayRedirectURL,
+		&vaNumber,
+		&qrCodeURL,
+		&payment.Status,
+		&createdAt,
+		&paidAt,
+		&expiredAt,
+		&paymentIntentID,
+		&payment.RefundedAmount,
+		&payment.Livemode,
+		&payment.WalletAmount,
+		&userID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("payment not found for this order")
+		}
+		return nil, err
+	}
+
+	payment.Currency = currency.String
+	payment.PaymentMethod = paymentMethod.String
+	payment.PaymentChannel = paymentChannel.String
+	payment.GatewayName = gatewayName.String
+	payment.GatewayTransactionId = gatewayTransactionID.String
+	payment.GatewayOrderId = gatewayOrderID.String
+	payment.GatewayToken = gatewayToken.String
+	payment.GatewayRedirectUrl = gatewayRedirectURL.String
+	payment.VaNumber = vaNumber.String
+	payment.QrCodeUrl = qrCodeURL.String
+	payment.CreatedAt = createdAt.Format(time.RFC3339)
+
+	if paidAt.Valid {
+		payment.PaidAt = paidAt.Time.Format(time.RFC3339)
+	}
+	if expiredAt.Valid {
+		payment.ExpiredAt = expiredAt.Time.Format(time.RFC3339)
+	}
+	payment.PaymentIntentId = paymentIntentID.Int32
+	payment.UserId = userID.Int32
+
+	return payment, nil
+}
+
+func (u *PaymentRepositoryImpl) GetByGatewayOrderID(ctx context.Context, gatewayOrderID string, db *sql.DB) (*proto.PaymentResponse, error) {
+	SQL := `SELECT id, order_id, amount, currency, payment_method, payment_channel, 
+			gateway_name, gateway_transaction_id, gateway_order_id, gateway_token, 
+			gateway_redirect_url, va_number, qr_code_url, status, created_at, paid_at, expired_at,
+			payment_intent_id, refunded_amount, livemode, wallet_amount, user_id
+			FROM payments WHERE gateway_order_id = $1`
+
+	row := db.QueryRowContext(ctx, SQL, gatewayOrderID)
+
+	payment := &proto.PaymentResponse{}
+	var (
+		currency             sql.NullString
+		paymentMethod        sql.NullString
+		paymentChannel       sql.NullString
+		gatewayName          sql.NullString
+		gatewayTransactionID sql.NullString
+		gatewayOrderIDVal    sql.NullString
+		gatewayToken         sql.NullString
+		gatewayRedirectURL   sql.NullString
+		vaNumber             sql.NullString
+		qrCodeURL            sql.NullString
+		createdAt            time.Time
+		paidAt               sql.NullTime
+		expiredAt            sql.NullTime
+		paymentIntentID      sql.NullInt32
+		userID               sql.NullInt32
+	)
+
+	if err := row.Scan(
+		&payment.Id,
+		&payment.OrderId,
+		&payment.Amount,
+		&currency,
+		&paymentMethod,
+		&paymentChannel,
+		&gatewayName,
+		&gatewayTransactionID,
+		&gatewayOrderIDVal,
+		&gatewayToken,
+		&gatewayRedirectURL,
+		&vaNumber,
+		&qrCodeURL,
+		&payment.Status,
+		&createdAt,
+		&paidAt,
+		&expiredAt,
+		&paymentIntentID,
+		&payment.RefundedAmount,
+		&payment.Livemode,
+		&payment.WalletAmount,
+		&userID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("payment not found by gateway order ID")
+		}
+		return nil, err
+	}
+
+	payment.Currency = currency.String
+	payment.PaymentMethod = paymentMethod.String
+	payment.PaymentChannel = paymentChannel.String
+	payment.GatewayName = gatewayName.String
+	payment.GatewayTransactionId = gatewayTransactionID.String
+	payment.GatewayOrderId = gatewayOrderIDVal.String
+	payment.GatewayToken = gatewayToken.String
+	payment.GatewayRedirectUrl = gatewayRedirectURL.String
+	payment.VaNumber = vaNumber.String
+	payment.QrCodeUrl = qrCodeURL.String
+	payment.CreatedAt = createdAt.Format(time.RFC3339)
+
+	if paidAt.Valid {
+		payment.PaidAt = paidAt.Time.Format(time.RFC3339)
+	}
+	if expiredAt.Valid {
+		payment.ExpiredAt = expiredAt.Time.Format(time.RFC3339)
+	}
+	payment.PaymentIntentId = paymentIntentID.Int32
+	payment.UserId = userID.Int32
+
+	return payment, nil
+}
+
+func (u *PaymentRepositoryImpl) UpdatePaymentGateway(ctx context.Context, payment *proto.PaymentResponse, db *sql.DB) error {
+	SQL := `UPDATE payments SET 
+			payment_method = $1, 
+			payment_channel = $2,
+			gateway_order_id = $3,
+			gateway_token = $4,
+			gateway_redirect_url = $5,
+			va_number = $6,
+			qr_code_url = $7,
+			expired_at = $8,
+			status = $9
+			WHERE order_id = $10`
+
+	var expiredAt interface{}
+	if payment.ExpiredAt != "" {
+		t, err := time.Parse(time.RFC3339, payment.ExpiredAt)
+		if err == nil {
+			expiredAt = t
+		}
+	}
+
+	_, err := db.ExecContext(ctx, SQL,
+		payment.PaymentMethod,
+		payment.PaymentChannel,
+		payment.GatewayOrderId,
+		payment.GatewayToken,
+		payment.GatewayRedirectUrl,
+		payment.VaNumber,
+		payment.QrCodeUrl,
+		expiredAt,
+		payment.Status,
+		payment.OrderId,
+	)
+
+	return err
+}
+
+func (u *PaymentRepositoryImpl) UpdatePaymentStatus(ctx context.Context, orderID int, status string, transactionID string, db *sql.DB) error {
+	loc := time.FixedZone("WIB", 7*60*60)
+	now := time.Now().In(loc)
+
+	var SQL string
+	var args []interface{}
+
+	if status == "success" || status == "paid" {
+		SQL = `UPDATE payments SET status = $1, gateway_transaction_id = $2, paid_at = $3 WHERE order_id = $4`
+		args = []interface{}{status, transactionID, now, orderID}
+	} else {
+		SQL = `UPDATE payments SET status = $1, gateway_transaction_id = $2 WHERE order_id = $3`
+		args = []interface{}{status, transactionID, orderID}
+	}
+
+	_, err := db.ExecContext(ctx, SQL, args...)
+	return err
+}
+
+// LockRefundable returns what is left to refund of a payment, less refunds the gateway
+// has not confirmed yet, locked until tx ends
+func (u *PaymentRepositoryImpl) LockRefundable(ctx context.Context, paymentID int, tx *sql.Tx) (float64, error) {
+	SQL := `SELECT amount - refunded_amount - (
+				SELECT COALESCE(SUM(amount), 0) FROM payment_refunds
+				WHERE payment_id = payments.id AND status = 'pending')
+			FROM payments WHERE id = $1 FOR UPDATE`
+
+	var refundable float64
+	if err := tx.QueryRowContext(ctx, SQL, paymentID).Scan(&refundable); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errors.New("payment not found")
+		}
+		return 0, err
+	}
+
+	return refundable, nil
+}
+
+// GatewayRefunded returns how much of a payment was refunded, or is being refunded,
+// through the gateway rather than as store credit
+func (u *PaymentRepositoryImpl) GatewayRefunded(ctx context.Context, paymentID int, tx *sql.Tx) (float64, error) {
+	SQL := `SELECT COALESCE(SUM(amount), 0) FROM payment_refunds
+			WHERE payment_id = $1 AND status IN ('pending', 'refunded')`
+
+	var refunded float64
+	if err := tx.QueryRowContext(ctx, SQL, paymentID).Scan(&refunded); err != nil {
+		return 0, err
+	}
+
+	return refunded, nil
+}
+
+// AddRefund adds to the refunded amount and returns the resulting payment status. It
+// fails with ErrRefundTooLarge rather than refunding more than was paid.
+func (u *PaymentRepositoryImpl) AddRefund(ctx context.Context, paymentID int, amount float64, tx *sql.Tx) (string, error) {
+	SQL := `UPDATE payments SET
+			refunded_amount = refunded_amount + $1,
+			status = CASE WHEN refunded_amount + $1 >= amount THEN 'refunded' ELSE 'partially_refunded' END
+			WHERE id = $2 AND refunded_amount + $1 <= amount
+			RETURNING status`
+
+	var status string
+	if err := tx.QueryRowContext(ctx, SQL, amount, paymentID).Scan(&status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrRefundTooLarge
+		}
+		return "", err
+	}
+
+	return status, nil
+}
+
+// SetWalletAmount records the portion of a payment covered by store credit.
+// It fails when a wallet portion was already taken, so a payment is never debited twice.
+func (u *PaymentRepositoryImpl) SetWalletAmount(ctx context.Context, paymentID int, amount float64, tx *sql.Tx) error {
+	SQL := `UPDATE payments SET wallet_amount = $1 WHERE id = $2 AND wallet_amount = 0`
+
+	result, err := tx.ExecContext(ctx, SQL, amount, paymentID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("payment already has a wallet portion")
+	}
+
+	return nil
+}
+
+// ReleaseWalletAmount clears the wallet portion of a payment and returns what it was
+func (u *PaymentRepositoryImpl) ReleaseWalletAmount(ctx context.Context, paymentID int, tx *sql.Tx) (float64, error) {
+	SQL := `SELECT wallet_amount FROM payments WHERE id = $1 FOR UPDATE`
+
+	var amount float64
+	if err := tx.QueryRowContext(ctx, SQL, paymentID).Scan(&amount); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errors.New("payment not found")
+		}
+		return 0, err
+	}
+
+	if amount == 0 {
+		return 0, nil
+	}
+
+	SQL = `UPDATE payments SET wallet_amount = 0 WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, SQL, paymentID); err != nil {
+		return 0, err
+	}
+
+	return amount, nil
+}
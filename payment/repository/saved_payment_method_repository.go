@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"payment/proto"
+	"time"
+)
+
+type SavedPaymentMethodRepository interface {
+	SaveMethod(ctx context.Context, method *proto.SavedPaymentMethod, gatewayToken string, db *sql.DB) (*proto.SavedPaymentMethod, error)
+	GetByID(ctx context.Context, id int, userID int, db *sql.DB) (*proto.SavedPaymentMethod, string, error)
+	GetByUserID(ctx context.Context, userID int, livemode bool, db *sql.DB) ([]*proto.SavedPaymentMethod, error)
+	DeleteMethod(ctx context.Context, id int, userID int, tx *sql.Tx) (bool, bool, error)
+	PromoteDefault(ctx context.Context, userID int, livemode bool, tx *sql.Tx) error
+}
+
+type SavedPaymentMethodRepositoryImpl struct{}
+
+func NewSavedPaymentMethodRepository() *SavedPaymentMethodRepositoryImpl {
+	return &SavedPaymentMethodRepositoryImpl{}
+}
+
+// SaveMethod stores a gateway token; saving the same token again refreshes its details.
+// The first card a user saves in each mode becomes the default.
+func (u *SavedPaymentMethodRepositoryImpl) SaveMethod(ctx context.Context, method *proto.SavedPaymentMethod, gatewayToken string, db *sql.DB) (*proto.SavedPaymentMethod, error) {
+	SQL := `INSERT INTO saved_payment_methods(user_id, gateway_token, masked_pan, brand, expiry_month, expiry_year, livemode, is_default)
+			VALUES ($1, $2, $3, $4, $5, $6, $7,
+				NOT EXISTS (SELECT 1 FROM saved_payment_methods WHERE user_id = $1 AND livemode = $7 AND is_default))
+			ON CONFLICT (user_id, gateway_token) DO UPDATE SET
+				masked_pan = EXCLUDED.masked_pan,
+				brand = EXCLUDED.brand,
+				expiry_month = EXCLUDED.expiry_month,
+				expiry_year = EXCLUDED.expiry_year
+			RETURNING id, is_default, created_at`
+
+	var createdAt time.Time
+	if err := db.QueryRowContext(ctx, SQL,
+		method.UserId,
+		gatewayToken,
+		method.MaskedPan,
+		method.Brand,
+		method.ExpiryMonth,
+		method.ExpiryYear,
+		method.Livemode,
+	).Scan(&method.Id, &method.IsDefault, &createdAt); err != nil {
+		return nil, err
+	}
+	method.CreatedAt = createdAt.Format(time.RFC3339)
+
+	return method, nil
+}
+
+// GetByID returns a saved card owned by the user along with its gateway token
+func (u *SavedPaymentMethodRepositoryImpl) GetByID(ctx context.Context, id int, userID int, db *sql.DB) (*proto.SavedPaymentMethod, string, error) {
+	SQL := `SELECT id, user_id, masked_pan, brand, expiry_month, expiry_year, is_default, created_at, livemode, gateway_token
+			FROM saved_payment_methods WHERE id = $1 AND user_id = $2`
+
+	method := &proto.SavedPaymentMethod{}
+	var (
+		createdAt    time.Time
+		gatewayToken string
+	)
+
+	if err := db.QueryRowContext(ctx, SQL, id, userID).Scan(
+		&method.Id,
+		&method.UserId,
+		&method.MaskedPan,
+		&method.Brand,
+		&method.ExpiryMonth,
+		&method.ExpiryYear,
+		&method.IsDefault,
+		&createdAt,
+		&method.Livemode,
+		&gatewayToken,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, "", errors.New("saved payment method not found")
+		}
+		return nil, "", err
+	}
+	method.CreatedAt = createdAt.Format(time.RFC3339)
+
+	return method, gatewayToken, nil
+}
+
+func (u *SavedPaymentMethodRepositoryImpl) GetByUserID(ctx context.Context, userID int, livemode bool, db *sql.DB) ([]*proto.SavedPaymentMethod, error) {
+	SQL := `SELECT id, user_id, masked_pan, brand, expiry_month, expiry_year, is_default, created_at, livemode
+			FROM saved_payment_methods WHERE user_id = $1 AND livemode = $2
+			ORDER BY is_default DESC, created_at DESC`
+
+	rows, err := db.QueryContext(ctx, SQL, userID, livemode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var methods []*proto.SavedPaymentMethod
+	for rows.Next() {
+		method := &proto.SavedPaymentMethod{}
+		var createdAt time.Time
+
+		if err := rows.Scan(
+			&method.Id,
+			&method.UserId,
+			&method.MaskedPan,
+			&method.Brand,
+			&method.ExpiryMonth,
+			&method.ExpiryYear,
+			&method.IsDefault,
+			&createdAt,
+			&method.Livemode,
+		); err != nil {
+			return nil, err
+		}
+		method.CreatedAt = createdAt.Format(time.RFC3339)
+		methods = append(methods, method)
+	}
+
+	return methods, rows.Err()
+}
+
+// DeleteMethod removes a saved card and reports whether it was the default, and in which mode
+func (u *SavedPaymentMethodRepositoryImpl) DeleteMethod(ctx context.Context, id int, userID int, tx *sql.Tx) (bool, bool, error) {
+	SQL := `DELETE FROM saved_payment_methods WHERE id = $1 AND user_id = $2 RETURNING is_default, livemode`
+
+	var wasDefault, livemode bool
+	if err := tx.QueryRowContext(ctx, SQL, id, userID).Scan(&wasDefault, &livemode); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, false, errors.New("saved payment method not found")
+		}
+		return false, false, err
+	}
+
+	return wasDefault, livemode, nil
+}
+
+// PromoteDefault makes the most recently saved card of a mode the user's default
+func (u *SavedPaymentMethodRepositoryImpl) PromoteDefault(ctx context.Context, userID int, livemode bool, tx *sql.Tx) error {
+	SQL := `UPDATE saved_payment_methods SET is_default = TRUE
+			WHERE id = (SELECT id FROM saved_payment_methods WHERE user_id = $1 AND livemode = $2 ORDER BY created_at DESC LIMIT 1)`
+
+	_, err := tx.ExecContext(ctx, SQL, userID, livemode)
+	return err
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"payment/proto"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type UserRepository interface {
+	GetUserByID(ctx context.Context, userID int) (*proto.User, error)
+}
+
+type UserRepositoryImpl struct {
+	client proto.AuthServiceClient
+}
+
+func NewUserRepository() *UserRepositoryImpl {
+	addr := os.Getenv("USER_SERVICE_ADDR")
+	if addr == "" {
+		addr = "user-service:50001"
+	}
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		logrus.Fatalf("Failed to connect to user service: %v", err)
+	}
+
+	logrus.Infof("Connected to user service at %s", addr)
+	client := proto.NewAuthServiceClient(conn)
+
+	return &UserRepositoryImpl{client: client}
+}
+
+func (u *UserRepositoryImpl) GetUserByID(ctx context.Context, userID int) (*proto.User, error) {
+	ctxRepo, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.client.GetUserByID(ctxRepo, &proto.GetUserRequest{UserId: int64(userID)})
+}
@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"payment/proto"
+	"time"
+)
+
+var ErrInsufficientBalance = errors.New("insufficient wallet balance")
+
+type WalletRepository interface {
+	GetOrCreateWallet(ctx context.Context, userID int, livemode bool, db *sql.DB) (*proto.WalletResponse, error)
+	LockWallet(ctx context.Context, userID int, livemode bool, tx *sql.Tx) (*proto.WalletResponse, error)
+	ApplyTransaction(ctx context.Context, entry *proto.WalletTransaction, livemode bool, tx *sql.Tx) (*proto.WalletTransaction, error)
+	GetPaymentWalletID(ctx context.Context, paymentID int, tx *sql.Tx) (int, error)
+	GetTransactions(ctx context.Context, walletID int, limit int, db *sql.DB) ([]*proto.WalletTransaction, error)
+	CreateTopUp(ctx context.Context, wallet *proto.WalletResponse, amount float64, db *sql.DB) (*proto.WalletTopUpResponse, error)
+	UpdateTopUpGateway(ctx context.Context, topUp *proto.WalletTopUpResponse, gatewayOrderID string, db *sql.DB) error
+	GetTopUpByGatewayOrderID(ctx context.Context, gatewayOrderID string, db *sql.DB) (*proto.WalletTopUpResponse, bool, error)
+	SettleTopUp(ctx context.Context, topUpID int, status string, transactionID string, tx *sql.Tx) (bool, error)
+}
+
+type WalletRepositoryImpl struct{}
+
+func NewWalletRepository() *WalletRepositoryImpl {
+	return &WalletRepositoryImpl{}
+}
+
+func (u *WalletRepositoryImpl) GetOrCreateWallet(ctx context.Context, userID int, livemode bool, db *sql.DB) (*proto.WalletResponse, error) {
+	SQL := `INSERT INTO wallets(user_id, livemode) VALUES ($1, $2)
+			ON CONFLICT (user_id, livemode) DO NOTHING`
+	if _, err := db.ExecContext(ctx, SQL, userID, livemode); err != nil {
+		return nil, err
+	}
+
+	SQL = `SELECT id, user_id, balance, currency, livemode FROM wallets WHERE user_id = $1 AND livemode = $2`
+	return scanWallet(db.QueryRowContext(ctx, SQL, userID, livemode))
+}
+
+// LockWallet creates the wallet if needed and locks its row for the rest of the transaction
+func (u *WalletRepositoryImpl) LockWallet(ctx context.Context, userID int, livemode bool, tx *sql.Tx) (*proto.WalletResponse, error) {
+	SQL := `INSERT INTO wallets(user_id, livemode) VALUES ($1, $2)
+			ON CONFLICT (user_id, livemode) DO NOTHING`
+	if _, err := tx.ExecContext(ctx, SQL, userID, livemode); err != nil {
+		return nil, err
+	}
+
+	SQL = `SELECT id, user_id, balance, currency, livemode FROM wallets WHERE user_id = $1 AND livemode = $2 FOR UPDATE`
+	return scanWallet(tx.QueryRowContext(ctx, SQL, userID, livemode))
+}
+
+// ApplyTransaction changes the balance and appends the change to the log in the same transaction
+func (u *WalletRepositoryImpl) ApplyTransaction(ctx context.Context, entry *proto.WalletTransaction, livemode bool, tx *sql.Tx) (*proto.WalletTransaction, error) {
+	SQL := `UPDATE wallets SET balance = balance + $1, updated_at = NOW()
+			WHERE id = $2 AND balance + $1 >= 0
+			RETURNING balance`
+
+	if err := tx.QueryRowContext(ctx, SQL, entry.Amount, entry.WalletId).Scan(&entry.BalanceAfter); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInsufficientBalance
+		}
+		return nil, err
+	}
+
+	SQL = `INSERT INTO wallet_transactions(wallet_id, livemode, type, amount, balance_after, reference_type, reference_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id, created_at`
+
+	var createdAt time.Time
+	if err := tx.QueryRowContext(ctx, SQL,
+		entry.WalletId,
+		livemode,
+		entry.Type,
+		entry.Amount,
+		entry.BalanceAfter,
+		entry.ReferenceType,
+		entry.ReferenceId,
+	).Scan(&entry.Id, &createdAt); err != nil {
+		return nil, err
+	}
+	entry.CreatedAt = createdAt.Format(time.RFC3339)
+
+	return entry, nil
+}
+
+// GetPaymentWalletID returns the wallet a payment's wallet portion was debited from
+func (u *WalletRepositoryImpl) GetPaymentWalletID(ctx context.Context, paymentID int, tx *sql.Tx) (int, error) {
+	SQL := `SELECT wallet_id FROM wallet_transactions
+			WHERE type = 'payment' AND reference_type = 'payment' AND reference_id = $1
+			ORDER BY id DESC LIMIT 1`
+
+	var walletID int
+	if err := tx.QueryRowContext(ctx, SQL, paymentID).Scan(&walletID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errors.New("wallet debit not found for payment")
+		}
+		return 0, err
+	}
+
+	return walletID, nil
+}
+
+func (u *WalletRepositoryImpl) GetTransactions(ctx context.Context, walletID int, limit int, db *sql.DB) ([]*proto.WalletTransaction, error) {
+	SQL := `SELECT id, wallet_id, type, amount, balance_after, reference_type, reference_id, created_at
+			FROM wallet_transactions WHERE wallet_id = $1
+			ORDER BY id DESC LIMIT $2`
+
+	rows, err := db.QueryContext(ctx, SQL, walletID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []*proto.WalletTransaction
+	for rows.Next() {
+		entry := &proto.WalletTransaction{}
+		var (
+			referenceType sql.NullString
+			referenceID   sql.NullInt32
+			createdAt     time.Time
+		)
+
+		if err := rows.Scan(
+			&entry.Id,
+			&entry.WalletId,
+			&entry.Type,
+			&entry.Amount,
+			&entry.BalanceAfter,
+			&referenceType,
+			&referenceID,
+			&createdAt,
+		); err != nil {
+			return nil, err
+		}
+
+		entry.ReferenceType = referenceType.String
+		entry.ReferenceId = referenceID.Int32
+		entry.CreatedAt = createdAt.Format(time.RFC3339)
+		transactions = append(transactions, entry)
+	}
+
+	return transactions, rows.Err()
+}
+
+func (u *WalletRepositoryImpl) CreateTopUp(ctx context.Context, wallet *proto.WalletResponse, amount float64, db *sql.DB) (*proto.WalletTopUpResponse, error) {
+	SQL := `INSERT INTO wallet_topups(wallet_id, user_id, livemode, amount, status)
+			VALUES ($1, $2, $3, $4, 'pending')
+			RETURNING id, wallet_id, amount, status`
+
+	topUp := &proto.WalletTopUpResponse{}
+	if err := db.QueryRowContext(ctx, SQL, wallet.Id, wallet.UserId, wallet.Livemode, amount).Scan(
+		&topUp.Id,
+		&topUp.WalletId,
+		&topUp.Amount,
+		&topUp.Status,
+	); err != nil {
+		return nil, err
+	}
+
+	return topUp, nil
+}
+
+func (u *WalletRepositoryImpl) UpdateTopUpGateway(ctx context.Context, topUp *proto.WalletTopUpResponse, gatewayOrderID string, db *sql.DB) error {
+	SQL := `UPDATE wallet_topups SET
+			gateway_order_id = $1,
+			gateway_token = $2,
+			gateway_redirect_url = $3,
+			expired_at = $4,
+			status = $5
+			WHERE id = $6`
+
+	var expiredAt interface{}
+	if topUp.ExpiredAt != "" {
+		t, err := time.Parse(time.RFC3339, topUp.ExpiredAt)
+		if err == nil {
+			expiredAt = t
+		}
+	}
+
+	_, err := db.ExecContext(ctx, SQL,
+		gatewayOrderID,
+		topUp.GatewayToken,
+		topUp.GatewayRedirectUrl,
+		expiredAt,
+		topUp.Status,
+		topUp.Id,
+	)
+
+	return err
+}
+
+// GetTopUpByGatewayOrderID returns the top-up and the mode it was made in
+func (u *WalletRepositoryImpl) GetTopUpByGatewayOrderID(ctx context.Context, gatewayOrderID string, db *sql.DB) (*proto.WalletTopUpResponse, bool, error) {
+	SQL := `SELECT id, wallet_id, amount, gateway_token, gateway_redirect_url, status, expired_at, livemode
+			FROM wallet_topups WHERE gateway_order_id = $1`
+
+	topUp := &proto.WalletTopUpResponse{}
+	var (
+		gatewayToken       sql.NullString
+		gatewayRedirectURL sql.NullString
+		expiredAt          sql.NullTime
+		livemode           bool
+	)
+
+	if err := db.QueryRowContext(ctx, SQL, gatewayOrderID).Scan(
+		&topUp.Id,
+		&topUp.WalletId,
+		&topUp.Amount,
+		&gatewayToken,
+		&gatewayRedirectURL,
+		&topUp.Status,
+		&expiredAt,
+		&livemode,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, errors.New("wallet top-up not found by gateway order ID")
+		}
+		return nil, false, err
+	}
+
+	topUp.GatewayToken = gatewayToken.String
+	topUp.GatewayRedirectUrl = gatewayRedirectURL.String
+	if expiredAt.Valid {
+		topUp.ExpiredAt = expiredAt.Time.Format(time.RFC3339)
+	}
+
+	return topUp, livemode, nil
+}
+
+// SettleTopUp moves a pending top-up to its final status and reports whether it changed,
+// so repeated webhooks never credit the wallet twice
+func (u *WalletRepositoryImpl) SettleTopUp(ctx context.Context, topUpID int, status string, transactionID string, tx *sql.Tx) (bool, error) {
+	loc := time.FixedZone("WIB", 7*60*60)
+	now := time.Now().In(loc)
+
+	SQL := `UPDATE wallet_topups SET status = $1, gateway_transaction_id = $2,
+			paid_at = CASE WHEN $1 = 'paid' THEN $3 ELSE paid_at END
+			WHERE id = $4 AND status = 'pending'`
+
+	result, err := tx.ExecContext(ctx, SQL, status, transactionID, now, topUpID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func scanWallet(row *sql.Row) (*proto.WalletResponse, error) {
+	wallet := &proto.WalletResponse{}
+	var currency sql.NullString
+
+	if err := row.Scan(
+		&wallet.Id,
+		&wallet.UserId,
+		&wallet.Balance,
+		&currency,
+		&wallet.Livemode,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("wallet not found")
+		}
+		return nil, err
+	}
+
+	wallet.Currency = currency.String
+	if wallet.Currency == "" {
+		wallet.Currency = "IDR"
+	}
+
+	return wallet, nil
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"payment/client"
+	"payment/proto"
+
+	"github.com/sirupsen/logrus"
+)
+
+// InitiateCardPayment charges an order to a card token. Saved cards are charged
+// one-click first and retried with 3DS when the issuer asks for it.
+func (u *PaymentService) InitiateCardPayment(req *proto.InitiateCardPaymentRequest) (*proto.InitiatePaymentResponse, error) {
+	logrus.Infof("Initiating card payment for order: %d", req.OrderId)
+
+	if (req.CardToken == "") == (req.SavedPaymentMethodId == 0) {
+		return nil, errors.New("either card_token or saved_payment_method_id is required")
+	}
+
+	payment, err := u.paymentRepo.GetByOrderID(u.ctx, int(req.OrderId), u.DB)
+	if err != nil {
+		return nil, fmt.Errorf("payment not found: %v", err)
+	}
+	if payment.Livemode != req.Livemode {
+		return nil, errors.New("payment not found")
+	}
+	if payment.Status != "pending" {
+		return nil, fmt.Errorf("payment is not awaiting payment (status: %s)", payment.Status)
+	}
+
+	if err := u.checkNoPendingIntent(payment); err != nil {
+		return nil, err
+	}
+
+	// Load the order for its lines
+	order, err := u.getUserOrder(req.OrderId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := u.userRepo.GetUserByID(u.ctx, int(req.UserId))
+	if err != nil {
+		return nil, fmt.Errorf("customer not found: %v", err)
+	}
+
+	charge := &client.CardCharge{
+		OrderID:      client.GenerateOrderID(payment.Id, payment.Livemode),
+		Amount:       int64(math.Round(payment.Amount - payment.WalletAmount)),
+		TokenID:      req.CardToken,
+		Authenticate: true,
+		SaveToken:    req.SaveCard,
+		Customer:     customerFromUser(user),
+		Items:        withWalletCredit(itemDetailsFromOrder(order), payment.WalletAmount),
+	}
+
+	var saved *proto.SavedPaymentMethod
+	if req.SavedPaymentMethodId != 0 {
+		var token string
+		saved, token, err = u.savedMethodRepo.GetByID(u.ctx, int(req.SavedPaymentMethodId), int(req.UserId), u.DB)
+		if err != nil {
+			return nil, err
+		}
+		// Sandbox tokens cannot be charged live and vice versa
+		if saved.Livemode != payment.Livemode {
+			return nil, errors.New("saved payment method not found")
+		}
+		charge.TokenID = token
+		charge.Authenticate = false
+		charge.SaveToken = false
+	}
+
+	cards := client.CardsFor(payment.Livemode)
+
+	result, err := cards.ChargeCard(charge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to charge card: %v", err)
+	}
+
+	// One-click charges fall back to 3DS under a fresh gateway order ID,
+	// since Midtrans does not accept a second charge on the same order ID
+	if saved != nil && result.RequiresAuthentication() {
+		logrus.Infof("Saved card charge for order %d needs 3DS, retrying with authentication", req.OrderId)
+		charge.OrderID += "-3DS"
+		charge.Authenticate = true
+		result, err = cards.ChargeCard(charge)
+		if err != nil {
+			return nil, fmt.Errorf("failed to charge card: %v", err)
+		}
+	}
+
+	status := client.MapTransactionStatus(result.TransactionStatus, result.FraudStatus)
+	if status == "failed" {
+		// The order stays payable so the customer can try another card
+		return nil, fmt.Errorf("card was declined (status: %s)", result.TransactionStatus)
+	}
+
+	payment.PaymentMethod = "credit_card"
+	payment.PaymentChannel = client.CardBrand(result.MaskedCard)
+	payment.GatewayOrderId = charge.OrderID
+	payment.GatewayToken = ""
+	payment.GatewayRedirectUrl = result.RedirectURL
+	payment.Status = "pending"
+
+	if err := u.paymentRepo.UpdatePaymentGateway(u.ctx, payment, u.DB); err != nil {
+		return nil, fmt.Errorf("failed to update payment: %v", err)
+	}
+
+	if status == "paid" {
+		if err := u.paymentRepo.UpdatePaymentStatus(u.ctx, int(payment.OrderId), status, result.TransactionID, u.DB); err != nil {
+			return nil, fmt.Errorf("failed to update payment status: %v", err)
+		}
+
+		logrus.Infof("Card payment captured, updating order status for order: %d", payment.OrderId)
+		if _, err := u.orderRepo.UpdateOrderStatus(u.ctx, &proto.UpdateOrderStatusRequest{
+			OrderId: payment.OrderId,
+			Status:  "paid",
+		}); err != nil {
+			logrus.Errorf("Failed to update order status: %v", err)
+			return nil, err
+		}
+	}
+
+	response := &proto.InitiatePaymentResponse{
+		PaymentId:          payment.Id,
+		GatewayRedirectUrl: result.RedirectURL,
+		Status:             status,
+		WalletAmount:       payment.WalletAmount,
+		GatewayAmount:      payment.Amount - payment.WalletAmount,
+	}
+
+	if saved != nil {
+		response.SavedPaymentMethodId = saved.Id
+	} else if req.SaveCard && result.SavedTokenID != "" {
+		method, err := u.saveCard(int(req.UserId), payment.Livemode, result)
+		if err != nil {
+			// The charge already went through; losing the saved card is not worth failing it
+			logrus.Errorf("Failed to save card for user %d: %v", req.UserId, err)
+		} else {
+			response.SavedPaymentMethodId = method.Id
+		}
+	}
+
+	return response, nil
+}
+
+// ListSavedPaymentMethods returns the user's saved cards, default first
+func (u *PaymentService) ListSavedPaymentMethods(req *proto.ListSavedPaymentMethodsRequest) (*proto.ListSavedPaymentMethodsResponse, error) {
+	methods, err := u.savedMethodRepo.GetByUserID(u.ctx, int(req.UserId), req.Livemode, u.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ListSavedPaymentMethodsResponse{Methods: methods}, nil
+}
+
+// DeleteSavedPaymentMethod removes a saved card, handing the default flag to the newest remaining card
+func (u *PaymentService) DeleteSavedPaymentMethod(req *proto.DeleteSavedPaymentMethodRequest) error {
+	logrus.Infof("Deleting saved payment method %d of user %d", req.Id, req.UserId)
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	wasDefault, livemode, err := u.savedMethodRepo.DeleteMethod(u.ctx, int(req.Id), int(req.UserId), tx)
+	if err != nil {
+		return err
+	}
+
+	if wasDefault {
+		if err := u.savedMethodRepo.PromoteDefault(u.ctx, int(req.UserId), livemode, tx); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rollback = false
+
+	return nil
+}
+
+func (u *PaymentService) saveCard(userID int, livemode bool, result *client.CardChargeResult) (*proto.SavedPaymentMethod, error) {
+	method := &proto.SavedPaymentMethod{
+		UserId:    int32(userID),
+		Livemode:  livemode,
+		MaskedPan: result.MaskedCard,
+		Brand:     client.CardBrand(result.MaskedCard),
+	}
+	if !result.SavedTokenExpiry.IsZero() {
+		method.ExpiryMonth = int32(result.SavedTokenExpiry.Month())
+		method.ExpiryYear = int32(result.SavedTokenExpiry.Year())
+	}
+
+	return u.savedMethodRepo.SaveMethod(u.ctx, method, result.SavedTokenID, u.DB)
+}
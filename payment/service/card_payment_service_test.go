@@ -0,0 +1,387 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"payment/client"
+	"payment/proto"
+	"payment/repository"
+	"strings"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// recordingCards charges through the local gateway and keeps every charge it was sent
+type recordingCards struct {
+	client.LocalCardGateway
+	charges []client.CardCharge
+}
+
+func (g *recordingCards) ChargeCard(charge *client.CardCharge) (*client.CardChargeResult, error) {
+	g.charges = append(g.charges, *charge)
+	return g.LocalCardGateway.ChargeCard(charge)
+}
+
+type fakePayments struct {
+	repository.PaymentRepository
+	payment *proto.PaymentResponse
+	gateway *proto.PaymentResponse // Last UpdatePaymentGateway
+	status  string                 // Last UpdatePaymentStatus
+}
+
+func (f *fakePayments) GetByOrderID(ctx context.Context, orderID int, db *sql.DB) (*proto.PaymentResponse, error) {
+	if f.payment == nil || int(f.payment.OrderId) != orderID {
+		return nil, sql.ErrNoRows
+	}
+	return f.payment, nil
+}
+
+func (f *fakePayments) UpdatePaymentGateway(ctx context.Context, payment *proto.PaymentResponse, db *sql.DB) error {
+	f.gateway = payment
+	return nil
+}
+
+func (f *fakePayments) UpdatePaymentStatus(ctx context.Context, orderID int, status string, transactionID string, db *sql.DB) error {
+	f.status = status
+	return nil
+}
+
+type fakeOrders struct {
+	repository.OrderRepository
+	owner  int32  // Owner of every order; the caller when zero
+	status string // Last UpdateOrderStatus
+}
+
+func (f *fakeOrders) GetOrderById(ctx context.Context, req *proto.GetOrderByIdRequest) (*proto.OrderResponse, error) {
+	owner := f.owner
+	if owner == 0 {
+		owner = req.UserId
+	}
+	return &proto.OrderResponse{Order: &proto.Order{
+		Id:     req.OrderId,
+		UserId: owner,
+		OrderItems: []*proto.OrderItem{
+			{ProductId: 1, ProductName: "Mug", Quantity: 2, Price: 50000},
+		},
+	}}, nil
+}
+
+func (f *fakeOrders) UpdateOrderStatus(ctx context.Context, req *proto.UpdateOrderStatusRequest) (*proto.EmptyOrder, error) {
+	f.status = req.Status
+	return &proto.EmptyOrder{}, nil
+}
+
+type fakeUsers struct{}
+
+func (fakeUsers) GetUserByID(ctx context.Context, userID int) (*proto.User, error) {
+	return &proto.User{ID: int32(userID), FullName: "Test Customer", Email: "customer@example.com"}, nil
+}
+
+type savedCard struct {
+	method *proto.SavedPaymentMethod
+	token  string
+}
+
+// fakeSavedMethods keeps saved cards in memory, scoped to their owner like the
+// real repository
+type fakeSavedMethods struct {
+	repository.SavedPaymentMethodRepository
+	cards map[int32]savedCard
+}
+
+func (f *fakeSavedMethods) SaveMethod(ctx context.Context, method *proto.SavedPaymentMethod, gatewayToken string, db *sql.DB) (*proto.SavedPaymentMethod, error) {
+	method.Id = int32(len(f.cards) + 100)
+	f.cards[method.Id] = savedCard{method: method, token: gatewayToken}
+	return method, nil
+}
+
+func (f *fakeSavedMethods) GetByID(ctx context.Context, id int, userID int, db *sql.DB) (*proto.SavedPaymentMethod, string, error) {
+	card, ok := f.cards[int32(id)]
+	if !ok || int(card.method.UserId) != userID {
+		return nil, "", errors.New("saved payment method not found")
+	}
+	return card.method, card.token, nil
+}
+
+// useTestCards routes test-mode card charges to a recording local gateway for the test
+func useTestCards(t *testing.T) *recordingCards {
+	t.Helper()
+
+	cards := &recordingCards{}
+	previous := client.TestCards
+	client.TestCards = cards
+	t.Cleanup(func() { client.TestCards = previous })
+	return cards
+}
+
+func TestInitiateCardPayment(t *testing.T) {
+	const (
+		owner = 7
+		other = 8
+	)
+
+	tests := []struct {
+		name        string
+		req         *proto.InitiateCardPaymentRequest
+		orderOwner  int32
+		wantErr     string
+		wantStatus  string
+		wantCharges []bool // Authenticate flag of each charge sent to the gateway
+		want3DSID   bool   // The last charge used a fresh "-3DS" gateway order ID
+		wantSaved   bool   // A new card was saved for the owner
+	}{
+		{
+			name:        "new card goes through 3DS",
+			req:         &proto.InitiateCardPaymentRequest{OrderId: 1, UserId: owner, CardToken: "tok-new"},
+			wantStatus:  "pending",
+			wantCharges: []bool{true},
+		},
+		{
+			name:        "new card is saved",
+			req:         &proto.InitiateCardPaymentRequest{OrderId: 1, UserId: owner, CardToken: "tok-new", SaveCard: true},
+			wantStatus:  "pending",
+			wantCharges: []bool{true},
+			wantSaved:   true,
+		},
+		{
+			name:        "one-click charge with a saved token",
+			req:         &proto.InitiateCardPaymentRequest{OrderId: 1, UserId: owner, SavedPaymentMethodId: 1},
+			wantStatus:  "paid",
+			wantCharges: []bool{false},
+		},
+		{
+			name:        "saved card the issuer wants 3DS for",
+			req:         &proto.InitiateCardPaymentRequest{OrderId: 1, UserId: owner, SavedPaymentMethodId: 2},
+			wantStatus:  "pending",
+			wantCharges: []bool{false, true},
+			want3DSID:   true,
+		},
+		{
+			name:        "declined new card",
+			req:         &proto.InitiateCardPaymentRequest{OrderId: 1, UserId: owner, CardToken: "tok-deny", SaveCard: true},
+			wantErr:     "card was declined",
+			wantCharges: []bool{true},
+		},
+		{
+			name:        "declined saved card",
+			req:         &proto.InitiateCardPaymentRequest{OrderId: 1, UserId: owner, SavedPaymentMethodId: 3},
+			wantErr:     "card was declined",
+			wantCharges: []bool{false, true},
+			want3DSID:   true,
+		},
+		{
+			name:       "another user's order",
+			req:        &proto.InitiateCardPaymentRequest{OrderId: 1, UserId: other, CardToken: "tok-new"},
+			orderOwner: owner,
+			wantErr:    "order not found",
+		},
+		{
+			name:    "another user's saved card",
+			req:     &proto.InitiateCardPaymentRequest{OrderId: 1, UserId: other, SavedPaymentMethodId: 1},
+			wantErr: "saved payment method not found",
+		},
+		{
+			name:    "live saved card on a test-mode payment",
+			req:     &proto.InitiateCardPaymentRequest{OrderId: 1, UserId: owner, SavedPaymentMethodId: 4},
+			wantErr: "saved payment method not found",
+		},
+		{
+			name:    "both a card token and a saved card",
+			req:     &proto.InitiateCardPaymentRequest{OrderId: 1, UserId: owner, CardToken: "tok-new", SavedPaymentMethodId: 1},
+			wantErr: "either card_token or saved_payment_method_id is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cards := useTestCards(t)
+			payments := &fakePayments{payment: &proto.PaymentResponse{
+				Id:       11,
+				OrderId:  1,
+				Amount:   100000,
+				Status:   "pending",
+				Livemode: false,
+			}}
+			orders := &fakeOrders{owner: tt.orderOwner}
+			saved := &fakeSavedMethods{cards: map[int32]savedCard{
+				1: {method: &proto.SavedPaymentMethod{Id: 1, UserId: owner}, token: "local-saved-one-click"},
+				2: {method: &proto.SavedPaymentMethod{Id: 2, UserId: owner}, token: "local-saved-3ds"},
+				3: {method: &proto.SavedPaymentMethod{Id: 3, UserId: owner}, token: "local-saved-deny"},
+				4: {method: &proto.SavedPaymentMethod{Id: 4, UserId: owner, Livemode: true}, token: "live-saved"},
+			}}
+			service := NewPaymentService(payments, nil, context.Background(), orders, fakeUsers{}, nil, saved, nil)
+
+			resp, err := service.InitiateCardPayment(tt.req)
+
+			if len(cards.charges) != len(tt.wantCharges) {
+				t.Fatalf("gateway got %d charges, want %d", len(cards.charges), len(tt.wantCharges))
+			}
+			for i, authenticate := range tt.wantCharges {
+				if cards.charges[i].Authenticate != authenticate {
+					t.Errorf("charge %d Authenticate = %t, want %t", i, cards.charges[i].Authenticate, authenticate)
+				}
+			}
+			if n := len(cards.charges); n > 1 {
+				first, last := cards.charges[0].OrderID, cards.charges[n-1].OrderID
+				if tt.want3DSID && last != first+"-3DS" {
+					t.Errorf("3DS retry used gateway order ID %q, want %q", last, first+"-3DS")
+				}
+			}
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("InitiateCardPayment() error = %v, want %q", err, tt.wantErr)
+				}
+				// The payment stays as it was so another card can be tried
+				if payments.gateway != nil || payments.status != "" || orders.status != "" {
+					t.Errorf("failed charge updated the payment: gateway %v, status %q, order %q", payments.gateway, payments.status, orders.status)
+				}
+				if len(saved.cards) != 4 {
+					t.Errorf("failed charge saved a card")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("InitiateCardPayment() error = %v", err)
+			}
+
+			if resp.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", resp.Status, tt.wantStatus)
+			}
+			if payments.gateway == nil {
+				t.Fatal("payment gateway details were not stored")
+			}
+			if payments.gateway.PaymentMethod != "credit_card" || payments.gateway.PaymentChannel != "visa" {
+				t.Errorf("payment method = %q/%q, want credit_card/visa", payments.gateway.PaymentMethod, payments.gateway.PaymentChannel)
+			}
+			if last := cards.charges[len(cards.charges)-1]; payments.gateway.GatewayOrderId != last.OrderID {
+				t.Errorf("GatewayOrderId = %q, want the charged order ID %q", payments.gateway.GatewayOrderId, last.OrderID)
+			}
+
+			wantOrderStatus := ""
+			if tt.wantStatus == "paid" {
+				wantOrderStatus = "paid"
+			}
+			if payments.status != wantOrderStatus || orders.status != wantOrderStatus {
+				t.Errorf("payment status %q and order status %q, want %q", payments.status, orders.status, wantOrderStatus)
+			}
+
+			if tt.req.SavedPaymentMethodId != 0 && resp.SavedPaymentMethodId != tt.req.SavedPaymentMethodId {
+				t.Errorf("SavedPaymentMethodId = %d, want the charged card %d", resp.SavedPaymentMethodId, tt.req.SavedPaymentMethodId)
+			}
+			if tt.wantSaved {
+				card, ok := saved.cards[resp.SavedPaymentMethodId]
+				if !ok {
+					t.Fatalf("SavedPaymentMethodId = %d, want a newly saved card", resp.SavedPaymentMethodId)
+				}
+				if card.method.UserId != owner || card.method.Livemode || !strings.HasPrefix(card.token, "local-saved-") {
+					t.Errorf("saved card = %+v with token %q, want a test-mode card of user %d", card.method, card.token, owner)
+				}
+			} else if len(saved.cards) != 4 {
+				t.Errorf("charge saved a card without save_card")
+			}
+		})
+	}
+}
+
+// testDB connects to TEST_DATABASE_URL, a migrated database the test may write to,
+// and skips the test when it is not set
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	DB, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := DB.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { DB.Close() })
+	return DB
+}
+
+func TestSavedCardOwnership(t *testing.T) {
+	DB := testDB(t)
+	ctx := context.Background()
+
+	const (
+		owner = 2000000001
+		other = 2000000002
+	)
+	cleanup := func() {
+		if _, err := DB.Exec(`DELETE FROM saved_payment_methods WHERE user_id IN ($1, $2)`, owner, other); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	cards := useTestCards(t)
+	payments := &fakePayments{payment: &proto.PaymentResponse{Id: 11, OrderId: 1, Amount: 100000, Status: "pending"}}
+	savedMethods := repository.NewSavedPaymentMethodRepository()
+	service := NewPaymentService(payments, DB, ctx, &fakeOrders{}, fakeUsers{}, nil, savedMethods, nil)
+
+	// Saving stores the card for the user who paid with it, as their default
+	resp, err := service.InitiateCardPayment(&proto.InitiateCardPaymentRequest{OrderId: 1, UserId: owner, CardToken: "tok-first", SaveCard: true})
+	if err != nil {
+		t.Fatalf("InitiateCardPayment() error = %v", err)
+	}
+	first := resp.SavedPaymentMethodId
+	if first == 0 {
+		t.Fatal("card was not saved")
+	}
+	resp, err = service.InitiateCardPayment(&proto.InitiateCardPaymentRequest{OrderId: 1, UserId: owner, CardToken: "tok-second", SaveCard: true})
+	if err != nil {
+		t.Fatalf("InitiateCardPayment() error = %v", err)
+	}
+	second := resp.SavedPaymentMethodId
+
+	list := func(userID int32) []*proto.SavedPaymentMethod {
+		t.Helper()
+		methods, err := service.ListSavedPaymentMethods(&proto.ListSavedPaymentMethodsRequest{UserId: userID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return methods.Methods
+	}
+	if methods := list(owner); len(methods) != 2 || methods[0].Id != first || !methods[0].IsDefault {
+		t.Fatalf("owner's cards = %v, want the first card %d as default of two", methods, first)
+	}
+	if methods := list(other); len(methods) != 0 {
+		t.Fatalf("other user's cards = %v, want none", methods)
+	}
+
+	// Another user can neither charge nor delete the owner's card
+	charges := len(cards.charges)
+	if _, err := service.InitiateCardPayment(&proto.InitiateCardPaymentRequest{OrderId: 1, UserId: other, SavedPaymentMethodId: first}); err == nil {
+		t.Error("another user charged the owner's saved card")
+	}
+	if len(cards.charges) != charges {
+		t.Error("another user's saved card reached the gateway")
+	}
+	if err := service.DeleteSavedPaymentMethod(&proto.DeleteSavedPaymentMethodRequest{Id: first, UserId: other}); err == nil {
+		t.Error("another user deleted the owner's saved card")
+	}
+	if methods := list(owner); len(methods) != 2 {
+		t.Fatalf("owner's cards after another user's delete = %v, want both", methods)
+	}
+
+	// The owner deleting the default hands the flag to the remaining card
+	if err := service.DeleteSavedPaymentMethod(&proto.DeleteSavedPaymentMethodRequest{Id: first, UserId: owner}); err != nil {
+		t.Fatalf("DeleteSavedPaymentMethod() error = %v", err)
+	}
+	if methods := list(owner); len(methods) != 1 || methods[0].Id != second || !methods[0].IsDefault {
+		t.Fatalf("owner's cards after delete = %v, want card %d as default", methods, second)
+	}
+	if err := service.DeleteSavedPaymentMethod(&proto.DeleteSavedPaymentMethodRequest{Id: first, UserId: owner}); err == nil {
+		t.Error("deleting a card twice succeeded")
+	}
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"payment/client"
+	"payment/proto"
+	"time"
+
+	"github.com/midtrans/midtrans-go"
+	"github.com/sirupsen/logrus"
+)
+
+// CreatePaymentIntent groups several pending orders of one user under a single Snap transaction
+func (u *PaymentService) CreatePaymentIntent(req *proto.CreatePaymentIntentRequest) (*proto.PaymentIntentResponse, error) {
+	orderIDs := uniqueOrderIDs(req.OrderIds)
+	if len(orderIDs) == 0 {
+		return nil, errors.New("at least one order is required")
+	}
+	logrus.Infof("Creating payment intent for user %d with orders: %v", req.UserId, orderIDs)
+
+	var (
+		amount     float64
+		paymentIDs []int32
+		items      []midtrans.ItemDetails
+	)
+
+	for _, orderID := range orderIDs {
+		order, err := u.getUserOrder(orderID, req.UserId)
+		if err != nil {
+			return nil, fmt.Errorf("order %d: %v", orderID, err)
+		}
+
+		payment, err := u.paymentRepo.GetByOrderID(u.ctx, int(orderID), u.DB)
+		if err != nil {
+			return nil, fmt.Errorf("payment for order %d not found: %v", orderID, err)
+		}
+		if payment.Livemode != req.Livemode {
+			return nil, fmt.Errorf("payment for order %d not found", orderID)
+		}
+		if payment.Status != "pending" {
+			return nil, fmt.Errorf("order %d is not awaiting payment (status: %s)", orderID, payment.Status)
+		}
+		if payment.WalletAmount > 0 {
+			return nil, fmt.Errorf("order %d is already partly paid from the wallet", orderID)
+		}
+		if payment.GatewayToken != "" {
+			return nil, fmt.Errorf("order %d already has a checkout of its own", orderID)
+		}
+
+		if payment.PaymentIntentId != 0 {
+			existing, err := u.intentRepo.GetByID(u.ctx, int(payment.PaymentIntentId), u.DB)
+			if err != nil {
+				return nil, err
+			}
+			if existing.Status == "pending" || existing.Status == "paid" {
+				return nil, fmt.Errorf("order %d is already part of payment intent %d", orderID, existing.Id)
+			}
+		}
+
+		amount += payment.Amount
+		paymentIDs = append(paymentIDs, payment.Id)
+
+		// Item IDs are prefixed with the order so lines from different orders stay distinct
+		for _, item := range itemDetailsFromOrder(order) {
+			item.ID = fmt.Sprintf("%d-%s", orderID, item.ID)
+			items = append(items, item)
+		}
+	}
+
+	user, err := u.userRepo.GetUserByID(u.ctx, int(req.UserId))
+	if err != nil {
+		return nil, fmt.Errorf("customer not found: %v", err)
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	intent, err := u.intentRepo.CreatePaymentIntent(u.ctx, int(req.UserId), amount, req.Livemode, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment intent: %v", err)
+	}
+
+	// The checks above ran outside this transaction; attaching only takes payments
+	// that still pass them
+	if err := u.intentRepo.AttachPayments(u.ctx, int(intent.Id), paymentIDs, tx); err != nil {
+		return nil, fmt.Errorf("failed to attach payments: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	gatewayOrderID := client.GenerateIntentOrderID(intent.Id, intent.Livemode)
+
+	snapResp, err := client.CreateSnapTransaction(
+		intent.Livemode,
+		gatewayOrderID,
+		int64(math.Round(amount)),
+		customerFromUser(user),
+		items,
+	)
+	if err != nil {
+		if uErr := u.intentRepo.UpdateIntentStatus(u.ctx, int(intent.Id), "failed", "", u.DB); uErr != nil {
+			logrus.Errorf("Failed to mark payment intent %d as failed: %v", intent.Id, uErr)
+		}
+		return nil, fmt.Errorf("failed to create snap transaction: %v", err)
+	}
+
+	intent.PaymentMethod = req.PaymentMethod
+	intent.PaymentChannel = req.PaymentChannel
+	intent.GatewayOrderId = gatewayOrderID
+	intent.GatewayToken = snapResp.Token
+	intent.GatewayRedirectUrl = snapResp.RedirectURL
+	intent.ExpiredAt = time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+	intent.Status = "pending"
+
+	if err := u.intentRepo.UpdateIntentGateway(u.ctx, intent, u.DB); err != nil {
+		return nil, fmt.Errorf("failed to update payment intent: %v", err)
+	}
+
+	intent.Payments, err = u.intentRepo.GetPayments(u.ctx, int(intent.Id), u.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Payment intent %d created for %d orders, amount: %f", intent.Id, len(paymentIDs), amount)
+	return intent, nil
+}
+
+// GetPaymentIntent retrieves a payment intent and its grouped payments
+func (u *PaymentService) GetPaymentIntent(req *proto.GetPaymentIntentRequest) (*proto.PaymentIntentResponse, error) {
+	intent, err := u.intentRepo.GetByID(u.ctx, int(req.IntentId), u.DB)
+	if err != nil {
+		return nil, err
+	}
+	if req.UserId != 0 && intent.UserId != req.UserId {
+		return nil, errors.New("payment intent not found")
+	}
+
+	intent.Payments, err = u.intentRepo.GetPayments(u.ctx, int(intent.Id), u.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	return intent, nil
+}
+
+// RefundPaymentIntent refunds part of a settled intent and allocates it to one of its orders.
+// Refunds issued as store credit go to the customer's wallet instead of back through the gateway.
+func (u *PaymentService) RefundPaymentIntent(req *proto.RefundPaymentIntentRequest) (*proto.RefundResponse, error) {
+	logrus.Infof("Refunding %f of payment intent %d to order %d", req.Amount, req.IntentId, req.OrderId)
+
+	return u.refundOrder(&proto.RefundPaymentRequest{
+		OrderId:       req.OrderId,
+		Amount:        req.Amount,
+		Reason:        req.Reason,
+		AsStoreCredit: req.AsStoreCredit,
+		Livemode:      req.Livemode,
+		Items:         req.Items,
+	}, req.IntentId)
+}
+
+// handleIntentWebhook applies a gateway status change to the intent and every grouped order
+func (u *PaymentService) handleIntentWebhook(req *proto.WebhookRequest, status string, livemode bool) error {
+	intent, err := u.intentRepo.GetByGatewayOrderID(u.ctx, req.OrderId, u.DB)
+	if err != nil {
+		return err
+	}
+
+	if intent.Livemode != livemode {
+		return fmt.Errorf("webhook mode does not match payment intent for order ID: %s", req.OrderId)
+	}
+
+	// Refunds are recorded when requested, so refund notifications need no further work
+	if status == "refunded" {
+		logrus.Infof("Refund notification for payment intent %d acknowledged", intent.Id)
+		return nil
+	}
+
+	if err := u.intentRepo.UpdateIntentStatus(u.ctx, int(intent.Id), status, req.TransactionId, u.DB); err != nil {
+		return fmt.Errorf("failed to update payment intent status: %v", err)
+	}
+
+	if status != "paid" && status != "failed" {
+		return nil
+	}
+
+	payments, err := u.intentRepo.GetPayments(u.ctx, int(intent.Id), u.DB)
+	if err != nil {
+		return err
+	}
+
+	for _, payment := range payments {
+		if err := u.paymentRepo.UpdatePaymentStatus(u.ctx, int(payment.OrderId), status, req.TransactionId, u.DB); err != nil {
+			return fmt.Errorf("failed to update payment status for order %d: %v", payment.OrderId, err)
+		}
+
+		logrus.Infof("Payment intent %d %s, updating order status for order: %d", intent.Id, status, payment.OrderId)
+		if _, err := u.orderRepo.UpdateOrderStatus(u.ctx, &proto.UpdateOrderStatusRequest{
+			OrderId: payment.OrderId,
+			Status:  status,
+		}); err != nil {
+			logrus.Errorf("Failed to update order status: %v", err)
+			if status == "paid" {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func uniqueOrderIDs(orderIDs []int32) []int32 {
+	seen := make(map[int32]bool, len(orderIDs))
+	unique := make([]int32, 0, len(orderIDs))
+	for _, id := range orderIDs {
+		if id <= 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"payment/client"
+	"payment/proto"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RefundPayment refunds part of what was paid for an order, through the intent or
+// gateway transaction that paid it, or as store credit
+func (u *PaymentService) RefundPayment(req *proto.RefundPaymentRequest) (*proto.RefundResponse, error) {
+	logrus.Infof("Refunding %f of order %d", req.Amount, req.OrderId)
+	return u.refundOrder(req, 0)
+}
+
+// refundOrder refunds part of what was paid for an order. intentID, when set, is the
+// intent the order must have been paid through. Returned items, bundles and bundle
+// components alike, are priced by the order service before anything is refunded and
+// only put back in stock once the refund is recorded.
+//
+// A gateway refund is committed as pending before the gateway is called and completed
+// after, so concurrent refunds count it against what is left and a refund the gateway
+// took is never lost with a rolled back transaction.
+func (u *PaymentService) refundOrder(req *proto.RefundPaymentRequest, intentID int32) (*proto.RefundResponse, error) {
+	var returnItems []*proto.ReturnItem
+	if len(req.Items) > 0 {
+		for _, item := range req.Items {
+			returnItems = append(returnItems, &proto.ReturnItem{OrderItemId: item.OrderItemId, Quantity: item.Quantity})
+		}
+		worth, err := u.orderRepo.ReturnOrderItems(u.ctx, &proto.ReturnOrderItemsRequest{
+			OrderId: req.OrderId,
+			Items:   returnItems,
+			Reason:  req.Reason,
+			DryRun:  true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to return items: %v", err)
+		}
+		if req.Amount == 0 {
+			req.Amount = worth.RefundAmount
+		}
+		if req.Amount > worth.RefundAmount {
+			return nil, fmt.Errorf("refund amount exceeds the %f paid for the returned items", worth.RefundAmount)
+		}
+	}
+
+	if req.Amount <= 0 {
+		return nil, errors.New("refund amount must be positive")
+	}
+
+	payment, err := u.paymentRepo.GetByOrderID(u.ctx, int(req.OrderId), u.DB)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Livemode != req.Livemode {
+		return nil, errors.New("payment not found")
+	}
+	if intentID != 0 && payment.PaymentIntentId != intentID {
+		return nil, fmt.Errorf("order %d is not part of payment intent %d", req.OrderId, intentID)
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	// The intent and payment stay locked until the refund is recorded, so concurrent
+	// refunds are checked against what the one before them left
+	var intent *proto.PaymentIntentResponse
+	if payment.PaymentIntentId != 0 {
+		intent, err = u.intentRepo.LockIntent(u.ctx, int(payment.PaymentIntentId), tx)
+		if err != nil {
+			return nil, err
+		}
+		if intent.Status != "paid" && intent.Status != "partially_refunded" {
+			if intentID != 0 {
+				return nil, fmt.Errorf("payment intent is not refundable (status: %s)", intent.Status)
+			}
+			// The intent's checkout was abandoned and the order paid on its own
+			intent = nil
+		}
+	}
+	if intent == nil && payment.Status != "paid" && payment.Status != "partially_refunded" {
+		return nil, fmt.Errorf("payment is not refundable (status: %s)", payment.Status)
+	}
+
+	remaining, err := u.paymentRepo.LockRefundable(u.ctx, int(payment.Id), tx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Amount > remaining {
+		return nil, fmt.Errorf("refund amount exceeds the remaining %f paid for order %d", remaining, req.OrderId)
+	}
+
+	gatewayOrderID, userID := payment.GatewayOrderId, payment.UserId
+	refund := &proto.RefundResponse{
+		PaymentId: payment.Id,
+		OrderId:   req.OrderId,
+		Amount:    req.Amount,
+		Reason:    req.Reason,
+		Status:    "pending",
+	}
+	if intent != nil {
+		gatewayOrderID, userID = intent.GatewayOrderId, intent.UserId
+		refund.PaymentIntentId = intent.Id
+	}
+	refund.RefundKey = client.GenerateRefundKey(refund.PaymentIntentId, req.OrderId)
+
+	if req.AsStoreCredit {
+		if userID == 0 {
+			return nil, fmt.Errorf("customer of order %d is unknown, refund it through the gateway", req.OrderId)
+		}
+		refund.Status = "credited"
+	} else {
+		// Only what went through the gateway can go back through it; the wallet
+		// portion of a payment is refunded as store credit
+		refunded, err := u.paymentRepo.GatewayRefunded(u.ctx, int(payment.Id), tx)
+		if err != nil {
+			return nil, err
+		}
+		if left := payment.Amount - payment.WalletAmount - refunded; gatewayOrderID == "" || req.Amount > left {
+			return nil, fmt.Errorf("only %f of order %d can be refunded through the gateway, refund the rest as store credit", math.Max(left, 0), req.OrderId)
+		}
+	}
+
+	refund, err = u.intentRepo.CreateRefund(u.ctx, refund, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var paymentStatus string
+	if req.AsStoreCredit {
+		wallet, err := u.walletRepo.LockWallet(u.ctx, int(userID), payment.Livemode, tx)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := u.walletRepo.ApplyTransaction(u.ctx, &proto.WalletTransaction{
+			WalletId:      wallet.Id,
+			Type:          "refund_credit",
+			Amount:        req.Amount,
+			ReferenceType: "payment_refund",
+			ReferenceId:   refund.Id,
+		}, payment.Livemode, tx); err != nil {
+			return nil, fmt.Errorf("failed to credit wallet: %v", err)
+		}
+
+		paymentStatus, err = u.addRefund(refund, tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	if !req.AsStoreCredit {
+		if _, err := client.RefundTransaction(payment.Livemode, gatewayOrderID, refund.RefundKey, int64(math.Round(req.Amount)), req.Reason); err != nil {
+			if fErr := u.intentRepo.FailRefund(u.ctx, int(refund.Id), u.DB); fErr != nil {
+				logrus.Errorf("Failed to mark refund %d as failed: %v", refund.Id, fErr)
+			}
+			return nil, fmt.Errorf("failed to refund transaction: %v", err)
+		}
+
+		paymentStatus, err = u.completeRefund(refund)
+		if err != nil {
+			// The refund stays pending, so its amount is not refunded a second time
+			logrus.Errorf("Refund %d of order %d went through the gateway but was not recorded: %v", refund.Id, req.OrderId, err)
+			return nil, fmt.Errorf("refund %d went through the gateway but could not be recorded: %v", refund.Id, err)
+		}
+		refund.Status = "refunded"
+	}
+
+	if len(returnItems) > 0 {
+		if _, err := u.orderRepo.ReturnOrderItems(u.ctx, &proto.ReturnOrderItemsRequest{
+			OrderId: req.OrderId,
+			Items:   returnItems,
+			Reason:  req.Reason,
+		}); err != nil {
+			logrus.Errorf("Failed to return items of order %d after refund %d: %v", req.OrderId, refund.Id, err)
+		}
+	}
+
+	if paymentStatus == "refunded" {
+		logrus.Infof("Order %d fully refunded, updating order status", req.OrderId)
+		if _, err := u.orderRepo.UpdateOrderStatus(u.ctx, &proto.UpdateOrderStatusRequest{
+			OrderId: req.OrderId,
+			Status:  "refunded",
+		}); err != nil {
+			logrus.Errorf("Failed to update order status: %v", err)
+		}
+	}
+
+	return refund, nil
+}
+
+// completeRefund records a pending refund the gateway has taken
+func (u *PaymentService) completeRefund(refund *proto.RefundResponse) (string, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return "", err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	if err := u.intentRepo.CompleteRefund(u.ctx, int(refund.Id), tx); err != nil {
+		return "", err
+	}
+
+	paymentStatus, err := u.addRefund(refund, tx)
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	rollback = false
+
+	return paymentStatus, nil
+}
+
+// addRefund adds a refund to the refunded amounts of its payment and intent and
+// returns the resulting payment status
+func (u *PaymentService) addRefund(refund *proto.RefundResponse, tx *sql.Tx) (string, error) {
+	paymentStatus, err := u.paymentRepo.AddRefund(u.ctx, int(refund.PaymentId), refund.Amount, tx)
+	if err != nil {
+		return "", err
+	}
+
+	if refund.PaymentIntentId != 0 {
+		if err := u.intentRepo.AddRefund(u.ctx, int(refund.PaymentIntentId), refund.Amount, tx); err != nil {
+			return "", err
+		}
+	}
+
+	return paymentStatus, nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"payment/proto"
+	"strings"
+	"testing"
+)
+
+// pricedReturns prices every return at worth, like the order service's dry run, and
+// keeps the returns that were not dry runs
+type pricedReturns struct {
+	fakeOrders
+	worth    float64
+	returned []*proto.ReturnOrderItemsRequest
+}
+
+func (f *pricedReturns) ReturnOrderItems(ctx context.Context, req *proto.ReturnOrderItemsRequest) (*proto.ReturnOrderItemsResponse, error) {
+	if !req.DryRun {
+		f.returned = append(f.returned, req)
+	}
+	return &proto.ReturnOrderItemsResponse{RefundAmount: f.worth}, nil
+}
+
+// TestRefundOrderChecksBeforeLocking covers the refunds turned down before a
+// transaction is started, so none of them needs a database
+func TestRefundOrderChecksBeforeLocking(t *testing.T) {
+	payments := &fakePayments{payment: &proto.PaymentResponse{Id: 3, OrderId: 9, Amount: 100000, Status: "paid", PaymentIntentId: 4}}
+	orders := &pricedReturns{worth: 30000}
+	svc := NewPaymentService(payments, nil, context.Background(), orders, fakeUsers{}, nil, nil, nil)
+	items := []*proto.RefundItem{{OrderItemId: 21, Quantity: 1}}
+
+	tests := []struct {
+		name     string
+		req      *proto.RefundPaymentRequest
+		intentID int32
+		wantErr  string
+	}{
+		{"no amount", &proto.RefundPaymentRequest{OrderId: 9}, 0, "must be positive"},
+		{"negative amount", &proto.RefundPaymentRequest{OrderId: 9, Amount: -5}, 0, "must be positive"},
+		{"unknown order", &proto.RefundPaymentRequest{OrderId: 10, Amount: 5}, 0, "no rows"},
+		{"other mode", &proto.RefundPaymentRequest{OrderId: 9, Amount: 5, Livemode: true}, 0, "payment not found"},
+		{"order of another intent", &proto.RefundPaymentRequest{OrderId: 9, Amount: 5}, 5, "not part of payment intent 5"},
+		{"more than the returned items were paid", &proto.RefundPaymentRequest{OrderId: 9, Amount: 30001, Items: items}, 0, "exceeds the 30000.000000 paid"},
+		{"returned items priced, then checked like any refund", &proto.RefundPaymentRequest{OrderId: 9, Items: items, Livemode: true}, 0, "payment not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.refundOrder(tt.req, tt.intentID)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("refundOrder() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Nothing is returned unless the refund is recorded
+	if len(orders.returned) > 0 {
+		t.Errorf("%d returns made for refunds that were turned down", len(orders.returned))
+	}
+}
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"payment/client"
+	"payment/proto"
+	"time"
+
+	"github.com/midtrans/midtrans-go"
+	"github.com/sirupsen/logrus"
+)
+
+// Number of wallet transactions returned with the balance
+const walletHistoryLimit = 50
+
+// GetWallet returns the user's wallet, creating an empty one on first use
+func (u *PaymentService) GetWallet(req *proto.GetWalletRequest) (*proto.WalletResponse, error) {
+	wallet, err := u.walletRepo.GetOrCreateWallet(u.ctx, int(req.UserId), req.Livemode, u.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet.Transactions, err = u.walletRepo.GetTransactions(u.ctx, int(wallet.Id), walletHistoryLimit, u.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+// TopUpWallet creates a Snap transaction for a top-up; the wallet is credited by the webhook
+func (u *PaymentService) TopUpWallet(req *proto.TopUpWalletRequest) (*proto.WalletTopUpResponse, error) {
+	logrus.Infof("Topping up wallet of user %d, amount: %f", req.UserId, req.Amount)
+
+	if req.Amount <= 0 {
+		return nil, errors.New("top-up amount must be positive")
+	}
+
+	user, err := u.userRepo.GetUserByID(u.ctx, int(req.UserId))
+	if err != nil {
+		return nil, fmt.Errorf("customer not found: %v", err)
+	}
+
+	wallet, err := u.walletRepo.GetOrCreateWallet(u.ctx, int(req.UserId), req.Livemode, u.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	topUp, err := u.walletRepo.CreateTopUp(u.ctx, wallet, req.Amount, u.DB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create top-up: %v", err)
+	}
+
+	gatewayOrderID := client.GenerateTopUpOrderID(topUp.Id, req.Livemode)
+	amount := int64(math.Round(req.Amount))
+
+	snapResp, err := client.CreateSnapTransaction(
+		req.Livemode,
+		gatewayOrderID,
+		amount,
+		customerFromUser(user),
+		[]midtrans.ItemDetails{{ID: "TOPUP", Name: "Wallet top-up", Price: amount, Qty: 1}},
+	)
+	if err != nil {
+		topUp.Status = "failed"
+		if uErr := u.walletRepo.UpdateTopUpGateway(u.ctx, topUp, gatewayOrderID, u.DB); uErr != nil {
+			logrus.Errorf("Failed to mark top-up %d as failed: %v", topUp.Id, uErr)
+		}
+		return nil, fmt.Errorf("failed to create snap transaction: %v", err)
+	}
+
+	topUp.GatewayToken = snapResp.Token
+	topUp.GatewayRedirectUrl = snapResp.RedirectURL
+	topUp.ExpiredAt = time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+	topUp.Status = "pending"
+
+	if err := u.walletRepo.UpdateTopUpGateway(u.ctx, topUp, gatewayOrderID, u.DB); err != nil {
+		return nil, fmt.Errorf("failed to update top-up: %v", err)
+	}
+
+	return topUp, nil
+}
+
+// initiateWalletPayment pays an order from the wallet. When the balance covers the
+// whole amount the payment settles immediately; otherwise the balance is used up and
+// the rest is paid through a Snap transaction.
+func (u *PaymentService) initiateWalletPayment(req *proto.InitiatePaymentRequest, payment *proto.PaymentResponse, order *proto.Order) (*proto.InitiatePaymentResponse, error) {
+	if payment.Status != "pending" {
+		return nil, fmt.Errorf("payment is not awaiting payment (status: %s)", payment.Status)
+	}
+
+	// A wallet portion left from an earlier attempt is reused rather than debited again
+	walletAmount := payment.WalletAmount
+	if walletAmount == 0 {
+		debited, err := u.debitWallet(payment, int(req.UserId))
+		if err != nil {
+			return nil, err
+		}
+		walletAmount = debited
+	}
+
+	payment.PaymentMethod = "wallet"
+	payment.PaymentChannel = ""
+	payment.WalletAmount = walletAmount
+	gatewayAmount := payment.Amount - walletAmount
+
+	if gatewayAmount <= 0 {
+		return u.settleWalletPayment(payment)
+	}
+
+	logrus.Infof("Wallet covers %f of payment %d, sending the remaining %f to the gateway", walletAmount, payment.Id, gatewayAmount)
+
+	user, err := u.userRepo.GetUserByID(u.ctx, int(req.UserId))
+	if err != nil {
+		u.releaseWalletPortion(payment)
+		return nil, fmt.Errorf("customer not found: %v", err)
+	}
+
+	gatewayOrderID := client.GenerateOrderID(payment.Id, payment.Livemode)
+
+	snapResp, err := client.CreateSnapTransaction(
+		payment.Livemode,
+		gatewayOrderID,
+		int64(math.Round(gatewayAmount)),
+		customerFromUser(user),
+		withWalletCredit(itemDetailsFromOrder(order), walletAmount),
+	)
+	if err != nil {
+		u.releaseWalletPortion(payment)
+		return nil, fmt.Errorf("failed to create snap transaction: %v", err)
+	}
+
+	expiredAt := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+
+	payment.PaymentChannel = req.PaymentChannel
+	payment.GatewayOrderId = gatewayOrderID
+	payment.GatewayToken = snapResp.Token
+	payment.GatewayRedirectUrl = snapResp.RedirectURL
+	payment.ExpiredAt = expiredAt
+	payment.Status = "pending"
+
+	if err := u.paymentRepo.UpdatePaymentGateway(u.ctx, payment, u.DB); err != nil {
+		return nil, fmt.Errorf("failed to update payment: %v", err)
+	}
+
+	return &proto.InitiatePaymentResponse{
+		PaymentId:          payment.Id,
+		GatewayToken:       snapResp.Token,
+		GatewayRedirectUrl: snapResp.RedirectURL,
+		ExpiredAt:          expiredAt,
+		Status:             "pending",
+		WalletAmount:       walletAmount,
+		GatewayAmount:      gatewayAmount,
+	}, nil
+}
+
+// debitWallet takes as much of the payment as the balance allows and records it on the payment
+func (u *PaymentService) debitWallet(payment *proto.PaymentResponse, userID int) (float64, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	wallet, err := u.walletRepo.LockWallet(u.ctx, userID, payment.Livemode, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	amount := math.Min(wallet.Balance, payment.Amount)
+	if amount <= 0 {
+		return 0, errors.New("wallet balance is empty")
+	}
+
+	if _, err := u.walletRepo.ApplyTransaction(u.ctx, &proto.WalletTransaction{
+		WalletId:      wallet.Id,
+		Type:          "payment",
+		Amount:        -amount,
+		ReferenceType: "payment",
+		ReferenceId:   payment.Id,
+	}, payment.Livemode, tx); err != nil {
+		return 0, err
+	}
+
+	if err := u.paymentRepo.SetWalletAmount(u.ctx, int(payment.Id), amount, tx); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	rollback = false
+
+	logrus.Infof("Debited %f from wallet %d for payment %d", amount, wallet.Id, payment.Id)
+	return amount, nil
+}
+
+// settleWalletPayment marks a payment covered entirely by the wallet as paid, without the gateway
+func (u *PaymentService) settleWalletPayment(payment *proto.PaymentResponse) (*proto.InitiatePaymentResponse, error) {
+	payment.GatewayOrderId = ""
+	payment.GatewayToken = ""
+	payment.GatewayRedirectUrl = ""
+	payment.ExpiredAt = ""
+
+	if err := u.paymentRepo.UpdatePaymentGateway(u.ctx, payment, u.DB); err != nil {
+		return nil, fmt.Errorf("failed to update payment: %v", err)
+	}
+
+	transactionID := fmt.Sprintf("WALLET-%d", payment.Id)
+	if err := u.paymentRepo.UpdatePaymentStatus(u.ctx, int(payment.OrderId), "paid", transactionID, u.DB); err != nil {
+		return nil, fmt.Errorf("failed to update payment status: %v", err)
+	}
+
+	logrus.Infof("Payment %d settled from the wallet, updating order status for order: %d", payment.Id, payment.OrderId)
+	if _, err := u.orderRepo.UpdateOrderStatus(u.ctx, &proto.UpdateOrderStatusRequest{
+		OrderId: payment.OrderId,
+		Status:  "paid",
+	}); err != nil {
+		logrus.Errorf("Failed to update order status: %v", err)
+		return nil, err
+	}
+
+	return &proto.InitiatePaymentResponse{
+		PaymentId:    payment.Id,
+		Status:       "paid",
+		WalletAmount: payment.WalletAmount,
+	}, nil
+}
+
+// releaseWalletPortion credits the wallet portion of a payment back once the gateway part cannot complete
+func (u *PaymentService) releaseWalletPortion(payment *proto.PaymentResponse) {
+	if err := u.creditWalletPortion(payment); err != nil {
+		logrus.Errorf("Failed to release wallet portion of payment %d: %v", payment.Id, err)
+	}
+}
+
+func (u *PaymentService) creditWalletPortion(payment *proto.PaymentResponse) error {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	amount, err := u.paymentRepo.ReleaseWalletAmount(u.ctx, int(payment.Id), tx)
+	if err != nil {
+		return err
+	}
+	if amount == 0 {
+		return nil
+	}
+
+	// The credit goes back to the wallet the debit came from
+	walletID, err := u.walletRepo.GetPaymentWalletID(u.ctx, int(payment.Id), tx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := u.walletRepo.ApplyTransaction(u.ctx, &proto.WalletTransaction{
+		WalletId:      int32(walletID),
+		Type:          "payment_reversal",
+		Amount:        amount,
+		ReferenceType: "payment",
+		ReferenceId:   payment.Id,
+	}, payment.Livemode, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rollback = false
+
+	payment.WalletAmount = 0
+	logrus.Infof("Released %f back to wallet %d from payment %d", amount, walletID, payment.Id)
+	return nil
+}
+
+// handleTopUpWebhook credits the wallet once a top-up is paid; repeated notifications are ignored
+func (u *PaymentService) handleTopUpWebhook(req *proto.WebhookRequest, status string, livemode bool) error {
+	topUp, topUpLivemode, err := u.walletRepo.GetTopUpByGatewayOrderID(u.ctx, req.OrderId, u.DB)
+	if err != nil {
+		return err
+	}
+
+	if topUpLivemode != livemode {
+		return fmt.Errorf("webhook mode does not match top-up for order ID: %s", req.OrderId)
+	}
+
+	if status != "paid" && status != "failed" {
+		return nil
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	changed, err := u.walletRepo.SettleTopUp(u.ctx, int(topUp.Id), status, req.TransactionId, tx)
+	if err != nil {
+		return fmt.Errorf("failed to update top-up status: %v", err)
+	}
+
+	if changed && status == "paid" {
+		if _, err := u.walletRepo.ApplyTransaction(u.ctx, &proto.WalletTransaction{
+			WalletId:      topUp.WalletId,
+			Type:          "topup",
+			Amount:        topUp.Amount,
+			ReferenceType: "wallet_topup",
+			ReferenceId:   topUp.Id,
+		}, livemode, tx); err != nil {
+			return fmt.Errorf("failed to credit wallet: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rollback = false
+
+	if changed {
+		logrus.Infof("Top-up %d %s", topUp.Id, status)
+	}
+	return nil
+}
+
+// withWalletCredit adds a negative line for the wallet portion so the items add up to the gateway amount
+func withWalletCredit(items []midtrans.ItemDetails, walletAmount float64) []midtrans.ItemDetails {
+	if walletAmount <= 0 || len(items) == 0 {
+		return items
+	}
+
+	return append(items, midtrans.ItemDetails{
+		ID:    "WALLET",
+		Name:  "Store credit",
+		Price: -int64(math.Round(walletAmount)),
+		Qty:   1,
+	})
+}
@@ -1,93 +1,177 @@
-package grpc
-
-import (
-	"context"
-	"net"
-	"payment/client"
-	"payment/cmd/db"
-	"payment/proto"
-	"payment/repository"
-	"payment/service"
-	"payment/transport/kafka"
-
-	"github.com/sirupsen/logrus"
-	"google.golang.org/grpc"
-)
-
-type PaymentGRPCServer struct {
-	service *service.PaymentService
-	proto.UnimplementedPaymentServiceServer
-}
-
-func NewPaymentGRPCServer(service *service.PaymentService) *PaymentGRPCServer {
-	return &PaymentGRPCServer{
-		service: service,
-	}
-}
-
-// CreatePayment creates a new payment record (called when order is created)
-func (u *PaymentGRPCServer) CreatePayment(ctx context.Context, req *proto.CreatePaymentRequest) (*proto.PaymentResponse, error) {
-	payment, err := u.service.CreatePayment(req)
-	if err != nil {
-		return nil, err
-	}
-	return payment, nil
-}
-
-// GetPaymentByOrderId retrieves payment by order ID
-func (u *PaymentGRPCServer) GetPaymentByOrderId(ctx context.Context, req *proto.GetPaymentByOrderIdRequest) (*proto.PaymentResponse, error) {
-	payment, err := u.service.GetPaymentByOrderId(int(req.OrderId))
-	if err != nil {
-		return nil, err
-	}
-	return payment, nil
-}
-
-// InitiatePayment creates a Midtrans Snap transaction
-func (u *PaymentGRPCServer) InitiatePayment(ctx context.Context, req *proto.InitiatePaymentRequest) (*proto.InitiatePaymentResponse, error) {
-	response, err := u.service.InitiatePayment(req)
-	if err != nil {
-		return nil, err
-	}
-	return response, nil
-}
-
-// HandleWebhook processes webhook notifications from Midtrans
-func (u *PaymentGRPCServer) HandleWebhook(ctx context.Context, req *proto.WebhookRequest) (*proto.EmptyPayment, error) {
-	if err := u.service.HandleWebhook(req); err != nil {
-		return nil, err
-	}
-	return &proto.EmptyPayment{}, nil
-}
-
-func GRPCListen(addr []string, topic []string, groupID string) {
-	// Initialize Midtrans client
-	client.InitMidtransClient()
-
-	DB, err := db.Connect()
-	if err != nil {
-		logrus.Fatalf("failed to connect to database: %v", err)
-	}
-
-	ctx := context.Background()
-	paymentRepo := repository.NewPaymentRepository()
-	orderRepo := repository.NewOrderRepository()
-	service := service.NewPaymentService(paymentRepo, DB, ctx, orderRepo)
-	connection := NewPaymentGRPCServer(service)
-
-	lis, err := net.Listen("tcp", ":60001")
-	if err != nil {
-		logrus.Fatalf("Failed to listen for gRPC: %v", err)
-	}
-
-	srv := grpc.NewServer()
-	proto.RegisterPaymentServiceServer(srv, connection)
-	logrus.Infof("gRPC Server started on port 60001")
-
-	go func() {
-		if err := srv.Serve(lis); err != nil {
-			logrus.Fatalf("error when connect to gRPC Server: %v", err)
-		}
-	}()
-	go kafka.ProcessMessage(addr, topic, groupID, service)
-}
+package grpc
+
+import (
+	"context"
+	"net"
+	"payment/client"
+	"payment/cmd/db"
+	"payment/proto"
+	"payment/repository"
+	"payment/service"
+	"payment/transport/kafka"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+type PaymentGRPCServer struct {
+	service *service.PaymentService
+	proto.UnimplementedPaymentServiceServer
+}
+
+func NewPaymentGRPCServer(service *service.PaymentService) *PaymentGRPCServer {
+	return &PaymentGRPCServer{
+		service: service,
+	}
+}
+
+// CreatePayment creates a new payment record (called when order is created)
+func (u *PaymentGRPCServer) CreatePayment(ctx context.Context, req *proto.CreatePaymentRequest) (*proto.PaymentResponse, error) {
+	payment, err := u.service.CreatePayment(req)
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// GetPaymentByOrderId retrieves payment by order ID
+func (u *PaymentGRPCServer) GetPaymentByOrderId(ctx context.Context, req *proto.GetPaymentByOrderIdRequest) (*proto.PaymentResponse, error) {
+	payment, err := u.service.GetPaymentByOrderId(int(req.OrderId))
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// InitiatePayment creates a Midtrans Snap transaction
+func (u *PaymentGRPCServer) InitiatePayment(ctx context.Context, req *proto.InitiatePaymentRequest) (*proto.InitiatePaymentResponse, error) {
+	response, err := u.service.InitiatePayment(req)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// HandleWebhook processes webhook notifications from Midtrans
+func (u *PaymentGRPCServer) HandleWebhook(ctx context.Context, req *proto.WebhookRequest) (*proto.EmptyPayment, error) {
+	if err := u.service.HandleWebhook(req); err != nil {
+		return nil, err
+	}
+	return &proto.EmptyPayment{}, nil
+}
+
+// CreatePaymentIntent creates a single Midtrans transaction covering several orders
+func (u *PaymentGRPCServer) CreatePaymentIntent(ctx context.Context, req *proto.CreatePaymentIntentRequest) (*proto.PaymentIntentResponse, error) {
+	intent, err := u.service.CreatePaymentIntent(req)
+	if err != nil {
+		return nil, err
+	}
+	return intent, nil
+}
+
+// GetPaymentIntent retrieves a payment intent with its grouped payments
+func (u *PaymentGRPCServer) GetPaymentIntent(ctx context.Context, req *proto.GetPaymentIntentRequest) (*proto.PaymentIntentResponse, error) {
+	intent, err := u.service.GetPaymentIntent(req)
+	if err != nil {
+		return nil, err
+	}
+	return intent, nil
+}
+
+// RefundPaymentIntent refunds part of a payment intent against one of its orders
+func (u *PaymentGRPCServer) RefundPaymentIntent(ctx context.Context, req *proto.RefundPaymentIntentRequest) (*proto.RefundResponse, error) {
+	refund, err := u.service.RefundPaymentIntent(req)
+	if err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+// RefundPayment refunds part of what was paid for an order, however it was paid
+func (u *PaymentGRPCServer) RefundPayment(ctx context.Context, req *proto.RefundPaymentRequest) (*proto.RefundResponse, error) {
+	refund, err := u.service.RefundPayment(req)
+	if err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+// InitiateCardPayment charges an order to a new or saved card
+func (u *PaymentGRPCServer) InitiateCardPayment(ctx context.Context, req *proto.InitiateCardPaymentRequest) (*proto.InitiatePaymentResponse, error) {
+	response, err := u.service.InitiateCardPayment(req)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// ListSavedPaymentMethods lists the user's saved cards
+func (u *PaymentGRPCServer) ListSavedPaymentMethods(ctx context.Context, req *proto.ListSavedPaymentMethodsRequest) (*proto.ListSavedPaymentMethodsResponse, error) {
+	methods, err := u.service.ListSavedPaymentMethods(req)
+	if err != nil {
+		return nil, err
+	}
+	return methods, nil
+}
+
+// DeleteSavedPaymentMethod deletes one of the user's saved cards
+func (u *PaymentGRPCServer) DeleteSavedPaymentMethod(ctx context.Context, req *proto.DeleteSavedPaymentMethodRequest) (*proto.EmptyPayment, error) {
+	if err := u.service.DeleteSavedPaymentMethod(req); err != nil {
+		return nil, err
+	}
+	return &proto.EmptyPayment{}, nil
+}
+
+// GetWallet retrieves the user's wallet balance and recent transactions
+func (u *PaymentGRPCServer) GetWallet(ctx context.Context, req *proto.GetWalletRequest) (*proto.WalletResponse, error) {
+	wallet, err := u.service.GetWallet(req)
+	if err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// TopUpWallet starts a wallet top-up paid through Midtrans
+func (u *PaymentGRPCServer) TopUpWallet(ctx context.Context, req *proto.TopUpWalletRequest) (*proto.WalletTopUpResponse, error) {
+	topUp, err := u.service.TopUpWallet(req)
+	if err != nil {
+		return nil, err
+	}
+	return topUp, nil
+}
+
+func GRPCListen(addr []string, topic []string, groupID string) {
+	// Initialize Midtrans client
+	client.InitMidtransClient()
+
+	DB, err := db.Connect()
+	if err != nil {
+		logrus.Fatalf("failed to connect to database: %v", err)
+	}
+
+	ctx := context.Background()
+	paymentRepo := repository.NewPaymentRepository()
+	orderRepo := repository.NewOrderRepository()
+	userRepo := repository.NewUserRepository()
+	intentRepo := repository.NewPaymentIntentRepository()
+	savedMethodRepo := repository.NewSavedPaymentMethodRepository()
+	walletRepo := repository.NewWalletRepository()
+	service := service.NewPaymentService(paymentRepo, DB, ctx, orderRepo, userRepo, intentRepo, savedMethodRepo, walletRepo)
+	connection := NewPaymentGRPCServer(service)
+
+	lis, err := net.Listen("tcp", ":60001")
+	if err != nil {
+		logrus.Fatalf("Failed to listen for gRPC: %v", err)
+	}
+
+	srv := grpc.NewServer()
+	proto.RegisterPaymentServiceServer(srv, connection)
+	logrus.Infof("gRPC Server started on port 60001")
+
+	go func() {
+		if err := srv.Serve(lis); err != nil {
+			logrus.Fatalf("error when connect to gRPC Server: %v", err)
+		}
+	}()
+	go kafka.ProcessMessage(addr, topic, groupID, service)
+}
@@ -19,6 +19,7 @@ type CachedProductList struct {
 	Products     []*proto.Product
 	TotalProduct int
 	TotalPage    int
+	Facets       []*proto.CategoryFacet
 }
 
 var cachedMiss = "cache miss"
@@ -89,6 +90,58 @@ func GetCacheProduct(ctx context.Context, key string) (*proto.Product, error) {
 	return result, nil
 }
 
+// GetCacheProducts reads several products with one MGET. The result lines up with
+// keys, with nil for keys that are not cached or cannot be decoded.
+func GetCacheProducts(ctx context.Context, keys []string) ([]*proto.Product, error) {
+	products := make([]*proto.Product, len(keys))
+	if len(keys) == 0 {
+		return products, nil
+	}
+
+	values, err := RedisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		logrus.Warnf("Failed to get cache for %d keys: %v", len(keys), err)
+		return products, err
+	}
+
+	for i, value := range values {
+		val, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal([]byte(val), &products[i]); err != nil {
+			logrus.Warnf("Failed to unmarshal cache data for key %s: %v", keys[i], err)
+			products[i] = nil
+		}
+	}
+
+	return products, nil
+}
+
+// SetCacheMany sets several values with the same TTL in one pipelined round trip
+func SetCacheMany(ctx context.Context, values map[string]interface{}, ttl time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	pipe := RedisClient.Pipeline()
+	for key, value := range values {
+		jsonData, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal data: %v", err)
+		}
+		pipe.Set(ctx, key, jsonData, ttl)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		logrus.Warnf("Failed to set cache for %d keys: %v", len(values), err)
+		return err
+	}
+
+	logrus.Debugf("Cache set for %d keys (TTL: %v)", len(values), ttl)
+	return nil
+}
+
 func GetCachedProductList(ctx context.Context, key string) (*CachedProductList, error) {
 	val, err := RedisClient.Get(ctx, key).Result()
 	if err == redis.Nil {
@@ -1,25 +1,53 @@
-package helper
-
-import (
-	"database/sql"
-
-	"github.com/sirupsen/logrus"
-)
-
-func CommitOrRollback(tx *sql.Tx) {
-	err := recover()
-	if err != nil {
-		rollbackError := tx.Rollback()
-		if rollbackError != nil {
-			logrus.Errorf("rollback error %v", rollbackError)
-			panic(rollbackError)
-		}
-		logrus.Errorf("error recover %v", err)
-		panic(err)
-	}
-	commitRollback := tx.Commit()
-	if commitRollback != nil {
-		logrus.Errorf("commit error %v", commitRollback)
-		panic(commitRollback)
-	}
-}
+package helper
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sirupsen/logrus"
+)
+
+func CommitOrRollback(tx *sql.Tx) {
+	err := recover()
+	if err != nil {
+		rollbackError := tx.Rollback()
+		if rollbackError != nil {
+			logrus.Errorf("rollback error %v", rollbackError)
+			panic(rollbackError)
+		}
+		logrus.Errorf("error recover %v", err)
+		panic(err)
+	}
+	commitRollback := tx.Commit()
+	if commitRollback != nil {
+		logrus.Errorf("commit error %v", commitRollback)
+		panic(commitRollback)
+	}
+}
+
+// Savepoint runs fn inside a savepoint, undoing only its changes when it fails so the
+// rest of the transaction can carry on
+func Savepoint(ctx context.Context, tx *sql.Tx, name string, fn func() error) error {
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+
+	if err := fn(); err != nil {
+		if _, rErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rErr != nil {
+			logrus.Errorf("Rollback to savepoint error: %v", rErr)
+		}
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
+
+// IsForeignKeyViolation reports whether err is Postgres refusing a change that would
+// leave rows referring to a missing one
+func IsForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
@@ -0,0 +1,71 @@
+package helper
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// NormalizeLocale lowercases a language tag such as pt-BR or pt_BR to pt-br and
+// reports whether it is well formed
+func NormalizeLocale(tag string) (string, bool) {
+	locale := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	return locale, localePattern.MatchString(locale)
+}
+
+// ParseAcceptLanguage returns the locales of an Accept-Language header, most
+// preferred first. Each regional tag is followed by its base language, so de-CH
+// also matches a de translation. Wildcards, malformed tags and tags with q=0 are
+// dropped.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		locale, ok := NormalizeLocale(fields[0])
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				parsed = 0
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{locale: locale, q: q})
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	seen := make(map[string]bool)
+	var locales []string
+	add := func(locale string) {
+		if !seen[locale] {
+			seen[locale] = true
+			locales = append(locales, locale)
+		}
+	}
+	for _, tag := range tags {
+		add(tag.locale)
+		if base, _, regional := strings.Cut(tag.locale, "-"); regional {
+			add(base)
+		}
+	}
+	return locales
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"product_service/proto"
+)
+
+type BundleRepository interface {
+	SetKind(ctx context.Context, tx *sql.Tx, productID int, kind string) error
+	GetComponents(ctx context.Context, tx *sql.Tx, bundleIDs []int32) (map[int32][]*proto.BundleComponent, error)
+	SetComponents(ctx context.Context, tx *sql.Tx, bundleID int, components []*proto.BundleComponent) error
+	GetBundleVariants(ctx context.Context, tx *sql.Tx, variantIDs []int32) (map[int32]int32, error)
+	GetBundlesOfVariant(ctx context.Context, tx *sql.Tx, variantID int) ([]int, error)
+	GetBundlesOfProduct(ctx context.Context, db *sql.DB, productID int) ([]int, error)
+	SyncBundleStock(ctx context.Context, tx *sql.Tx, bundleID int) (int32, int32, error)
+}
+
+type BundleRepositoryImpl struct{}
+
+func NewBundleRepositoryImpl() *BundleRepositoryImpl {
+	return &BundleRepositoryImpl{}
+}
+
+func (u *BundleRepositoryImpl) SetKind(ctx context.Context, tx *sql.Tx, productID int, kind string) error {
+	SQL := "UPDATE products SET kind = $1, updated_at = NOW() WHERE id = $2"
+	_, err := tx.ExecContext(ctx, SQL, kind, productID)
+	return err
+}
+
+// GetComponents returns the components of several bundles, keyed by bundle product ID.
+// Unit prices are what the component variants sell for now.
+func (u *BundleRepositoryImpl) GetComponents(ctx context.Context, tx *sql.Tx, bundleIDs []int32) (map[int32][]*proto.BundleComponent, error) {
+	SQL := `SELECT bc.bundle_product_id, bc.variant_id, v.product_id, v.sku, products.name, bc.quantity,
+				COALESCE(NULLIF(v.price, 0), ` + currentPriceSQL + `), v.stock, v.test_stock
+			FROM bundle_components bc
+			JOIN product_variants v ON v.id = bc.variant_id
+			JOIN products ON products.id = v.product_id
+			WHERE bc.bundle_product_id = ANY($1)
+			ORDER BY bc.bundle_product_id ASC, bc.position ASC, bc.variant_id ASC`
+	rows, err := tx.QueryContext(ctx, SQL, bundleIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	components := make(map[int32][]*proto.BundleComponent)
+	for rows.Next() {
+		var bundleID int32
+		component := &proto.BundleComponent{}
+		if err := rows.Scan(&bundleID, &component.VariantId, &component.ProductId, &component.Sku, &component.ProductName,
+			&component.Quantity, &component.UnitPrice, &component.Stock, &component.TestStock); err != nil {
+			return nil, err
+		}
+		components[bundleID] = append(components[bundleID], component)
+	}
+	return components, rows.Err()
+}
+
+// SetComponents replaces the components of a bundle, keeping their order
+func (u *BundleRepositoryImpl) SetComponents(ctx context.Context, tx *sql.Tx, bundleID int, components []*proto.BundleComponent) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM bundle_components WHERE bundle_product_id = $1", bundleID); err != nil {
+		return err
+	}
+
+	SQL := "INSERT INTO bundle_components(bundle_product_id, variant_id, quantity, position) VALUES ($1, $2, $3, $4)"
+	for i, component := range components {
+		if _, err := tx.ExecContext(ctx, SQL, bundleID, component.VariantId, component.Quantity, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetBundleVariants maps those of the given variants that belong to bundles to
+// their bundle's product ID
+func (u *BundleRepositoryImpl) GetBundleVariants(ctx context.Context, tx *sql.Tx, variantIDs []int32) (map[int32]int32, error) {
+	SQL := `SELECT v.id, v.product_id FROM product_variants v
+			JOIN products p ON p.id = v.product_id
+			WHERE v.id = ANY($1) AND p.kind = 'bundle'`
+	rows, err := tx.QueryContext(ctx, SQL, variantIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bundles := make(map[int32]int32)
+	for rows.Next() {
+		var variantID, productID int32
+		if err := rows.Scan(&variantID, &productID); err != nil {
+			return nil, err
+		}
+		bundles[variantID] = productID
+	}
+	return bundles, rows.Err()
+}
+
+// GetBundlesOfVariant returns the bundles a variant is a component of
+func (u *BundleRepositoryImpl) GetBundlesOfVariant(ctx context.Context, tx *sql.Tx, variantID int) ([]int, error) {
+	SQL := "SELECT bundle_product_id FROM bundle_components WHERE variant_id = $1 ORDER BY bundle_product_id ASC"
+	rows, err := tx.QueryContext(ctx, SQL, variantID)
+	if err != nil {
+		return nil, err
+	}
+	return scanBundleIDs(rows)
+}
+
+// GetBundlesOfProduct returns the bundles any variant of a product is a component of
+func (u *BundleRepositoryImpl) GetBundlesOfProduct(ctx context.Context, db *sql.DB, productID int) ([]int, error) {
+	SQL := `SELECT DISTINCT bc.bundle_product_id FROM bundle_components bc
+			JOIN product_variants v ON v.id = bc.variant_id
+			WHERE v.product_id = $1
+			ORDER BY bc.bundle_product_id ASC`
+	rows, err := db.QueryContext(ctx, SQL, productID)
+	if err != nil {
+		return nil, err
+	}
+	return scanBundleIDs(rows)
+}
+
+func scanBundleIDs(rows *sql.Rows) ([]int, error) {
+	defer rows.Close()
+
+	var IDs []int
+	for rows.Next() {
+		var ID int
+		if err := rows.Scan(&ID); err != nil {
+			return nil, err
+		}
+		IDs = append(IDs, ID)
+	}
+	return IDs, rows.Err()
+}
+
+// SyncBundleStock sets a bundle's stock in both pools to the number of bundles its
+// components can make up, and returns the live stock before and after. A bundle
+// without components has none.
+func (u *BundleRepositoryImpl) SyncBundleStock(ctx context.Context, tx *sql.Tx, bundleID int) (int32, int32, error) {
+	var before int32
+	if err := tx.QueryRowContext(ctx, "SELECT stock FROM products WHERE id = $1 FOR UPDATE", bundleID).Scan(&before); err != nil {
+		return 0, 0, err
+	}
+
+	SQL := `UPDATE product_variants v
+			SET stock = COALESCE(a.stock, 0), test_stock = COALESCE(a.test_stock, 0), updated_at = NOW()
+			FROM (SELECT MIN(c.stock / bc.quantity) AS stock, MIN(c.test_stock / bc.quantity) AS test_stock
+				FROM bundle_components bc
+				JOIN product_variants c ON c.id = bc.variant_id
+				WHERE bc.bundle_product_id = $1) a
+			WHERE v.product_id = $1`
+	if _, err := tx.ExecContext(ctx, SQL, bundleID); err != nil {
+		return 0, 0, err
+	}
+
+	SQL = `UPDATE products p
+			SET stock = v.stock, test_stock = v.test_stock, updated_at = NOW()
+			FROM (SELECT COALESCE(SUM(stock), 0) AS stock, COALESCE(SUM(test_stock), 0) AS test_stock FROM product_variants WHERE product_id = $1) v
+			WHERE p.id = $1
+			RETURNING p.stock`
+	var after int32
+	if err := tx.QueryRowContext(ctx, SQL, bundleID).Scan(&after); err != nil {
+		return 0, 0, err
+	}
+	return before, after, nil
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"product_service/proto"
+)
+
+type CategoryRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, category *proto.Category) (*proto.Category, error)
+	Update(ctx context.Context, tx *sql.Tx, category *proto.Category) (*proto.Category, error)
+	Delete(ctx context.Context, tx *sql.Tx, ID int) error
+	GetByID(ctx context.Context, db *sql.DB, ID int) (*proto.Category, error)
+	GetBySlug(ctx context.Context, db *sql.DB, slug string) (*proto.Category, error)
+	GetAll(ctx context.Context, db *sql.DB) ([]*proto.Category, error)
+	GetBreadcrumbs(ctx context.Context, db *sql.DB, ID int) ([]*proto.Category, error)
+	IsDescendant(ctx context.Context, tx *sql.Tx, ID int, ancestorID int) (bool, error)
+	SetProductCategories(ctx context.Context, tx *sql.Tx, productID int, categoryIDs []int32) error
+	GetProductCategories(ctx context.Context, tx *sql.Tx, productID int) ([]*proto.Category, error)
+	GetCategoriesForProducts(ctx context.Context, tx *sql.Tx, productIDs []int32) (map[int32][]*proto.Category, error)
+}
+
+type CategoryRepositoryImpl struct{}
+
+func NewCategoryRepositoryImpl() *CategoryRepositoryImpl {
+	return &CategoryRepositoryImpl{}
+}
+
+const categoryColumns = "id, COALESCE(parent_id, 0), name, slug, position, created_at, updated_at"
+
+func (u *CategoryRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, category *proto.Category) (*proto.Category, error) {
+	SQL := `INSERT INTO categories(parent_id, name, slug, position) VALUES (NULLIF($1, 0), $2, $3, $4)
+			RETURNING ` + categoryColumns
+	return scanCategory(tx.QueryRowContext(ctx, SQL, category.ParentId, category.Name, category.Slug, category.Position))
+}
+
+func (u *CategoryRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, category *proto.Category) (*proto.Category, error) {
+	SQL := `UPDATE categories
+			SET parent_id = NULLIF($1, 0),
+				name = $2,
+				slug = $3,
+				position = $4,
+				updated_at = NOW()
+			WHERE id = $5
+			RETURNING ` + categoryColumns
+	return scanCategory(tx.QueryRowContext(ctx, SQL, category.ParentId, category.Name, category.Slug, category.Position, category.Id))
+}
+
+// Delete removes a category without subcategories; product links are removed with it
+func (u *CategoryRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, ID int) error {
+	var children int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(id) FROM categories WHERE parent_id = $1", ID).Scan(&children); err != nil {
+		return err
+	}
+	if children > 0 {
+		return errors.New("category has subcategories")
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM categories WHERE id = $1", ID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("category not found")
+	}
+
+	return nil
+}
+
+func (u *CategoryRepositoryImpl) GetByID(ctx context.Context, db *sql.DB, ID int) (*proto.Category, error) {
+	SQL := "SELECT " + categoryColumns + " FROM categories WHERE id = $1"
+	return scanCategory(db.QueryRowContext(ctx, SQL, ID))
+}
+
+func (u *CategoryRepositoryImpl) GetBySlug(ctx context.Context, db *sql.DB, slug string) (*proto.Category, error) {
+	SQL := "SELECT " + categoryColumns + " FROM categories WHERE slug = $1"
+	return scanCategory(db.QueryRowContext(ctx, SQL, slug))
+}
+
+// GetAll returns every category ordered for display, parents before their children
+func (u *CategoryRepositoryImpl) GetAll(ctx context.Context, db *sql.DB) ([]*proto.Category, error) {
+	SQL := "SELECT " + categoryColumns + " FROM categories ORDER BY position ASC, name ASC"
+	rows, err := db.QueryContext(ctx, SQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCategories(rows)
+}
+
+// GetBreadcrumbs returns the path from the top-level category down to the given one
+func (u *CategoryRepositoryImpl) GetBreadcrumbs(ctx context.Context, db *sql.DB, ID int) ([]*proto.Category, error) {
+	SQL := `WITH RECURSIVE path AS (
+				SELECT id, parent_id, 0 AS depth FROM categories WHERE id = $1
+				UNION ALL
+				SELECT c.id, c.parent_id, p.depth + 1 FROM categories c JOIN path p ON c.id = p.parent_id
+			)
+			SELECT c.id, COALESCE(c.parent_id, 0), c.name, c.slug, c.position, c.created_at, c.updated_at
+			FROM path JOIN categories c ON c.id = path.id
+			ORDER BY path.depth DESC`
+	rows, err := db.QueryContext(ctx, SQL, ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCategories(rows)
+}
+
+// IsDescendant reports whether a category sits anywhere below ancestorID, or is ancestorID itself
+func (u *CategoryRepositoryImpl) IsDescendant(ctx context.Context, tx *sql.Tx, ID int, ancestorID int) (bool, error) {
+	SQL := `WITH RECURSIVE tree AS (
+				SELECT id FROM categories WHERE id = $1
+				UNION ALL
+				SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id
+			)
+			SELECT EXISTS (SELECT 1 FROM tree WHERE id = $2)`
+
+	var found bool
+	if err := tx.QueryRowContext(ctx, SQL, ancestorID, ID).Scan(&found); err != nil {
+		return false, err
+	}
+	return found, nil
+}
+
+// SetProductCategories replaces the categories a product is listed under
+func (u *CategoryRepositoryImpl) SetProductCategories(ctx context.Context, tx *sql.Tx, productID int, categoryIDs []int32) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM product_categories WHERE product_id = $1", productID); err != nil {
+		return err
+	}
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+
+	SQL := `INSERT INTO product_categories(product_id, category_id)
+			SELECT $1, id FROM categories WHERE id = ANY($2)`
+	result, err := tx.ExecContext(ctx, SQL, productID, categoryIDs)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if int(affected) != len(categoryIDs) {
+		return errors.New("category not found")
+	}
+
+	return nil
+}
+
+func (u *CategoryRepositoryImpl) GetProductCategories(ctx context.Context, tx *sql.Tx, productID int) ([]*proto.Category, error) {
+	SQL := `SELECT c.id, COALESCE(c.parent_id, 0), c.name, c.slug, c.position, c.created_at, c.updated_at
+			FROM categories c JOIN product_categories pc ON pc.category_id = c.id
+			WHERE pc.product_id = $1
+			ORDER BY c.position ASC, c.name ASC`
+	rows, err := tx.QueryContext(ctx, SQL, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCategories(rows)
+}
+
+// GetCategoriesForProducts loads the categories of several products in one query, keyed by product ID
+func (u *CategoryRepositoryImpl) GetCategoriesForProducts(ctx context.Context, tx *sql.Tx, productIDs []int32) (map[int32][]*proto.Category, error) {
+	SQL := `SELECT pc.product_id, c.id, COALESCE(c.parent_id, 0), c.name, c.slug, c.position, c.created_at, c.updated_at
+			FROM categories c JOIN product_categories pc ON pc.category_id = c.id
+			WHERE pc.product_id = ANY($1)
+			ORDER BY c.position ASC, c.name ASC`
+	rows, err := tx.QueryContext(ctx, SQL, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := make(map[int32][]*proto.Category)
+	for rows.Next() {
+		var productID int32
+		category := &proto.Category{}
+		if err := rows.Scan(
+			&productID,
+			&category.Id,
+			&category.ParentId,
+			&category.Name,
+			&category.Slug,
+			&category.Position,
+			&category.CreatedAt,
+			&category.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		categories[productID] = append(categories[productID], category)
+	}
+	return categories, rows.Err()
+}
+
+func scanCategory(row *sql.Row) (*proto.Category, error) {
+	category := &proto.Category{}
+	if err := row.Scan(
+		&category.Id,
+		&category.ParentId,
+		&category.Name,
+		&category.Slug,
+		&category.Position,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+	return category, nil
+}
+
+func scanCategories(rows *sql.Rows) ([]*proto.Category, error) {
+	var categories []*proto.Category
+	for rows.Next() {
+		category := &proto.Category{}
+		if err := rows.Scan(
+			&category.Id,
+			&category.ParentId,
+			&category.Name,
+			&category.Slug,
+			&category.Position,
+			&category.CreatedAt,
+			&category.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	return categories, rows.Err()
+}
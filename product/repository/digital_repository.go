@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"product_service/proto"
+)
+
+var ErrNotEnoughLicenseKeys = errors.New("not enough licence keys")
+
+type DigitalRepository interface {
+	Upsert(ctx context.Context, tx *sql.Tx, digital *proto.DigitalDelivery) error
+	GetForProducts(ctx context.Context, tx *sql.Tx, productIDs []int32) (map[int32]*proto.DigitalDelivery, error)
+	AddKeys(ctx context.Context, tx *sql.Tx, productID int, keys []string) (int, error)
+	CountAvailableKeys(ctx context.Context, tx *sql.Tx, productID int) (int, error)
+	GetAssignedKeys(ctx context.Context, tx *sql.Tx, orderItemID int) ([]string, error)
+	AssignKeys(ctx context.Context, tx *sql.Tx, productID int, orderID int, orderItemID int, count int) ([]string, error)
+}
+
+type DigitalRepositoryImpl struct{}
+
+func NewDigitalRepositoryImpl() *DigitalRepositoryImpl {
+	return &DigitalRepositoryImpl{}
+}
+
+func (u *DigitalRepositoryImpl) Upsert(ctx context.Context, tx *sql.Tx, digital *proto.DigitalDelivery) error {
+	SQL := `INSERT INTO digital_products(product_id, delivery, file_key, file_name, content_type, download_limit, link_ttl_hours)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (product_id) DO UPDATE
+			SET delivery = EXCLUDED.delivery,
+				file_key = EXCLUDED.file_key,
+				file_name = EXCLUDED.file_name,
+				content_type = EXCLUDED.content_type,
+				download_limit = EXCLUDED.download_limit,
+				link_ttl_hours = EXCLUDED.link_ttl_hours,
+				updated_at = NOW()`
+	_, err := tx.ExecContext(ctx, SQL, digital.ProductId, digital.Delivery, digital.FileKey, digital.FileName, digital.ContentType, digital.DownloadLimit, digital.LinkTtlHours)
+	return err
+}
+
+// GetForProducts returns how the digital products among the given ones are delivered,
+// with the licence keys left in their pools, keyed by product ID
+func (u *DigitalRepositoryImpl) GetForProducts(ctx context.Context, tx *sql.Tx, productIDs []int32) (map[int32]*proto.DigitalDelivery, error) {
+	SQL := `SELECT d.product_id, d.delivery, d.file_key, d.file_name, d.content_type, d.download_limit, d.link_ttl_hours,
+				(SELECT COUNT(*) FROM license_keys k WHERE k.product_id = d.product_id AND k.order_item_id IS NULL)
+			FROM digital_products d
+			WHERE d.product_id = ANY($1)`
+	rows, err := tx.QueryContext(ctx, SQL, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byProduct := make(map[int32]*proto.DigitalDelivery)
+	for rows.Next() {
+		digital := &proto.DigitalDelivery{}
+		if err := rows.Scan(
+			&digital.ProductId,
+			&digital.Delivery,
+			&digital.FileKey,
+			&digital.FileName,
+			&digital.ContentType,
+			&digital.DownloadLimit,
+			&digital.LinkTtlHours,
+			&digital.AvailableKeys,
+		); err != nil {
+			return nil, err
+		}
+		byProduct[digital.ProductId] = digital
+	}
+	return byProduct, rows.Err()
+}
+
+// AddKeys adds licence keys to a product's pool, skipping keys it already has, and
+// returns how many were added
+func (u *DigitalRepositoryImpl) AddKeys(ctx context.Context, tx *sql.Tx, productID int, keys []string) (int, error) {
+	SQL := `INSERT INTO license_keys(product_id, license_key)
+			SELECT $1, key FROM UNNEST($2::text[]) AS key
+			ON CONFLICT (product_id, license_key) DO NOTHING`
+	result, err := tx.ExecContext(ctx, SQL, productID, keys)
+	if err != nil {
+		return 0, err
+	}
+
+	added, err := result.RowsAffected()
+	return int(added), err
+}
+
+func (u *DigitalRepositoryImpl) CountAvailableKeys(ctx context.Context, tx *sql.Tx, productID int) (int, error) {
+	var count int
+	SQL := "SELECT COUNT(*) FROM license_keys WHERE product_id = $1 AND order_item_id IS NULL"
+	err := tx.QueryRowContext(ctx, SQL, productID).Scan(&count)
+	return count, err
+}
+
+// GetAssignedKeys returns the licence keys an order item already has, oldest first
+func (u *DigitalRepositoryImpl) GetAssignedKeys(ctx context.Context, tx *sql.Tx, orderItemID int) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT license_key FROM license_keys WHERE order_item_id = $1 ORDER BY id ASC", orderItemID)
+	if err != nil {
+		return nil, err
+	}
+	return scanLicenseKeys(rows)
+}
+
+// AssignKeys takes count keys from a product's pool, oldest first, for an order
+// item. Keys being assigned by concurrent deliveries are skipped rather than waited
+// for; ErrNotEnoughLicenseKeys is returned when the pool runs short.
+func (u *DigitalRepositoryImpl) AssignKeys(ctx context.Context, tx *sql.Tx, productID int, orderID int, orderItemID int, count int) ([]string, error) {
+	SQL := `UPDATE license_keys
+			SET order_id = $2, order_item_id = $3, assigned_at = NOW()
+			WHERE id IN (
+				SELECT id FROM license_keys
+				WHERE product_id = $1 AND order_item_id IS NULL
+				ORDER BY id ASC
+				LIMIT $4
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING license_key`
+	rows, err := tx.QueryContext(ctx, SQL, productID, orderID, orderItemID, count)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := scanLicenseKeys(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) < count {
+		return nil, ErrNotEnoughLicenseKeys
+	}
+	return keys, nil
+}
+
+func scanLicenseKeys(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"product_service/proto"
+)
+
+type ImageRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, image *proto.ProductImage) (*proto.ProductImage, error)
+	Update(ctx context.Context, tx *sql.Tx, image *proto.ProductImage) (*proto.ProductImage, error)
+	Delete(ctx context.Context, tx *sql.Tx, ID int) (*proto.ProductImage, error)
+	GetByID(ctx context.Context, tx *sql.Tx, ID int) (*proto.ProductImage, error)
+	GetProductImages(ctx context.Context, tx *sql.Tx, productID int) ([]*proto.ProductImage, error)
+	GetImagesForProducts(ctx context.Context, db *sql.DB, productIDs []int32) ([]*proto.ProductImage, error)
+	SetPosition(ctx context.Context, tx *sql.Tx, ID int, productID int, position int) error
+	NextPosition(ctx context.Context, tx *sql.Tx, productID int) (int, error)
+}
+
+type ImageRepositoryImpl struct{}
+
+func NewImageRepositoryImpl() *ImageRepositoryImpl {
+	return &ImageRepositoryImpl{}
+}
+
+const imageColumns = `id, product_id, storage_key, thumbnail_key, content_type, size_bytes, width, height, alt_text, position, created_at`
+
+func (u *ImageRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, image *proto.ProductImage) (*proto.ProductImage, error) {
+	SQL := `INSERT INTO product_images(product_id, storage_key, thumbnail_key, content_type, size_bytes, width, height, alt_text, position)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING ` + imageColumns
+
+	return scanImage(tx.QueryRowContext(ctx, SQL,
+		image.ProductId,
+		image.StorageKey,
+		image.ThumbnailKey,
+		image.ContentType,
+		image.SizeBytes,
+		image.Width,
+		image.Height,
+		image.AltText,
+		image.Position,
+	))
+}
+
+// Update changes the alt text and position of an image; the stored files never change
+func (u *ImageRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, image *proto.ProductImage) (*proto.ProductImage, error) {
+	SQL := `UPDATE product_images SET alt_text = $1, position = $2
+			WHERE id = $3
+			RETURNING ` + imageColumns
+
+	return scanImage(tx.QueryRowContext(ctx, SQL, image.AltText, image.Position, image.Id))
+}
+
+// Delete removes an image and returns it, so the caller can remove the stored files
+func (u *ImageRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, ID int) (*proto.ProductImage, error) {
+	SQL := "DELETE FROM product_images WHERE id = $1 RETURNING " + imageColumns
+	return scanImage(tx.QueryRowContext(ctx, SQL, ID))
+}
+
+func (u *ImageRepositoryImpl) GetByID(ctx context.Context, tx *sql.Tx, ID int) (*proto.ProductImage, error) {
+	SQL := "SELECT " + imageColumns + " FROM product_images WHERE id = $1"
+	return scanImage(tx.QueryRowContext(ctx, SQL, ID))
+}
+
+func (u *ImageRepositoryImpl) GetProductImages(ctx context.Context, tx *sql.Tx, productID int) ([]*proto.ProductImage, error) {
+	SQL := "SELECT " + imageColumns + " FROM product_images WHERE product_id = $1 ORDER BY position ASC, id ASC"
+	rows, err := tx.QueryContext(ctx, SQL, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanImages(rows)
+}
+
+// GetImagesForProducts loads the images of a page of products in one query
+func (u *ImageRepositoryImpl) GetImagesForProducts(ctx context.Context, db *sql.DB, productIDs []int32) ([]*proto.ProductImage, error) {
+	if len(productIDs) == 0 {
+		return nil, nil
+	}
+
+	SQL := "SELECT " + imageColumns + " FROM product_images WHERE product_id = ANY($1) ORDER BY product_id ASC, position ASC, id ASC"
+	rows, err := db.QueryContext(ctx, SQL, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanImages(rows)
+}
+
+func (u *ImageRepositoryImpl) SetPosition(ctx context.Context, tx *sql.Tx, ID int, productID int, position int) error {
+	result, err := tx.ExecContext(ctx, "UPDATE product_images SET position = $1 WHERE id = $2 AND product_id = $3", position, ID, productID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("image not found")
+	}
+
+	return nil
+}
+
+// NextPosition returns the position after the product's last image
+func (u *ImageRepositoryImpl) NextPosition(ctx context.Context, tx *sql.Tx, productID int) (int, error) {
+	var position int
+	SQL := "SELECT COALESCE(MAX(position) + 1, 0) FROM product_images WHERE product_id = $1"
+	if err := tx.QueryRowContext(ctx, SQL, productID).Scan(&position); err != nil {
+		return 0, err
+	}
+	return position, nil
+}
+
+func scanImages(rows *sql.Rows) ([]*proto.ProductImage, error) {
+	var images []*proto.ProductImage
+	for rows.Next() {
+		image, err := scanImage(rows)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	}
+	return images, rows.Err()
+}
+
+func scanImage(row rowScanner) (*proto.ProductImage, error) {
+	image := &proto.ProductImage{}
+	if err := row.Scan(
+		&image.Id,
+		&image.ProductId,
+		&image.StorageKey,
+		&image.ThumbnailKey,
+		&image.ContentType,
+		&image.SizeBytes,
+		&image.Width,
+		&image.Height,
+		&image.AltText,
+		&image.Position,
+		&image.CreatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("image not found")
+		}
+		return nil, err
+	}
+	return image, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"product_service/proto"
+)
+
+type InventoryRepository interface {
+	Record(ctx context.Context, tx *sql.Tx, movement *proto.InventoryMovement) error
+	GetProductMovements(ctx context.Context, tx *sql.Tx, productID int, livemode bool, limit int, offset int) ([]*proto.InventoryMovement, int, error)
+}
+
+type InventoryRepositoryImpl struct{}
+
+func NewInventoryRepositoryImpl() *InventoryRepositoryImpl {
+	return &InventoryRepositoryImpl{}
+}
+
+// Record appends a movement to the ledger. It runs in the transaction that
+// changes the stock, so the two can never disagree.
+func (u *InventoryRepositoryImpl) Record(ctx context.Context, tx *sql.Tx, movement *proto.InventoryMovement) error {
+	SQL := `INSERT INTO inventory_movements(product_id, variant_id, sku, delta, stock_after, livemode, reason, reference_type, reference_id, actor, note, warehouse_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, 0))`
+	_, err := tx.ExecContext(ctx, SQL,
+		movement.ProductId,
+		movement.VariantId,
+		movement.Sku,
+		movement.Delta,
+		movement.StockAfter,
+		movement.Livemode,
+		movement.Reason,
+		movement.ReferenceType,
+		movement.ReferenceId,
+		movement.Actor,
+		movement.Note,
+		movement.WarehouseId,
+	)
+	return err
+}
+
+// GetProductMovements returns a page of a product's movements in one stock pool,
+// newest first, with the total number of movements
+func (u *InventoryRepositoryImpl) GetProductMovements(ctx context.Context, tx *sql.Tx, productID int, livemode bool, limit int, offset int) ([]*proto.InventoryMovement, int, error) {
+	SQL := `SELECT m.id, m.product_id, COALESCE(m.variant_id, 0), m.sku, m.delta, m.stock_after, m.livemode, m.reason,
+				m.reference_type, m.reference_id, m.actor, m.note, m.created_at,
+				COALESCE(m.warehouse_id, 0), COALESCE(w.code, '')
+			FROM inventory_movements m
+			LEFT JOIN warehouses w ON w.id = m.warehouse_id
+			WHERE m.product_id = $1 AND m.livemode = $2
+			ORDER BY m.created_at DESC, m.id DESC
+			LIMIT $3 OFFSET $4`
+	rows, err := tx.QueryContext(ctx, SQL, productID, livemode, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var movements []*proto.InventoryMovement
+	for rows.Next() {
+		movement := &proto.InventoryMovement{}
+		if err := rows.Scan(
+			&movement.Id,
+			&movement.ProductId,
+			&movement.VariantId,
+			&movement.Sku,
+			&movement.Delta,
+			&movement.StockAfter,
+			&movement.Livemode,
+			&movement.Reason,
+			&movement.ReferenceType,
+			&movement.ReferenceId,
+			&movement.Actor,
+			&movement.Note,
+			&movement.CreatedAt,
+			&movement.WarehouseId,
+			&movement.WarehouseCode,
+		); err != nil {
+			return nil, 0, err
+		}
+		movements = append(movements, movement)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	SQL = "SELECT COUNT(id) FROM inventory_movements WHERE product_id = $1 AND livemode = $2"
+	if err := tx.QueryRowContext(ctx, SQL, productID, livemode).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	return movements, total, nil
+}
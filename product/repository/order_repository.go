@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"product_service/proto"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// OrderRepository asks the order service about orders, which the product service
+// does not read itself
+type OrderRepository interface {
+	HasPurchased(ctx context.Context, userID int, productID int) (bool, error)
+	HasOrders(ctx context.Context, productID int) (bool, error)
+}
+
+type OrderRepositoryImpl struct {
+	client proto.OrderServiceClient
+}
+
+func NewOrderRepositoryImpl() *OrderRepositoryImpl {
+	conn, err := grpc.NewClient("order-service:30001", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		logrus.Fatalf("Failed to connect: %v", err)
+	}
+	logrus.Info("Connected to order service")
+
+	client := proto.NewOrderServiceClient(conn)
+	return &OrderRepositoryImpl{client: client}
+}
+
+// HasPurchased reports whether the user has a paid or fulfilled live order containing
+// the product
+func (u *OrderRepositoryImpl) HasPurchased(ctx context.Context, userID int, productID int) (bool, error) {
+	ctxRepo, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	resp, err := u.client.HasPurchased(ctxRepo, &proto.HasPurchasedRequest{
+		UserId:    int32(userID),
+		ProductId: int32(productID),
+	})
+	if err != nil {
+		return false, err
+	}
+	return resp.Purchased, nil
+}
+
+// HasOrders reports whether any order of either mode has a line of the product,
+// including bundle lines and the component lines under them
+func (u *OrderRepositoryImpl) HasOrders(ctx context.Context, productID int) (bool, error) {
+	ctxRepo, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	resp, err := u.client.HasOrders(ctxRepo, &proto.HasOrdersRequest{ProductId: int32(productID)})
+	if err != nil {
+		return false, err
+	}
+	return resp.Ordered, nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ProductEvent is a product change waiting in the outbox to be published
+type ProductEvent struct {
+	ID             int64
+	ProductID      int
+	ProductVersion int64
+	EventType      string
+	ChangedFields  []string
+	Product        json.RawMessage
+	CreatedAt      string
+}
+
+type OutboxRepository interface {
+	LockProduct(ctx context.Context, tx *sql.Tx, productID int) (int64, error)
+	LatestImage(ctx context.Context, tx *sql.Tx, productID int) (json.RawMessage, error)
+	Record(ctx context.Context, tx *sql.Tx, event *ProductEvent) error
+	LockPublisher(ctx context.Context, tx *sql.Tx) (bool, error)
+	ClaimEvents(ctx context.Context, tx *sql.Tx, limit int) ([]*ProductEvent, error)
+	MarkPublished(ctx context.Context, tx *sql.Tx, IDs []int64) error
+}
+
+type OutboxRepositoryImpl struct{}
+
+func NewOutboxRepositoryImpl() *OutboxRepositoryImpl {
+	return &OutboxRepositoryImpl{}
+}
+
+// LockProduct locks a product until tx ends and returns its current version, so the
+// changes of one product are recorded one at a time and in order
+func (u *OutboxRepositoryImpl) LockProduct(ctx context.Context, tx *sql.Tx, productID int) (int64, error) {
+	var version int64
+	err := tx.QueryRowContext(ctx, "SELECT version FROM products WHERE id = $1 FOR UPDATE", productID).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, errors.New("product not found")
+	}
+	return version, err
+}
+
+// LatestImage returns the product as of its last recorded event, or nil when none was
+// recorded yet
+func (u *OutboxRepositoryImpl) LatestImage(ctx context.Context, tx *sql.Tx, productID int) (json.RawMessage, error) {
+	var image []byte
+	SQL := "SELECT product FROM product_outbox WHERE product_id = $1 ORDER BY product_version DESC LIMIT 1"
+	err := tx.QueryRowContext(ctx, SQL, productID).Scan(&image)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return image, err
+}
+
+// Record bumps the product's version to the event's and queues the event for the
+// publisher. The product has to be locked with LockProduct first; a purged product's
+// event is recorded before its row is deleted.
+func (u *OutboxRepositoryImpl) Record(ctx context.Context, tx *sql.Tx, event *ProductEvent) error {
+	if _, err := tx.ExecContext(ctx, "UPDATE products SET version = $1 WHERE id = $2", event.ProductVersion, event.ProductID); err != nil {
+		return err
+	}
+
+	var product interface{}
+	if event.Product != nil {
+		product = []byte(event.Product)
+	}
+
+	SQL := `INSERT INTO product_outbox(product_id, product_version, event_type, changed_fields, product)
+			VALUES ($1, $2, $3, $4, $5)`
+	_, err := tx.ExecContext(ctx, SQL, event.ProductID, event.ProductVersion, event.EventType, event.ChangedFields, product)
+	return err
+}
+
+// outboxPublisherLock is the advisory lock held by the one publisher sending events
+const outboxPublisherLock = 40001
+
+// LockPublisher makes tx the only one publishing events until it ends, and returns
+// false when another publisher already is
+func (u *OutboxRepositoryImpl) LockPublisher(ctx context.Context, tx *sql.Tx) (bool, error) {
+	var locked bool
+	err := tx.QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock($1)", outboxPublisherLock).Scan(&locked)
+	return locked, err
+}
+
+// ClaimEvents returns the oldest unpublished events. Callers hold the publisher lock,
+// so no other publisher can send the same events out of order.
+func (u *OutboxRepositoryImpl) ClaimEvents(ctx context.Context, tx *sql.Tx, limit int) ([]*ProductEvent, error) {
+	SQL := `SELECT id, product_id, product_version, event_type, changed_fields, product, created_at
+			FROM product_outbox
+			WHERE published_at IS NULL
+			ORDER BY id ASC
+			LIMIT $1`
+	rows, err := tx.QueryContext(ctx, SQL, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types := pgtype.NewMap()
+	var events []*ProductEvent
+	for rows.Next() {
+		event := &ProductEvent{}
+		var product []byte
+		if err := rows.Scan(&event.ID, &event.ProductID, &event.ProductVersion, &event.EventType,
+			types.SQLScanner(&event.ChangedFields), &product, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		if product != nil {
+			event.Product = product
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func (u *OutboxRepositoryImpl) MarkPublished(ctx context.Context, tx *sql.Tx, IDs []int64) error {
+	_, err := tx.ExecContext(ctx, "UPDATE product_outbox SET published_at = NOW() WHERE id = ANY($1)", IDs)
+	return err
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"product_service/proto"
+	"time"
+)
+
+var ErrPriceNotFound = errors.New("price not found")
+
+// currentPriceSQL resolves the price a product sells for now: the latest started
+// scheduled price that has not ended, or else the list price
+const currentPriceSQL = `COALESCE((
+		SELECT pp.price FROM product_prices pp
+		WHERE pp.product_id = products.id AND pp.kind = 'scheduled'
+			AND pp.effective_from <= NOW() AND (pp.effective_to IS NULL OR pp.effective_to > NOW())
+		ORDER BY pp.effective_from DESC, pp.id DESC
+		LIMIT 1
+	), products.price)`
+
+type PriceRepository interface {
+	RecordListPrice(ctx context.Context, tx *sql.Tx, productID int, price float64) error
+	Schedule(ctx context.Context, tx *sql.Tx, price *proto.ProductPrice) (*proto.ProductPrice, error)
+	GetByID(ctx context.Context, tx *sql.Tx, ID int) (*proto.ProductPrice, error)
+	HasOverlap(ctx context.Context, tx *sql.Tx, price *proto.ProductPrice) (bool, error)
+	End(ctx context.Context, tx *sql.Tx, ID int) (*proto.ProductPrice, error)
+	Delete(ctx context.Context, tx *sql.Tx, ID int) error
+	GetHistory(ctx context.Context, tx *sql.Tx, productID int) ([]*proto.ProductPrice, error)
+	PriceAt(ctx context.Context, tx *sql.Tx, productID int, at string) (float64, error)
+	NextTransition(ctx context.Context, db *sql.DB) (time.Duration, bool, error)
+	TransitionedProducts(ctx context.Context, db *sql.DB, since string) ([]int, string, error)
+}
+
+type PriceRepositoryImpl struct{}
+
+func NewPriceRepositoryImpl() *PriceRepositoryImpl {
+	return &PriceRepositoryImpl{}
+}
+
+const priceColumns = `id, product_id, kind, price, effective_from, effective_to, note, created_at`
+
+// RecordListPrice closes the open list price of a product and opens one at price,
+// unless the price did not change
+func (u *PriceRepositoryImpl) RecordListPrice(ctx context.Context, tx *sql.Tx, productID int, price float64) error {
+	var current float64
+	SQL := "SELECT price FROM product_prices WHERE product_id = $1 AND kind = 'list' AND effective_to IS NULL FOR UPDATE"
+	err := tx.QueryRowContext(ctx, SQL, productID).Scan(&current)
+	if err == nil && current == price {
+		return nil
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	SQL = "UPDATE product_prices SET effective_to = NOW() WHERE product_id = $1 AND kind = 'list' AND effective_to IS NULL"
+	if _, err := tx.ExecContext(ctx, SQL, productID); err != nil {
+		return err
+	}
+
+	SQL = "INSERT INTO product_prices(product_id, kind, price, effective_from) VALUES ($1, 'list', $2, NOW())"
+	_, err = tx.ExecContext(ctx, SQL, productID, price)
+	return err
+}
+
+// Schedule saves a scheduled price. Times are bound as RFC 3339 and converted to
+// the database's time zone, like NOW().
+func (u *PriceRepositoryImpl) Schedule(ctx context.Context, tx *sql.Tx, price *proto.ProductPrice) (*proto.ProductPrice, error) {
+	SQL := `INSERT INTO product_prices(product_id, kind, price, effective_from, effective_to, note)
+			VALUES ($1, 'scheduled', $2, $3::timestamptz, NULLIF($4, '')::timestamptz, $5)
+			RETURNING ` + priceColumns
+	return scanPrice(tx.QueryRowContext(ctx, SQL, price.ProductId, price.Price, price.EffectiveFrom, price.EffectiveTo, price.Note))
+}
+
+func (u *PriceRepositoryImpl) GetByID(ctx context.Context, tx *sql.Tx, ID int) (*proto.ProductPrice, error) {
+	SQL := "SELECT " + priceColumns + " FROM product_prices WHERE id = $1"
+	return scanPrice(tx.QueryRowContext(ctx, SQL, ID))
+}
+
+// HasOverlap reports whether another scheduled price of the product shares part of
+// the new price's period
+func (u *PriceRepositoryImpl) HasOverlap(ctx context.Context, tx *sql.Tx, price *proto.ProductPrice) (bool, error) {
+	SQL := `SELECT EXISTS (
+				SELECT 1 FROM product_prices
+				WHERE product_id = $1 AND kind = 'scheduled'
+					AND (effective_to IS NULL OR effective_to > $2::timestamptz)
+					AND (NULLIF($3, '') IS NULL OR effective_from < NULLIF($3, '')::timestamptz)
+			)`
+	var overlap bool
+	err := tx.QueryRowContext(ctx, SQL, price.ProductId, price.EffectiveFrom, price.EffectiveTo).Scan(&overlap)
+	return overlap, err
+}
+
+// End stops an active scheduled price now; its history stays
+func (u *PriceRepositoryImpl) End(ctx context.Context, tx *sql.Tx, ID int) (*proto.ProductPrice, error) {
+	SQL := `UPDATE product_prices SET effective_to = NOW()
+			WHERE id = $1 AND kind = 'scheduled' AND effective_from <= NOW()
+				AND (effective_to IS NULL OR effective_to > NOW())
+			RETURNING ` + priceColumns
+	return scanPrice(tx.QueryRowContext(ctx, SQL, ID))
+}
+
+// Delete removes a scheduled price that has not started
+func (u *PriceRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, ID int) error {
+	result, err := tx.ExecContext(ctx, "DELETE FROM product_prices WHERE id = $1 AND kind = 'scheduled' AND effective_from > NOW()", ID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrPriceNotFound
+	}
+
+	return nil
+}
+
+// GetHistory returns every list and scheduled price of a product, newest first
+func (u *PriceRepositoryImpl) GetHistory(ctx context.Context, tx *sql.Tx, productID int) ([]*proto.ProductPrice, error) {
+	SQL := "SELECT " + priceColumns + " FROM product_prices WHERE product_id = $1 ORDER BY effective_from DESC, id DESC"
+	rows, err := tx.QueryContext(ctx, SQL, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prices []*proto.ProductPrice
+	for rows.Next() {
+		price, err := scanPrice(rows)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, price)
+	}
+	return prices, rows.Err()
+}
+
+// PriceAt resolves what a product sold for at an RFC 3339 time, the same way
+// currentPriceSQL does for now
+func (u *PriceRepositoryImpl) PriceAt(ctx context.Context, tx *sql.Tx, productID int, at string) (float64, error) {
+	SQL := `SELECT price FROM product_prices
+			WHERE product_id = $1
+				AND effective_from <= $2::timestamptz AND (effective_to IS NULL OR effective_to > $2::timestamptz)
+			ORDER BY kind = 'scheduled' DESC, effective_from DESC, id DESC
+			LIMIT 1`
+	var price float64
+	err := tx.QueryRowContext(ctx, SQL, productID, at).Scan(&price)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrPriceNotFound
+	}
+	return price, err
+}
+
+// NextTransition returns how long until the next scheduled price starts or ends,
+// and false when none is coming
+func (u *PriceRepositoryImpl) NextTransition(ctx context.Context, db *sql.DB) (time.Duration, bool, error) {
+	SQL := `SELECT EXTRACT(EPOCH FROM (MIN(t) - NOW()))::float8 FROM (
+				SELECT effective_from AS t FROM product_prices WHERE kind = 'scheduled' AND effective_from > NOW()
+				UNION ALL
+				SELECT effective_to FROM product_prices WHERE kind = 'scheduled' AND effective_to > NOW()
+			) transitions`
+	var seconds sql.NullFloat64
+	if err := db.QueryRowContext(ctx, SQL).Scan(&seconds); err != nil {
+		return 0, false, err
+	}
+	if !seconds.Valid {
+		return 0, false, nil
+	}
+	return time.Duration(seconds.Float64 * float64(time.Second)), true, nil
+}
+
+func scanPrice(row rowScanner) (*proto.ProductPrice, error) {
+	price := &proto.ProductPrice{}
+	var effectiveTo sql.NullString
+	if err := row.Scan(
+		&price.Id,
+		&price.ProductId,
+		&price.Kind,
+		&price.Price,
+		&price.EffectiveFrom,
+		&effectiveTo,
+		&price.Note,
+		&price.CreatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPriceNotFound
+		}
+		return nil, err
+	}
+	price.EffectiveTo = effectiveTo.String
+	return price, nil
+}
+
+// TransitionedProducts returns the products with a scheduled price that started or
+// ended after since, and the database time they were looked up at to pass as since
+// next time. An empty since only returns the time.
+func (u *PriceRepositoryImpl) TransitionedProducts(ctx context.Context, db *sql.DB, since string) ([]int, string, error) {
+	var until string
+	if err := db.QueryRowContext(ctx, "SELECT LOCALTIMESTAMP::text").Scan(&until); err != nil {
+		return nil, "", err
+	}
+	if since == "" {
+		return nil, until, nil
+	}
+
+	SQL := `SELECT DISTINCT product_id FROM product_prices
+			WHERE kind = 'scheduled'
+			AND ((effective_from > $1::timestamp AND effective_from <= $2::timestamp)
+				OR (effective_to > $1::timestamp AND effective_to <= $2::timestamp))
+			ORDER BY product_id ASC`
+	rows, err := db.QueryContext(ctx, SQL, since, until)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var productIDs []int
+	for rows.Next() {
+		var productID int
+		if err := rows.Scan(&productID); err != nil {
+			return nil, "", err
+		}
+		productIDs = append(productIDs, productID)
+	}
+	return productIDs, until, rows.Err()
+}
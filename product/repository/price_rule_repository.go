@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"product_service/proto"
+)
+
+var ErrPriceRuleNotFound = errors.New("price rule not found")
+
+type PriceRuleRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, rule *proto.PriceRule) (*proto.PriceRule, error)
+	Delete(ctx context.Context, tx *sql.Tx, ID int) (*proto.PriceRule, error)
+	ListForProduct(ctx context.Context, tx *sql.Tx, productID int) ([]*proto.PriceRule, error)
+	GetApplicable(ctx context.Context, tx *sql.Tx, userID int, productIDs []int32) (map[int32][]*proto.PriceRule, error)
+	GroupExists(ctx context.Context, tx *sql.Tx, groupID int) (bool, error)
+}
+
+type PriceRuleRepositoryImpl struct{}
+
+func NewPriceRuleRepositoryImpl() *PriceRuleRepositoryImpl {
+	return &PriceRuleRepositoryImpl{}
+}
+
+const priceRuleColumns = `id, product_id, COALESCE(customer_group_id, 0), min_quantity, price, discount_percent, created_at`
+
+// Create saves a rule; a rule without a discount sets the price, and a customer
+// group of 0 is stored as none
+func (u *PriceRuleRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, rule *proto.PriceRule) (*proto.PriceRule, error) {
+	var price, discount sql.NullFloat64
+	if rule.DiscountPercent > 0 {
+		discount = sql.NullFloat64{Float64: rule.DiscountPercent, Valid: true}
+	} else {
+		price = sql.NullFloat64{Float64: rule.Price, Valid: true}
+	}
+
+	SQL := `INSERT INTO price_rules(product_id, customer_group_id, min_quantity, price, discount_percent)
+			VALUES ($1, NULLIF($2, 0), $3, $4, $5)
+			RETURNING ` + priceRuleColumns
+	return scanPriceRule(tx.QueryRowContext(ctx, SQL, rule.ProductId, rule.CustomerGroupId, rule.MinQuantity, price, discount))
+}
+
+func (u *PriceRuleRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, ID int) (*proto.PriceRule, error) {
+	SQL := "DELETE FROM price_rules WHERE id = $1 RETURNING " + priceRuleColumns
+	return scanPriceRule(tx.QueryRowContext(ctx, SQL, ID))
+}
+
+// ListForProduct returns the rules of a product by minimum quantity
+func (u *PriceRuleRepositoryImpl) ListForProduct(ctx context.Context, tx *sql.Tx, productID int) ([]*proto.PriceRule, error) {
+	SQL := "SELECT " + priceRuleColumns + " FROM price_rules WHERE product_id = $1 ORDER BY min_quantity ASC, customer_group_id ASC NULLS FIRST, id ASC"
+	rows, err := tx.QueryContext(ctx, SQL, productID)
+	if err != nil {
+		return nil, err
+	}
+	return scanPriceRules(rows)
+}
+
+// GetApplicable returns the rules of the given products that apply to a user: those
+// for every customer and those for the user's customer groups, keyed by product ID
+func (u *PriceRuleRepositoryImpl) GetApplicable(ctx context.Context, tx *sql.Tx, userID int, productIDs []int32) (map[int32][]*proto.PriceRule, error) {
+	SQL := `SELECT ` + priceRuleColumns + ` FROM price_rules
+			WHERE product_id = ANY($1)
+				AND (customer_group_id IS NULL
+					OR customer_group_id IN (SELECT group_id FROM customer_group_members WHERE user_id = $2))
+			ORDER BY product_id ASC, min_quantity ASC, id ASC`
+	rows, err := tx.QueryContext(ctx, SQL, productIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := scanPriceRules(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	byProduct := make(map[int32][]*proto.PriceRule)
+	for _, rule := range rules {
+		byProduct[rule.ProductId] = append(byProduct[rule.ProductId], rule)
+	}
+	return byProduct, nil
+}
+
+// GroupExists reports whether a customer group of the user service exists
+func (u *PriceRuleRepositoryImpl) GroupExists(ctx context.Context, tx *sql.Tx, groupID int) (bool, error) {
+	var exists bool
+	err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM customer_groups WHERE id = $1)", groupID).Scan(&exists)
+	return exists, err
+}
+
+func scanPriceRules(rows *sql.Rows) ([]*proto.PriceRule, error) {
+	defer rows.Close()
+
+	var rules []*proto.PriceRule
+	for rows.Next() {
+		rule, err := scanPriceRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func scanPriceRule(row rowScanner) (*proto.PriceRule, error) {
+	rule := &proto.PriceRule{}
+	var price, discount sql.NullFloat64
+	if err := row.Scan(
+		&rule.Id,
+		&rule.ProductId,
+		&rule.CustomerGroupId,
+		&rule.MinQuantity,
+		&price,
+		&discount,
+		&rule.CreatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPriceRuleNotFound
+		}
+		return nil, err
+	}
+	rule.Price = price.Float64
+	rule.DiscountPercent = discount.Float64
+	return rule, nil
+}
@@ -1,111 +1,392 @@
-package repository
-
-import (
-	"context"
-	"database/sql"
-	"errors"
-	"product_service/proto"
-)
-
-type ProductRepository interface {
-	Create(ctx context.Context, tx *sql.Tx, payload *proto.ProductPayload) error
-	GetProductByID(ctx context.Context, tx *sql.Tx, ID int) (*proto.Product, error)
-	GetAllProduct(ctx context.Context, db *sql.DB, offset int) ([]*proto.Product, int, int, error)
-	UpdateProduct(ctx context.Context, tx *sql.Tx, payload *proto.Product) error
-	DeleteProduct(ctx context.Context, tx *sql.Tx, ID int) error
-}
-
-type ProductRepositoryImpl struct{}
-
-func NewProductRepositoryImpl() *ProductRepositoryImpl {
-	return &ProductRepositoryImpl{}
-}
-
-func (u *ProductRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, payload *proto.ProductPayload) error {
-	SQL := "INSERT INTO products(name, description, price, stock) VALUES ($1, $2, $3, $4)"
-	if _, err := tx.ExecContext(ctx, SQL, payload.Name, payload.Description, payload.Price, payload.Stock); err != nil {
-		return err
-	}
-	return nil
-}
-
-func (u *ProductRepositoryImpl) GetProductByID(ctx context.Context, tx *sql.Tx, ID int) (*proto.Product, error) {
-	SQL := "SELECT id, name, description, price, stock, created_at, updated_at FROM products WHERE id = $1"
-	rows := tx.QueryRowContext(ctx, SQL, ID)
-
-	productResponse := &proto.Product{}
-	if err := rows.Scan(
-		&productResponse.Id,
-		&productResponse.Name,
-		&productResponse.Description,
-		&productResponse.Price,
-		&productResponse.Stock,
-		&productResponse.CreatedAt,
-		&productResponse.UpdatedAt,
-	); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("product not found")
-		}
-		return nil, err
-	}
-	return productResponse, nil
-}
-
-func (u *ProductRepositoryImpl) GetAllProduct(ctx context.Context, db *sql.DB, offset int) ([]*proto.Product, int, int, error) {
-	SQL := "SELECT id, name, description, price, stock, created_at, updated_at FROM products ORDER BY id ASC LIMIT 15 OFFSET $1"
-	rows, err := db.QueryContext(ctx, SQL, offset)
-	if err != nil {
-		return nil, 0, 0, err
-	}
-	defer rows.Close()
-
-	var products []*proto.Product
-	for rows.Next() {
-		productItem := &proto.Product{}
-		if err := rows.Scan(&productItem.Id, &productItem.Name, &productItem.Description, &productItem.Price, &productItem.Stock, &productItem.CreatedAt, &productItem.UpdatedAt); err != nil {
-			return nil, 0, 0, err
-		}
-		products = append(products, productItem)
-	}
-
-	var totalProduct int
-	if err := db.QueryRow("SELECT COUNT(id) FROM products").Scan(&totalProduct); err != nil {
-		return nil, 0, 0, err
-	}
-	totalPage := (totalProduct + 15 - 1) / 15
-
-	return products, totalProduct, totalPage, nil
-}
-
-func (u *ProductRepositoryImpl) UpdateProduct(ctx context.Context, tx *sql.Tx, payload *proto.Product) error {
-	SQL := `UPDATE products
-        SET name = COALESCE(NULLIF($1, ''), name),
-            description = COALESCE(NULLIF($2, ''), description),
-            price = $3,
-            stock = $4,
-            updated_at = NOW()
-        WHERE id = $5`
-	if _, err := tx.ExecContext(ctx, SQL, payload.Name, payload.Description, payload.Price, payload.Stock, payload.Id); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func (repository *ProductRepositoryImpl) DeleteProduct(ctx context.Context, tx *sql.Tx, ID int) error {
-	SQL := "DELETE FROM products WHERE id = $1"
-	result, err := tx.ExecContext(ctx, SQL, ID)
-	if err != nil {
-		return err
-	}
-
-	affected, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if affected == 0 {
-		return errors.New("product not found")
-	}
-
-	return nil
-}
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"product_service/proto"
+	"strings"
+)
+
+type ProductRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, payload *proto.ProductPayload) (int, error)
+	GetProductByID(ctx context.Context, tx *sql.Tx, ID int) (*proto.Product, error)
+	GetProductsByIDs(ctx context.Context, tx *sql.Tx, IDs []int32) ([]*proto.Product, error)
+	GetAllProduct(ctx context.Context, db *sql.DB, offset int) ([]*proto.Product, int, int, error)
+	UpdateProduct(ctx context.Context, tx *sql.Tx, payload *proto.Product) error
+	ArchiveProduct(ctx context.Context, tx *sql.Tx, ID int) error
+	UnarchiveProduct(ctx context.Context, tx *sql.Tx, ID int) error
+	PurgeProduct(ctx context.Context, tx *sql.Tx, ID int) error
+	SearchFacets(ctx context.Context, db *sql.DB, req *proto.SearchProductsRequest) ([]*proto.CategoryFacet, error)
+	SearchProducts(ctx context.Context, db *sql.DB, req *proto.SearchProductsRequest) ([]*proto.Product, int, error)
+	ExportProducts(ctx context.Context, db *sql.DB, send func(row *proto.ProductRow) error) error
+	GetIDBySlug(ctx context.Context, db *sql.DB, slug string) (int, error)
+	SlugOwner(ctx context.Context, tx *sql.Tx, slug string) (int, error)
+	ChangeSlug(ctx context.Context, tx *sql.Tx, ID int, slug string) error
+}
+
+type ProductRepositoryImpl struct{}
+
+func NewProductRepositoryImpl() *ProductRepositoryImpl {
+	return &ProductRepositoryImpl{}
+}
+
+func (u *ProductRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, payload *proto.ProductPayload) (int, error) {
+	SQL := "INSERT INTO products(name, description, price, stock, test_stock, slug) VALUES ($1, $2, $3, $4, $4, $5) RETURNING id"
+	var ID int
+	if err := tx.QueryRowContext(ctx, SQL, payload.Name, payload.Description, payload.Price, payload.Stock, payload.Slug).Scan(&ID); err != nil {
+		return 0, err
+	}
+	return ID, nil
+}
+
+const productColumns = "id, name, description, " + currentPriceSQL + " AS price, price AS list_price, stock, test_stock, rating_average::float8, rating_count, kind, slug, created_at, updated_at, archived_at"
+
+// GetProductByID also returns archived products, which old orders still refer to
+func (u *ProductRepositoryImpl) GetProductByID(ctx context.Context, tx *sql.Tx, ID int) (*proto.Product, error) {
+	SQL := "SELECT " + productColumns + " FROM products WHERE id = $1"
+	productResponse, err := scanProduct(tx.QueryRowContext(ctx, SQL, ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("product not found")
+		}
+		return nil, err
+	}
+	return productResponse, nil
+}
+
+// GetProductsByIDs loads several products, archived ones included, in one query.
+// Unknown IDs are skipped.
+func (u *ProductRepositoryImpl) GetProductsByIDs(ctx context.Context, tx *sql.Tx, IDs []int32) ([]*proto.Product, error) {
+	SQL := "SELECT " + productColumns + " FROM products WHERE id = ANY($1)"
+	rows, err := tx.QueryContext(ctx, SQL, IDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []*proto.Product
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	return products, rows.Err()
+}
+
+func scanProduct(row rowScanner) (*proto.Product, error) {
+	product := &proto.Product{}
+	var archivedAt sql.NullString
+	if err := row.Scan(
+		&product.Id,
+		&product.Name,
+		&product.Description,
+		&product.Price,
+		&product.ListPrice,
+		&product.Stock,
+		&product.TestStock,
+		&product.RatingAverage,
+		&product.RatingCount,
+		&product.Kind,
+		&product.Slug,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+		&archivedAt,
+	); err != nil {
+		return nil, err
+	}
+	product.ArchivedAt = archivedAt.String
+	return product, nil
+}
+
+func (u *ProductRepositoryImpl) GetAllProduct(ctx context.Context, db *sql.DB, offset int) ([]*proto.Product, int, int, error) {
+	SQL := "SELECT id, name, description, " + currentPriceSQL + " AS price, price AS list_price, stock, test_stock, rating_average::float8, rating_count, kind, slug, created_at, updated_at FROM products WHERE archived_at IS NULL ORDER BY id ASC LIMIT 15 OFFSET $1"
+	rows, err := db.QueryContext(ctx, SQL, offset)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer rows.Close()
+
+	var products []*proto.Product
+	for rows.Next() {
+		productItem := &proto.Product{}
+		if err := rows.Scan(&productItem.Id, &productItem.Name, &productItem.Description, &productItem.Price, &productItem.ListPrice, &productItem.Stock, &productItem.TestStock, &productItem.RatingAverage, &productItem.RatingCount, &productItem.Kind, &productItem.Slug, &productItem.CreatedAt, &productItem.UpdatedAt); err != nil {
+			return nil, 0, 0, err
+		}
+		products = append(products, productItem)
+	}
+
+	var totalProduct int
+	if err := db.QueryRow("SELECT COUNT(id) FROM products WHERE archived_at IS NULL").Scan(&totalProduct); err != nil {
+		return nil, 0, 0, err
+	}
+	totalPage := (totalProduct + 15 - 1) / 15
+
+	return products, totalProduct, totalPage, nil
+}
+
+func (u *ProductRepositoryImpl) UpdateProduct(ctx context.Context, tx *sql.Tx, payload *proto.Product) error {
+	SQL := `UPDATE products
+        SET name = COALESCE(NULLIF($1, ''), name),
+            description = COALESCE(NULLIF($2, ''), description),
+            price = $3,
+            updated_at = NOW()
+        WHERE id = $4`
+	if _, err := tx.ExecContext(ctx, SQL, payload.Name, payload.Description, payload.Price, payload.Id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ArchiveProduct hides a product from listings and search. Archiving an archived
+// product keeps its original archived_at.
+func (u *ProductRepositoryImpl) ArchiveProduct(ctx context.Context, tx *sql.Tx, ID int) error {
+	SQL := "UPDATE products SET archived_at = COALESCE(archived_at, NOW()), updated_at = NOW() WHERE id = $1"
+	return execProductUpdate(ctx, tx, SQL, ID)
+}
+
+func (u *ProductRepositoryImpl) UnarchiveProduct(ctx context.Context, tx *sql.Tx, ID int) error {
+	SQL := "UPDATE products SET archived_at = NULL, updated_at = NOW() WHERE id = $1"
+	return execProductUpdate(ctx, tx, SQL, ID)
+}
+
+// PurgeProduct deletes an archived product with its variants, images and stock history
+func (u *ProductRepositoryImpl) PurgeProduct(ctx context.Context, tx *sql.Tx, ID int) error {
+	SQL := "DELETE FROM products WHERE id = $1 AND archived_at IS NOT NULL"
+	return execProductUpdate(ctx, tx, SQL, ID)
+}
+
+func execProductUpdate(ctx context.Context, tx *sql.Tx, SQL string, ID int, args ...interface{}) error {
+	result, err := tx.ExecContext(ctx, SQL, append([]interface{}{ID}, args...)...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("product not found")
+	}
+
+	return nil
+}
+
+const slugOwnerSQL = `SELECT id FROM products WHERE slug = $1
+			UNION ALL
+			SELECT product_id FROM product_slug_history WHERE slug = $1
+			LIMIT 1`
+
+// GetIDBySlug finds the product a slug belongs to, now or in the past, archived
+// products included
+func (u *ProductRepositoryImpl) GetIDBySlug(ctx context.Context, db *sql.DB, slug string) (int, error) {
+	var ID int
+	if err := db.QueryRowContext(ctx, slugOwnerSQL, slug).Scan(&ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errors.New("product not found")
+		}
+		return 0, err
+	}
+	return ID, nil
+}
+
+// SlugOwner returns the product that uses or used a slug, or 0 when it is free
+func (u *ProductRepositoryImpl) SlugOwner(ctx context.Context, tx *sql.Tx, slug string) (int, error) {
+	var ID int
+	if err := tx.QueryRowContext(ctx, slugOwnerSQL, slug).Scan(&ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return ID, nil
+}
+
+// ChangeSlug moves a product to a new slug and keeps the current one in its history.
+// Taking back a slug the product used before removes it from the history.
+func (u *ProductRepositoryImpl) ChangeSlug(ctx context.Context, tx *sql.Tx, ID int, slug string) error {
+	SQL := `INSERT INTO product_slug_history(slug, product_id)
+			SELECT slug, id FROM products WHERE id = $1
+			ON CONFLICT (slug) DO NOTHING`
+	if _, err := tx.ExecContext(ctx, SQL, ID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM product_slug_history WHERE slug = $1 AND product_id = $2", slug, ID); err != nil {
+		return err
+	}
+
+	return execProductUpdate(ctx, tx, "UPDATE products SET slug = $2, updated_at = NOW() WHERE id = $1", ID, slug)
+}
+
+// ExportProducts walks every variant of the active catalog, handing rows to send as they are
+// read so the catalog never has to fit in memory
+func (u *ProductRepositoryImpl) ExportProducts(ctx context.Context, db *sql.DB, send func(row *proto.ProductRow) error) error {
+	SQL := `WITH default_warehouse AS (
+				SELECT id FROM warehouses WHERE is_active ORDER BY priority ASC, id ASC LIMIT 1
+			)
+			SELECT v.sku, p.id, p.name, p.description, COALESCE(v.price, p.price), COALESCE(l.stock, 0), v.attributes
+			FROM products p
+			JOIN product_variants v ON v.product_id = p.id
+			LEFT JOIN variant_stock_levels l ON l.variant_id = v.id AND l.warehouse_id = (SELECT id FROM default_warehouse)
+			WHERE p.archived_at IS NULL
+			ORDER BY p.id ASC, v.position ASC, v.id ASC`
+	rows, err := db.QueryContext(ctx, SQL)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := &proto.ProductRow{}
+		var attributes []byte
+		if err := rows.Scan(&row.Sku, &row.ProductId, &row.Name, &row.Description, &row.Price, &row.Stock, &attributes); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(attributes, &row.Attributes); err != nil {
+			return err
+		}
+		if err := send(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// SearchProducts runs a full-text search with optional price, stock and category filters.
+// Prices are filtered and sorted by what products sell for now. The request is expected
+// to be normalised by the caller.
+func (u *ProductRepositoryImpl) SearchProducts(ctx context.Context, db *sql.DB, req *proto.SearchProductsRequest) ([]*proto.Product, int, error) {
+	where, args := searchFilter(req, true)
+
+	rank := "0::real"
+	if req.Query != "" {
+		rank = "ts_rank(search_vector, websearch_to_tsquery('simple', $1))"
+	}
+
+	var orderBy string
+	switch req.Sort {
+	case "price_asc":
+		orderBy = "price ASC, id ASC"
+	case "price_desc":
+		orderBy = "price DESC, id ASC"
+	case "newest":
+		orderBy = "created_at DESC, id DESC"
+	default:
+		orderBy = "rank DESC, id ASC"
+	}
+
+	var total int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(id) FROM products "+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, req.PageSize, (req.Page-1)*req.PageSize)
+	SQL := fmt.Sprintf(`SELECT id, name, description, %s AS price, price AS list_price, stock, test_stock, rating_average::float8, rating_count, kind, slug, created_at, updated_at, %s AS rank
+			FROM products %s
+			ORDER BY %s
+			LIMIT $%d OFFSET $%d`, currentPriceSQL, rank, where, orderBy, len(args)-1, len(args))
+
+	rows, err := db.QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var products []*proto.Product
+	for rows.Next() {
+		productItem := &proto.Product{}
+		var productRank float64
+		if err := rows.Scan(&productItem.Id, &productItem.Name, &productItem.Description, &productItem.Price, &productItem.ListPrice, &productItem.Stock, &productItem.TestStock, &productItem.RatingAverage, &productItem.RatingCount, &productItem.Kind, &productItem.Slug, &productItem.CreatedAt, &productItem.UpdatedAt, &productRank); err != nil {
+			return nil, 0, err
+		}
+		products = append(products, productItem)
+	}
+
+	return products, total, rows.Err()
+}
+
+// SearchFacets counts the matching products in each category. The category filter
+// itself is left out so shoppers can see what other categories would offer.
+func (u *ProductRepositoryImpl) SearchFacets(ctx context.Context, db *sql.DB, req *proto.SearchProductsRequest) ([]*proto.CategoryFacet, error) {
+	where, args := searchFilter(req, false)
+
+	SQL := fmt.Sprintf(`SELECT c.id, c.name, c.slug, COUNT(products.id)
+			FROM products
+			JOIN product_categories pc ON pc.product_id = products.id
+			JOIN categories c ON c.id = pc.category_id
+			%s
+			GROUP BY c.id, c.name, c.slug, c.position
+			ORDER BY c.position ASC, c.name ASC`, where)
+
+	rows, err := db.QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var facets []*proto.CategoryFacet
+	for rows.Next() {
+		facet := &proto.CategoryFacet{}
+		if err := rows.Scan(&facet.CategoryId, &facet.Name, &facet.Slug, &facet.Count); err != nil {
+			return nil, err
+		}
+		facets = append(facets, facet)
+	}
+
+	return facets, rows.Err()
+}
+
+// searchFilter builds the WHERE clause of a search; the text query is always bound as $1.
+// Archived products never match.
+func searchFilter(req *proto.SearchProductsRequest, withCategory bool) (string, []interface{}) {
+	var (
+		conditions = []string{"products.archived_at IS NULL"}
+		args       []interface{}
+	)
+
+	if req.Query != "" {
+		args = append(args, req.Query)
+		conditions = append(conditions, "products.search_vector @@ websearch_to_tsquery('simple', $1)")
+	}
+	if req.MinPrice > 0 {
+		args = append(args, req.MinPrice)
+		conditions = append(conditions, fmt.Sprintf("%s >= $%d", currentPriceSQL, len(args)))
+	}
+	if req.MaxPrice > 0 {
+		args = append(args, req.MaxPrice)
+		conditions = append(conditions, fmt.Sprintf("%s <= $%d", currentPriceSQL, len(args)))
+	}
+	if req.InStock {
+		conditions = append(conditions, inStockSQL("products"))
+	}
+	if withCategory && req.CategoryId > 0 {
+		args = append(args, req.CategoryId)
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+				SELECT 1 FROM product_categories pc
+				WHERE pc.product_id = products.id AND pc.category_id IN (
+					WITH RECURSIVE tree AS (
+						SELECT id FROM categories WHERE id = $%d
+						UNION ALL
+						SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id
+					)
+					SELECT id FROM tree
+				))`, len(args)))
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// inStockSQL is the condition for a product of table to count as in stock: stock in
+// any warehouse or variant rolls up into products.stock, and digital products hold
+// none
+func inStockSQL(table string) string {
+	return fmt.Sprintf("(%[1]s.stock > 0 OR %[1]s.kind = 'digital')", table)
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"product_service/proto"
+	"strings"
+)
+
+var (
+	ErrSupplierNotFound      = errors.New("supplier not found")
+	ErrPurchaseOrderNotFound = errors.New("purchase order not found")
+)
+
+type PurchaseOrderRepository interface {
+	CreateSupplier(ctx context.Context, tx *sql.Tx, supplier *proto.Supplier) (*proto.Supplier, error)
+	GetSupplier(ctx context.Context, tx *sql.Tx, ID int) (*proto.Supplier, error)
+	ListSuppliers(ctx context.Context, tx *sql.Tx) ([]*proto.Supplier, error)
+	Create(ctx context.Context, tx *sql.Tx, order *proto.PurchaseOrder) (int, error)
+	AddLine(ctx context.Context, tx *sql.Tx, orderID int, line *proto.PurchaseOrderLine) error
+	GetByID(ctx context.Context, tx *sql.Tx, ID int) (*proto.PurchaseOrder, error)
+	Lock(ctx context.Context, tx *sql.Tx, ID int) (*proto.PurchaseOrder, error)
+	GetLines(ctx context.Context, tx *sql.Tx, orderID int) ([]*proto.PurchaseOrderLine, error)
+	List(ctx context.Context, tx *sql.Tx, req *proto.ListPurchaseOrdersRequest, limit int, offset int) ([]*proto.PurchaseOrder, int, error)
+	MarkSent(ctx context.Context, tx *sql.Tx, ID int) error
+	AddReceived(ctx context.Context, tx *sql.Tx, lineID int, quantity int32) error
+	SetStatus(ctx context.Context, tx *sql.Tx, ID int, status string) error
+}
+
+type PurchaseOrderRepositoryImpl struct{}
+
+func NewPurchaseOrderRepositoryImpl() *PurchaseOrderRepositoryImpl {
+	return &PurchaseOrderRepositoryImpl{}
+}
+
+const supplierColumns = `id, name, email, phone, lead_time_days, created_at`
+
+func (u *PurchaseOrderRepositoryImpl) CreateSupplier(ctx context.Context, tx *sql.Tx, supplier *proto.Supplier) (*proto.Supplier, error) {
+	SQL := `INSERT INTO suppliers(name, email, phone, lead_time_days)
+			VALUES ($1, $2, $3, $4)
+			RETURNING ` + supplierColumns
+	return scanSupplier(tx.QueryRowContext(ctx, SQL, supplier.Name, supplier.Email, supplier.Phone, supplier.LeadTimeDays))
+}
+
+func (u *PurchaseOrderRepositoryImpl) GetSupplier(ctx context.Context, tx *sql.Tx, ID int) (*proto.Supplier, error) {
+	SQL := "SELECT " + supplierColumns + " FROM suppliers WHERE id = $1"
+	return scanSupplier(tx.QueryRowContext(ctx, SQL, ID))
+}
+
+func (u *PurchaseOrderRepositoryImpl) ListSuppliers(ctx context.Context, tx *sql.Tx) ([]*proto.Supplier, error) {
+	SQL := "SELECT " + supplierColumns + " FROM suppliers ORDER BY name ASC"
+	rows, err := tx.QueryContext(ctx, SQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var suppliers []*proto.Supplier
+	for rows.Next() {
+		supplier, err := scanSupplier(rows)
+		if err != nil {
+			return nil, err
+		}
+		suppliers = append(suppliers, supplier)
+	}
+	return suppliers, rows.Err()
+}
+
+func scanSupplier(row rowScanner) (*proto.Supplier, error) {
+	supplier := &proto.Supplier{}
+	if err := row.Scan(
+		&supplier.Id,
+		&supplier.Name,
+		&supplier.Email,
+		&supplier.Phone,
+		&supplier.LeadTimeDays,
+		&supplier.CreatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSupplierNotFound
+		}
+		return nil, err
+	}
+	return supplier, nil
+}
+
+// Create saves a draft purchase order without its lines. The expected arrival is
+// bound as RFC 3339, like scheduled prices.
+func (u *PurchaseOrderRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, order *proto.PurchaseOrder) (int, error) {
+	SQL := `INSERT INTO purchase_orders(supplier_id, warehouse_id, expected_at, freight_cost, duty_cost, note, created_by)
+			VALUES ($1, $2, NULLIF($3, '')::timestamptz, $4, $5, $6, $7)
+			RETURNING id`
+	var ID int
+	err := tx.QueryRowContext(ctx, SQL,
+		order.SupplierId,
+		order.WarehouseId,
+		order.ExpectedAt,
+		order.FreightCost,
+		order.DutyCost,
+		order.Note,
+		order.CreatedBy,
+	).Scan(&ID)
+	return ID, err
+}
+
+func (u *PurchaseOrderRepositoryImpl) AddLine(ctx context.Context, tx *sql.Tx, orderID int, line *proto.PurchaseOrderLine) error {
+	SQL := `INSERT INTO purchase_order_lines(purchase_order_id, variant_id, sku, quantity_ordered, unit_cost)
+			VALUES ($1, $2, $3, $4, $5)`
+	_, err := tx.ExecContext(ctx, SQL, orderID, line.VariantId, line.Sku, line.QuantityOrdered, line.UnitCost)
+	return err
+}
+
+const purchaseOrderSQL = `SELECT po.id, po.supplier_id, s.name, po.warehouse_id, po.status, po.expected_at,
+				po.freight_cost, po.duty_cost, po.note, po.created_by, po.sent_at, po.received_at,
+				po.created_at, po.updated_at
+			FROM purchase_orders po
+			JOIN suppliers s ON s.id = po.supplier_id`
+
+func (u *PurchaseOrderRepositoryImpl) GetByID(ctx context.Context, tx *sql.Tx, ID int) (*proto.PurchaseOrder, error) {
+	return scanPurchaseOrder(tx.QueryRowContext(ctx, purchaseOrderSQL+" WHERE po.id = $1", ID))
+}
+
+// Lock returns a purchase order like GetByID, locked until tx ends
+func (u *PurchaseOrderRepositoryImpl) Lock(ctx context.Context, tx *sql.Tx, ID int) (*proto.PurchaseOrder, error) {
+	return scanPurchaseOrder(tx.QueryRowContext(ctx, purchaseOrderSQL+" WHERE po.id = $1 FOR UPDATE OF po", ID))
+}
+
+// GetLines returns the lines of a purchase order in the order they were added
+func (u *PurchaseOrderRepositoryImpl) GetLines(ctx context.Context, tx *sql.Tx, orderID int) ([]*proto.PurchaseOrderLine, error) {
+	SQL := `SELECT l.id, COALESCE(l.variant_id, 0), COALESCE(v.product_id, 0), l.sku, l.quantity_ordered, l.quantity_received, l.unit_cost
+			FROM purchase_order_lines l
+			LEFT JOIN product_variants v ON v.id = l.variant_id
+			WHERE l.purchase_order_id = $1
+			ORDER BY l.id ASC`
+	rows, err := tx.QueryContext(ctx, SQL, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []*proto.PurchaseOrderLine
+	for rows.Next() {
+		line := &proto.PurchaseOrderLine{}
+		if err := rows.Scan(&line.Id, &line.VariantId, &line.ProductId, &line.Sku, &line.QuantityOrdered, &line.QuantityReceived, &line.UnitCost); err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
+
+// List returns a page of purchase orders, newest first, with the total that match
+func (u *PurchaseOrderRepositoryImpl) List(ctx context.Context, tx *sql.Tx, req *proto.ListPurchaseOrdersRequest, limit int, offset int) ([]*proto.PurchaseOrder, int, error) {
+	var conditions []string
+	var args []interface{}
+	if req.Status != "" {
+		args = append(args, req.Status)
+		conditions = append(conditions, fmt.Sprintf("po.status = $%d", len(args)))
+	}
+	if req.SupplierId != 0 {
+		args = append(args, req.SupplierId)
+		conditions = append(conditions, fmt.Sprintf("po.supplier_id = $%d", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM purchase_orders po"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, limit, offset)
+	SQL := fmt.Sprintf("%s%s ORDER BY po.created_at DESC, po.id DESC LIMIT $%d OFFSET $%d", purchaseOrderSQL, where, len(args)-1, len(args))
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var orders []*proto.PurchaseOrder
+	for rows.Next() {
+		order, err := scanPurchaseOrder(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, total, rows.Err()
+}
+
+// MarkSent marks a purchase order sent now. Without an expected arrival it is
+// expected after its supplier's lead time.
+func (u *PurchaseOrderRepositoryImpl) MarkSent(ctx context.Context, tx *sql.Tx, ID int) error {
+	SQL := `UPDATE purchase_orders po
+			SET status = 'sent', sent_at = NOW(), updated_at = NOW(),
+				expected_at = COALESCE(po.expected_at, NOW() + make_interval(days => s.lead_time_days))
+			FROM suppliers s
+			WHERE s.id = po.supplier_id AND po.id = $1`
+	_, err := tx.ExecContext(ctx, SQL, ID)
+	return err
+}
+
+// AddReceived counts quantity more of a line as received; more than was ordered is
+// refused by the database
+func (u *PurchaseOrderRepositoryImpl) AddReceived(ctx context.Context, tx *sql.Tx, lineID int, quantity int32) error {
+	SQL := "UPDATE purchase_order_lines SET quantity_received = quantity_received + $1 WHERE id = $2"
+	_, err := tx.ExecContext(ctx, SQL, quantity, lineID)
+	return err
+}
+
+// SetStatus moves a purchase order to status, recording when it was fully received
+func (u *PurchaseOrderRepositoryImpl) SetStatus(ctx context.Context, tx *sql.Tx, ID int, status string) error {
+	SQL := `UPDATE purchase_orders
+			SET status = $1, updated_at = NOW(),
+				received_at = CASE WHEN $1 = 'received' THEN NOW() ELSE received_at END
+			WHERE id = $2`
+	_, err := tx.ExecContext(ctx, SQL, status, ID)
+	return err
+}
+
+func scanPurchaseOrder(row rowScanner) (*proto.PurchaseOrder, error) {
+	order := &proto.PurchaseOrder{}
+	var expectedAt, sentAt, receivedAt sql.NullString
+	if err := row.Scan(
+		&order.Id,
+		&order.SupplierId,
+		&order.SupplierName,
+		&order.WarehouseId,
+		&order.Status,
+		&expectedAt,
+		&order.FreightCost,
+		&order.DutyCost,
+		&order.Note,
+		&order.CreatedBy,
+		&sentAt,
+		&receivedAt,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPurchaseOrderNotFound
+		}
+		return nil, err
+	}
+	order.ExpectedAt = expectedAt.String
+	order.SentAt = sentAt.String
+	order.ReceivedAt = receivedAt.String
+	return order, nil
+}
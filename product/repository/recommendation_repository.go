@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// RankedProduct is a product with the number of paid orders it is recommended by
+type RankedProduct struct {
+	ProductID  int32
+	OrderCount int32
+}
+
+type RecommendationRepository interface {
+	ClaimOrder(ctx context.Context, tx *sql.Tx, orderID int) (bool, error)
+	AddCopurchases(ctx context.Context, tx *sql.Tx, productIDs []int32) error
+	AddPopularity(ctx context.Context, tx *sql.Tx, productIDs []int32) error
+	GetCopurchased(ctx context.Context, tx *sql.Tx, productID int, limit int) ([]*RankedProduct, error)
+	GetPopular(ctx context.Context, tx *sql.Tx, excludeIDs []int32, limit int) ([]*RankedProduct, error)
+}
+
+type RecommendationRepositoryImpl struct{}
+
+func NewRecommendationRepositoryImpl() *RecommendationRepositoryImpl {
+	return &RecommendationRepositoryImpl{}
+}
+
+// ClaimOrder marks a paid order as counted, and returns false when it already was,
+// as when its event is delivered again
+func (u *RecommendationRepositoryImpl) ClaimOrder(ctx context.Context, tx *sql.Tx, orderID int) (bool, error) {
+	SQL := "INSERT INTO recommendation_orders(order_id) VALUES ($1) ON CONFLICT (order_id) DO NOTHING"
+	result, err := tx.ExecContext(ctx, SQL, orderID)
+	if err != nil {
+		return false, err
+	}
+	claimed, err := result.RowsAffected()
+	return claimed == 1, err
+}
+
+// orderProducts lists each of the given products of an order once, leaving out
+// products that no longer exist
+const orderProducts = `SELECT p.id AS product_id FROM products p WHERE p.id = ANY($1)`
+
+// AddCopurchases counts every pair of products bought in one order, in both
+// directions. Rows are written in key order so orders counted at the same time
+// do not deadlock.
+func (u *RecommendationRepositoryImpl) AddCopurchases(ctx context.Context, tx *sql.Tx, productIDs []int32) error {
+	SQL := `WITH lines AS (` + orderProducts + `)
+			INSERT INTO product_copurchases(product_id, related_product_id, order_count)
+			SELECT a.product_id, b.product_id, 1
+			FROM lines a
+			JOIN lines b ON b.product_id <> a.product_id
+			ORDER BY a.product_id, b.product_id
+			ON CONFLICT (product_id, related_product_id) DO UPDATE
+			SET order_count = product_copurchases.order_count + EXCLUDED.order_count, updated_at = NOW()`
+	_, err := tx.ExecContext(ctx, SQL, productIDs)
+	return err
+}
+
+// AddPopularity counts one more order for every product bought in it
+func (u *RecommendationRepositoryImpl) AddPopularity(ctx context.Context, tx *sql.Tx, productIDs []int32) error {
+	SQL := `WITH lines AS (` + orderProducts + `)
+			INSERT INTO product_popularity(product_id, order_count)
+			SELECT product_id, 1 FROM lines
+			ORDER BY product_id
+			ON CONFLICT (product_id) DO UPDATE
+			SET order_count = product_popularity.order_count + EXCLUDED.order_count, updated_at = NOW()`
+	_, err := tx.ExecContext(ctx, SQL, productIDs)
+	return err
+}
+
+// GetCopurchased returns the products most often bought with a product that are in
+// stock and not archived
+func (u *RecommendationRepositoryImpl) GetCopurchased(ctx context.Context, tx *sql.Tx, productID int, limit int) ([]*RankedProduct, error) {
+	SQL := `SELECT c.related_product_id, c.order_count FROM product_copurchases c
+			JOIN products p ON p.id = c.related_product_id
+			WHERE c.product_id = $1 AND p.archived_at IS NULL AND ` + inStockSQL("p") + `
+			ORDER BY c.order_count DESC, c.related_product_id ASC
+			LIMIT $2`
+	return queryRanked(ctx, tx, SQL, productID, limit)
+}
+
+// GetPopular returns the products bought in the most orders that are in stock, not
+// archived and not among excludeIDs
+func (u *RecommendationRepositoryImpl) GetPopular(ctx context.Context, tx *sql.Tx, excludeIDs []int32, limit int) ([]*RankedProduct, error) {
+	SQL := `SELECT pp.product_id, pp.order_count FROM product_popularity pp
+			JOIN products p ON p.id = pp.product_id
+			WHERE NOT (pp.product_id = ANY($1)) AND p.archived_at IS NULL AND ` + inStockSQL("p") + `
+			ORDER BY pp.order_count DESC, pp.product_id ASC
+			LIMIT $2`
+	return queryRanked(ctx, tx, SQL, excludeIDs, limit)
+}
+
+func queryRanked(ctx context.Context, tx *sql.Tx, SQL string, args ...interface{}) ([]*RankedProduct, error) {
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ranked []*RankedProduct
+	for rows.Next() {
+		product := &RankedProduct{}
+		if err := rows.Scan(&product.ProductID, &product.OrderCount); err != nil {
+			return nil, err
+		}
+		ranked = append(ranked, product)
+	}
+	return ranked, rows.Err()
+}
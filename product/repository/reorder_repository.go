@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"product_service/proto"
+)
+
+type ReorderRepository interface {
+	SetThreshold(ctx context.Context, tx *sql.Tx, productID int, threshold int32) error
+	GetThreshold(ctx context.Context, tx *sql.Tx, productID int) (int32, error)
+	GetSales(ctx context.Context, db *sql.DB, windowDays int32) ([]*proto.ReorderSuggestion, error)
+}
+
+type ReorderRepositoryImpl struct{}
+
+func NewReorderRepositoryImpl() *ReorderRepositoryImpl {
+	return &ReorderRepositoryImpl{}
+}
+
+func (u *ReorderRepositoryImpl) SetThreshold(ctx context.Context, tx *sql.Tx, productID int, threshold int32) error {
+	SQL := "UPDATE products SET reorder_threshold = $1, updated_at = NOW() WHERE id = $2"
+	_, err := tx.ExecContext(ctx, SQL, threshold, productID)
+	return err
+}
+
+func (u *ReorderRepositoryImpl) GetThreshold(ctx context.Context, tx *sql.Tx, productID int) (int32, error) {
+	var threshold int32
+	err := tx.QueryRowContext(ctx, "SELECT reorder_threshold FROM products WHERE id = $1", productID).Scan(&threshold)
+	return threshold, err
+}
+
+// GetSales returns the live stock, threshold and units sold over the last windowDays
+// of every standard product that has a threshold or sold in that time. Units are
+// counted from the live sales in the stock ledger, which orders record when they
+// are placed whether or not they are paid, net of the stock orders handed back when
+// they were not saved or returned; bundles are left out, since what they
+// sell is taken from their components' stock.
+func (u *ReorderRepositoryImpl) GetSales(ctx context.Context, db *sql.DB, windowDays int32) ([]*proto.ReorderSuggestion, error) {
+	SQL := `SELECT p.id, p.name, p.stock, p.reorder_threshold, COALESCE(s.units, 0)
+			FROM products p
+			LEFT JOIN (
+				SELECT product_id, GREATEST(-SUM(delta), 0) AS units
+				FROM inventory_movements
+				WHERE livemode AND reason IN ('sale', 'reservation_release', 'return')
+					AND created_at >= NOW() - make_interval(days => $1)
+				GROUP BY product_id
+			) s ON s.product_id = p.id
+			WHERE p.kind = 'standard' AND p.archived_at IS NULL
+				AND (p.reorder_threshold > 0 OR s.units > 0)
+			ORDER BY p.id ASC`
+	rows, err := db.QueryContext(ctx, SQL, windowDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sales []*proto.ReorderSuggestion
+	for rows.Next() {
+		line := &proto.ReorderSuggestion{}
+		if err := rows.Scan(&line.ProductId, &line.Name, &line.Stock, &line.ReorderThreshold, &line.UnitsSold); err != nil {
+			return nil, err
+		}
+		sales = append(sales, line)
+	}
+	return sales, rows.Err()
+}
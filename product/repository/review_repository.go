@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"product_service/proto"
+)
+
+var (
+	ErrReviewNotFound = errors.New("review not found")
+	ErrReviewExists   = errors.New("product already reviewed by this user")
+)
+
+type ReviewRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, review *proto.ProductReview) (*proto.ProductReview, error)
+	AddImage(ctx context.Context, tx *sql.Tx, image *proto.ReviewImage) (*proto.ReviewImage, error)
+	GetByID(ctx context.Context, tx *sql.Tx, ID int) (*proto.ProductReview, error)
+	Delete(ctx context.Context, tx *sql.Tx, ID int) error
+	SetStatus(ctx context.Context, tx *sql.Tx, ID int, status string, note string) (*proto.ProductReview, error)
+	List(ctx context.Context, tx *sql.Tx, productID int, status string, limit int, offset int) ([]*proto.ProductReview, int, error)
+	GetImagesForReviews(ctx context.Context, tx *sql.Tx, reviewIDs []int32) ([]*proto.ReviewImage, error)
+	RefreshRating(ctx context.Context, tx *sql.Tx, productID int) error
+}
+
+type ReviewRepositoryImpl struct{}
+
+func NewReviewRepositoryImpl() *ReviewRepositoryImpl {
+	return &ReviewRepositoryImpl{}
+}
+
+const (
+	reviewColumns      = `id, product_id, user_id, rating, body, status, moderation_note, created_at, updated_at`
+	reviewImageColumns = `id, review_id, storage_key, thumbnail_key, content_type, size_bytes, width, height`
+)
+
+// Create saves a pending review, or returns ErrReviewExists when the user has
+// already reviewed the product
+func (u *ReviewRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, review *proto.ProductReview) (*proto.ProductReview, error) {
+	SQL := `INSERT INTO product_reviews(product_id, user_id, rating, body)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (product_id, user_id) DO NOTHING
+			RETURNING ` + reviewColumns
+	created, err := scanReview(tx.QueryRowContext(ctx, SQL, review.ProductId, review.UserId, review.Rating, review.Body))
+	if errors.Is(err, ErrReviewNotFound) {
+		return nil, ErrReviewExists
+	}
+	return created, err
+}
+
+func (u *ReviewRepositoryImpl) AddImage(ctx context.Context, tx *sql.Tx, image *proto.ReviewImage) (*proto.ReviewImage, error) {
+	SQL := `INSERT INTO product_review_images(review_id, storage_key, thumbnail_key, content_type, size_bytes, width, height)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING ` + reviewImageColumns
+
+	created := &proto.ReviewImage{}
+	err := tx.QueryRowContext(ctx, SQL,
+		image.ReviewId,
+		image.StorageKey,
+		image.ThumbnailKey,
+		image.ContentType,
+		image.SizeBytes,
+		image.Width,
+		image.Height,
+	).Scan(&created.Id, &created.ReviewId, &created.StorageKey, &created.ThumbnailKey, &created.ContentType, &created.SizeBytes, &created.Width, &created.Height)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (u *ReviewRepositoryImpl) GetByID(ctx context.Context, tx *sql.Tx, ID int) (*proto.ProductReview, error) {
+	SQL := "SELECT " + reviewColumns + " FROM product_reviews WHERE id = $1"
+	return scanReview(tx.QueryRowContext(ctx, SQL, ID))
+}
+
+// Delete removes a review with its images
+func (u *ReviewRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, ID int) error {
+	result, err := tx.ExecContext(ctx, "DELETE FROM product_reviews WHERE id = $1", ID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrReviewNotFound
+	}
+
+	return nil
+}
+
+func (u *ReviewRepositoryImpl) SetStatus(ctx context.Context, tx *sql.Tx, ID int, status string, note string) (*proto.ProductReview, error) {
+	SQL := `UPDATE product_reviews SET status = $1, moderation_note = $2, updated_at = NOW()
+			WHERE id = $3
+			RETURNING ` + reviewColumns
+	return scanReview(tx.QueryRowContext(ctx, SQL, status, note, ID))
+}
+
+// List returns a page of reviews in a status, newest first, with the total count.
+// A productID of 0 lists every product's reviews.
+func (u *ReviewRepositoryImpl) List(ctx context.Context, tx *sql.Tx, productID int, status string, limit int, offset int) ([]*proto.ProductReview, int, error) {
+	where := "WHERE status = $1 AND ($2 = 0 OR product_id = $2)"
+
+	var total int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(id) FROM product_reviews "+where, status, productID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	SQL := "SELECT " + reviewColumns + " FROM product_reviews " + where + " ORDER BY created_at DESC, id DESC LIMIT $3 OFFSET $4"
+	rows, err := tx.QueryContext(ctx, SQL, status, productID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var reviews []*proto.ProductReview
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, total, rows.Err()
+}
+
+// GetImagesForReviews loads the images of several reviews in one query
+func (u *ReviewRepositoryImpl) GetImagesForReviews(ctx context.Context, tx *sql.Tx, reviewIDs []int32) ([]*proto.ReviewImage, error) {
+	SQL := "SELECT " + reviewImageColumns + " FROM product_review_images WHERE review_id = ANY($1) ORDER BY review_id ASC, id ASC"
+	rows, err := tx.QueryContext(ctx, SQL, reviewIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var images []*proto.ReviewImage
+	for rows.Next() {
+		image := &proto.ReviewImage{}
+		if err := rows.Scan(&image.Id, &image.ReviewId, &image.StorageKey, &image.ThumbnailKey, &image.ContentType, &image.SizeBytes, &image.Width, &image.Height); err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	}
+	return images, rows.Err()
+}
+
+// RefreshRating recomputes a product's rating from its approved reviews
+func (u *ReviewRepositoryImpl) RefreshRating(ctx context.Context, tx *sql.Tx, productID int) error {
+	SQL := `UPDATE products
+			SET rating_average = COALESCE(r.average, 0),
+				rating_count = r.count
+			FROM (
+				SELECT ROUND(AVG(rating), 2) AS average, COUNT(id) AS count
+				FROM product_reviews
+				WHERE product_id = $1 AND status = 'approved'
+			) r
+			WHERE products.id = $1`
+	_, err := tx.ExecContext(ctx, SQL, productID)
+	return err
+}
+
+func scanReview(row rowScanner) (*proto.ProductReview, error) {
+	review := &proto.ProductReview{}
+	if err := row.Scan(
+		&review.Id,
+		&review.ProductId,
+		&review.UserId,
+		&review.Rating,
+		&review.Body,
+		&review.Status,
+		&review.ModerationNote,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, err
+	}
+	return review, nil
+}
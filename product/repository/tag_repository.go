@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"product_service/proto"
+)
+
+type TagRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, tag *proto.Tag) (*proto.Tag, error)
+	Delete(ctx context.Context, tx *sql.Tx, ID int) error
+	GetAll(ctx context.Context, db *sql.DB) ([]*proto.Tag, error)
+	SetProductTags(ctx context.Context, tx *sql.Tx, productID int, tags []*proto.Tag) error
+	GetProductTags(ctx context.Context, tx *sql.Tx, productID int) ([]*proto.Tag, error)
+	GetTagsForProducts(ctx context.Context, tx *sql.Tx, productIDs []int32) (map[int32][]*proto.Tag, error)
+}
+
+type TagRepositoryImpl struct{}
+
+func NewTagRepositoryImpl() *TagRepositoryImpl {
+	return &TagRepositoryImpl{}
+}
+
+// Create inserts a tag, returning the existing one when the slug is already taken
+func (u *TagRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, tag *proto.Tag) (*proto.Tag, error) {
+	SQL := `INSERT INTO tags(name, slug) VALUES ($1, $2)
+			ON CONFLICT (slug) DO UPDATE SET slug = EXCLUDED.slug
+			RETURNING id, name, slug`
+
+	result := &proto.Tag{}
+	if err := tx.QueryRowContext(ctx, SQL, tag.Name, tag.Slug).Scan(&result.Id, &result.Name, &result.Slug); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (u *TagRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, ID int) error {
+	result, err := tx.ExecContext(ctx, "DELETE FROM tags WHERE id = $1", ID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("tag not found")
+	}
+
+	return nil
+}
+
+func (u *TagRepositoryImpl) GetAll(ctx context.Context, db *sql.DB) ([]*proto.Tag, error) {
+	rows, err := db.QueryContext(ctx, "SELECT id, name, slug FROM tags ORDER BY name ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTags(rows)
+}
+
+// SetProductTags replaces the tags of a product, creating tags that don't exist yet
+func (u *TagRepositoryImpl) SetProductTags(ctx context.Context, tx *sql.Tx, productID int, tags []*proto.Tag) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM product_tags WHERE product_id = $1", productID); err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		saved, err := u.Create(ctx, tx, tag)
+		if err != nil {
+			return err
+		}
+
+		SQL := "INSERT INTO product_tags(product_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+		if _, err := tx.ExecContext(ctx, SQL, productID, saved.Id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u *TagRepositoryImpl) GetProductTags(ctx context.Context, tx *sql.Tx, productID int) ([]*proto.Tag, error) {
+	SQL := `SELECT t.id, t.name, t.slug
+			FROM tags t JOIN product_tags pt ON pt.tag_id = t.id
+			WHERE pt.product_id = $1
+			ORDER BY t.name ASC`
+	rows, err := tx.QueryContext(ctx, SQL, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTags(rows)
+}
+
+// GetTagsForProducts loads the tags of several products in one query, keyed by product ID
+func (u *TagRepositoryImpl) GetTagsForProducts(ctx context.Context, tx *sql.Tx, productIDs []int32) (map[int32][]*proto.Tag, error) {
+	SQL := `SELECT pt.product_id, t.id, t.name, t.slug
+			FROM tags t JOIN product_tags pt ON pt.tag_id = t.id
+			WHERE pt.product_id = ANY($1)
+			ORDER BY t.name ASC`
+	rows, err := tx.QueryContext(ctx, SQL, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make(map[int32][]*proto.Tag)
+	for rows.Next() {
+		var productID int32
+		tag := &proto.Tag{}
+		if err := rows.Scan(&productID, &tag.Id, &tag.Name, &tag.Slug); err != nil {
+			return nil, err
+		}
+		tags[productID] = append(tags[productID], tag)
+	}
+	return tags, rows.Err()
+}
+
+func scanTags(rows *sql.Rows) ([]*proto.Tag, error) {
+	var tags []*proto.Tag
+	for rows.Next() {
+		tag := &proto.Tag{}
+		if err := rows.Scan(&tag.Id, &tag.Name, &tag.Slug); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"product_service/proto"
+)
+
+var ErrTranslationNotFound = errors.New("translation not found")
+
+type TranslationRepository interface {
+	Upsert(ctx context.Context, tx *sql.Tx, translation *proto.ProductTranslation) (*proto.ProductTranslation, error)
+	Delete(ctx context.Context, tx *sql.Tx, productID int, locale string) error
+	ListForProduct(ctx context.Context, db *sql.DB, productID int) ([]*proto.ProductTranslation, error)
+	GetForProducts(ctx context.Context, db *sql.DB, productIDs []int32, locales []string) (map[int32]map[string]*proto.ProductTranslation, error)
+}
+
+type TranslationRepositoryImpl struct{}
+
+func NewTranslationRepositoryImpl() *TranslationRepositoryImpl {
+	return &TranslationRepositoryImpl{}
+}
+
+const translationColumns = "product_id, locale, name, description, created_at, updated_at"
+
+// Upsert creates the translation of a product for a locale or replaces the existing one
+func (u *TranslationRepositoryImpl) Upsert(ctx context.Context, tx *sql.Tx, translation *proto.ProductTranslation) (*proto.ProductTranslation, error) {
+	SQL := `INSERT INTO product_translations(product_id, locale, name, description)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (product_id, locale) DO UPDATE
+			SET name = EXCLUDED.name,
+				description = EXCLUDED.description,
+				updated_at = NOW()
+			RETURNING ` + translationColumns
+	return scanTranslation(tx.QueryRowContext(ctx, SQL, translation.ProductId, translation.Locale, translation.Name, translation.Description))
+}
+
+func (u *TranslationRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, productID int, locale string) error {
+	result, err := tx.ExecContext(ctx, "DELETE FROM product_translations WHERE product_id = $1 AND locale = $2", productID, locale)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrTranslationNotFound
+	}
+	return nil
+}
+
+func (u *TranslationRepositoryImpl) ListForProduct(ctx context.Context, db *sql.DB, productID int) ([]*proto.ProductTranslation, error) {
+	SQL := "SELECT " + translationColumns + " FROM product_translations WHERE product_id = $1 ORDER BY locale ASC"
+	rows, err := db.QueryContext(ctx, SQL, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var translations []*proto.ProductTranslation
+	for rows.Next() {
+		translation, err := scanTranslation(rows)
+		if err != nil {
+			return nil, err
+		}
+		translations = append(translations, translation)
+	}
+	return translations, rows.Err()
+}
+
+// GetForProducts returns the translations of the given products in any of the given
+// locales, keyed by product ID and then locale
+func (u *TranslationRepositoryImpl) GetForProducts(ctx context.Context, db *sql.DB, productIDs []int32, locales []string) (map[int32]map[string]*proto.ProductTranslation, error) {
+	SQL := "SELECT " + translationColumns + " FROM product_translations WHERE product_id = ANY($1) AND locale = ANY($2)"
+	rows, err := db.QueryContext(ctx, SQL, productIDs, locales)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byProduct := make(map[int32]map[string]*proto.ProductTranslation)
+	for rows.Next() {
+		translation, err := scanTranslation(rows)
+		if err != nil {
+			return nil, err
+		}
+		if byProduct[translation.ProductId] == nil {
+			byProduct[translation.ProductId] = make(map[string]*proto.ProductTranslation)
+		}
+		byProduct[translation.ProductId][translation.Locale] = translation
+	}
+	return byProduct, rows.Err()
+}
+
+func scanTranslation(row rowScanner) (*proto.ProductTranslation, error) {
+	translation := &proto.ProductTranslation{}
+	if err := row.Scan(
+		&translation.ProductId,
+		&translation.Locale,
+		&translation.Name,
+		&translation.Description,
+		&translation.CreatedAt,
+		&translation.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTranslationNotFound
+		}
+		return nil, err
+	}
+	return translation, nil
+}
@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"product_service/proto"
+)
+
+var (
+	ErrVariantNotFound   = errors.New("variant not found")
+	ErrInsufficientStock = errors.New("stock is not enough")
+)
+
+type VariantRepository interface {
+	SetOptions(ctx context.Context, tx *sql.Tx, productID int, options []*proto.ProductOption) error
+	GetOptions(ctx context.Context, tx *sql.Tx, productID int) ([]*proto.ProductOption, error)
+	Create(ctx context.Context, tx *sql.Tx, variant *proto.ProductVariant) (*proto.ProductVariant, error)
+	Update(ctx context.Context, tx *sql.Tx, variant *proto.ProductVariant) (*proto.ProductVariant, error)
+	Delete(ctx context.Context, tx *sql.Tx, ID int) error
+	GetByID(ctx context.Context, tx *sql.Tx, ID int) (*proto.ProductVariant, error)
+	GetBySKU(ctx context.Context, tx *sql.Tx, sku string) (*proto.ProductVariant, error)
+	GetProductVariants(ctx context.Context, tx *sql.Tx, productID int) ([]*proto.ProductVariant, error)
+	GetOptionsForProducts(ctx context.Context, tx *sql.Tx, productIDs []int32) ([]*proto.ProductOption, error)
+	GetVariantsForProducts(ctx context.Context, tx *sql.Tx, productIDs []int32) ([]*proto.ProductVariant, error)
+	SyncVariantStock(ctx context.Context, tx *sql.Tx, ID int) (*proto.ProductVariant, error)
+	SyncProductStock(ctx context.Context, tx *sql.Tx, productID int) (int32, int32, error)
+}
+
+type VariantRepositoryImpl struct{}
+
+func NewVariantRepositoryImpl() *VariantRepositoryImpl {
+	return &VariantRepositoryImpl{}
+}
+
+const variantColumns = `id, product_id, sku, COALESCE(price, 0), stock, test_stock, attributes, is_default, position, created_at, updated_at`
+
+// SetOptions replaces the option types of a product
+func (u *VariantRepositoryImpl) SetOptions(ctx context.Context, tx *sql.Tx, productID int, options []*proto.ProductOption) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM product_options WHERE product_id = $1", productID); err != nil {
+		return err
+	}
+
+	SQL := `INSERT INTO product_options(product_id, name, "values", position) VALUES ($1, $2, $3, $4)`
+	for _, option := range options {
+		if _, err := tx.ExecContext(ctx, SQL, productID, option.Name, option.Values, option.Position); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u *VariantRepositoryImpl) GetOptions(ctx context.Context, tx *sql.Tx, productID int) ([]*proto.ProductOption, error) {
+	SQL := `SELECT id, product_id, name, array_to_json("values"), position FROM product_options
+			WHERE product_id = $1
+			ORDER BY position ASC, id ASC`
+	rows, err := tx.QueryContext(ctx, SQL, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var options []*proto.ProductOption
+	for rows.Next() {
+		option := &proto.ProductOption{}
+		var values []byte
+		if err := rows.Scan(&option.Id, &option.ProductId, &option.Name, &values, &option.Position); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(values, &option.Values); err != nil {
+			return nil, err
+		}
+		options = append(options, option)
+	}
+	return options, rows.Err()
+}
+
+func (u *VariantRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, variant *proto.ProductVariant) (*proto.ProductVariant, error) {
+	attributes, err := json.Marshal(attributesOrEmpty(variant.Attributes))
+	if err != nil {
+		return nil, err
+	}
+
+	SQL := `INSERT INTO product_variants(product_id, sku, price, stock, test_stock, attributes, is_default, position)
+			VALUES ($1, $2, NULLIF($3, 0::double precision), $4, $5, $6, $7, $8)
+			RETURNING ` + variantColumns
+
+	return scanVariant(tx.QueryRowContext(ctx, SQL,
+		variant.ProductId,
+		variant.Sku,
+		variant.Price,
+		variant.Stock,
+		variant.TestStock,
+		attributes,
+		variant.IsDefault,
+		variant.Position,
+	))
+}
+
+// Update replaces a variant's SKU, price override, attributes and position. Stock
+// is the total over the variant's warehouses and only changes with them.
+func (u *VariantRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, variant *proto.ProductVariant) (*proto.ProductVariant, error) {
+	attributes, err := json.Marshal(attributesOrEmpty(variant.Attributes))
+	if err != nil {
+		return nil, err
+	}
+
+	SQL := `UPDATE product_variants
+			SET sku = $1,
+				price = NULLIF($2, 0::double precision),
+				attributes = $3,
+				position = $4,
+				updated_at = NOW()
+			WHERE id = $5
+			RETURNING ` + variantColumns
+
+	return scanVariant(tx.QueryRowContext(ctx, SQL,
+		variant.Sku,
+		variant.Price,
+		attributes,
+		variant.Position,
+		variant.Id,
+	))
+}
+
+func (u *VariantRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, ID int) error {
+	result, err := tx.ExecContext(ctx, "DELETE FROM product_variants WHERE id = $1", ID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrVariantNotFound
+	}
+
+	return nil
+}
+
+func (u *VariantRepositoryImpl) GetByID(ctx context.Context, tx *sql.Tx, ID int) (*proto.ProductVariant, error) {
+	SQL := "SELECT " + variantColumns + " FROM product_variants WHERE id = $1"
+	return scanVariant(tx.QueryRowContext(ctx, SQL, ID))
+}
+
+func (u *VariantRepositoryImpl) GetBySKU(ctx context.Context, tx *sql.Tx, sku string) (*proto.ProductVariant, error) {
+	SQL := "SELECT " + variantColumns + " FROM product_variants WHERE sku = $1"
+	return scanVariant(tx.QueryRowContext(ctx, SQL, sku))
+}
+
+func (u *VariantRepositoryImpl) GetProductVariants(ctx context.Context, tx *sql.Tx, productID int) ([]*proto.ProductVariant, error) {
+	SQL := "SELECT " + variantColumns + " FROM product_variants WHERE product_id = $1 ORDER BY position ASC, id ASC"
+	rows, err := tx.QueryContext(ctx, SQL, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var variants []*proto.ProductVariant
+	for rows.Next() {
+		variant, err := scanVariant(rows)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+	}
+	return variants, rows.Err()
+}
+
+// GetOptionsForProducts loads the options of several products in one query
+func (u *VariantRepositoryImpl) GetOptionsForProducts(ctx context.Context, tx *sql.Tx, productIDs []int32) ([]*proto.ProductOption, error) {
+	SQL := `SELECT id, product_id, name, array_to_json("values"), position FROM product_options
+			WHERE product_id = ANY($1)
+			ORDER BY product_id ASC, position ASC, id ASC`
+	rows, err := tx.QueryContext(ctx, SQL, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var options []*proto.ProductOption
+	for rows.Next() {
+		option := &proto.ProductOption{}
+		var values []byte
+		if err := rows.Scan(&option.Id, &option.ProductId, &option.Name, &values, &option.Position); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(values, &option.Values); err != nil {
+			return nil, err
+		}
+		options = append(options, option)
+	}
+	return options, rows.Err()
+}
+
+// GetVariantsForProducts loads the variants of several products in one query
+func (u *VariantRepositoryImpl) GetVariantsForProducts(ctx context.Context, tx *sql.Tx, productIDs []int32) ([]*proto.ProductVariant, error) {
+	SQL := "SELECT " + variantColumns + " FROM product_variants WHERE product_id = ANY($1) ORDER BY product_id ASC, position ASC, id ASC"
+	rows, err := tx.QueryContext(ctx, SQL, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var variants []*proto.ProductVariant
+	for rows.Next() {
+		variant, err := scanVariant(rows)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+	}
+	return variants, rows.Err()
+}
+
+// SyncVariantStock recomputes the variant's stock totals from its warehouse levels
+func (u *VariantRepositoryImpl) SyncVariantStock(ctx context.Context, tx *sql.Tx, ID int) (*proto.ProductVariant, error) {
+	SQL := `UPDATE product_variants v
+			SET stock = COALESCE(l.total_stock, 0), test_stock = COALESCE(l.total_test_stock, 0), updated_at = NOW()
+			FROM (SELECT SUM(stock) AS total_stock, SUM(test_stock) AS total_test_stock FROM variant_stock_levels WHERE variant_id = $1) l
+			WHERE v.id = $1
+			RETURNING ` + variantColumns
+	return scanVariant(tx.QueryRowContext(ctx, SQL, ID))
+}
+
+// SyncProductStock recomputes the product's stock totals from its variants and returns
+// the live stock before and after
+func (u *VariantRepositoryImpl) SyncProductStock(ctx context.Context, tx *sql.Tx, productID int) (int32, int32, error) {
+	var before int32
+	if err := tx.QueryRowContext(ctx, "SELECT stock FROM products WHERE id = $1 FOR UPDATE", productID).Scan(&before); err != nil {
+		return 0, 0, err
+	}
+
+	SQL := `UPDATE products p
+			SET stock = COALESCE(v.stock, 0), test_stock = COALESCE(v.test_stock, 0), updated_at = NOW()
+			FROM (SELECT SUM(stock) AS stock, SUM(test_stock) AS test_stock FROM product_variants WHERE product_id = $1) v
+			WHERE p.id = $1
+			RETURNING p.stock`
+	var after int32
+	if err := tx.QueryRowContext(ctx, SQL, productID).Scan(&after); err != nil {
+		return 0, 0, err
+	}
+	return before, after, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanVariant(row rowScanner) (*proto.ProductVariant, error) {
+	variant := &proto.ProductVariant{}
+	var attributes []byte
+
+	if err := row.Scan(
+		&variant.Id,
+		&variant.ProductId,
+		&variant.Sku,
+		&variant.Price,
+		&variant.Stock,
+		&variant.TestStock,
+		&attributes,
+		&variant.IsDefault,
+		&variant.Position,
+		&variant.CreatedAt,
+		&variant.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrVariantNotFound
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(attributes, &variant.Attributes); err != nil {
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+func attributesOrEmpty(attributes map[string]string) map[string]string {
+	if attributes == nil {
+		return map[string]string{}
+	}
+	return attributes
+}
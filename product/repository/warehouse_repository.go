@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"product_service/proto"
+)
+
+var (
+	ErrWarehouseNotFound = errors.New("warehouse not found")
+	ErrNoActiveWarehouse = errors.New("no active warehouse")
+)
+
+type WarehouseRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, warehouse *proto.Warehouse) (*proto.Warehouse, error)
+	Update(ctx context.Context, tx *sql.Tx, warehouse *proto.Warehouse) (*proto.Warehouse, error)
+	GetByID(ctx context.Context, tx *sql.Tx, ID int) (*proto.Warehouse, error)
+	GetAll(ctx context.Context, tx *sql.Tx) ([]*proto.Warehouse, error)
+	GetDefault(ctx context.Context, tx *sql.Tx) (*proto.Warehouse, error)
+	AdjustLevel(ctx context.Context, tx *sql.Tx, variantID int, warehouseID int, delta int, livemode bool) (int32, error)
+	LockVariantLevels(ctx context.Context, tx *sql.Tx, variantID int) ([]*proto.WarehouseStock, error)
+	GetLevelsForVariants(ctx context.Context, tx *sql.Tx, variantIDs []int32) ([]*proto.WarehouseStock, error)
+}
+
+type WarehouseRepositoryImpl struct{}
+
+func NewWarehouseRepositoryImpl() *WarehouseRepositoryImpl {
+	return &WarehouseRepositoryImpl{}
+}
+
+const warehouseColumns = `id, code, name, COALESCE(latitude, 0), COALESCE(longitude, 0), priority, is_active, created_at, updated_at`
+
+func (u *WarehouseRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, warehouse *proto.Warehouse) (*proto.Warehouse, error) {
+	SQL := `INSERT INTO warehouses(code, name, latitude, longitude, priority, is_active)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING ` + warehouseColumns
+	return scanWarehouse(tx.QueryRowContext(ctx, SQL,
+		warehouse.Code,
+		warehouse.Name,
+		warehouse.Latitude,
+		warehouse.Longitude,
+		warehouse.Priority,
+		warehouse.Active,
+	))
+}
+
+func (u *WarehouseRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, warehouse *proto.Warehouse) (*proto.Warehouse, error) {
+	SQL := `UPDATE warehouses
+			SET code = $1,
+				name = $2,
+				latitude = $3,
+				longitude = $4,
+				priority = $5,
+				is_active = $6,
+				updated_at = NOW()
+			WHERE id = $7
+			RETURNING ` + warehouseColumns
+	return scanWarehouse(tx.QueryRowContext(ctx, SQL,
+		warehouse.Code,
+		warehouse.Name,
+		warehouse.Latitude,
+		warehouse.Longitude,
+		warehouse.Priority,
+		warehouse.Active,
+		warehouse.Id,
+	))
+}
+
+func (u *WarehouseRepositoryImpl) GetByID(ctx context.Context, tx *sql.Tx, ID int) (*proto.Warehouse, error) {
+	SQL := "SELECT " + warehouseColumns + " FROM warehouses WHERE id = $1"
+	return scanWarehouse(tx.QueryRowContext(ctx, SQL, ID))
+}
+
+// GetAll returns every warehouse in allocation order
+func (u *WarehouseRepositoryImpl) GetAll(ctx context.Context, tx *sql.Tx) ([]*proto.Warehouse, error) {
+	SQL := "SELECT " + warehouseColumns + " FROM warehouses ORDER BY priority ASC, id ASC"
+	rows, err := tx.QueryContext(ctx, SQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var warehouses []*proto.Warehouse
+	for rows.Next() {
+		warehouse, err := scanWarehouse(rows)
+		if err != nil {
+			return nil, err
+		}
+		warehouses = append(warehouses, warehouse)
+	}
+	return warehouses, rows.Err()
+}
+
+// GetDefault returns the active warehouse with the highest priority, which receives
+// stock changes that do not name a warehouse
+func (u *WarehouseRepositoryImpl) GetDefault(ctx context.Context, tx *sql.Tx) (*proto.Warehouse, error) {
+	SQL := "SELECT " + warehouseColumns + " FROM warehouses WHERE is_active ORDER BY priority ASC, id ASC LIMIT 1"
+	warehouse, err := scanWarehouse(tx.QueryRowContext(ctx, SQL))
+	if errors.Is(err, ErrWarehouseNotFound) {
+		return nil, ErrNoActiveWarehouse
+	}
+	return warehouse, err
+}
+
+// AdjustLevel adds delta to the live or test stock of a variant in a warehouse and
+// returns the stock left there. The check and the update are one statement, so
+// concurrent orders can never take a level below zero.
+func (u *WarehouseRepositoryImpl) AdjustLevel(ctx context.Context, tx *sql.Tx, variantID int, warehouseID int, delta int, livemode bool) (int32, error) {
+	if delta > 0 {
+		SQL := `INSERT INTO variant_stock_levels(variant_id, warehouse_id) VALUES ($1, $2)
+				ON CONFLICT (variant_id, warehouse_id) DO NOTHING`
+		if _, err := tx.ExecContext(ctx, SQL, variantID, warehouseID); err != nil {
+			return 0, err
+		}
+	}
+
+	SQL := `UPDATE variant_stock_levels SET stock = stock + $1, updated_at = NOW()
+			WHERE variant_id = $2 AND warehouse_id = $3 AND stock + $1 >= 0
+			RETURNING stock`
+	if !livemode {
+		SQL = `UPDATE variant_stock_levels SET test_stock = test_stock + $1, updated_at = NOW()
+			WHERE variant_id = $2 AND warehouse_id = $3 AND test_stock + $1 >= 0
+			RETURNING test_stock`
+	}
+
+	var stock int32
+	if err := tx.QueryRowContext(ctx, SQL, delta, variantID, warehouseID).Scan(&stock); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrInsufficientStock
+		}
+		return 0, err
+	}
+	return stock, nil
+}
+
+// LockVariantLevels returns a variant's stock in every active warehouse and locks
+// those levels until tx ends, so an allocation plans against stock nobody else takes.
+// Levels are locked in warehouse ID order, like every other multi-level change.
+func (u *WarehouseRepositoryImpl) LockVariantLevels(ctx context.Context, tx *sql.Tx, variantID int) ([]*proto.WarehouseStock, error) {
+	SQL := `SELECT l.variant_id, l.warehouse_id, w.code, l.stock, l.test_stock
+			FROM variant_stock_levels l
+			JOIN warehouses w ON w.id = l.warehouse_id
+			WHERE l.variant_id = $1 AND w.is_active
+			ORDER BY w.id ASC
+			FOR UPDATE OF l`
+	return queryLevels(ctx, tx, SQL, variantID)
+}
+
+// GetLevelsForVariants loads the per-warehouse stock of several variants in one query
+func (u *WarehouseRepositoryImpl) GetLevelsForVariants(ctx context.Context, tx *sql.Tx, variantIDs []int32) ([]*proto.WarehouseStock, error) {
+	SQL := `SELECT l.variant_id, l.warehouse_id, w.code, l.stock, l.test_stock
+			FROM variant_stock_levels l
+			JOIN warehouses w ON w.id = l.warehouse_id
+			WHERE l.variant_id = ANY($1)
+			ORDER BY l.variant_id ASC, w.priority ASC, w.id ASC`
+	return queryLevels(ctx, tx, SQL, variantIDs)
+}
+
+func queryLevels(ctx context.Context, tx *sql.Tx, SQL string, args ...interface{}) ([]*proto.WarehouseStock, error) {
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var levels []*proto.WarehouseStock
+	for rows.Next() {
+		level := &proto.WarehouseStock{}
+		if err := rows.Scan(&level.VariantId, &level.WarehouseId, &level.WarehouseCode, &level.Stock, &level.TestStock); err != nil {
+			return nil, err
+		}
+		levels = append(levels, level)
+	}
+	return levels, rows.Err()
+}
+
+func scanWarehouse(row rowScanner) (*proto.Warehouse, error) {
+	warehouse := &proto.Warehouse{}
+	if err := row.Scan(
+		&warehouse.Id,
+		&warehouse.Code,
+		&warehouse.Name,
+		&warehouse.Latitude,
+		&warehouse.Longitude,
+		&warehouse.Priority,
+		&warehouse.Active,
+		&warehouse.CreatedAt,
+		&warehouse.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWarehouseNotFound
+		}
+		return nil, err
+	}
+	return warehouse, nil
+}
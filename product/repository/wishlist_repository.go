@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"product_service/proto"
+)
+
+var (
+	ErrWishlistItemNotFound = errors.New("product is not on the wishlist")
+	ErrSubscriptionNotFound = errors.New("not subscribed to this product")
+)
+
+// Restock is a product's live stock going from zero to positive
+type Restock struct {
+	ID        int
+	ProductID int
+	Stock     int
+	CreatedAt string
+}
+
+type WishlistRepository interface {
+	AddItem(ctx context.Context, tx *sql.Tx, userID int, productID int) error
+	RemoveItem(ctx context.Context, tx *sql.Tx, userID int, productID int) error
+	GetItems(ctx context.Context, tx *sql.Tx, userID int) ([]*proto.WishlistItem, []int32, error)
+	Subscribe(ctx context.Context, tx *sql.Tx, userID int, productID int) (*proto.StockSubscription, error)
+	Unsubscribe(ctx context.Context, tx *sql.Tx, userID int, productID int) error
+	GetSubscriptions(ctx context.Context, tx *sql.Tx, userID int) ([]*proto.StockSubscription, error)
+	RecordRestock(ctx context.Context, tx *sql.Tx, productID int, stock int) error
+	ClaimRestocks(ctx context.Context, tx *sql.Tx, limit int) ([]*Restock, error)
+	GetRestockSubscribers(ctx context.Context, tx *sql.Tx, restock *Restock) ([]int32, error)
+	MarkRestockPublished(ctx context.Context, tx *sql.Tx, restock *Restock, userIDs []int32) error
+}
+
+type WishlistRepositoryImpl struct{}
+
+func NewWishlistRepositoryImpl() *WishlistRepositoryImpl {
+	return &WishlistRepositoryImpl{}
+}
+
+// AddItem puts a product on a user's wishlist; adding it twice keeps the first date
+func (u *WishlistRepositoryImpl) AddItem(ctx context.Context, tx *sql.Tx, userID int, productID int) error {
+	SQL := "INSERT INTO wishlist_items(user_id, product_id) VALUES ($1, $2) ON CONFLICT (user_id, product_id) DO NOTHING"
+	_, err := tx.ExecContext(ctx, SQL, userID, productID)
+	return err
+}
+
+func (u *WishlistRepositoryImpl) RemoveItem(ctx context.Context, tx *sql.Tx, userID int, productID int) error {
+	return execDelete(ctx, tx, "DELETE FROM wishlist_items WHERE user_id = $1 AND product_id = $2", ErrWishlistItemNotFound, userID, productID)
+}
+
+// GetItems returns a user's wishlist, most recently added first, without the products
+// themselves; their IDs are returned in the same order for loading them in one query
+func (u *WishlistRepositoryImpl) GetItems(ctx context.Context, tx *sql.Tx, userID int) ([]*proto.WishlistItem, []int32, error) {
+	SQL := `SELECT w.product_id, w.created_at, s.user_id IS NOT NULL
+			FROM wishlist_items w
+			LEFT JOIN stock_subscriptions s ON s.user_id = w.user_id AND s.product_id = w.product_id
+			WHERE w.user_id = $1
+			ORDER BY w.created_at DESC, w.product_id DESC`
+	rows, err := tx.QueryContext(ctx, SQL, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var (
+		items      []*proto.WishlistItem
+		productIDs []int32
+	)
+	for rows.Next() {
+		item := &proto.WishlistItem{}
+		var productID int32
+		if err := rows.Scan(&productID, &item.AddedAt, &item.Subscribed); err != nil {
+			return nil, nil, err
+		}
+		items = append(items, item)
+		productIDs = append(productIDs, productID)
+	}
+	return items, productIDs, rows.Err()
+}
+
+// Subscribe asks to be told when a product is back in stock; subscribing twice
+// keeps the existing subscription
+func (u *WishlistRepositoryImpl) Subscribe(ctx context.Context, tx *sql.Tx, userID int, productID int) (*proto.StockSubscription, error) {
+	SQL := `INSERT INTO stock_subscriptions(user_id, product_id) VALUES ($1, $2)
+			ON CONFLICT (user_id, product_id) DO UPDATE SET user_id = EXCLUDED.user_id
+			RETURNING ` + subscriptionColumns
+	return scanSubscription(tx.QueryRowContext(ctx, SQL, userID, productID))
+}
+
+func (u *WishlistRepositoryImpl) Unsubscribe(ctx context.Context, tx *sql.Tx, userID int, productID int) error {
+	return execDelete(ctx, tx, "DELETE FROM stock_subscriptions WHERE user_id = $1 AND product_id = $2", ErrSubscriptionNotFound, userID, productID)
+}
+
+func (u *WishlistRepositoryImpl) GetSubscriptions(ctx context.Context, tx *sql.Tx, userID int) ([]*proto.StockSubscription, error) {
+	SQL := "SELECT " + subscriptionColumns + " FROM stock_subscriptions WHERE user_id = $1 ORDER BY created_at DESC, product_id DESC"
+	rows, err := tx.QueryContext(ctx, SQL, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*proto.StockSubscription
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, rows.Err()
+}
+
+// RecordRestock queues a restock for the publisher. It is written in the transaction
+// that changed the stock, so a restock is only published once that change commits.
+func (u *WishlistRepositoryImpl) RecordRestock(ctx context.Context, tx *sql.Tx, productID int, stock int) error {
+	_, err := tx.ExecContext(ctx, "INSERT INTO product_restocks(product_id, stock) VALUES ($1, $2)", productID, stock)
+	return err
+}
+
+// ClaimRestocks locks the oldest unpublished restocks until tx ends. Restocks claimed
+// by another publisher are skipped rather than waited for.
+func (u *WishlistRepositoryImpl) ClaimRestocks(ctx context.Context, tx *sql.Tx, limit int) ([]*Restock, error) {
+	SQL := `SELECT id, product_id, stock, created_at FROM product_restocks
+			WHERE published_at IS NULL
+			ORDER BY id ASC
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED`
+	rows, err := tx.QueryContext(ctx, SQL, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var restocks []*Restock
+	for rows.Next() {
+		restock := &Restock{}
+		if err := rows.Scan(&restock.ID, &restock.ProductID, &restock.Stock, &restock.CreatedAt); err != nil {
+			return nil, err
+		}
+		restocks = append(restocks, restock)
+	}
+	return restocks, rows.Err()
+}
+
+// GetRestockSubscribers returns the subscribers of the product who have not been
+// notified of this restock, or a later one, yet
+func (u *WishlistRepositoryImpl) GetRestockSubscribers(ctx context.Context, tx *sql.Tx, restock *Restock) ([]int32, error) {
+	SQL := `SELECT user_id FROM stock_subscriptions
+			WHERE product_id = $1 AND (last_notified_restock_id IS NULL OR last_notified_restock_id < $2)
+			ORDER BY user_id ASC`
+	rows, err := tx.QueryContext(ctx, SQL, restock.ProductID, restock.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []int32
+	for rows.Next() {
+		var userID int32
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
+}
+
+// MarkRestockPublished records that the restock was sent to the given subscribers
+func (u *WishlistRepositoryImpl) MarkRestockPublished(ctx context.Context, tx *sql.Tx, restock *Restock, userIDs []int32) error {
+	SQL := `UPDATE stock_subscriptions SET last_notified_restock_id = $1, last_notified_at = NOW()
+			WHERE product_id = $2 AND user_id = ANY($3)`
+	if _, err := tx.ExecContext(ctx, SQL, restock.ID, restock.ProductID, userIDs); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, "UPDATE product_restocks SET published_at = NOW() WHERE id = $1", restock.ID)
+	return err
+}
+
+const subscriptionColumns = `user_id, product_id, created_at, last_notified_at`
+
+func scanSubscription(row rowScanner) (*proto.StockSubscription, error) {
+	subscription := &proto.StockSubscription{}
+	var lastNotifiedAt sql.NullString
+	if err := row.Scan(&subscription.UserId, &subscription.ProductId, &subscription.CreatedAt, &lastNotifiedAt); err != nil {
+		return nil, err
+	}
+	subscription.LastNotifiedAt = lastNotifiedAt.String
+	return subscription, nil
+}
+
+// execDelete runs a DELETE and returns notFound when it removed nothing
+func execDelete(ctx context.Context, tx *sql.Tx, SQL string, notFound error, args ...interface{}) error {
+	result, err := tx.ExecContext(ctx, SQL, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound
+	}
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"product_service/proto"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Kinds of product
+const (
+	ProductKindStandard = "standard"
+	ProductKindBundle   = "bundle"
+	ProductKindDigital  = "digital"
+)
+
+// SetBundleComponents makes a product a bundle of the given variants, or a standard
+// product again when there are none. A bundle keeps its single default variant and
+// holds no stock of its own, so a product has to be emptied before it becomes one.
+func (u *ProductService) SetBundleComponents(req *proto.SetBundleComponentsRequest) (*proto.Product, error) {
+	seen := make(map[int32]bool, len(req.Components))
+	variantIDs := make([]int32, 0, len(req.Components))
+	for _, component := range req.Components {
+		if component.Quantity <= 0 {
+			return nil, errors.New("component quantity must be positive")
+		}
+		if seen[component.VariantId] {
+			return nil, fmt.Errorf("duplicate component: variant %d", component.VariantId)
+		}
+		seen[component.VariantId] = true
+		variantIDs = append(variantIDs, component.VariantId)
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	ID := int(req.ProductId)
+	product, err := u.repo.GetProductByID(u.ctx, tx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := u.variantRepo.GetProductVariants(u.ctx, tx, ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(variants) != 1 || !variants[0].IsDefault {
+		return nil, errors.New("only a product with a single default variant can be a bundle")
+	}
+	if product.Kind == ProductKindDigital {
+		return nil, errors.New("a digital product cannot be a bundle")
+	}
+	if product.Kind != ProductKindBundle && len(req.Components) > 0 && (product.Stock != 0 || product.TestStock != 0) {
+		return nil, errors.New("empty the product's stock before making it a bundle")
+	}
+
+	containing, err := u.bundleRepo.GetBundlesOfVariant(u.ctx, tx, int(variants[0].Id))
+	if err != nil {
+		return nil, err
+	}
+	if len(containing) > 0 && len(req.Components) > 0 {
+		return nil, fmt.Errorf("product is a component of bundle %d and cannot be a bundle itself", containing[0])
+	}
+
+	for _, component := range req.Components {
+		variant, err := u.variantRepo.GetByID(u.ctx, tx, int(component.VariantId))
+		if err != nil {
+			return nil, fmt.Errorf("variant %d: %w", component.VariantId, err)
+		}
+		if int(variant.ProductId) == ID {
+			return nil, errors.New("a bundle cannot contain itself")
+		}
+		componentProduct, err := u.repo.GetProductByID(u.ctx, tx, int(variant.ProductId))
+		if err != nil {
+			return nil, err
+		}
+		if componentProduct.Kind == ProductKindDigital {
+			return nil, errors.New("a bundle cannot contain digital products")
+		}
+	}
+	bundled, err := u.bundleRepo.GetBundleVariants(u.ctx, tx, variantIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(bundled) > 0 {
+		return nil, errors.New("a bundle cannot contain other bundles")
+	}
+
+	if err := u.bundleRepo.SetComponents(u.ctx, tx, ID, req.Components); err != nil {
+		return nil, err
+	}
+
+	var before, after int32
+	if len(req.Components) > 0 {
+		if err := u.bundleRepo.SetKind(u.ctx, tx, ID, ProductKindBundle); err != nil {
+			return nil, err
+		}
+		if before, after, err = u.bundleRepo.SyncBundleStock(u.ctx, tx, ID); err != nil {
+			return nil, err
+		}
+	} else {
+		// A standard product's stock comes from its warehouses again, where it has none
+		if err := u.bundleRepo.SetKind(u.ctx, tx, ID, ProductKindStandard); err != nil {
+			return nil, err
+		}
+		if _, err := u.variantRepo.SyncVariantStock(u.ctx, tx, int(variants[0].Id)); err != nil {
+			return nil, err
+		}
+		if before, after, err = u.variantRepo.SyncProductStock(u.ctx, tx, ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.announceStock(tx, ID, before, after); err != nil {
+		return nil, err
+	}
+	if err := u.recordProductEvent(tx, ProductUpdated, ID); err != nil {
+		return nil, err
+	}
+
+	product, err = u.readProduct(tx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Product %d now has %d bundle components", ID, len(req.Components))
+	u.invalidateProductCache(ID)
+	return product, nil
+}
+
+// syncBundles recomputes the stock of every bundle a variant is a component of after
+// its stock changed, announcing live changes like moveStock does for the variant's
+// own product
+func (u *ProductService) syncBundles(tx *sql.Tx, variantID int, announce bool) error {
+	bundleIDs, err := u.bundleRepo.GetBundlesOfVariant(u.ctx, tx, variantID)
+	if err != nil {
+		return err
+	}
+
+	for _, bundleID := range bundleIDs {
+		before, after, err := u.bundleRepo.SyncBundleStock(u.ctx, tx, bundleID)
+		if err != nil {
+			return err
+		}
+		if announce {
+			if err := u.announceStock(tx, bundleID, before, after); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loadComponents attaches their components to the bundles among products
+func (u *ProductService) loadComponents(tx *sql.Tx, products []*proto.Product) error {
+	var bundleIDs []int32
+	for _, product := range products {
+		if product.Kind == ProductKindBundle {
+			bundleIDs = append(bundleIDs, int32(product.Id))
+		}
+	}
+	if len(bundleIDs) == 0 {
+		return nil
+	}
+
+	components, err := u.bundleRepo.GetComponents(u.ctx, tx, bundleIDs)
+	if err != nil {
+		return err
+	}
+	for _, product := range products {
+		product.Components = components[int32(product.Id)]
+	}
+	return nil
+}
+
+// allocationItem is the stock a line of an allocation takes of one variant: the
+// line's own variant, or one of the components when the line is a bundle
+type allocationItem struct {
+	line            int
+	variantID       int32
+	bundleVariantID int32
+	quantity        int32
+}
+
+// expandBundles turns allocation lines into the variants they take, replacing each
+// bundle with its components
+func (u *ProductService) expandBundles(tx *sql.Tx, lines []*proto.AllocationLine) ([]allocationItem, error) {
+	variantIDs := make([]int32, len(lines))
+	for i, line := range lines {
+		variantIDs[i] = line.VariantId
+	}
+
+	bundles, err := u.bundleRepo.GetBundleVariants(u.ctx, tx, variantIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var components map[int32][]*proto.BundleComponent
+	if len(bundles) > 0 {
+		bundleIDs := make([]int32, 0, len(bundles))
+		for _, bundleID := range bundles {
+			bundleIDs = append(bundleIDs, bundleID)
+		}
+		if components, err = u.bundleRepo.GetComponents(u.ctx, tx, bundleIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	items := make([]allocationItem, 0, len(lines))
+	for i, line := range lines {
+		bundleID, ok := bundles[line.VariantId]
+		if !ok {
+			items = append(items, allocationItem{line: i, variantID: line.VariantId, quantity: line.Quantity})
+			continue
+		}
+		if len(components[bundleID]) == 0 {
+			return nil, fmt.Errorf("bundle %d has no components", bundleID)
+		}
+		for _, component := range components[bundleID] {
+			items = append(items, allocationItem{
+				line:            i,
+				variantID:       component.VariantId,
+				bundleVariantID: line.VariantId,
+				quantity:        line.Quantity * component.Quantity,
+			})
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,452 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"product_service/cmd/db"
+	"product_service/helper"
+	"product_service/proto"
+
+	"github.com/sirupsen/logrus"
+)
+
+func (u *ProductService) CreateCategory(category *proto.Category) (*proto.Category, error) {
+	if category.Name == "" {
+		return nil, errors.New("category name is required")
+	}
+
+	category.Slug = slugOrName(category.Slug, category.Name)
+	if category.Slug == "" {
+		return nil, errors.New("category slug cannot be empty")
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	created, err := u.categoryRepo.Create(u.ctx, tx, category)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Category %d created: %s", created.Id, created.Slug)
+	return created, nil
+}
+
+// UpdateCategory replaces a category's parent, name, slug and position. An empty name
+// or slug keeps the current one; a category cannot be moved below itself.
+func (u *ProductService) UpdateCategory(category *proto.Category) (*proto.Category, error) {
+	existing, err := u.categoryRepo.GetByID(u.ctx, u.DB, int(category.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	if category.Name == "" {
+		category.Name = existing.Name
+	}
+	if category.Slug == "" {
+		category.Slug = existing.Slug
+	} else {
+		category.Slug = helper.Slugify(category.Slug)
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	if category.ParentId != 0 {
+		cycle, err := u.categoryRepo.IsDescendant(u.ctx, tx, int(category.ParentId), int(category.Id))
+		if err != nil {
+			return nil, err
+		}
+		if cycle {
+			return nil, errors.New("category cannot be moved below itself")
+		}
+	}
+
+	updated, err := u.categoryRepo.Update(u.ctx, tx, category)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	u.invalidateCatalogCache()
+	return updated, nil
+}
+
+func (u *ProductService) DeleteCategory(ID int) error {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	if err := u.categoryRepo.Delete(u.ctx, tx, ID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rollback = false
+
+	u.invalidateCatalogCache()
+	return nil
+}
+
+// ListCategories returns the whole category tree for navigation
+func (u *ProductService) ListCategories() ([]*proto.Category, error) {
+	categories, err := u.categoryRepo.GetAll(u.ctx, u.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	roots, _ := buildCategoryTree(categories)
+	return roots, nil
+}
+
+// ListCategoryProducts lists the products of a category and its subcategories, with
+// the breadcrumb path and the subcategories to drill into
+func (u *ProductService) ListCategoryProducts(req *proto.ListCategoryProductsRequest) (*proto.CategoryProductList, error) {
+	var (
+		category *proto.Category
+		err      error
+	)
+	if req.CategoryId != 0 {
+		category, err = u.categoryRepo.GetByID(u.ctx, u.DB, int(req.CategoryId))
+	} else if req.Slug != "" {
+		category, err = u.categoryRepo.GetBySlug(u.ctx, u.DB, req.Slug)
+	} else {
+		return nil, errors.New("category_id or slug is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	breadcrumbs, err := u.categoryRepo.GetBreadcrumbs(u.ctx, u.DB, int(category.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := u.categoryRepo.GetAll(u.ctx, u.DB)
+	if err != nil {
+		return nil, err
+	}
+	if _, nodes := buildCategoryTree(categories); nodes[category.Id] != nil {
+		category.Children = nodes[category.Id].Children
+	}
+
+	products, err := u.Search(&proto.SearchProductsRequest{
+		CategoryId: category.Id,
+		Sort:       req.Sort,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.CategoryProductList{
+		Category:    category,
+		Breadcrumbs: breadcrumbs,
+		Products:    products,
+	}, nil
+}
+
+// SetProductCategories replaces the categories a product is listed under
+func (u *ProductService) SetProductCategories(req *proto.SetProductCategoriesRequest) (*proto.Product, error) {
+	categoryIDs := uniqueIDs(req.CategoryIds)
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	product, err := u.repo.GetProductByID(u.ctx, tx, int(req.ProductId))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.categoryRepo.SetProductCategories(u.ctx, tx, int(req.ProductId), categoryIDs); err != nil {
+		return nil, err
+	}
+
+	if err := u.recordProductEvent(tx, ProductUpdated, int(req.ProductId)); err != nil {
+		return nil, err
+	}
+
+	if err := u.loadTaxonomy(tx, product); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	u.invalidateProductCache(int(req.ProductId))
+	return product, nil
+}
+
+func (u *ProductService) CreateTag(tag *proto.Tag) (*proto.Tag, error) {
+	if tag.Name == "" {
+		return nil, errors.New("tag name is required")
+	}
+
+	tag.Slug = slugOrName(tag.Slug, tag.Name)
+	if tag.Slug == "" {
+		return nil, errors.New("tag slug cannot be empty")
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	created, err := u.tagRepo.Create(u.ctx, tx, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	return created, nil
+}
+
+func (u *ProductService) DeleteTag(ID int) error {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	if err := u.tagRepo.Delete(u.ctx, tx, ID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rollback = false
+
+	u.invalidateCatalogCache()
+	return nil
+}
+
+func (u *ProductService) ListTags() ([]*proto.Tag, error) {
+	return u.tagRepo.GetAll(u.ctx, u.DB)
+}
+
+// SetProductTags replaces the tags of a product; tags are matched by slug and created when new
+func (u *ProductService) SetProductTags(req *proto.SetProductTagsRequest) (*proto.Product, error) {
+	seen := make(map[string]bool, len(req.Tags))
+	tags := make([]*proto.Tag, 0, len(req.Tags))
+	for _, name := range req.Tags {
+		slug := helper.Slugify(name)
+		if slug == "" {
+			return nil, fmt.Errorf("invalid tag: %q", name)
+		}
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		tags = append(tags, &proto.Tag{Name: name, Slug: slug})
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	product, err := u.repo.GetProductByID(u.ctx, tx, int(req.ProductId))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.tagRepo.SetProductTags(u.ctx, tx, int(req.ProductId), tags); err != nil {
+		return nil, err
+	}
+
+	if err := u.recordProductEvent(tx, ProductUpdated, int(req.ProductId)); err != nil {
+		return nil, err
+	}
+
+	if err := u.loadTaxonomy(tx, product); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	u.invalidateProductCache(int(req.ProductId))
+	return product, nil
+}
+
+func (u *ProductService) loadTaxonomy(tx *sql.Tx, product *proto.Product) error {
+	categories, err := u.categoryRepo.GetProductCategories(u.ctx, tx, int(product.Id))
+	if err != nil {
+		return err
+	}
+
+	tags, err := u.tagRepo.GetProductTags(u.ctx, tx, int(product.Id))
+	if err != nil {
+		return err
+	}
+
+	product.Categories = categories
+	product.Tags = tags
+	return nil
+}
+
+func (u *ProductService) invalidateProductCache(ID int) {
+	productKey := fmt.Sprintf("product:%d", ID)
+	if err := db.DeleteCache(u.ctx, productKey); err != nil {
+		logrus.Warnf("Failed to invalidate product cache for ID %d: %v", ID, err)
+	}
+
+	// Cached bundles carry the stock and prices of their components
+	bundleIDs, err := u.bundleRepo.GetBundlesOfProduct(u.ctx, u.DB, ID)
+	if err != nil {
+		logrus.Warnf("Failed to find the bundles of product %d: %v", ID, err)
+	}
+	for _, bundleID := range bundleIDs {
+		if err := db.DeleteCache(u.ctx, fmt.Sprintf("product:%d", bundleID)); err != nil {
+			logrus.Warnf("Failed to invalidate product cache for ID %d: %v", bundleID, err)
+		}
+	}
+
+	if err := db.DeleteCachePattern(u.ctx, "products:list:*"); err != nil {
+		logrus.Warnf("Failed to invalidate product list cache: %v", err)
+	}
+}
+
+// invalidateCatalogCache drops every cached product and listing, for changes that
+// reach many products at once, like category and tag names embedded in cached
+// products and search facets
+func (u *ProductService) invalidateCatalogCache() {
+	if err := db.DeleteCachePattern(u.ctx, "product:*"); err != nil {
+		logrus.Warnf("Failed to invalidate product cache: %v", err)
+	}
+
+	if err := db.DeleteCachePattern(u.ctx, "products:list:*"); err != nil {
+		logrus.Warnf("Failed to invalidate product list cache: %v", err)
+	}
+}
+
+// buildCategoryTree links categories to their parents, returning the top-level
+// categories and every node by ID. Input order is kept among siblings.
+func buildCategoryTree(categories []*proto.Category) ([]*proto.Category, map[int32]*proto.Category) {
+	nodes := make(map[int32]*proto.Category, len(categories))
+	for _, category := range categories {
+		nodes[category.Id] = category
+	}
+
+	var roots []*proto.Category
+	for _, category := range categories {
+		if parent := nodes[category.ParentId]; parent != nil {
+			parent.Children = append(parent.Children, category)
+		} else {
+			roots = append(roots, category)
+		}
+	}
+
+	return roots, nodes
+}
+
+func slugOrName(slug string, name string) string {
+	if slug != "" {
+		return helper.Slugify(slug)
+	}
+	return helper.Slugify(name)
+}
+
+func uniqueIDs(ids []int32) []int32 {
+	seen := make(map[int32]bool, len(ids))
+	unique := make([]int32, 0, len(ids))
+	for _, id := range ids {
+		if id <= 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
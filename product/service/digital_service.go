@@ -0,0 +1,316 @@
+package service
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"product_service/proto"
+	"product_service/repository"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Ways a digital product is delivered
+const (
+	DeliveryDownload   = "download"
+	DeliveryLicenseKey = "license_key"
+
+	defaultDownloadLimit = 5
+	defaultLinkTTLHours  = 72
+
+	// maxLicenseKeysPerRequest bounds how many keys are added to a pool at once
+	maxLicenseKeysPerRequest = 1000
+)
+
+// SetDigitalDelivery sets how a digital product is delivered. A standard product
+// becomes digital only while it has no stock, is in no bundle and has never been
+// ordered, since its order items would otherwise change meaning.
+func (u *ProductService) SetDigitalDelivery(req *proto.DigitalDelivery) (*proto.Product, error) {
+	switch req.Delivery {
+	case DeliveryDownload:
+		if req.FileKey == "" {
+			return nil, errors.New("file_key is required for downloads")
+		}
+	case DeliveryLicenseKey:
+		req.FileKey, req.FileName, req.ContentType = "", "", ""
+	default:
+		return nil, fmt.Errorf("unknown delivery: %s", req.Delivery)
+	}
+	if req.DownloadLimit < 0 || req.LinkTtlHours < 0 {
+		return nil, errors.New("download_limit and link_ttl_hours cannot be negative")
+	}
+	if req.DownloadLimit == 0 {
+		req.DownloadLimit = defaultDownloadLimit
+	}
+	if req.LinkTtlHours == 0 {
+		req.LinkTtlHours = defaultLinkTTLHours
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	ID := int(req.ProductId)
+	product, err := u.repo.GetProductByID(u.ctx, tx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch product.Kind {
+	case ProductKindBundle:
+		return nil, errors.New("a bundle cannot be a digital product")
+	case ProductKindStandard:
+		if err := u.checkDigitalConversion(tx, product); err != nil {
+			return nil, err
+		}
+		if err := u.bundleRepo.SetKind(u.ctx, tx, ID, ProductKindDigital); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.digitalRepo.Upsert(u.ctx, tx, req); err != nil {
+		return nil, err
+	}
+
+	if err := u.recordProductEvent(tx, ProductUpdated, ID); err != nil {
+		return nil, err
+	}
+
+	product, err = u.readProduct(tx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Product %d is delivered as %s", ID, req.Delivery)
+	u.invalidateProductCache(ID)
+	return product, nil
+}
+
+func (u *ProductService) checkDigitalConversion(tx *sql.Tx, product *proto.Product) error {
+	ID := int(product.Id)
+	if product.Stock != 0 || product.TestStock != 0 {
+		return errors.New("empty the product's stock before making it digital")
+	}
+
+	variants, err := u.variantRepo.GetProductVariants(u.ctx, tx, ID)
+	if err != nil {
+		return err
+	}
+	if len(variants) != 1 || !variants[0].IsDefault {
+		return errors.New("only a product with a single default variant can be digital")
+	}
+
+	containing, err := u.bundleRepo.GetBundlesOfVariant(u.ctx, tx, int(variants[0].Id))
+	if err != nil {
+		return err
+	}
+	if len(containing) > 0 {
+		return fmt.Errorf("product is a component of bundle %d and cannot be digital", containing[0])
+	}
+
+	ordered, err := u.orderRepo.HasOrders(u.ctx, ID)
+	if err != nil {
+		return err
+	}
+	if ordered {
+		return errors.New("a product that has been ordered cannot become digital")
+	}
+	return nil
+}
+
+// AddLicenseKeys adds keys to the pool of a product delivered as licence keys
+func (u *ProductService) AddLicenseKeys(req *proto.AddLicenseKeysRequest) (*proto.LicenseKeyPool, error) {
+	keys := make([]string, 0, len(req.Keys))
+	for _, key := range req.Keys {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("keys are required")
+	}
+	if len(keys) > maxLicenseKeysPerRequest {
+		return nil, fmt.Errorf("at most %d keys can be added at once", maxLicenseKeysPerRequest)
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	ID := int(req.ProductId)
+	digital, err := u.digitalRepo.GetForProducts(u.ctx, tx, []int32{req.ProductId})
+	if err != nil {
+		return nil, err
+	}
+	if d, ok := digital[req.ProductId]; !ok || d.Delivery != DeliveryLicenseKey {
+		return nil, errors.New("product is not delivered as licence keys")
+	}
+
+	added, err := u.digitalRepo.AddKeys(u.ctx, tx, ID, keys)
+	if err != nil {
+		return nil, err
+	}
+	available, err := u.digitalRepo.CountAvailableKeys(u.ctx, tx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Added %d licence keys to product %d, %d available", added, ID, available)
+	u.invalidateProductCache(ID)
+	return &proto.LicenseKeyPool{
+		ProductId: req.ProductId,
+		Added:     int32(added),
+		Available: int32(available),
+	}, nil
+}
+
+// DeliverDigitalItems works out what the digital items of a paid order deliver.
+// Licence keys are assigned from the pools in one transaction, so either every item
+// gets its keys or none does; items that already have keys keep them. Test-mode
+// orders get generated keys and leave the pools alone.
+func (u *ProductService) DeliverDigitalItems(req *proto.DeliverDigitalItemsRequest) ([]*proto.DigitalItemDelivery, error) {
+	productIDs := make([]int32, 0, len(req.Items))
+	for _, item := range req.Items {
+		productIDs = append(productIDs, item.ProductId)
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	digital, err := u.digitalRepo.GetForProducts(u.ctx, tx, uniqueIDs(productIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveries []*proto.DigitalItemDelivery
+	assigned := make(map[int]bool)
+	for _, item := range req.Items {
+		d, ok := digital[item.ProductId]
+		if !ok {
+			continue
+		}
+		delivery := &proto.DigitalItemDelivery{OrderItemId: item.OrderItemId, Digital: d}
+
+		if d.Delivery == DeliveryLicenseKey {
+			if !req.Livemode {
+				delivery.LicenseKeys, err = testLicenseKeys(int(item.Quantity))
+			} else {
+				delivery.LicenseKeys, err = u.assignLicenseKeys(tx, req.OrderId, item)
+				assigned[int(item.ProductId)] = true
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Delivered %d digital items of order %d", len(deliveries), req.OrderId)
+	for ID := range assigned {
+		u.invalidateProductCache(ID)
+	}
+	return deliveries, nil
+}
+
+// assignLicenseKeys tops up the keys of an order item to its quantity
+func (u *ProductService) assignLicenseKeys(tx *sql.Tx, orderID int32, item *proto.DigitalItem) ([]string, error) {
+	keys, err := u.digitalRepo.GetAssignedKeys(u.ctx, tx, int(item.OrderItemId))
+	if err != nil {
+		return nil, err
+	}
+
+	if missing := int(item.Quantity) - len(keys); missing > 0 {
+		more, err := u.digitalRepo.AssignKeys(u.ctx, tx, int(item.ProductId), int(orderID), int(item.OrderItemId), missing)
+		if errors.Is(err, repository.ErrNotEnoughLicenseKeys) {
+			return nil, fmt.Errorf("not enough licence keys for product %d", item.ProductId)
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, more...)
+	}
+	return keys, nil
+}
+
+func testLicenseKeys(count int) ([]string, error) {
+	keys := make([]string, count)
+	for i := range keys {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		keys[i] = "TEST-" + strings.ToUpper(hex.EncodeToString(b))
+	}
+	return keys, nil
+}
+
+// loadDigital attaches how they are delivered to digital products
+func (u *ProductService) loadDigital(tx *sql.Tx, products []*proto.Product) error {
+	var digitalIDs []int32
+	for _, product := range products {
+		if product.Kind == ProductKindDigital {
+			digitalIDs = append(digitalIDs, int32(product.Id))
+		}
+	}
+	if len(digitalIDs) == 0 {
+		return nil
+	}
+
+	digital, err := u.digitalRepo.GetForProducts(u.ctx, tx, digitalIDs)
+	if err != nil {
+		return err
+	}
+	for _, product := range products {
+		product.Digital = digital[int32(product.Id)]
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"product_service/repository"
+	"product_service/transport/kafka"
+	"product_service/types"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Types of the events published when a product changes
+const (
+	ProductCreated    = "product.created"
+	ProductUpdated    = "product.updated"
+	ProductRepriced   = "product.repriced"
+	ProductRestocked  = "product.restocked"
+	ProductSoldOut    = "product.sold_out"
+	ProductLowStock   = "product.low_stock"
+	ProductArchived   = "product.archived"
+	ProductUnarchived = "product.unarchived"
+	ProductPurged     = "product.purged"
+)
+
+const (
+	// outboxPollInterval is how often the publisher looks for new product events
+	outboxPollInterval = 2 * time.Second
+	outboxBatchSize    = 100
+)
+
+// productImage renders products the way the gateway's clients know them, with every
+// field present so consumers can tell a zero from a missing value
+var productImage = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
+// recordProductEvent queues an event with the product as it is in tx, listing the
+// fields that changed since the product's previous event. Nothing is recorded when
+// nothing changed. A product's first event is always its creation, whichever change
+// records it, and an update that changes the price is recorded as a repricing.
+func (u *ProductService) recordProductEvent(tx *sql.Tx, eventType string, productID int) error {
+	version, err := u.outboxRepo.LockProduct(u.ctx, tx, productID)
+	if err != nil {
+		return err
+	}
+
+	event := &repository.ProductEvent{
+		ProductID:      productID,
+		ProductVersion: version + 1,
+		EventType:      eventType,
+		ChangedFields:  []string{},
+	}
+
+	if eventType != ProductPurged {
+		product, err := u.readProduct(tx, productID)
+		if err != nil {
+			return err
+		}
+		image, err := productImage.Marshal(product)
+		if err != nil {
+			return err
+		}
+
+		previous, err := u.outboxRepo.LatestImage(u.ctx, tx, productID)
+		if err != nil {
+			return err
+		}
+		changed, err := changedFields(previous, image)
+		if err != nil {
+			return err
+		}
+		if len(changed) == 0 {
+			logrus.Debugf("Product %d did not change, no %s event recorded", productID, eventType)
+			return nil
+		}
+
+		switch {
+		case version == 0:
+			event.EventType = ProductCreated
+		case eventType == ProductUpdated && containsField(changed, "price"):
+			event.EventType = ProductRepriced
+		}
+		event.ChangedFields = changed
+		event.Product = image
+	}
+
+	return u.outboxRepo.Record(u.ctx, tx, event)
+}
+
+// changedFields returns the top-level fields that differ between two product images,
+// all of them when there is no previous image. updated_at is left out since every
+// write touches it.
+func changedFields(previous []byte, current []byte) ([]string, error) {
+	var before, after map[string]interface{}
+	if previous != nil {
+		if err := json.Unmarshal(previous, &before); err != nil {
+			return nil, err
+		}
+	}
+	if err := json.Unmarshal(current, &after); err != nil {
+		return nil, err
+	}
+
+	changed := []string{}
+	for field, value := range after {
+		if field == "updated_at" {
+			continue
+		}
+		if old, ok := before[field]; !ok || !reflect.DeepEqual(old, value) {
+			changed = append(changed, field)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// RunOutboxPublisher sends recorded product events to Kafka in the order they were
+// recorded. Only one instance of the service publishes at a time, and events are
+// marked published in the transaction that sent them, so a failed batch is sent again;
+// consumers drop events whose product_version they have already seen. It runs for the
+// life of the service.
+func (u *ProductService) RunOutboxPublisher(topic string) {
+	logrus.Info("Product event publisher started")
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			published, err := u.publishProductEvents(topic)
+			if err != nil {
+				logrus.Errorf("Failed to publish product events: %v", err)
+				break
+			}
+			if published < outboxBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-u.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishProductEvents publishes one batch of product events and returns how many it sent
+func (u *ProductService) publishProductEvents(topic string) (int, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	locked, err := u.outboxRepo.LockPublisher(u.ctx, tx)
+	if err != nil || !locked {
+		return 0, err
+	}
+
+	events, err := u.outboxRepo.ClaimEvents(u.ctx, tx, outboxBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	IDs := make([]int64, 0, len(events))
+	for _, event := range events {
+		if _, _, err := kafka.SendProductEvent(topic, &types.ProductEvent{
+			EventID:        fmt.Sprintf("product-%d-v%d", event.ProductID, event.ProductVersion),
+			Type:           event.EventType,
+			SchemaVersion:  types.ProductEventSchemaVersion,
+			ProductID:      int32(event.ProductID),
+			ProductVersion: event.ProductVersion,
+			ChangedFields:  event.ChangedFields,
+			Product:        event.Product,
+			OccurredAt:     event.CreatedAt,
+		}); err != nil {
+			return 0, err
+		}
+		IDs = append(IDs, event.ID)
+	}
+
+	if len(IDs) > 0 {
+		if err := u.outboxRepo.MarkPublished(u.ctx, tx, IDs); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	rollback = false
+
+	if len(events) > 0 {
+		logrus.Infof("Published %d product events", len(events))
+	}
+	return len(events), nil
+}
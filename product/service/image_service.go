@@ -0,0 +1,224 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"product_service/helper"
+	"product_service/proto"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AddProductImage records an image the gateway has already stored. Without a
+// position the image goes after the product's existing images.
+func (u *ProductService) AddProductImage(image *proto.ProductImage) (*proto.ProductImage, error) {
+	if image.StorageKey == "" || image.ThumbnailKey == "" {
+		return nil, errors.New("storage_key and thumbnail_key are required")
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	if _, err := u.repo.GetProductByID(u.ctx, tx, int(image.ProductId)); err != nil {
+		return nil, err
+	}
+
+	if image.Position <= 0 {
+		position, err := u.imageRepo.NextPosition(u.ctx, tx, int(image.ProductId))
+		if err != nil {
+			return nil, err
+		}
+		image.Position = int32(position)
+	}
+
+	created, err := u.imageRepo.Create(u.ctx, tx, image)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.recordProductEvent(tx, ProductUpdated, int(created.ProductId)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Image %d added to product %d", created.Id, created.ProductId)
+	u.invalidateProductCache(int(created.ProductId))
+	return created, nil
+}
+
+// UpdateProductImage changes the alt text and position of an image
+func (u *ProductService) UpdateProductImage(image *proto.ProductImage) (*proto.ProductImage, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	existing, err := u.imageRepo.GetByID(u.ctx, tx, int(image.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	existing.AltText = image.AltText
+	existing.Position = image.Position
+
+	updated, err := u.imageRepo.Update(u.ctx, tx, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.recordProductEvent(tx, ProductUpdated, int(updated.ProductId)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	u.invalidateProductCache(int(updated.ProductId))
+	return updated, nil
+}
+
+// DeleteProductImage removes an image record and returns it, so the gateway can
+// delete the stored files
+func (u *ProductService) DeleteProductImage(ID int) (*proto.ProductImage, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	image, err := u.imageRepo.Delete(u.ctx, tx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.recordProductEvent(tx, ProductUpdated, int(image.ProductId)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	u.invalidateProductCache(int(image.ProductId))
+	return image, nil
+}
+
+func (u *ProductService) ListProductImages(productID int) ([]*proto.ProductImage, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	if _, err := u.repo.GetProductByID(u.ctx, tx, productID); err != nil {
+		return nil, err
+	}
+
+	return u.imageRepo.GetProductImages(u.ctx, tx, productID)
+}
+
+// ReorderProductImages puts a product's images in the given order. Every image of
+// the product must be listed exactly once.
+func (u *ProductService) ReorderProductImages(req *proto.ReorderProductImagesRequest) ([]*proto.ProductImage, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	current, err := u.imageRepo.GetProductImages(u.ctx, tx, int(req.ProductId))
+	if err != nil {
+		return nil, err
+	}
+	if len(uniqueIDs(req.ImageIds)) != len(req.ImageIds) || len(req.ImageIds) != len(current) {
+		return nil, fmt.Errorf("image_ids must list each of the product's %d images once", len(current))
+	}
+
+	for position, imageID := range req.ImageIds {
+		if err := u.imageRepo.SetPosition(u.ctx, tx, int(imageID), int(req.ProductId), position); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.recordProductEvent(tx, ProductUpdated, int(req.ProductId)); err != nil {
+		return nil, err
+	}
+
+	images, err := u.imageRepo.GetProductImages(u.ctx, tx, int(req.ProductId))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	u.invalidateProductCache(int(req.ProductId))
+	return images, nil
+}
+
+// attachImages loads the images of a page of products with a single query
+func (u *ProductService) attachImages(products []*proto.Product) error {
+	ids := make([]int32, 0, len(products))
+	byID := make(map[int32]*proto.Product, len(products))
+	for _, product := range products {
+		ids = append(ids, int32(product.Id))
+		byID[int32(product.Id)] = product
+	}
+
+	images, err := u.imageRepo.GetImagesForProducts(u.ctx, u.DB, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, image := range images {
+		if product := byID[image.ProductId]; product != nil {
+			product.Images = append(product.Images, image)
+		}
+	}
+	return nil
+}
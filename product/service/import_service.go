@@ -0,0 +1,275 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"product_service/helper"
+	"product_service/proto"
+	"product_service/repository"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// importBatchSize is how many rows are saved per transaction
+	importBatchSize = 500
+
+	// importReference is the reference type of stock movements made by an import
+	importReference = "import"
+)
+
+// ImportProducts upserts catalog rows by SKU. Rows are read from next until io.EOF and
+// saved in batches, each row in its own savepoint so a bad row only fails itself.
+// A dry run goes through the same steps and rolls every batch back.
+func (u *ProductService) ImportProducts(options *proto.ImportOptions, next func() (*proto.ProductRow, error)) (*proto.ImportReport, error) {
+	dryRun := options.DryRun
+	report := &proto.ImportReport{DryRun: dryRun}
+	seen := make(map[string]int32)
+	batch := make([]*proto.ProductRow, 0, importBatchSize)
+
+	for {
+		row, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		report.TotalRows++
+		row.Sku = strings.TrimSpace(row.Sku)
+		row.Name = strings.TrimSpace(row.Name)
+
+		if err := validateImportRow(row); err != nil {
+			addImportError(report, row, err)
+			continue
+		}
+		if line, ok := seen[row.Sku]; ok {
+			addImportError(report, row, fmt.Errorf("duplicate SKU, first seen on line %d", line))
+			continue
+		}
+		seen[row.Sku] = row.Line
+
+		batch = append(batch, row)
+		if len(batch) == importBatchSize {
+			if err := u.importBatch(batch, options, report); err != nil {
+				return nil, err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	if len(batch) > 0 {
+		if err := u.importBatch(batch, options, report); err != nil {
+			return nil, err
+		}
+	}
+
+	if !dryRun && report.Created+report.Updated > 0 {
+		u.invalidateCatalogCache()
+	}
+
+	logrus.Infof("Import finished: %d rows, %d created, %d updated, %d failed (dry run: %v)",
+		report.TotalRows, report.Created, report.Updated, report.Failed, dryRun)
+	return report, nil
+}
+
+func (u *ProductService) importBatch(rows []*proto.ProductRow, options *proto.ImportOptions, report *proto.ImportReport) error {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	var created, updated int32
+	for _, row := range rows {
+		var isNew bool
+		err := helper.Savepoint(u.ctx, tx, "import_row", func() error {
+			var err error
+			isNew, err = u.upsertRow(tx, row, options.Actor)
+			return err
+		})
+		if err != nil {
+			addImportError(report, row, err)
+			continue
+		}
+
+		if isNew {
+			created++
+		} else {
+			updated++
+		}
+	}
+
+	if !options.DryRun {
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		rollback = false
+	}
+
+	report.Created += created
+	report.Updated += updated
+	return nil
+}
+
+// upsertRow updates the variant with the row's SKU, or creates a product whose
+// default variant has that SKU. It reports whether a product was created. An
+// update keeps the name and description where the row leaves them blank. Stock
+// is the stock in the default warehouse, the only one an import adjusts; a change
+// is recorded as a manual adjustment by the importing actor.
+func (u *ProductService) upsertRow(tx *sql.Tx, row *proto.ProductRow, actor string) (bool, error) {
+	variant, err := u.variantRepo.GetBySKU(u.ctx, tx, row.Sku)
+	if errors.Is(err, repository.ErrVariantNotFound) {
+		if row.Name == "" {
+			return false, errors.New("name is required for a new SKU")
+		}
+
+		slug, err := u.productSlug(tx, "", row.Name, 0)
+		if err != nil {
+			return false, err
+		}
+
+		productID, err := u.repo.Create(u.ctx, tx, &proto.ProductPayload{
+			Name:        row.Name,
+			Description: row.Description,
+			Price:       row.Price,
+			Stock:       row.Stock,
+			Slug:        slug,
+		})
+		if err != nil {
+			return false, err
+		}
+
+		if err := u.priceRepo.RecordListPrice(u.ctx, tx, productID, row.Price); err != nil {
+			return false, err
+		}
+
+		variant, err := u.variantRepo.Create(u.ctx, tx, &proto.ProductVariant{
+			ProductId: int32(productID),
+			Sku:       row.Sku,
+			IsDefault: true,
+		})
+		if err != nil {
+			return false, err
+		}
+		if err := u.addInitialStock(tx, variant, row.Stock, actor, importReference); err != nil {
+			return false, err
+		}
+		return true, u.recordProductEvent(tx, ProductCreated, productID)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	product, err := u.repo.GetProductByID(u.ctx, tx, int(variant.ProductId))
+	if err != nil {
+		return false, err
+	}
+
+	variants, err := u.variantRepo.GetProductVariants(u.ctx, tx, int(variant.ProductId))
+	if err != nil {
+		return false, err
+	}
+
+	// A single-SKU product is priced on the product; a SKU among several gets an
+	// override. An active scheduled price is left to run its course.
+	product.Price = product.ListPrice
+	if len(variants) == 1 {
+		product.Price = row.Price
+		variant.Price = 0
+	} else {
+		variant.Price = row.Price
+	}
+	if row.Name != "" {
+		product.Name = row.Name
+	}
+	if row.Description != "" {
+		product.Description = row.Description
+	}
+
+	if err := u.repo.UpdateProduct(u.ctx, tx, product); err != nil {
+		return false, err
+	}
+	if err := u.priceRepo.RecordListPrice(u.ctx, tx, int(product.Id), product.Price); err != nil {
+		return false, err
+	}
+	if _, err := u.variantRepo.Update(u.ctx, tx, variant); err != nil {
+		return false, err
+	}
+	if err := u.recordProductEvent(tx, ProductUpdated, int(product.Id)); err != nil {
+		return false, err
+	}
+
+	warehouse, err := u.stockWarehouse(tx, 0)
+	if err != nil {
+		return false, err
+	}
+	levels, err := u.warehouseRepo.LockVariantLevels(u.ctx, tx, int(variant.Id))
+	if err != nil {
+		return false, err
+	}
+	var current int32
+	for _, level := range levels {
+		if level.WarehouseId == warehouse.Id {
+			current = level.Stock
+		}
+	}
+
+	if delta := row.Stock - current; delta != 0 {
+		if _, err := u.moveStock(tx, &proto.AdjustVariantStockRequest{
+			VariantId:     variant.Id,
+			WarehouseId:   warehouse.Id,
+			Delta:         delta,
+			Livemode:      true,
+			Reason:        MovementManualAdjustment,
+			ReferenceType: importReference,
+			Actor:         actor,
+		}); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// ExportProducts streams one row per SKU of the catalog to send, with its stock in
+// the default warehouse so an export imports back unchanged
+func (u *ProductService) ExportProducts(send func(row *proto.ProductRow) error) error {
+	return u.repo.ExportProducts(u.ctx, u.DB, send)
+}
+
+func validateImportRow(row *proto.ProductRow) error {
+	if row.Sku == "" {
+		return errors.New("sku is required")
+	}
+	if len(row.Sku) > 64 {
+		return errors.New("sku is longer than 64 characters")
+	}
+	if row.Price < 0 {
+		return errors.New("price cannot be negative")
+	}
+	if row.Stock < 0 {
+		return errors.New("stock cannot be negative")
+	}
+	return nil
+}
+
+func addImportError(report *proto.ImportReport, row *proto.ProductRow, err error) {
+	report.Failed++
+	report.Errors = append(report.Errors, &proto.ImportRowError{
+		Line:  row.Line,
+		Sku:   row.Sku,
+		Error: err.Error(),
+	})
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product_service/proto"
+	"product_service/repository"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateImportRow(t *testing.T) {
+	tests := []struct {
+		name    string
+		row     *proto.ProductRow
+		wantErr string
+	}{
+		{"valid", &proto.ProductRow{Sku: "MUG-1", Name: "Mug", Price: 10, Stock: 5}, ""},
+		{"blank name and free", &proto.ProductRow{Sku: "MUG-1"}, ""},
+		{"64 character SKU", &proto.ProductRow{Sku: strings.Repeat("A", 64)}, ""},
+		{"missing SKU", &proto.ProductRow{Name: "Mug"}, "sku is required"},
+		{"65 character SKU", &proto.ProductRow{Sku: strings.Repeat("A", 65)}, "longer than 64"},
+		{"negative price", &proto.ProductRow{Sku: "MUG-1", Price: -1}, "price cannot be negative"},
+		{"negative stock", &proto.ProductRow{Sku: "MUG-1", Stock: -1}, "stock cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateImportRow(tt.row)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateImportRow() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateImportRow() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestUpsertRow imports a SKU twice in a transaction it rolls back, so it leaves
+// nothing behind
+func TestUpsertRow(t *testing.T) {
+	DB := testDB(t)
+	ctx := context.Background()
+
+	variantRepo := repository.NewVariantRepositoryImpl()
+	warehouseRepo := repository.NewWarehouseRepositoryImpl()
+	repo := repository.NewProductRepositoryImpl()
+	svc := NewProductService(repo, DB, ctx, repository.NewCategoryRepositoryImpl(), repository.NewTagRepositoryImpl(),
+		variantRepo, repository.NewImageRepositoryImpl(), repository.NewInventoryRepositoryImpl(), warehouseRepo,
+		repository.NewPriceRepositoryImpl(), repository.NewReviewRepositoryImpl(), repository.NewWishlistRepositoryImpl(),
+		repository.NewOutboxRepositoryImpl(), repository.NewRecommendationRepositoryImpl(), repository.NewBundleRepositoryImpl(),
+		repository.NewPriceRuleRepositoryImpl(), repository.NewReorderRepositoryImpl(), repository.NewPurchaseOrderRepositoryImpl(),
+		repository.NewTranslationRepositoryImpl(), repository.NewDigitalRepositoryImpl(), nil)
+
+	tx, err := DB.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+
+	suffix := time.Now().UnixNano() % 1e9
+	sku := fmt.Sprintf("TEST-IMPORT-%d", suffix)
+
+	created, err := svc.upsertRow(tx, &proto.ProductRow{Sku: sku, Name: "Import mug", Description: "Blue", Price: 100, Stock: 5}, "test")
+	if err != nil {
+		t.Fatalf("upsertRow() error = %v", err)
+	}
+	if !created {
+		t.Fatal("upsertRow() did not create a product for a new SKU")
+	}
+
+	variant, err := variantRepo.GetBySKU(ctx, tx, sku)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stock in another warehouse is not the import's to change
+	var otherID int32
+	if err := tx.QueryRow(`INSERT INTO warehouses(code, name, priority) VALUES ($1, 'Import test', 1000) RETURNING id`,
+		fmt.Sprintf("T%d", suffix)).Scan(&otherID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.moveStock(tx, &proto.AdjustVariantStockRequest{
+		VariantId:   variant.Id,
+		WarehouseId: otherID,
+		Delta:       3,
+		Livemode:    true,
+		Reason:      MovementManualAdjustment,
+		Actor:       "test",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Blank name and description keep what the product has
+	created, err = svc.upsertRow(tx, &proto.ProductRow{Sku: sku, Price: 120, Stock: 2}, "test")
+	if err != nil {
+		t.Fatalf("second upsertRow() error = %v", err)
+	}
+	if created {
+		t.Error("upsertRow() created a product for an existing SKU")
+	}
+
+	product, err := repo.GetProductByID(ctx, tx, int(variant.ProductId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product.Name != "Import mug" || product.Description != "Blue" || product.Price != 120 {
+		t.Errorf("product = %q, %q at %v, want \"Import mug\", \"Blue\" at 120", product.Name, product.Description, product.Price)
+	}
+
+	defaultWarehouse, err := warehouseRepo.GetDefault(ctx, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	levels, err := warehouseRepo.LockVariantLevels(ctx, tx, int(variant.Id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stock := make(map[int32]int32)
+	for _, level := range levels {
+		stock[level.WarehouseId] = level.Stock
+	}
+	if stock[defaultWarehouse.Id] != 2 || stock[otherID] != 3 {
+		t.Errorf("stock = %d in the default warehouse and %d in the other, want 2 and 3", stock[defaultWarehouse.Id], stock[otherID])
+	}
+
+	if _, err := svc.upsertRow(tx, &proto.ProductRow{Sku: sku + "-NEW", Price: 10}, "test"); err == nil || !strings.Contains(err.Error(), "name is required") {
+		t.Errorf("upsertRow() of a new SKU without a name error = %v, want name is required", err)
+	}
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"product_service/helper"
+	"product_service/proto"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Reasons a stock movement is recorded with
+const (
+	MovementSale               = "sale"
+	MovementRestock            = "restock"
+	MovementReservationRelease = "reservation_release"
+	MovementManualAdjustment   = "manual_adjustment"
+	MovementReturn             = "return"
+	MovementTransfer           = "transfer"
+)
+
+var errTransferReason = errors.New("use TransferStock to move stock between warehouses")
+
+const movementPageSize = 20
+
+// moveStock applies a stock change to a variant in one warehouse and records it in
+// the inventory ledger within tx. It is the only way stock changes after a variant
+// is created; the variant and product totals are recomputed from the warehouses,
+// and those of the bundles the variant is a component of from their components.
+func (u *ProductService) moveStock(tx *sql.Tx, req *proto.AdjustVariantStockRequest) (*proto.ProductVariant, error) {
+	if err := validateMovement(req.Reason, req.Delta); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.variantRepo.GetByID(u.ctx, tx, int(req.VariantId)); err != nil {
+		return nil, err
+	}
+
+	bundles, err := u.bundleRepo.GetBundleVariants(u.ctx, tx, []int32{req.VariantId})
+	if err != nil {
+		return nil, err
+	}
+	if len(bundles) > 0 {
+		return nil, errors.New("a bundle has no stock of its own, change the stock of its components")
+	}
+
+	warehouse, err := u.stockWarehouse(tx, int(req.WarehouseId))
+	if err != nil {
+		return nil, err
+	}
+
+	stockAfter, err := u.warehouseRepo.AdjustLevel(u.ctx, tx, int(req.VariantId), int(warehouse.Id), int(req.Delta), req.Livemode)
+	if err != nil {
+		return nil, err
+	}
+
+	variant, err := u.variantRepo.SyncVariantStock(u.ctx, tx, int(req.VariantId))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.inventoryRepo.Record(u.ctx, tx, &proto.InventoryMovement{
+		ProductId:     variant.ProductId,
+		VariantId:     variant.Id,
+		Sku:           variant.Sku,
+		Delta:         req.Delta,
+		StockAfter:    stockAfter,
+		Livemode:      req.Livemode,
+		Reason:        req.Reason,
+		ReferenceType: req.ReferenceType,
+		ReferenceId:   req.ReferenceId,
+		Actor:         req.Actor,
+		Note:          req.Note,
+		WarehouseId:   warehouse.Id,
+	}); err != nil {
+		return nil, err
+	}
+
+	before, after, err := u.variantRepo.SyncProductStock(u.ctx, tx, int(variant.ProductId))
+	if err != nil {
+		return nil, err
+	}
+	// Only live stock changes are announced. A transfer leaves the product's total as it
+	// was, though its first half can briefly take it to zero.
+	announce := req.Livemode && req.Reason != MovementTransfer
+	if announce {
+		if err := u.announceStock(tx, int(variant.ProductId), before, after); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.syncBundles(tx, int(variant.Id), announce); err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Stock of %s in %s changed by %d (%s by %s)", variant.Sku, warehouse.Code, req.Delta, req.Reason, req.Actor)
+	return variant, nil
+}
+
+// announceStock tells subscribers and the outbox about a change of a product's live stock
+func (u *ProductService) announceStock(tx *sql.Tx, productID int, before int32, after int32) error {
+	// Stock coming back wakes up back-in-stock subscribers
+	if before <= 0 && after > 0 {
+		if err := u.wishlistRepo.RecordRestock(u.ctx, tx, productID, int(after)); err != nil {
+			return err
+		}
+	}
+
+	// Stock going up or running out is published; sales that leave stock are not, so
+	// checkout does not produce an event per product sold
+	if after > before || (after <= 0 && before > 0) {
+		eventType := ProductRestocked
+		if after <= 0 {
+			eventType = ProductSoldOut
+		}
+		if err := u.recordProductEvent(tx, eventType, productID); err != nil {
+			return err
+		}
+	}
+
+	// Stock falling to the product's reorder threshold is published once, when it
+	// crosses it; running out entirely is announced as sold out instead
+	if after < before && after > 0 {
+		threshold, err := u.reorderRepo.GetThreshold(u.ctx, tx, productID)
+		if err != nil {
+			return err
+		}
+		if threshold > 0 && before > threshold && after <= threshold {
+			if err := u.recordProductEvent(tx, ProductLowStock, productID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// stockWarehouse returns the warehouse a stock change applies to; 0 is the default
+func (u *ProductService) stockWarehouse(tx *sql.Tx, ID int) (*proto.Warehouse, error) {
+	if ID == 0 {
+		return u.warehouseRepo.GetDefault(u.ctx, tx)
+	}
+	return u.warehouseRepo.GetByID(u.ctx, tx, ID)
+}
+
+// AdjustStockBatch applies several stock changes in one transaction, so either all of
+// them are recorded or none. Stock levels are locked in variant and warehouse ID
+// order, so concurrent batches touching the same variants cannot deadlock.
+func (u *ProductService) AdjustStockBatch(adjustments []*proto.AdjustVariantStockRequest) ([]*proto.ProductVariant, error) {
+	if len(adjustments) == 0 {
+		return nil, errors.New("adjustments are required")
+	}
+	for _, adjustment := range adjustments {
+		if adjustment.Reason == MovementTransfer {
+			return nil, errTransferReason
+		}
+	}
+
+	order := make([]int, len(adjustments))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		x, y := adjustments[order[a]], adjustments[order[b]]
+		if x.VariantId != y.VariantId {
+			return x.VariantId < y.VariantId
+		}
+		return x.WarehouseId < y.WarehouseId
+	})
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	variants := make([]*proto.ProductVariant, len(adjustments))
+	for _, i := range order {
+		variant, err := u.moveStock(tx, adjustments[i])
+		if err != nil {
+			return nil, fmt.Errorf("variant %d: %w", adjustments[i].VariantId, err)
+		}
+		variants[i] = variant
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	invalidated := make(map[int32]bool)
+	for _, variant := range variants {
+		if !invalidated[variant.ProductId] {
+			invalidated[variant.ProductId] = true
+			u.invalidateProductCache(int(variant.ProductId))
+		}
+	}
+	return variants, nil
+}
+
+// addInitialStock adds the stock a variant is created with to both pools of the
+// default warehouse as a restock, so the ledger of a new product adds up from the start
+func (u *ProductService) addInitialStock(tx *sql.Tx, variant *proto.ProductVariant, stock int32, actor string, referenceType string) error {
+	if stock == 0 {
+		return nil
+	}
+
+	// The sandbox pool is stocked first, so the product's created event, recorded with
+	// the live stock, carries both
+	for _, livemode := range []bool{false, true} {
+		if _, err := u.moveStock(tx, &proto.AdjustVariantStockRequest{
+			VariantId:     variant.Id,
+			Delta:         stock,
+			Livemode:      livemode,
+			Reason:        MovementRestock,
+			ReferenceType: referenceType,
+			Actor:         actor,
+			Note:          "Initial stock",
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListInventoryMovements returns a page of a product's stock history in one pool, newest first
+func (u *ProductService) ListInventoryMovements(req *proto.ListInventoryMovementsRequest) (*proto.InventoryMovementList, error) {
+	page := int(req.Page)
+	if page < 1 {
+		page = 1
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	if _, err := u.repo.GetProductByID(u.ctx, tx, int(req.ProductId)); err != nil {
+		return nil, err
+	}
+
+	movements, total, err := u.inventoryRepo.GetProductMovements(u.ctx, tx, int(req.ProductId), req.Livemode, movementPageSize, (page-1)*movementPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.InventoryMovementList{
+		Movements: movements,
+		Page:      int32(page),
+		Total:     int32(total),
+		TotalPage: int32((total + movementPageSize - 1) / movementPageSize),
+	}, nil
+}
+
+// validateMovement checks the direction of a stock change against its reason
+func validateMovement(reason string, delta int32) error {
+	if delta == 0 {
+		return errors.New("delta cannot be zero")
+	}
+
+	switch reason {
+	case MovementSale:
+		if delta > 0 {
+			return errors.New("a sale must take stock")
+		}
+	case MovementRestock, MovementReservationRelease, MovementReturn:
+		if delta < 0 {
+			return fmt.Errorf("a %s must add stock", reason)
+		}
+	case MovementManualAdjustment, MovementTransfer:
+	case "":
+		return errors.New("reason is required")
+	default:
+		return fmt.Errorf("unknown reason %q", reason)
+	}
+	return nil
+}
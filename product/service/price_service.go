@@ -0,0 +1,274 @@
+package service
+
+import (
+	"errors"
+	"product_service/helper"
+	"product_service/proto"
+	"product_service/repository"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// priceSchedulerMaxWait bounds how long the price scheduler sleeps, so schedules
+// made by other instances of the service are picked up
+const priceSchedulerMaxWait = 5 * time.Minute
+
+// SchedulePrice saves a price that overrides the list price from effective_from until
+// effective_to, or indefinitely without one. Scheduled prices of a product cannot overlap.
+// A price that already applies is announced as a repricing right away; a later one is
+// announced by the price scheduler once it starts.
+func (u *ProductService) SchedulePrice(price *proto.ProductPrice) (*proto.ProductPrice, error) {
+	if price.Price < 0 {
+		return nil, errors.New("price cannot be negative")
+	}
+
+	from, err := time.Parse(time.RFC3339, price.EffectiveFrom)
+	if err != nil {
+		return nil, errors.New("effective_from must be an RFC 3339 time")
+	}
+	if price.EffectiveTo != "" {
+		to, err := time.Parse(time.RFC3339, price.EffectiveTo)
+		if err != nil {
+			return nil, errors.New("effective_to must be an RFC 3339 time")
+		}
+		if !to.After(from) {
+			return nil, errors.New("effective_to must be after effective_from")
+		}
+		if !to.After(time.Now()) {
+			return nil, errors.New("effective_to must be in the future")
+		}
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	// The product stays locked until the price is saved, so two schedules of it cannot
+	// both pass the overlap check
+	if _, err := u.outboxRepo.LockProduct(u.ctx, tx, int(price.ProductId)); err != nil {
+		return nil, err
+	}
+	if _, err := u.repo.GetProductByID(u.ctx, tx, int(price.ProductId)); err != nil {
+		return nil, err
+	}
+
+	overlap, err := u.priceRepo.HasOverlap(u.ctx, tx, price)
+	if err != nil {
+		return nil, err
+	}
+	if overlap {
+		return nil, errors.New("price overlaps another scheduled price of the product")
+	}
+
+	scheduled, err := u.priceRepo.Schedule(u.ctx, tx, price)
+	if err != nil {
+		return nil, err
+	}
+
+	// Records nothing unless the price already applies
+	if err := u.recordProductEvent(tx, ProductUpdated, int(scheduled.ProductId)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Price %.2f scheduled for product %d from %s", scheduled.Price, scheduled.ProductId, price.EffectiveFrom)
+	if !from.After(time.Now()) {
+		u.invalidateProductCache(int(scheduled.ProductId))
+	}
+	u.wakePriceScheduler()
+	return scheduled, nil
+}
+
+// EndScheduledPrice cancels a scheduled price that has not started, or ends an active
+// one now so it stays in the price history
+func (u *ProductService) EndScheduledPrice(ID int) (*proto.ProductPrice, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	price, err := u.priceRepo.GetByID(u.ctx, tx, ID)
+	if err != nil {
+		return nil, err
+	}
+	if price.Kind != "scheduled" {
+		return nil, errors.New("list prices change with the product")
+	}
+
+	err = u.priceRepo.Delete(u.ctx, tx, ID)
+	if errors.Is(err, repository.ErrPriceNotFound) {
+		price, err = u.priceRepo.End(u.ctx, tx, ID)
+		if errors.Is(err, repository.ErrPriceNotFound) {
+			return nil, errors.New("price has already ended")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.recordProductEvent(tx, ProductUpdated, int(price.ProductId)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Scheduled price %d of product %d ended", ID, price.ProductId)
+	u.invalidateProductCache(int(price.ProductId))
+	u.wakePriceScheduler()
+	return price, nil
+}
+
+// GetPriceHistory returns every price of a product, newest first, and the price it
+// sold for at req.At when given
+func (u *ProductService) GetPriceHistory(req *proto.PriceHistoryRequest) (*proto.PriceHistory, error) {
+	if req.At != "" {
+		if _, err := time.Parse(time.RFC3339, req.At); err != nil {
+			return nil, errors.New("at must be an RFC 3339 time")
+		}
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	if _, err := u.repo.GetProductByID(u.ctx, tx, int(req.ProductId)); err != nil {
+		return nil, err
+	}
+
+	prices, err := u.priceRepo.GetHistory(u.ctx, tx, int(req.ProductId))
+	if err != nil {
+		return nil, err
+	}
+
+	history := &proto.PriceHistory{Prices: prices}
+	if req.At != "" {
+		history.PriceAt, err = u.priceRepo.PriceAt(u.ctx, tx, int(req.ProductId), req.At)
+		if errors.Is(err, repository.ErrPriceNotFound) {
+			return nil, errors.New("product had no price at that time")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return history, nil
+}
+
+// RunPriceScheduler sleeps until the next scheduled price starts or ends and then drops
+// the cached products and listings, which were cached with the old price, and records
+// the repricing of the products whose price changed. It runs for the life of the service.
+func (u *ProductService) RunPriceScheduler() {
+	logrus.Info("Price scheduler started")
+	_, since, err := u.priceRepo.TransitionedProducts(u.ctx, u.DB, "")
+	if err != nil {
+		logrus.Errorf("Failed to read the database time: %v", err)
+	}
+
+	for {
+		wait, ok, err := u.priceRepo.NextTransition(u.ctx, u.DB)
+		if err != nil {
+			logrus.Errorf("Failed to find the next price change: %v", err)
+		}
+
+		transition := ok && wait <= priceSchedulerMaxWait
+		if !transition {
+			wait = priceSchedulerMaxWait
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-u.ctx.Done():
+			timer.Stop()
+			return
+		case <-u.priceScheduled:
+			timer.Stop()
+		case <-timer.C:
+			if transition {
+				logrus.Info("Scheduled price changed, invalidating product caches")
+				u.invalidateCatalogCache()
+				since = u.recordPriceTransitions(since)
+			}
+		}
+	}
+}
+
+// wakePriceScheduler makes the scheduler look for the next price change again
+func (u *ProductService) wakePriceScheduler() {
+	select {
+	case u.priceScheduled <- struct{}{}:
+	default:
+	}
+}
+
+// recordPriceTransitions records an event for every product whose scheduled price
+// started or ended since the given database time, and returns the time to continue
+// from. Several instances may record the same transition; only the first one finds
+// the product changed.
+func (u *ProductService) recordPriceTransitions(since string) string {
+	productIDs, until, err := u.priceRepo.TransitionedProducts(u.ctx, u.DB, since)
+	if err != nil {
+		logrus.Errorf("Failed to find repriced products: %v", err)
+		return since
+	}
+
+	for _, productID := range productIDs {
+		if err := u.recordScheduledPrice(productID); err != nil {
+			logrus.Errorf("Failed to record repricing of product %d: %v", productID, err)
+		}
+	}
+	return until
+}
+
+func (u *ProductService) recordScheduledPrice(productID int) error {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	if err := u.recordProductEvent(tx, ProductUpdated, productID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rollback = false
+	return nil
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"product_service/helper"
+	"product_service/proto"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CreatePriceRule saves a rule that prices a product from a minimum quantity, for one
+// customer group or for every customer
+func (u *ProductService) CreatePriceRule(rule *proto.PriceRule) (*proto.PriceRule, error) {
+	if rule.MinQuantity == 0 {
+		rule.MinQuantity = 1
+	}
+	if rule.MinQuantity < 0 {
+		return nil, errors.New("min_quantity must be positive")
+	}
+	switch {
+	case rule.DiscountPercent != 0 && rule.Price != 0:
+		return nil, errors.New("a rule sets either price or discount_percent")
+	case rule.DiscountPercent < 0 || rule.DiscountPercent > 100:
+		return nil, errors.New("discount_percent must be between 0 and 100")
+	case rule.Price < 0:
+		return nil, errors.New("price cannot be negative")
+	case rule.DiscountPercent == 0 && rule.Price == 0:
+		return nil, errors.New("price or discount_percent is required")
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	if _, err := u.repo.GetProductByID(u.ctx, tx, int(rule.ProductId)); err != nil {
+		return nil, err
+	}
+	if rule.CustomerGroupId != 0 {
+		exists, err := u.priceRuleRepo.GroupExists(u.ctx, tx, int(rule.CustomerGroupId))
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, errors.New("customer group not found")
+		}
+	}
+
+	created, err := u.priceRuleRepo.Create(u.ctx, tx, rule)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Price rule %d created for product %d", created.Id, created.ProductId)
+	return created, nil
+}
+
+func (u *ProductService) DeletePriceRule(ID int) error {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	rule, err := u.priceRuleRepo.Delete(u.ctx, tx, ID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rollback = false
+
+	logrus.Infof("Price rule %d of product %d deleted", rule.Id, rule.ProductId)
+	return nil
+}
+
+func (u *ProductService) ListPriceRules(productID int) ([]*proto.PriceRule, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	if _, err := u.repo.GetProductByID(u.ctx, tx, productID); err != nil {
+		return nil, err
+	}
+	return u.priceRuleRepo.ListForProduct(u.ctx, tx, productID)
+}
+
+// QuotePrices prices the lines of a cart for a user. A line's base price is what its
+// variant sells for now, and the rule that gives the lowest unit price below it
+// applies, among those for every customer and for the user's groups whose minimum
+// quantity the product's lines reach together.
+func (u *ProductService) QuotePrices(req *proto.QuotePricesRequest) (*proto.PriceQuote, error) {
+	productIDs := make([]int32, 0, len(req.Lines))
+	totals := make(map[int32]int32, len(req.Lines))
+	for _, line := range req.Lines {
+		if line.Quantity <= 0 {
+			return nil, errors.New("quantity must be positive")
+		}
+		if _, ok := totals[line.ProductId]; !ok {
+			productIDs = append(productIDs, line.ProductId)
+		}
+		totals[line.ProductId] += line.Quantity
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	products, err := u.repo.GetProductsByIDs(u.ctx, tx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	prices := make(map[int32]float64, len(products))
+	for _, product := range products {
+		prices[int32(product.Id)] = product.Price
+	}
+
+	variants, err := u.variantRepo.GetVariantsForProducts(u.ctx, tx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	productVariants := make(map[int32][]*proto.ProductVariant, len(products))
+	for _, variant := range variants {
+		productVariants[variant.ProductId] = append(productVariants[variant.ProductId], variant)
+	}
+
+	rules, err := u.priceRuleRepo.GetApplicable(u.ctx, tx, int(req.UserId), productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &proto.PriceQuote{}
+	for _, line := range req.Lines {
+		price, ok := prices[line.ProductId]
+		if !ok {
+			return nil, fmt.Errorf("product %d not found", line.ProductId)
+		}
+		variant, err := quotedVariant(productVariants[line.ProductId], line)
+		if err != nil {
+			return nil, err
+		}
+
+		base := variant.Price
+		if base == 0 {
+			base = price
+		}
+		quoted := &proto.QuotedLine{
+			ProductId: line.ProductId,
+			VariantId: variant.Id,
+			Quantity:  line.Quantity,
+			BasePrice: base,
+			UnitPrice: base,
+		}
+		if rule, unitPrice := bestPriceRule(rules[line.ProductId], base, totals[line.ProductId]); rule != nil {
+			quoted.UnitPrice = unitPrice
+			quoted.PriceRuleId = rule.Id
+		}
+		quote.Lines = append(quote.Lines, quoted)
+	}
+	return quote, nil
+}
+
+// quotedVariant finds the variant a line is for; a product with one variant needs no
+// variant ID
+func quotedVariant(variants []*proto.ProductVariant, line *proto.QuoteLine) (*proto.ProductVariant, error) {
+	if line.VariantId == 0 {
+		if len(variants) == 1 {
+			return variants[0], nil
+		}
+		return nil, fmt.Errorf("variant_id is required for product %d", line.ProductId)
+	}
+
+	for _, variant := range variants {
+		if variant.Id == line.VariantId {
+			return variant, nil
+		}
+	}
+	return nil, fmt.Errorf("variant %d not found for product %d", line.VariantId, line.ProductId)
+}
+
+// bestPriceRule picks the rule giving the lowest unit price below base at quantity, and
+// returns it with that price. Ties go to the rule with the higher minimum quantity,
+// then to a customer group's rule over one for everyone, then to the older rule.
+func bestPriceRule(rules []*proto.PriceRule, base float64, quantity int32) (*proto.PriceRule, float64) {
+	var best *proto.PriceRule
+	bestPrice := base
+	for _, rule := range rules {
+		if rule.MinQuantity > quantity {
+			continue
+		}
+
+		price := rule.Price
+		if rule.DiscountPercent > 0 {
+			price = math.Round(base*(100-rule.DiscountPercent)) / 100
+		}
+		if price >= base {
+			continue
+		}
+
+		if best == nil || price < bestPrice || (price == bestPrice && preferRule(rule, best)) {
+			best = rule
+			bestPrice = price
+		}
+	}
+	return best, bestPrice
+}
+
+func preferRule(rule *proto.PriceRule, other *proto.PriceRule) bool {
+	if rule.MinQuantity != other.MinQuantity {
+		return rule.MinQuantity > other.MinQuantity
+	}
+	if (rule.CustomerGroupId != 0) != (other.CustomerGroupId != 0) {
+		return rule.CustomerGroupId != 0
+	}
+	return rule.Id < other.Id
+}
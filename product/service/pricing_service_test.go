@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"product_service/proto"
+	"product_service/repository"
+	"strings"
+	"testing"
+)
+
+func TestBestPriceRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     []*proto.PriceRule
+		base      float64
+		quantity  int32
+		wantRule  int32 // 0 when no rule applies
+		wantPrice float64
+	}{
+		{
+			name: "no rules", base: 100, quantity: 1,
+			wantPrice: 100,
+		},
+		{
+			name: "discount", base: 100, quantity: 1,
+			rules:    []*proto.PriceRule{{Id: 1, MinQuantity: 1, DiscountPercent: 10}},
+			wantRule: 1, wantPrice: 90,
+		},
+		{
+			name: "discount is rounded to cents", base: 99.99, quantity: 1,
+			rules:    []*proto.PriceRule{{Id: 1, MinQuantity: 1, DiscountPercent: 15}},
+			wantRule: 1, wantPrice: 84.99,
+		},
+		{
+			name: "minimum quantity not reached", base: 100, quantity: 9,
+			rules:     []*proto.PriceRule{{Id: 1, MinQuantity: 10, Price: 80}},
+			wantPrice: 100,
+		},
+		{
+			name: "rule at or above the base price is ignored", base: 100, quantity: 1,
+			rules:     []*proto.PriceRule{{Id: 1, MinQuantity: 1, Price: 120}, {Id: 2, MinQuantity: 1, Price: 100}},
+			wantPrice: 100,
+		},
+		{
+			name: "lowest price wins", base: 100, quantity: 10,
+			rules: []*proto.PriceRule{
+				{Id: 1, MinQuantity: 1, DiscountPercent: 10},
+				{Id: 2, MinQuantity: 10, Price: 75},
+				{Id: 3, CustomerGroupId: 5, MinQuantity: 5, Price: 80},
+			},
+			wantRule: 2, wantPrice: 75,
+		},
+		{
+			name: "tie goes to the higher minimum quantity", base: 100, quantity: 5,
+			rules:    []*proto.PriceRule{{Id: 1, MinQuantity: 1, DiscountPercent: 15}, {Id: 2, MinQuantity: 5, Price: 85}},
+			wantRule: 2, wantPrice: 85,
+		},
+		{
+			name: "tie goes to the customer group's rule", base: 100, quantity: 10,
+			rules:    []*proto.PriceRule{{Id: 1, MinQuantity: 10, Price: 80}, {Id: 2, CustomerGroupId: 5, MinQuantity: 10, Price: 80}},
+			wantRule: 2, wantPrice: 80,
+		},
+		{
+			name: "tie goes to the older rule", base: 100, quantity: 10,
+			rules:    []*proto.PriceRule{{Id: 7, MinQuantity: 10, Price: 80}, {Id: 3, MinQuantity: 10, Price: 80}},
+			wantRule: 3, wantPrice: 80,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, price := bestPriceRule(tt.rules, tt.base, tt.quantity)
+
+			var ruleID int32
+			if rule != nil {
+				ruleID = rule.Id
+			}
+			if ruleID != tt.wantRule || price != tt.wantPrice {
+				t.Errorf("bestPriceRule() = rule %d at %v, want rule %d at %v", ruleID, price, tt.wantRule, tt.wantPrice)
+			}
+		})
+	}
+}
+
+type quoteProducts struct {
+	repository.ProductRepository
+	products []*proto.Product
+}
+
+func (f *quoteProducts) GetProductsByIDs(ctx context.Context, tx *sql.Tx, IDs []int32) ([]*proto.Product, error) {
+	return f.products, nil
+}
+
+type quoteVariants struct {
+	repository.VariantRepository
+	variants []*proto.ProductVariant
+}
+
+func (f *quoteVariants) GetVariantsForProducts(ctx context.Context, tx *sql.Tx, productIDs []int32) ([]*proto.ProductVariant, error) {
+	return f.variants, nil
+}
+
+// quoteRules returns the rules of a product to every user but userID 0, who belongs
+// to no customer group and only gets the rules for everyone
+type quoteRules struct {
+	repository.PriceRuleRepository
+	rules map[int32][]*proto.PriceRule
+}
+
+func (f *quoteRules) GetApplicable(ctx context.Context, tx *sql.Tx, userID int, productIDs []int32) (map[int32][]*proto.PriceRule, error) {
+	result := make(map[int32][]*proto.PriceRule)
+	for productID, rules := range f.rules {
+		for _, rule := range rules {
+			if rule.CustomerGroupId == 0 || userID != 0 {
+				result[productID] = append(result[productID], rule)
+			}
+		}
+	}
+	return result, nil
+}
+
+func TestQuotePrices(t *testing.T) {
+	DB := testDB(t)
+
+	svc := &ProductService{
+		DB:  DB,
+		ctx: context.Background(),
+		repo: &quoteProducts{products: []*proto.Product{
+			{Id: 1, Price: 100},
+			{Id: 2, Price: 50},
+		}},
+		variantRepo: &quoteVariants{variants: []*proto.ProductVariant{
+			{Id: 10, ProductId: 1, IsDefault: true},
+			{Id: 20, ProductId: 2, IsDefault: true},
+			{Id: 21, ProductId: 2, Price: 60},
+		}},
+		priceRuleRepo: &quoteRules{rules: map[int32][]*proto.PriceRule{
+			1: {{Id: 1, ProductId: 1, MinQuantity: 5, Price: 90}, {Id: 2, ProductId: 1, CustomerGroupId: 3, MinQuantity: 1, DiscountPercent: 20}},
+			2: {{Id: 3, ProductId: 2, MinQuantity: 1, DiscountPercent: 10}},
+		}},
+	}
+
+	type line struct {
+		variant   int32
+		base      float64
+		unitPrice float64
+		rule      int32
+	}
+
+	tests := []struct {
+		name    string
+		req     *proto.QuotePricesRequest
+		want    []line
+		wantErr string
+	}{
+		{
+			name: "minimum quantity counts every line of the product",
+			req: &proto.QuotePricesRequest{Lines: []*proto.QuoteLine{
+				{ProductId: 1, Quantity: 2},
+				{ProductId: 1, Quantity: 3},
+			}},
+			want: []line{{10, 100, 90, 1}, {10, 100, 90, 1}},
+		},
+		{
+			name: "customer group rule for a user in the group",
+			req:  &proto.QuotePricesRequest{UserId: 7, Lines: []*proto.QuoteLine{{ProductId: 1, Quantity: 1}}},
+			want: []line{{10, 100, 80, 2}},
+		},
+		{
+			name: "variant price override is the base price",
+			req: &proto.QuotePricesRequest{Lines: []*proto.QuoteLine{
+				{ProductId: 2, VariantId: 20, Quantity: 1},
+				{ProductId: 2, VariantId: 21, Quantity: 1},
+			}},
+			want: []line{{20, 50, 45, 3}, {21, 60, 54, 3}},
+		},
+		{
+			name:    "variant required for a product with several",
+			req:     &proto.QuotePricesRequest{Lines: []*proto.QuoteLine{{ProductId: 2, Quantity: 1}}},
+			wantErr: "variant_id is required",
+		},
+		{
+			name:    "variant of another product",
+			req:     &proto.QuotePricesRequest{Lines: []*proto.QuoteLine{{ProductId: 1, VariantId: 20, Quantity: 1}}},
+			wantErr: "variant 20 not found",
+		},
+		{
+			name:    "unknown product",
+			req:     &proto.QuotePricesRequest{Lines: []*proto.QuoteLine{{ProductId: 9, Quantity: 1}}},
+			wantErr: "product 9 not found",
+		},
+		{
+			name:    "quantity must be positive",
+			req:     &proto.QuotePricesRequest{Lines: []*proto.QuoteLine{{ProductId: 1}}},
+			wantErr: "quantity must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := svc.QuotePrices(tt.req)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("QuotePrices() error = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("QuotePrices() error = %v", err)
+			}
+
+			if len(quote.Lines) != len(tt.want) {
+				t.Fatalf("got %d lines, want %d", len(quote.Lines), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := quote.Lines[i]
+				if got.VariantId != want.variant || got.BasePrice != want.base || got.UnitPrice != want.unitPrice || got.PriceRuleId != want.rule {
+					t.Errorf("line %d = variant %d, base %v, unit %v, rule %d; want variant %d, base %v, unit %v, rule %d",
+						i, got.VariantId, got.BasePrice, got.UnitPrice, got.PriceRuleId, want.variant, want.base, want.unitPrice, want.rule)
+				}
+			}
+		})
+	}
+}
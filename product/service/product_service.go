@@ -1,185 +1,788 @@
-package service
-
-import (
-	"context"
-	"database/sql"
-	"fmt"
-	"product_service/cmd/db"
-	"product_service/helper"
-	"product_service/proto"
-	"product_service/repository"
-	"time"
-
-	"github.com/sirupsen/logrus"
-)
-
-const (
-	productCacheTTL     = 10 * time.Minute
-	productListCacheTTL = 5 * time.Minute
-)
-
-type ProductService struct {
-	repo repository.ProductRepository
-	DB   *sql.DB
-	ctx  context.Context
-}
-
-func NewProductService(ProductRepository repository.ProductRepository, DB *sql.DB, ctx context.Context) *ProductService {
-	return &ProductService{
-		repo: ProductRepository,
-		DB:   DB,
-		ctx:  ctx,
-	}
-}
-
-func (u *ProductService) Create(payload *proto.ProductRequest) error {
-	tx, err := u.DB.Begin()
-	if err != nil {
-		return err
-	}
-	defer helper.CommitOrRollback(tx)
-
-	logrus.Info("calling create product service")
-	if err := u.repo.Create(u.ctx, tx, payload.Payload); err != nil {
-		return err
-	}
-
-	// Invalidate product list cache after create
-	if db.RedisClient != nil {
-		if err := db.DeleteCachePattern(u.ctx, "products:list:*"); err != nil {
-			logrus.Warnf("Failed to invalidate product list cache: %v", err)
-		} else {
-			logrus.Info("Product list cache invalidated after create")
-		}
-	}
-
-	return nil
-}
-
-func (u *ProductService) GetProductByID(ID int) (*proto.Product, error) {
-	cacheKey := fmt.Sprintf("product:%d", ID)
-
-	cachedProduct, err := db.GetCacheProduct(u.ctx, cacheKey)
-	if err == nil {
-		logrus.Infof("Cache HIT for product ID: %d", ID)
-		return cachedProduct, nil
-	}
-	logrus.Debugf("Cache MISS for product ID: %d", ID)
-
-	// Cache miss or Redis not available, query from database
-	tx, err := u.DB.Begin()
-	if err != nil {
-		return nil, err
-	}
-	defer helper.CommitOrRollback(tx)
-
-	logrus.Info("getting product from database")
-	product, err := u.repo.GetProductByID(u.ctx, tx, ID)
-	if err != nil {
-		return nil, err
-	}
-
-	if err := db.SetCache(u.ctx, cacheKey, product, productCacheTTL); err != nil {
-		logrus.Warnf("Failed to cache product ID %d: %v", ID, err)
-	} else {
-		logrus.Debugf("Product ID %d cached successfully", ID)
-	}
-
-	return product, nil
-}
-
-func (u *ProductService) Update(payload *proto.Product) (*proto.Product, error) {
-	tx, err := u.DB.Begin()
-	if err != nil {
-		return nil, err
-	}
-	defer helper.CommitOrRollback(tx)
-
-	logrus.Info("getting product")
-	productResult, err := u.repo.GetProductByID(u.ctx, tx, int(payload.Id))
-	if err != nil {
-		return nil, err
-	}
-
-	productResult.Name = payload.Name
-	productResult.Description = payload.Description
-	productResult.Price = payload.Price
-	productResult.Stock = payload.Stock
-
-	if err := u.repo.UpdateProduct(u.ctx, tx, payload); err != nil {
-		return nil, err
-	}
-	logrus.Info("product updated")
-
-	// Invalidate cache for this product and product list
-	productKey := fmt.Sprintf("product:%d", payload.Id)
-	if err := db.DeleteCache(u.ctx, productKey); err != nil {
-		logrus.Warnf("Failed to invalidate product cache for ID %d: %v", payload.Id, err)
-	}
-
-	if err := db.DeleteCachePattern(u.ctx, "products:list:*"); err != nil {
-		logrus.Warnf("Failed to invalidate product list cache: %v", err)
-	}
-
-	logrus.Infof("Cache invalidated for product ID: %d", payload.Id)
-
-	return productResult, nil
-}
-
-func (u *ProductService) GetAll(page int) ([]*proto.Product, int, int, int, error) {
-	offset := (page - 1) * 10
-
-	cacheKey := fmt.Sprintf("products:list:page:%d", page)
-	cachedList, err := db.GetCachedProductList(u.ctx, cacheKey)
-	if err == nil && cachedList != nil {
-		logrus.Infof("Cache HIT for product list page: %d", page)
-		return cachedList.Products, cachedList.TotalProduct, cachedList.TotalPage, page, nil
-	}
-	logrus.Infof("Cache MISS for product list page: %d", page)
-
-	logrus.Info("getting all products from database")
-	products, totalProducts, totalPage, err := u.repo.GetAllProduct(u.ctx, u.DB, offset)
-	if err != nil {
-		return nil, 0, 0, 0, err
-	}
-
-	// Store in cache for next time
-	setCachedList := &db.CachedProductList{
-		Products:     products,
-		TotalProduct: totalProducts,
-		TotalPage:    totalPage,
-	}
-	if err := db.SetCache(u.ctx, cacheKey, setCachedList, productListCacheTTL); err != nil {
-		logrus.Warnf("Failed to cache product list: %v", err)
-	} else {
-		logrus.Debugf("Product list page %d cached successfully", page)
-	}
-
-	return products, totalProducts, totalPage, page, nil
-}
-
-func (u *ProductService) Delete(ID int) error {
-	tx, err := u.DB.Begin()
-	if err != nil {
-		return err
-	}
-	defer helper.CommitOrRollback(tx)
-
-	if err := u.repo.DeleteProduct(u.ctx, tx, ID); err != nil {
-		return err
-	}
-
-	// Invalidate cache for this product and product list
-	productKey := fmt.Sprintf("product:%d", ID)
-	if err := db.DeleteCache(u.ctx, productKey); err != nil {
-		logrus.Warnf("Failed to invalidate product cache for ID %d: %v", ID, err)
-	}
-
-	if err := db.DeleteCachePattern(u.ctx, "products:list:*"); err != nil {
-		logrus.Warnf("Failed to invalidate product list cache: %v", err)
-	}
-
-	logrus.Infof("Cache invalidated for deleted product ID: %d", ID)
-
-	return nil
-}
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"product_service/cmd/db"
+	"product_service/helper"
+	"product_service/proto"
+	"product_service/repository"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	productCacheTTL     = 10 * time.Minute
+	productListCacheTTL = 5 * time.Minute
+
+	defaultSearchPageSize = 15
+	maxSearchPageSize     = 100
+)
+
+type ProductService struct {
+	repo               repository.ProductRepository
+	categoryRepo       repository.CategoryRepository
+	tagRepo            repository.TagRepository
+	variantRepo        repository.VariantRepository
+	imageRepo          repository.ImageRepository
+	inventoryRepo      repository.InventoryRepository
+	warehouseRepo      repository.WarehouseRepository
+	priceRepo          repository.PriceRepository
+	reviewRepo         repository.ReviewRepository
+	wishlistRepo       repository.WishlistRepository
+	outboxRepo         repository.OutboxRepository
+	recommendationRepo repository.RecommendationRepository
+	bundleRepo         repository.BundleRepository
+	priceRuleRepo      repository.PriceRuleRepository
+	reorderRepo        repository.ReorderRepository
+	purchaseOrderRepo  repository.PurchaseOrderRepository
+	translationRepo    repository.TranslationRepository
+	digitalRepo        repository.DigitalRepository
+	orderRepo          repository.OrderRepository
+
+	// defaultLocale is the locale of the names and descriptions stored on products
+	defaultLocale string
+	// priceScheduled wakes the price scheduler when a schedule changes
+	priceScheduled chan struct{}
+	DB             *sql.DB
+	ctx            context.Context
+}
+
+func NewProductService(ProductRepository repository.ProductRepository, DB *sql.DB, ctx context.Context, categoryRepo repository.CategoryRepository, tagRepo repository.TagRepository, variantRepo repository.VariantRepository, imageRepo repository.ImageRepository, inventoryRepo repository.InventoryRepository, warehouseRepo repository.WarehouseRepository, priceRepo repository.PriceRepository, reviewRepo repository.ReviewRepository, wishlistRepo repository.WishlistRepository, outboxRepo repository.OutboxRepository, recommendationRepo repository.RecommendationRepository, bundleRepo repository.BundleRepository, priceRuleRepo repository.PriceRuleRepository, reorderRepo repository.ReorderRepository, purchaseOrderRepo repository.PurchaseOrderRepository, translationRepo repository.TranslationRepository, digitalRepo repository.DigitalRepository, orderRepo repository.OrderRepository) *ProductService {
+	return &ProductService{
+		repo:               ProductRepository,
+		categoryRepo:       categoryRepo,
+		tagRepo:            tagRepo,
+		variantRepo:        variantRepo,
+		imageRepo:          imageRepo,
+		inventoryRepo:      inventoryRepo,
+		warehouseRepo:      warehouseRepo,
+		priceRepo:          priceRepo,
+		reviewRepo:         reviewRepo,
+		wishlistRepo:       wishlistRepo,
+		outboxRepo:         outboxRepo,
+		recommendationRepo: recommendationRepo,
+		bundleRepo:         bundleRepo,
+		priceRuleRepo:      priceRuleRepo,
+		reorderRepo:        reorderRepo,
+		purchaseOrderRepo:  purchaseOrderRepo,
+		translationRepo:    translationRepo,
+		digitalRepo:        digitalRepo,
+		orderRepo:          orderRepo,
+
+		defaultLocale:  fallbackLocale,
+		priceScheduled: make(chan struct{}, 1),
+		DB:             DB,
+		ctx:            ctx,
+	}
+}
+
+func (u *ProductService) Create(payload *proto.ProductRequest) error {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	logrus.Info("calling create product service")
+	slug, err := u.productSlug(tx, payload.Payload.Slug, payload.Payload.Name, 0)
+	if err != nil {
+		return err
+	}
+	payload.Payload.Slug = slug
+
+	productID, err := u.repo.Create(u.ctx, tx, payload.Payload)
+	if err != nil {
+		return err
+	}
+
+	if err := u.priceRepo.RecordListPrice(u.ctx, tx, productID, payload.Payload.Price); err != nil {
+		return err
+	}
+
+	// Products start with a single default variant holding all of their stock
+	variant, err := u.variantRepo.Create(u.ctx, tx, &proto.ProductVariant{
+		ProductId: int32(productID),
+		Sku:       defaultSKU(productID),
+		IsDefault: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := u.addInitialStock(tx, variant, payload.Payload.Stock, payload.Actor, ""); err != nil {
+		return err
+	}
+
+	if err := u.recordProductEvent(tx, ProductCreated, productID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rollback = false
+
+	// Invalidate product list cache after create
+	if db.RedisClient != nil {
+		if err := db.DeleteCachePattern(u.ctx, "products:list:*"); err != nil {
+			logrus.Warnf("Failed to invalidate product list cache: %v", err)
+		} else {
+			logrus.Info("Product list cache invalidated after create")
+		}
+	}
+
+	return nil
+}
+
+// GetProductByID returns a product with its name and description in the locale the
+// Accept-Language header prefers
+func (u *ProductService) GetProductByID(ID int, acceptLanguage string) (*proto.Product, error) {
+	product, err := u.cachedProduct(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.localize([]*proto.Product{product}, acceptLanguage); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// GetProductBySlug returns the product a slug belongs to. The product carries its
+// current slug, which differs from the one asked for when the slug has changed since.
+func (u *ProductService) GetProductBySlug(slug string, acceptLanguage string) (*proto.Product, error) {
+	ID, err := u.repo.GetIDBySlug(u.ctx, u.DB, helper.Slugify(slug))
+	if err != nil {
+		return nil, err
+	}
+	return u.GetProductByID(ID, acceptLanguage)
+}
+
+func (u *ProductService) cachedProduct(ID int) (*proto.Product, error) {
+	cacheKey := fmt.Sprintf("product:%d", ID)
+
+	cachedProduct, err := db.GetCacheProduct(u.ctx, cacheKey)
+	if err == nil {
+		logrus.Infof("Cache HIT for product ID: %d", ID)
+		return cachedProduct, nil
+	}
+	logrus.Debugf("Cache MISS for product ID: %d", ID)
+
+	// Cache miss or Redis not available, query from database
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	logrus.Info("getting product from database")
+	product, err := u.readProduct(tx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.SetCache(u.ctx, cacheKey, product, productCacheTTL); err != nil {
+		logrus.Warnf("Failed to cache product ID %d: %v", ID, err)
+	} else {
+		logrus.Debugf("Product ID %d cached successfully", ID)
+	}
+
+	return product, nil
+}
+
+// readProduct loads a product with its taxonomy, variants, images and bundle
+// components within tx
+func (u *ProductService) readProduct(tx *sql.Tx, ID int) (*proto.Product, error) {
+	product, err := u.repo.GetProductByID(u.ctx, tx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.loadTaxonomy(tx, product); err != nil {
+		return nil, err
+	}
+
+	if err := u.loadVariants(tx, product); err != nil {
+		return nil, err
+	}
+
+	images, err := u.imageRepo.GetProductImages(u.ctx, tx, ID)
+	if err != nil {
+		return nil, err
+	}
+	product.Images = images
+
+	if err := u.loadComponents(tx, []*proto.Product{product}); err != nil {
+		return nil, err
+	}
+
+	if err := u.loadDigital(tx, []*proto.Product{product}); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// BatchGetProducts returns several products, in the order asked for, with one Redis
+// MGET and one set of queries for the products that were not cached. Unknown IDs
+// are left out.
+func (u *ProductService) BatchGetProducts(IDs []int32) ([]*proto.Product, error) {
+	IDs = uniqueIDs(IDs)
+	keys := make([]string, len(IDs))
+	for i, ID := range IDs {
+		keys[i] = fmt.Sprintf("product:%d", ID)
+	}
+
+	cached, err := db.GetCacheProducts(u.ctx, keys)
+	if err != nil {
+		logrus.Warnf("Batch cache lookup failed, loading %d products from database: %v", len(IDs), err)
+	}
+
+	found := make(map[int32]*proto.Product, len(IDs))
+	var misses []int32
+	for i, ID := range IDs {
+		if cached[i] != nil {
+			found[ID] = cached[i]
+		} else {
+			misses = append(misses, ID)
+		}
+	}
+	logrus.Debugf("Batch get: %d cached, %d from database", len(found), len(misses))
+
+	if len(misses) > 0 {
+		loaded, err := u.loadProducts(misses)
+		if err != nil {
+			return nil, err
+		}
+
+		toCache := make(map[string]interface{}, len(loaded))
+		for _, product := range loaded {
+			found[int32(product.Id)] = product
+			toCache[fmt.Sprintf("product:%d", product.Id)] = product
+		}
+		if err := db.SetCacheMany(u.ctx, toCache, productCacheTTL); err != nil {
+			logrus.Warnf("Failed to cache %d products: %v", len(toCache), err)
+		}
+	}
+
+	products := make([]*proto.Product, 0, len(found))
+	for _, ID := range IDs {
+		if product, ok := found[ID]; ok {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+// loadProducts reads products from the database with everything GetProductByID
+// attaches, using one query per kind of data rather than per product
+func (u *ProductService) loadProducts(IDs []int32) ([]*proto.Product, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	products, err := u.repo.GetProductsByIDs(u.ctx, tx, IDs)
+	if err != nil || len(products) == 0 {
+		return products, err
+	}
+
+	byID := make(map[int32]*proto.Product, len(products))
+	for _, product := range products {
+		byID[int32(product.Id)] = product
+	}
+
+	categories, err := u.categoryRepo.GetCategoriesForProducts(u.ctx, tx, IDs)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := u.tagRepo.GetTagsForProducts(u.ctx, tx, IDs)
+	if err != nil {
+		return nil, err
+	}
+	options, err := u.variantRepo.GetOptionsForProducts(u.ctx, tx, IDs)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := u.variantRepo.GetVariantsForProducts(u.ctx, tx, IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for ID, product := range byID {
+		product.Categories = categories[ID]
+		product.Tags = tags[ID]
+	}
+	for _, option := range options {
+		byID[option.ProductId].Options = append(byID[option.ProductId].Options, option)
+	}
+	for _, variant := range variants {
+		byID[variant.ProductId].Variants = append(byID[variant.ProductId].Variants, variant)
+	}
+	if err := u.attachWarehouseStock(tx, variants); err != nil {
+		return nil, err
+	}
+
+	if err := u.attachImages(products); err != nil {
+		return nil, err
+	}
+
+	if err := u.loadComponents(tx, products); err != nil {
+		return nil, err
+	}
+
+	if err := u.loadDigital(tx, products); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (u *ProductService) Update(payload *proto.Product) (*proto.Product, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	logrus.Info("getting product")
+	existing, err := u.repo.GetProductByID(u.ctx, tx, int(payload.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	// The slug does not follow the name, so links stay valid; the old slug redirects
+	if payload.Slug != "" {
+		slug, err := u.productSlug(tx, payload.Slug, "", int(payload.Id))
+		if err != nil {
+			return nil, err
+		}
+		if slug != existing.Slug {
+			if err := u.repo.ChangeSlug(u.ctx, tx, int(payload.Id), slug); err != nil {
+				return nil, err
+			}
+			logrus.Infof("Product %d slug changed from %s to %s", payload.Id, existing.Slug, slug)
+		}
+	}
+
+	// Stock is not part of the update; it only changes through recorded movements
+	if err := u.repo.UpdateProduct(u.ctx, tx, payload); err != nil {
+		return nil, err
+	}
+
+	if err := u.priceRepo.RecordListPrice(u.ctx, tx, int(payload.Id), payload.Price); err != nil {
+		return nil, err
+	}
+
+	if err := u.recordProductEvent(tx, ProductUpdated, int(payload.Id)); err != nil {
+		return nil, err
+	}
+
+	productResult, err := u.repo.GetProductByID(u.ctx, tx, int(payload.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+	logrus.Info("product updated")
+
+	// Invalidate cache for this product, the bundles it is part of and product list
+	u.invalidateProductCache(int(payload.Id))
+
+	logrus.Infof("Cache invalidated for product ID: %d", payload.Id)
+
+	return productResult, nil
+}
+
+// UpdateTestStock sets the sandbox stock pool without touching live stock. The
+// difference is recorded as a manual adjustment, so it only applies to products
+// with a single variant.
+func (u *ProductService) UpdateTestStock(req *proto.UpdateTestStockRequest) error {
+	if req.TestStock < 0 {
+		return errors.New("test stock cannot be negative")
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	ID := int(req.Id)
+	if _, err := u.repo.GetProductByID(u.ctx, tx, ID); err != nil {
+		return err
+	}
+
+	variants, err := u.variantRepo.GetProductVariants(u.ctx, tx, ID)
+	if err != nil {
+		return err
+	}
+	if len(variants) != 1 {
+		return errors.New("product has several variants, adjust their stock individually")
+	}
+
+	if delta := req.TestStock - variants[0].TestStock; delta != 0 {
+		if _, err := u.moveStock(tx, &proto.AdjustVariantStockRequest{
+			VariantId: variants[0].Id,
+			Delta:     delta,
+			Livemode:  false,
+			Reason:    MovementManualAdjustment,
+			Actor:     req.Actor,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rollback = false
+
+	productKey := fmt.Sprintf("product:%d", ID)
+	if err := db.DeleteCache(u.ctx, productKey); err != nil {
+		logrus.Warnf("Failed to invalidate product cache for ID %d: %v", ID, err)
+	}
+
+	if err := db.DeleteCachePattern(u.ctx, "products:list:*"); err != nil {
+		logrus.Warnf("Failed to invalidate product list cache: %v", err)
+	}
+
+	return nil
+}
+
+func (u *ProductService) GetAll(page int, acceptLanguage string) ([]*proto.Product, int, int, int, error) {
+	offset := (page - 1) * 10
+
+	cacheKey := fmt.Sprintf("products:list:page:%d", page)
+	cachedList, err := db.GetCachedProductList(u.ctx, cacheKey)
+	if err == nil && cachedList != nil {
+		logrus.Infof("Cache HIT for product list page: %d", page)
+		if err := u.localize(cachedList.Products, acceptLanguage); err != nil {
+			return nil, 0, 0, 0, err
+		}
+		return cachedList.Products, cachedList.TotalProduct, cachedList.TotalPage, page, nil
+	}
+	logrus.Infof("Cache MISS for product list page: %d", page)
+
+	logrus.Info("getting all products from database")
+	products, totalProducts, totalPage, err := u.repo.GetAllProduct(u.ctx, u.DB, offset)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+
+	if err := u.attachImages(products); err != nil {
+		return nil, 0, 0, 0, err
+	}
+
+	// Store in cache for next time
+	setCachedList := &db.CachedProductList{
+		Products:     products,
+		TotalProduct: totalProducts,
+		TotalPage:    totalPage,
+	}
+	if err := db.SetCache(u.ctx, cacheKey, setCachedList, productListCacheTTL); err != nil {
+		logrus.Warnf("Failed to cache product list: %v", err)
+	} else {
+		logrus.Debugf("Product list page %d cached successfully", page)
+	}
+
+	if err := u.localize(products, acceptLanguage); err != nil {
+		return nil, 0, 0, 0, err
+	}
+
+	return products, totalProducts, totalPage, page, nil
+}
+
+// ArchiveProduct hides a product from listings and search. It stays readable by ID
+// so orders that include it still resolve.
+func (u *ProductService) ArchiveProduct(ID int) error {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	if err := u.repo.ArchiveProduct(u.ctx, tx, ID); err != nil {
+		return err
+	}
+
+	if err := u.recordProductEvent(tx, ProductArchived, ID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rollback = false
+
+	logrus.Infof("Product %d archived", ID)
+	u.invalidateProductCache(ID)
+	return nil
+}
+
+func (u *ProductService) UnarchiveProduct(ID int) (*proto.Product, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	if err := u.repo.UnarchiveProduct(u.ctx, tx, ID); err != nil {
+		return nil, err
+	}
+
+	if err := u.recordProductEvent(tx, ProductUnarchived, ID); err != nil {
+		return nil, err
+	}
+
+	product, err := u.repo.GetProductByID(u.ctx, tx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Product %d unarchived", ID)
+	u.invalidateProductCache(ID)
+	return product, nil
+}
+
+// PurgeProduct permanently deletes an archived product. It refuses once any order
+// has a line of the product, bundle components included, since order history has
+// to keep its lines.
+func (u *ProductService) PurgeProduct(ID int) error {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	product, err := u.repo.GetProductByID(u.ctx, tx, ID)
+	if err != nil {
+		return err
+	}
+	if product.ArchivedAt == "" {
+		return errors.New("archive the product before purging it")
+	}
+
+	ordered, err := u.orderRepo.HasOrders(u.ctx, ID)
+	if err != nil {
+		return err
+	}
+	if ordered {
+		return errors.New("product has been ordered and cannot be purged")
+	}
+
+	bundleIDs, err := u.bundleRepo.GetBundlesOfProduct(u.ctx, u.DB, ID)
+	if err != nil {
+		return err
+	}
+	if len(bundleIDs) > 0 {
+		return fmt.Errorf("product is a component of bundle %d and cannot be purged", bundleIDs[0])
+	}
+
+	// The event is recorded first, while the product's row can still be locked
+	if err := u.recordProductEvent(tx, ProductPurged, ID); err != nil {
+		return err
+	}
+
+	if err := u.repo.PurgeProduct(u.ctx, tx, ID); err != nil {
+		// An order placed since the check still keeps the product
+		if helper.IsForeignKeyViolation(err) {
+			return errors.New("product has been ordered and cannot be purged")
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rollback = false
+
+	logrus.Infof("Product %d purged", ID)
+	u.invalidateProductCache(ID)
+	return nil
+}
+
+// Search runs a full-text product search. Results are cached under the products:list
+// prefix, so every write that invalidates the product list also drops search results.
+func (u *ProductService) Search(req *proto.SearchProductsRequest) (*proto.ProductList, error) {
+	query, err := normaliseSearch(req)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey, err := searchCacheKey(query)
+	if err != nil {
+		return nil, err
+	}
+
+	cachedList, err := db.GetCachedProductList(u.ctx, cacheKey)
+	if err == nil && cachedList != nil {
+		logrus.Infof("Cache HIT for product search: %s", cacheKey)
+		if err := u.localize(cachedList.Products, req.AcceptLanguage); err != nil {
+			return nil, err
+		}
+		return searchResult(query, cachedList), nil
+	}
+	logrus.Infof("Cache MISS for product search: %s", cacheKey)
+
+	products, totalProducts, err := u.repo.SearchProducts(u.ctx, u.DB, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.attachImages(products); err != nil {
+		return nil, err
+	}
+
+	facets, err := u.repo.SearchFacets(u.ctx, u.DB, query)
+	if err != nil {
+		return nil, err
+	}
+
+	setCachedList := &db.CachedProductList{
+		Products:     products,
+		TotalProduct: totalProducts,
+		TotalPage:    (totalProducts + int(query.PageSize) - 1) / int(query.PageSize),
+		Facets:       facets,
+	}
+	if err := db.SetCache(u.ctx, cacheKey, setCachedList, productListCacheTTL); err != nil {
+		logrus.Warnf("Failed to cache product search: %v", err)
+	}
+
+	if err := u.localize(products, req.AcceptLanguage); err != nil {
+		return nil, err
+	}
+
+	return searchResult(query, setCachedList), nil
+}
+
+func searchResult(query *proto.SearchProductsRequest, list *db.CachedProductList) *proto.ProductList {
+	return &proto.ProductList{
+		Products:  list.Products,
+		Total:     int64(list.TotalProduct),
+		TotalPage: int32(list.TotalPage),
+		Page:      query.Page,
+		PageSize:  query.PageSize,
+		Facets:    list.Facets,
+	}
+}
+
+// normaliseSearch applies defaults and canonical forms so equivalent searches share a cache entry
+func normaliseSearch(req *proto.SearchProductsRequest) (*proto.SearchProductsRequest, error) {
+	query := &proto.SearchProductsRequest{
+		Query:      strings.ToLower(strings.Join(strings.Fields(req.Query), " ")),
+		MinPrice:   req.MinPrice,
+		MaxPrice:   req.MaxPrice,
+		InStock:    req.InStock,
+		Sort:       strings.ToLower(strings.TrimSpace(req.Sort)),
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		CategoryId: req.CategoryId,
+	}
+
+	if query.MinPrice < 0 || query.MaxPrice < 0 {
+		return nil, fmt.Errorf("price range cannot be negative")
+	}
+	if query.MaxPrice > 0 && query.MinPrice > query.MaxPrice {
+		return nil, fmt.Errorf("min_price cannot be greater than max_price")
+	}
+
+	switch query.Sort {
+	case "", "relevance":
+		query.Sort = "relevance"
+		// Without a query every product is equally relevant, so show the newest first
+		if query.Query == "" {
+			query.Sort = "newest"
+		}
+	case "price_asc", "price_desc", "newest":
+	default:
+		return nil, fmt.Errorf("invalid sort: %s", req.Sort)
+	}
+
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 {
+		query.PageSize = defaultSearchPageSize
+	}
+	if query.PageSize > maxSearchPageSize {
+		query.PageSize = maxSearchPageSize
+	}
+
+	return query, nil
+}
+
+func searchCacheKey(query *proto.SearchProductsRequest) (string, error) {
+	data, err := json.Marshal(query)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return "products:list:search:" + hex.EncodeToString(sum[:]), nil
+}
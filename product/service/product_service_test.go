@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"product_service/proto"
+	"product_service/repository"
+	"strings"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// testDB connects to TEST_DATABASE_URL, a migrated database the test may write to,
+// and skips the test when it is not set
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	DB, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := DB.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { DB.Close() })
+	return DB
+}
+
+type purgeProducts struct {
+	repository.ProductRepository
+	product *proto.Product
+}
+
+func (f *purgeProducts) GetProductByID(ctx context.Context, tx *sql.Tx, ID int) (*proto.Product, error) {
+	if f.product == nil || int(f.product.Id) != ID {
+		return nil, errors.New("product not found")
+	}
+	return f.product, nil
+}
+
+// fakeOrders answers for the order service
+type fakeOrders struct {
+	repository.OrderRepository
+	ordered bool
+	err     error
+}
+
+func (f *fakeOrders) HasOrders(ctx context.Context, productID int) (bool, error) {
+	return f.ordered, f.err
+}
+
+type purgeBundles struct {
+	repository.BundleRepository
+	bundles []int
+}
+
+func (f *purgeBundles) GetBundlesOfProduct(ctx context.Context, db *sql.DB, productID int) ([]int, error) {
+	return f.bundles, nil
+}
+
+// TestPurgeProductRefusals covers the products PurgeProduct keeps; none of them gets
+// as far as deleting anything
+func TestPurgeProductRefusals(t *testing.T) {
+	DB := testDB(t)
+
+	archived := &proto.Product{Id: 4, Name: "Old mug", ArchivedAt: "2026-01-02T03:04:05Z"}
+
+	tests := []struct {
+		name    string
+		product *proto.Product
+		orders  *fakeOrders
+		bundles []int
+		wantErr string
+	}{
+		{"not archived", &proto.Product{Id: 4, Name: "Mug"}, &fakeOrders{}, nil, "archive the product"},
+		{"ordered", archived, &fakeOrders{ordered: true}, nil, "has been ordered"},
+		{"order service unavailable", archived, &fakeOrders{err: errors.New("order service unavailable")}, nil, "order service unavailable"},
+		{"component of a bundle", archived, &fakeOrders{}, []int{12}, "component of bundle 12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &ProductService{
+				DB:         DB,
+				ctx:        context.Background(),
+				repo:       &purgeProducts{product: tt.product},
+				orderRepo:  tt.orders,
+				bundleRepo: &purgeBundles{bundles: tt.bundles},
+			}
+
+			err := svc.PurgeProduct(4)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("PurgeProduct() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,408 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"product_service/helper"
+	"product_service/proto"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Statuses of a purchase order
+const (
+	PurchaseOrderDraft             = "draft"
+	PurchaseOrderSent              = "sent"
+	PurchaseOrderPartiallyReceived = "partially_received"
+	PurchaseOrderReceived          = "received"
+	PurchaseOrderCancelled         = "cancelled"
+)
+
+const purchaseOrderPageSize = 20
+
+func (u *ProductService) CreateSupplier(req *proto.Supplier) (*proto.Supplier, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if req.LeadTimeDays < 0 {
+		return nil, errors.New("lead_time_days cannot be negative")
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	supplier, err := u.purchaseOrderRepo.CreateSupplier(u.ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Supplier %s created", supplier.Name)
+	return supplier, nil
+}
+
+func (u *ProductService) ListSuppliers() ([]*proto.Supplier, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	return u.purchaseOrderRepo.ListSuppliers(u.ctx, tx)
+}
+
+// CreatePurchaseOrder drafts a purchase order for a supplier, received into one
+// warehouse. Each variant can be on one line, and bundles cannot be ordered since they
+// hold no stock of their own.
+func (u *ProductService) CreatePurchaseOrder(req *proto.PurchaseOrder) (*proto.PurchaseOrder, error) {
+	if len(req.Lines) == 0 {
+		return nil, errors.New("lines are required")
+	}
+	if req.FreightCost < 0 || req.DutyCost < 0 {
+		return nil, errors.New("freight_cost and duty_cost cannot be negative")
+	}
+	if req.ExpectedAt != "" {
+		if _, err := time.Parse(time.RFC3339, req.ExpectedAt); err != nil {
+			return nil, errors.New("expected_at must be an RFC 3339 time")
+		}
+	}
+
+	seen := make(map[int32]bool, len(req.Lines))
+	variantIDs := make([]int32, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		if line.QuantityOrdered <= 0 {
+			return nil, errors.New("quantity_ordered must be positive")
+		}
+		if line.UnitCost < 0 {
+			return nil, errors.New("unit_cost cannot be negative")
+		}
+		if seen[line.VariantId] {
+			return nil, fmt.Errorf("duplicate line: variant %d", line.VariantId)
+		}
+		seen[line.VariantId] = true
+		variantIDs = append(variantIDs, line.VariantId)
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	if _, err := u.purchaseOrderRepo.GetSupplier(u.ctx, tx, int(req.SupplierId)); err != nil {
+		return nil, err
+	}
+	warehouse, err := u.stockWarehouse(tx, int(req.WarehouseId))
+	if err != nil {
+		return nil, err
+	}
+	req.WarehouseId = warehouse.Id
+
+	bundles, err := u.bundleRepo.GetBundleVariants(u.ctx, tx, variantIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(bundles) > 0 {
+		return nil, errors.New("a bundle has no stock of its own, order its components")
+	}
+
+	ID, err := u.purchaseOrderRepo.Create(u.ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range req.Lines {
+		variant, err := u.variantRepo.GetByID(u.ctx, tx, int(line.VariantId))
+		if err != nil {
+			return nil, fmt.Errorf("variant %d: %w", line.VariantId, err)
+		}
+		product, err := u.repo.GetProductByID(u.ctx, tx, int(variant.ProductId))
+		if err != nil {
+			return nil, err
+		}
+		if product.Kind == ProductKindDigital {
+			return nil, fmt.Errorf("product %d is digital and holds no stock", product.Id)
+		}
+		line.Sku = variant.Sku
+		if err := u.purchaseOrderRepo.AddLine(u.ctx, tx, ID, line); err != nil {
+			return nil, err
+		}
+	}
+
+	order, err := u.readPurchaseOrder(tx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Purchase order %d drafted for supplier %d with %d lines", ID, req.SupplierId, len(req.Lines))
+	return order, nil
+}
+
+func (u *ProductService) GetPurchaseOrder(ID int) (*proto.PurchaseOrder, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	return u.readPurchaseOrder(tx, ID)
+}
+
+// ListPurchaseOrders returns a page of purchase orders without their lines, newest first
+func (u *ProductService) ListPurchaseOrders(req *proto.ListPurchaseOrdersRequest) (*proto.PurchaseOrderList, error) {
+	page := int(req.Page)
+	if page < 1 {
+		page = 1
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	orders, total, err := u.purchaseOrderRepo.List(u.ctx, tx, req, purchaseOrderPageSize, (page-1)*purchaseOrderPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.PurchaseOrderList{
+		PurchaseOrders: orders,
+		Page:           int32(page),
+		Total:          int32(total),
+		TotalPage:      int32((total + purchaseOrderPageSize - 1) / purchaseOrderPageSize),
+	}, nil
+}
+
+// SendPurchaseOrder marks a draft as sent to its supplier
+func (u *ProductService) SendPurchaseOrder(ID int) (*proto.PurchaseOrder, error) {
+	return u.changePurchaseOrder(ID, func(tx *sql.Tx, order *proto.PurchaseOrder) error {
+		if order.Status != PurchaseOrderDraft {
+			return fmt.Errorf("a %s purchase order cannot be sent", order.Status)
+		}
+		return u.purchaseOrderRepo.MarkSent(u.ctx, tx, ID)
+	})
+}
+
+// CancelPurchaseOrder cancels a purchase order nothing was received of yet
+func (u *ProductService) CancelPurchaseOrder(ID int) (*proto.PurchaseOrder, error) {
+	return u.changePurchaseOrder(ID, func(tx *sql.Tx, order *proto.PurchaseOrder) error {
+		if order.Status != PurchaseOrderDraft && order.Status != PurchaseOrderSent {
+			return fmt.Errorf("a %s purchase order cannot be cancelled", order.Status)
+		}
+		return u.purchaseOrderRepo.SetStatus(u.ctx, tx, ID, PurchaseOrderCancelled)
+	})
+}
+
+// ReceivePurchaseOrder puts arrived stock of a sent purchase order in its warehouse's
+// live stock, recording each line as a restock referring to the purchase order. The
+// order is received once every line has arrived in full.
+func (u *ProductService) ReceivePurchaseOrder(req *proto.ReceivePurchaseOrderRequest) (*proto.PurchaseOrder, error) {
+	for _, line := range req.Lines {
+		if line.Quantity <= 0 {
+			return nil, errors.New("quantity must be positive")
+		}
+	}
+
+	var restocked []int32
+	order, err := u.changePurchaseOrder(int(req.Id), func(tx *sql.Tx, order *proto.PurchaseOrder) error {
+		if order.Status != PurchaseOrderSent && order.Status != PurchaseOrderPartiallyReceived {
+			return fmt.Errorf("a %s purchase order cannot be received", order.Status)
+		}
+
+		lines, err := u.purchaseOrderRepo.GetLines(u.ctx, tx, int(order.Id))
+		if err != nil {
+			return err
+		}
+		byID := make(map[int32]*proto.PurchaseOrderLine, len(lines))
+		for _, line := range lines {
+			byID[line.Id] = line
+		}
+
+		receipts := append([]*proto.ReceiveLine(nil), req.Lines...)
+		if len(receipts) == 0 {
+			for _, line := range lines {
+				if outstanding := line.QuantityOrdered - line.QuantityReceived; outstanding > 0 {
+					receipts = append(receipts, &proto.ReceiveLine{LineId: line.Id, Quantity: outstanding})
+				}
+			}
+		}
+
+		// Stock is locked in variant order, like AdjustStockBatch does
+		sort.SliceStable(receipts, func(i, j int) bool {
+			var a, b int32
+			if line, ok := byID[receipts[i].LineId]; ok {
+				a = line.VariantId
+			}
+			if line, ok := byID[receipts[j].LineId]; ok {
+				b = line.VariantId
+			}
+			return a < b
+		})
+
+		reference := strconv.Itoa(int(order.Id))
+		for _, receipt := range receipts {
+			line, ok := byID[receipt.LineId]
+			if !ok {
+				return fmt.Errorf("line %d is not part of purchase order %d", receipt.LineId, order.Id)
+			}
+			if outstanding := line.QuantityOrdered - line.QuantityReceived; receipt.Quantity > outstanding {
+				return fmt.Errorf("only %d of line %d are outstanding", outstanding, line.Id)
+			}
+			if line.VariantId == 0 {
+				return fmt.Errorf("variant %s of line %d was deleted", line.Sku, line.Id)
+			}
+
+			if err := u.purchaseOrderRepo.AddReceived(u.ctx, tx, int(line.Id), receipt.Quantity); err != nil {
+				return err
+			}
+			line.QuantityReceived += receipt.Quantity
+
+			if _, err := u.moveStock(tx, &proto.AdjustVariantStockRequest{
+				VariantId:     line.VariantId,
+				Delta:         receipt.Quantity,
+				Livemode:      true,
+				Reason:        MovementRestock,
+				ReferenceType: "purchase_order",
+				ReferenceId:   reference,
+				Actor:         req.Actor,
+				Note:          req.Note,
+				WarehouseId:   order.WarehouseId,
+			}); err != nil {
+				return fmt.Errorf("variant %d: %w", line.VariantId, err)
+			}
+			restocked = append(restocked, line.ProductId)
+		}
+
+		status := PurchaseOrderReceived
+		for _, line := range lines {
+			if line.QuantityReceived < line.QuantityOrdered {
+				status = PurchaseOrderPartiallyReceived
+			}
+		}
+		return u.purchaseOrderRepo.SetStatus(u.ctx, tx, int(order.Id), status)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidated := make(map[int32]bool)
+	for _, productID := range restocked {
+		if !invalidated[productID] {
+			invalidated[productID] = true
+			u.invalidateProductCache(int(productID))
+		}
+	}
+	return order, nil
+}
+
+// changePurchaseOrder applies change to a locked purchase order in one transaction
+// and returns the order as it is after
+func (u *ProductService) changePurchaseOrder(ID int, change func(tx *sql.Tx, order *proto.PurchaseOrder) error) (*proto.PurchaseOrder, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	order, err := u.purchaseOrderRepo.Lock(u.ctx, tx, ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := change(tx, order); err != nil {
+		return nil, err
+	}
+
+	order, err = u.readPurchaseOrder(tx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Purchase order %d is %s", ID, order.Status)
+	return order, nil
+}
+
+// readPurchaseOrder loads a purchase order with its lines and landed cost
+func (u *ProductService) readPurchaseOrder(tx *sql.Tx, ID int) (*proto.PurchaseOrder, error) {
+	order, err := u.purchaseOrderRepo.GetByID(u.ctx, tx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Lines, err = u.purchaseOrderRepo.GetLines(u.ctx, tx, ID); err != nil {
+		return nil, err
+	}
+	applyLandedCost(order)
+	return order, nil
+}
+
+// applyLandedCost spreads freight and duty over the lines of a purchase order in
+// proportion to their value, or per unit when every line is free
+func applyLandedCost(order *proto.PurchaseOrder) {
+	var subtotal float64
+	var units int32
+	for _, line := range order.Lines {
+		subtotal += float64(line.QuantityOrdered) * line.UnitCost
+		units += line.QuantityOrdered
+	}
+	extra := order.FreightCost + order.DutyCost
+
+	order.Subtotal = subtotal
+	order.LandedCost = subtotal + extra
+	for _, line := range order.Lines {
+		switch {
+		case subtotal > 0:
+			line.LandedUnitCost = line.UnitCost * order.LandedCost / subtotal
+		case units > 0:
+			line.LandedUnitCost = extra / float64(units)
+		}
+		line.LandedUnitCost = math.Round(line.LandedUnitCost*100) / 100
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"product_service/helper"
+	"product_service/proto"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Reasons a product is recommended for
+const (
+	ReasonBoughtTogether = "bought_together"
+	ReasonPopular        = "popular"
+)
+
+const (
+	defaultRecommendations = 8
+	maxRecommendations     = 20
+)
+
+// GetRecommendations returns the in-stock products most often bought together with a
+// product. When there are too few, as for a new product, the most popular products
+// fill the rest.
+func (u *ProductService) GetRecommendations(req *proto.GetRecommendationsRequest) (*proto.RecommendationList, error) {
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultRecommendations
+	}
+	if limit > maxRecommendations {
+		limit = maxRecommendations
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	if _, err := u.repo.GetProductByID(u.ctx, tx, int(req.ProductId)); err != nil {
+		return nil, err
+	}
+
+	ranked, err := u.recommendationRepo.GetCopurchased(u.ctx, tx, int(req.ProductId), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	reasons := make(map[int32]string, limit)
+	exclude := []int32{req.ProductId}
+	for _, product := range ranked {
+		reasons[product.ProductID] = ReasonBoughtTogether
+		exclude = append(exclude, product.ProductID)
+	}
+
+	if len(ranked) < limit {
+		popular, err := u.recommendationRepo.GetPopular(u.ctx, tx, exclude, limit-len(ranked))
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range popular {
+			reasons[product.ProductID] = ReasonPopular
+		}
+		ranked = append(ranked, popular...)
+	}
+
+	IDs := make([]int32, len(ranked))
+	counts := make(map[int32]int32, len(ranked))
+	for i, product := range ranked {
+		IDs[i] = product.ProductID
+		counts[product.ProductID] = product.OrderCount
+	}
+
+	products, err := u.BatchGetProducts(IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &proto.RecommendationList{}
+	for _, product := range products {
+		ID := int32(product.Id)
+		list.Recommendations = append(list.Recommendations, &proto.Recommendation{
+			Product:    product,
+			Reason:     reasons[ID],
+			OrderCount: counts[ID],
+		})
+	}
+	return list, nil
+}
+
+// CountPaidOrder counts the products of a paid order, from the order service's
+// order.paid event, into the co-purchase and popularity tables. Each order is counted
+// once however often its event is delivered. Test-mode orders are not purchases.
+func (u *ProductService) CountPaidOrder(order *proto.Order) error {
+	if !order.Livemode || order.Status != "paid" {
+		return nil
+	}
+
+	productIDs := make([]int32, 0, len(order.OrderItems))
+	seen := make(map[int32]bool, len(order.OrderItems))
+	for _, item := range order.OrderItems {
+		if !seen[item.ProductId] {
+			seen[item.ProductId] = true
+			productIDs = append(productIDs, item.ProductId)
+		}
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	claimed, err := u.recommendationRepo.ClaimOrder(u.ctx, tx, int(order.Id))
+	if err != nil || !claimed {
+		return err
+	}
+
+	if err := u.recommendationRepo.AddCopurchases(u.ctx, tx, productIDs); err != nil {
+		return err
+	}
+	if err := u.recommendationRepo.AddPopularity(u.ctx, tx, productIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rollback = false
+
+	logrus.Infof("Counted paid order %d for recommendations", order.Id)
+	return nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"product_service/proto"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultReorderWindowDays = 30
+	defaultReorderCoverDays  = 30
+	maxReorderDays           = 365
+)
+
+// SetReorderThreshold sets the live stock at or below which a product is announced as
+// low on stock. Bundles have none, since their stock follows their components'.
+func (u *ProductService) SetReorderThreshold(req *proto.ReorderThreshold) (*proto.ReorderThreshold, error) {
+	if req.ReorderThreshold < 0 {
+		return nil, errors.New("reorder_threshold cannot be negative")
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	ID := int(req.ProductId)
+	product, err := u.repo.GetProductByID(u.ctx, tx, ID)
+	if err != nil {
+		return nil, err
+	}
+	if product.Kind == ProductKindBundle && req.ReorderThreshold > 0 {
+		return nil, errors.New("a bundle's stock follows its components, set their thresholds instead")
+	}
+
+	if err := u.reorderRepo.SetThreshold(u.ctx, tx, ID, req.ReorderThreshold); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Reorder threshold of product %d set to %d", ID, req.ReorderThreshold)
+	return &proto.ReorderThreshold{ProductId: req.ProductId, ReorderThreshold: req.ReorderThreshold}, nil
+}
+
+// GetReorderReport works out how fast products sell over a rolling window, how many
+// days their live stock lasts at that pace, and how many to reorder so stock covers
+// cover_days of sales on top of the reorder threshold. Sales are the stock orders
+// take when they are placed, paid or not; an order that fails payment keeps its
+// stock, so it counts as sold too.
+func (u *ProductService) GetReorderReport(req *proto.ReorderReportRequest) (*proto.ReorderReport, error) {
+	window := req.WindowDays
+	if window <= 0 {
+		window = defaultReorderWindowDays
+	}
+	cover := req.CoverDays
+	if cover <= 0 {
+		cover = defaultReorderCoverDays
+	}
+	if window > maxReorderDays || cover > maxReorderDays {
+		return nil, errors.New("window_days and cover_days cannot be over 365")
+	}
+
+	sales, err := u.reorderRepo.GetSales(u.ctx, u.DB, window)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &proto.ReorderReport{WindowDays: window, CoverDays: cover}
+	for _, line := range sales {
+		line.DailyVelocity = float64(line.UnitsSold) / float64(window)
+		line.DaysOfCover = -1
+		if line.DailyVelocity > 0 {
+			line.DaysOfCover = math.Round(float64(line.Stock)/line.DailyVelocity*10) / 10
+		}
+
+		target := line.DailyVelocity*float64(cover) + float64(line.ReorderThreshold)
+		if missing := int32(math.Ceil(target)) - line.Stock; missing > 0 {
+			line.SuggestedQuantity = missing
+		}
+		line.LowStock = line.ReorderThreshold > 0 && line.Stock <= line.ReorderThreshold
+
+		if req.ReorderOnly && line.SuggestedQuantity == 0 {
+			continue
+		}
+		report.Suggestions = append(report.Suggestions, line)
+	}
+
+	sort.SliceStable(report.Suggestions, func(i, j int) bool {
+		a, b := report.Suggestions[i], report.Suggestions[j]
+		if (a.DaysOfCover < 0) != (b.DaysOfCover < 0) {
+			return b.DaysOfCover < 0
+		}
+		return a.DaysOfCover < b.DaysOfCover
+	})
+	return report, nil
+}
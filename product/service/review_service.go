@@ -0,0 +1,271 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"product_service/helper"
+	"product_service/proto"
+	"product_service/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Review moderation states; only approved reviews are published and rated
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+const (
+	reviewPageSize     = 10
+	maxReviewImages    = 3
+	maxReviewBodyChars = 5000
+)
+
+// CreateReview saves a review for moderation. Only users with a paid or fulfilled
+// order containing the product may review it, once. Images must already be stored
+// by the gateway.
+func (u *ProductService) CreateReview(review *proto.ProductReview) (*proto.ProductReview, error) {
+	if review.Rating < 1 || review.Rating > 5 {
+		return nil, errors.New("rating must be between 1 and 5")
+	}
+	if len([]rune(review.Body)) > maxReviewBodyChars {
+		return nil, fmt.Errorf("review cannot be longer than %d characters", maxReviewBodyChars)
+	}
+	if len(review.Images) > maxReviewImages {
+		return nil, fmt.Errorf("a review can have at most %d images", maxReviewImages)
+	}
+	for _, image := range review.Images {
+		if image.StorageKey == "" || image.ThumbnailKey == "" {
+			return nil, errors.New("storage_key and thumbnail_key are required")
+		}
+	}
+
+	// Orders belong to the order service, asked before the transaction starts
+	purchased, err := u.orderRepo.HasPurchased(u.ctx, int(review.UserId), int(review.ProductId))
+	if err != nil {
+		return nil, err
+	}
+	if !purchased {
+		return nil, errors.New("only buyers with a paid or fulfilled order can review this product")
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	if _, err := u.repo.GetProductByID(u.ctx, tx, int(review.ProductId)); err != nil {
+		return nil, err
+	}
+
+	created, err := u.reviewRepo.Create(u.ctx, tx, review)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, image := range review.Images {
+		image.ReviewId = created.Id
+		saved, err := u.reviewRepo.AddImage(u.ctx, tx, image)
+		if err != nil {
+			return nil, err
+		}
+		created.Images = append(created.Images, saved)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Review %d of product %d submitted by user %d", created.Id, created.ProductId, created.UserId)
+	return created, nil
+}
+
+// DeleteReview removes a review by its author and returns it with its images, so
+// the gateway can delete the stored files
+func (u *ProductService) DeleteReview(req *proto.DeleteReviewRequest) (*proto.ProductReview, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	review, err := u.reviewRepo.GetByID(u.ctx, tx, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+	// Other users' reviews look missing rather than forbidden
+	if review.UserId != req.UserId {
+		return nil, repository.ErrReviewNotFound
+	}
+
+	if review.Images, err = u.reviewRepo.GetImagesForReviews(u.ctx, tx, []int32{review.Id}); err != nil {
+		return nil, err
+	}
+
+	if err := u.reviewRepo.Delete(u.ctx, tx, int(review.Id)); err != nil {
+		return nil, err
+	}
+
+	if review.Status == ReviewApproved {
+		if err := u.reviewRepo.RefreshRating(u.ctx, tx, int(review.ProductId)); err != nil {
+			return nil, err
+		}
+		if err := u.recordProductEvent(tx, ProductUpdated, int(review.ProductId)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Review %d of product %d deleted by its author", review.Id, review.ProductId)
+	if review.Status == ReviewApproved {
+		u.invalidateProductCache(int(review.ProductId))
+	}
+	return review, nil
+}
+
+// ModerateReview moves a review to another moderation state and refreshes the
+// product's rating
+func (u *ProductService) ModerateReview(req *proto.ModerateReviewRequest) (*proto.ProductReview, error) {
+	switch req.Status {
+	case ReviewPending, ReviewApproved, ReviewRejected:
+	default:
+		return nil, fmt.Errorf("unknown review status %q", req.Status)
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	review, err := u.reviewRepo.SetStatus(u.ctx, tx, int(req.Id), req.Status, req.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.reviewRepo.RefreshRating(u.ctx, tx, int(review.ProductId)); err != nil {
+		return nil, err
+	}
+	if err := u.recordProductEvent(tx, ProductUpdated, int(review.ProductId)); err != nil {
+		return nil, err
+	}
+
+	if review.Images, err = u.reviewRepo.GetImagesForReviews(u.ctx, tx, []int32{review.Id}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Review %d of product %d moved to %s", review.Id, review.ProductId, review.Status)
+	u.invalidateProductCache(int(review.ProductId))
+	return review, nil
+}
+
+// ListReviews returns a page of reviews in one moderation state, approved by default,
+// newest first. For a single product the list carries its rating.
+func (u *ProductService) ListReviews(req *proto.ListReviewsRequest) (*proto.ReviewList, error) {
+	status := req.Status
+	switch status {
+	case "":
+		status = ReviewApproved
+	case ReviewPending, ReviewApproved, ReviewRejected:
+	default:
+		return nil, fmt.Errorf("unknown review status %q", status)
+	}
+
+	page := int(req.Page)
+	if page < 1 {
+		page = 1
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	list := &proto.ReviewList{Page: int32(page)}
+	if req.ProductId > 0 {
+		product, err := u.repo.GetProductByID(u.ctx, tx, int(req.ProductId))
+		if err != nil {
+			return nil, err
+		}
+		list.RatingAverage = product.RatingAverage
+		list.RatingCount = product.RatingCount
+	}
+
+	reviews, total, err := u.reviewRepo.List(u.ctx, tx, int(req.ProductId), status, reviewPageSize, (page-1)*reviewPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.attachReviewImages(tx, reviews); err != nil {
+		return nil, err
+	}
+
+	list.Reviews = reviews
+	list.Total = int32(total)
+	list.TotalPage = int32((total + reviewPageSize - 1) / reviewPageSize)
+	return list, nil
+}
+
+// attachReviewImages loads the images of a page of reviews in one query
+func (u *ProductService) attachReviewImages(tx *sql.Tx, reviews []*proto.ProductReview) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	byID := make(map[int32]*proto.ProductReview, len(reviews))
+	IDs := make([]int32, 0, len(reviews))
+	for _, review := range reviews {
+		byID[review.Id] = review
+		IDs = append(IDs, review.Id)
+	}
+
+	images, err := u.reviewRepo.GetImagesForReviews(u.ctx, tx, IDs)
+	if err != nil {
+		return err
+	}
+	for _, image := range images {
+		if review := byID[image.ReviewId]; review != nil {
+			review.Images = append(review.Images, image)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"product_service/helper"
+	"product_service/proto"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fallbackLocale is the default locale when DEFAULT_LOCALE is not set
+const fallbackLocale = "en"
+
+// SetDefaultLocale sets the locale of the names and descriptions stored on products.
+// An empty or malformed locale keeps English.
+func (u *ProductService) SetDefaultLocale(locale string) {
+	normalized, ok := helper.NormalizeLocale(locale)
+	if !ok {
+		if locale != "" {
+			logrus.Warnf("Invalid default locale %q, using %s", locale, fallbackLocale)
+		}
+		normalized = fallbackLocale
+	}
+	u.defaultLocale = normalized
+}
+
+func (u *ProductService) locale() string {
+	if u.defaultLocale == "" {
+		return fallbackLocale
+	}
+	return u.defaultLocale
+}
+
+// SetProductTranslation creates or replaces a product's name and description in a
+// locale other than the default one
+func (u *ProductService) SetProductTranslation(translation *proto.ProductTranslation) (*proto.ProductTranslation, error) {
+	locale, ok := helper.NormalizeLocale(translation.Locale)
+	if !ok {
+		return nil, errors.New("locale must be a language tag such as de or pt-BR")
+	}
+	if locale == u.locale() {
+		return nil, errors.New("the default locale is stored on the product itself")
+	}
+	translation.Locale = locale
+	translation.Name = strings.TrimSpace(translation.Name)
+	if translation.Name == "" {
+		return nil, errors.New("translation name is required")
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	if _, err := u.repo.GetProductByID(u.ctx, tx, int(translation.ProductId)); err != nil {
+		return nil, err
+	}
+
+	saved, err := u.translationRepo.Upsert(u.ctx, tx, translation)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Product %d translated to %s", saved.ProductId, saved.Locale)
+	return saved, nil
+}
+
+func (u *ProductService) DeleteProductTranslation(productID int, locale string) error {
+	locale, ok := helper.NormalizeLocale(locale)
+	if !ok {
+		return errors.New("locale must be a language tag such as de or pt-BR")
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	if err := u.translationRepo.Delete(u.ctx, tx, productID, locale); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rollback = false
+
+	logrus.Infof("Product %d translation %s deleted", productID, locale)
+	return nil
+}
+
+func (u *ProductService) ListProductTranslations(productID int) ([]*proto.ProductTranslation, error) {
+	return u.translationRepo.ListForProduct(u.ctx, u.DB, productID)
+}
+
+// localize replaces the names and descriptions of products with their translation in
+// the most preferred locale of an Accept-Language header. Products fall back to the
+// default locale when no preferred locale is translated, and so do the locales after
+// the default one in the header. A translation without a description keeps the
+// default description.
+func (u *ProductService) localize(products []*proto.Product, acceptLanguage string) error {
+	defaultLocale := u.locale()
+	for _, product := range products {
+		product.Locale = defaultLocale
+	}
+
+	var locales []string
+	for _, locale := range helper.ParseAcceptLanguage(acceptLanguage) {
+		if locale == defaultLocale {
+			break
+		}
+		locales = append(locales, locale)
+	}
+	if len(locales) == 0 || len(products) == 0 {
+		return nil
+	}
+
+	IDs := make([]int32, len(products))
+	for i, product := range products {
+		IDs[i] = int32(product.Id)
+	}
+
+	translations, err := u.translationRepo.GetForProducts(u.ctx, u.DB, IDs, locales)
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		for _, locale := range locales {
+			translation, ok := translations[int32(product.Id)][locale]
+			if !ok {
+				continue
+			}
+			product.Name = translation.Name
+			if translation.Description != "" {
+				product.Description = translation.Description
+			}
+			product.Locale = locale
+			break
+		}
+	}
+	return nil
+}
+
+// productSlug returns the slug for a product, ID 0 for a new one. A requested slug
+// must not belong to another product, now or in its history; a slug made from the
+// name gets a number appended until it is free.
+func (u *ProductService) productSlug(tx *sql.Tx, requested string, name string, ID int) (string, error) {
+	if requested != "" {
+		slug := helper.Slugify(requested)
+		if slug == "" {
+			return "", errors.New("product slug cannot be empty")
+		}
+		owner, err := u.repo.SlugOwner(u.ctx, tx, slug)
+		if err != nil {
+			return "", err
+		}
+		if owner != 0 && owner != ID {
+			return "", errors.New("product slug is already in use")
+		}
+		return slug, nil
+	}
+
+	base := helper.Slugify(name)
+	if base == "" {
+		base = "product"
+	}
+	slug := base
+	for n := 2; ; n++ {
+		owner, err := u.repo.SlugOwner(u.ctx, tx, slug)
+		if err != nil {
+			return "", err
+		}
+		if owner == 0 || owner == ID {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+}
@@ -0,0 +1,407 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"product_service/helper"
+	"product_service/proto"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SetProductOptions replaces the option types of a product, such as size or colour.
+// Existing variants must still fit the new options.
+func (u *ProductService) SetProductOptions(req *proto.SetProductOptionsRequest) (*proto.Product, error) {
+	seen := make(map[string]bool, len(req.Options))
+	for i, option := range req.Options {
+		option.Name = strings.TrimSpace(option.Name)
+		if option.Name == "" {
+			return nil, errors.New("option name is required")
+		}
+		if seen[option.Name] {
+			return nil, fmt.Errorf("duplicate option: %s", option.Name)
+		}
+		seen[option.Name] = true
+
+		if len(option.Values) == 0 {
+			return nil, fmt.Errorf("option %s needs at least one value", option.Name)
+		}
+		values := make(map[string]bool, len(option.Values))
+		for _, value := range option.Values {
+			if value == "" || values[value] {
+				return nil, fmt.Errorf("option %s has an empty or duplicate value", option.Name)
+			}
+			values[value] = true
+		}
+
+		if option.Position == 0 {
+			option.Position = int32(i)
+		}
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	product, err := u.repo.GetProductByID(u.ctx, tx, int(req.ProductId))
+	if err != nil {
+		return nil, err
+	}
+	if product.Kind == ProductKindBundle {
+		return nil, errors.New("a bundle cannot have options")
+	}
+
+	variants, err := u.variantRepo.GetProductVariants(u.ctx, tx, int(req.ProductId))
+	if err != nil {
+		return nil, err
+	}
+	for _, variant := range variants {
+		if variant.IsDefault && len(variant.Attributes) == 0 {
+			continue
+		}
+		if err := validateAttributes(req.Options, variant.Attributes); err != nil {
+			return nil, fmt.Errorf("variant %s: %w", variant.Sku, err)
+		}
+	}
+
+	if err := u.variantRepo.SetOptions(u.ctx, tx, int(req.ProductId), req.Options); err != nil {
+		return nil, err
+	}
+
+	if err := u.recordProductEvent(tx, ProductUpdated, int(req.ProductId)); err != nil {
+		return nil, err
+	}
+
+	if err := u.loadTaxonomy(tx, product); err != nil {
+		return nil, err
+	}
+	if err := u.loadVariants(tx, product); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	u.invalidateProductCache(int(req.ProductId))
+	return product, nil
+}
+
+// CreateVariant adds a variant with one value for each of the product's options.
+// A SKU is generated from the attribute values when none is given. New variants
+// start without stock, which is added through AdjustVariantStock.
+func (u *ProductService) CreateVariant(variant *proto.ProductVariant) (*proto.ProductVariant, error) {
+	if variant.Price < 0 {
+		return nil, errors.New("price cannot be negative")
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	product, err := u.repo.GetProductByID(u.ctx, tx, int(variant.ProductId))
+	if err != nil {
+		return nil, err
+	}
+	if product.Kind == ProductKindBundle {
+		return nil, errors.New("a bundle has a single variant")
+	}
+
+	options, err := u.variantRepo.GetOptions(u.ctx, tx, int(variant.ProductId))
+	if err != nil {
+		return nil, err
+	}
+	if len(options) == 0 {
+		return nil, errors.New("set the product options before adding variants")
+	}
+	if err := validateAttributes(options, variant.Attributes); err != nil {
+		return nil, err
+	}
+
+	variant.Sku = strings.TrimSpace(variant.Sku)
+	if variant.Sku == "" {
+		variant.Sku = variantSKU(int(variant.ProductId), options, variant.Attributes)
+	}
+	variant.IsDefault = false
+	variant.Stock = 0
+	variant.TestStock = 0
+
+	created, err := u.variantRepo.Create(u.ctx, tx, variant)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.recordProductEvent(tx, ProductUpdated, int(created.ProductId)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Variant %s created for product %d", created.Sku, created.ProductId)
+	u.invalidateProductCache(int(created.ProductId))
+	return created, nil
+}
+
+// UpdateVariant replaces a variant's SKU, price override, attributes and position.
+// An empty SKU or attribute set keeps the current one. Stock is left as it is.
+func (u *ProductService) UpdateVariant(variant *proto.ProductVariant) (*proto.ProductVariant, error) {
+	if variant.Price < 0 {
+		return nil, errors.New("price cannot be negative")
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	existing, err := u.variantRepo.GetByID(u.ctx, tx, int(variant.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	variant.Sku = strings.TrimSpace(variant.Sku)
+	if variant.Sku == "" {
+		variant.Sku = existing.Sku
+	}
+	if len(variant.Attributes) == 0 {
+		variant.Attributes = existing.Attributes
+	}
+
+	if !existing.IsDefault || len(variant.Attributes) > 0 {
+		options, err := u.variantRepo.GetOptions(u.ctx, tx, int(existing.ProductId))
+		if err != nil {
+			return nil, err
+		}
+		if err := validateAttributes(options, variant.Attributes); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := u.variantRepo.Update(u.ctx, tx, variant)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.recordProductEvent(tx, ProductUpdated, int(existing.ProductId)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	u.invalidateProductCache(int(existing.ProductId))
+	return updated, nil
+}
+
+// DeleteVariant removes a variant; past order lines keep their price but lose the link.
+// The last variant of a product cannot be removed, since orders need one to buy.
+func (u *ProductService) DeleteVariant(ID int) error {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	variant, err := u.variantRepo.GetByID(u.ctx, tx, ID)
+	if err != nil {
+		return err
+	}
+
+	variants, err := u.variantRepo.GetProductVariants(u.ctx, tx, int(variant.ProductId))
+	if err != nil {
+		return err
+	}
+	if len(variants) == 1 {
+		return errors.New("a product must keep at least one variant")
+	}
+
+	bundleIDs, err := u.bundleRepo.GetBundlesOfVariant(u.ctx, tx, ID)
+	if err != nil {
+		return err
+	}
+	if len(bundleIDs) > 0 {
+		return fmt.Errorf("variant is a component of bundle %d", bundleIDs[0])
+	}
+
+	if err := u.variantRepo.Delete(u.ctx, tx, ID); err != nil {
+		return err
+	}
+
+	if _, _, err := u.variantRepo.SyncProductStock(u.ctx, tx, int(variant.ProductId)); err != nil {
+		return err
+	}
+
+	if err := u.recordProductEvent(tx, ProductUpdated, int(variant.ProductId)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rollback = false
+
+	u.invalidateProductCache(int(variant.ProductId))
+	return nil
+}
+
+func (u *ProductService) GetVariant(ID int) (*proto.ProductVariant, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	variant, err := u.variantRepo.GetByID(u.ctx, tx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.attachWarehouseStock(tx, []*proto.ProductVariant{variant}); err != nil {
+		return nil, err
+	}
+	return variant, nil
+}
+
+// AdjustVariantStock takes or returns stock of a variant and records the movement in
+// the inventory ledger, failing instead of going below zero
+func (u *ProductService) AdjustVariantStock(req *proto.AdjustVariantStockRequest) (*proto.ProductVariant, error) {
+	if req.Reason == MovementTransfer {
+		return nil, errTransferReason
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	variant, err := u.moveStock(tx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.attachWarehouseStock(tx, []*proto.ProductVariant{variant}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	u.invalidateProductCache(int(variant.ProductId))
+	return variant, nil
+}
+
+func (u *ProductService) loadVariants(tx *sql.Tx, product *proto.Product) error {
+	options, err := u.variantRepo.GetOptions(u.ctx, tx, int(product.Id))
+	if err != nil {
+		return err
+	}
+
+	variants, err := u.variantRepo.GetProductVariants(u.ctx, tx, int(product.Id))
+	if err != nil {
+		return err
+	}
+
+	if err := u.attachWarehouseStock(tx, variants); err != nil {
+		return err
+	}
+
+	product.Options = options
+	product.Variants = variants
+	return nil
+}
+
+// validateAttributes checks that a variant has exactly one allowed value for every option
+func validateAttributes(options []*proto.ProductOption, attributes map[string]string) error {
+	if len(attributes) != len(options) {
+		return fmt.Errorf("variant needs exactly one value for each of the %d product options", len(options))
+	}
+
+	for _, option := range options {
+		value, ok := attributes[option.Name]
+		if !ok {
+			return fmt.Errorf("missing value for option %s", option.Name)
+		}
+
+		allowed := false
+		for _, v := range option.Values {
+			if v == value {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("invalid value %q for option %s", value, option.Name)
+		}
+	}
+
+	return nil
+}
+
+func defaultSKU(productID int) string {
+	return fmt.Sprintf("SKU-%d", productID)
+}
+
+// variantSKU builds a SKU such as SKU-12-RED-XL from the attribute values in option order
+func variantSKU(productID int, options []*proto.ProductOption, attributes map[string]string) string {
+	parts := []string{defaultSKU(productID)}
+	for _, option := range options {
+		parts = append(parts, strings.ToUpper(helper.Slugify(attributes[option.Name])))
+	}
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,430 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"product_service/helper"
+	"product_service/proto"
+	"product_service/repository"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Strategies AllocateStock picks warehouses with. A line that no single warehouse
+// has in full is shipped from several, in the strategy's order, so any order whose
+// total stock covers it can be allocated.
+const (
+	AllocatePriority = "priority" // The first warehouse by priority that has the whole line
+	AllocateNearest  = "nearest"  // The closest warehouse that has the whole line
+	AllocateSplit    = "split"    // Closest (or by priority) first, several warehouses per line if needed
+)
+
+// transferReference is the reference type of the two movements of a transfer; the
+// reference ID is the code of the other warehouse
+const transferReference = "warehouse"
+
+const earthRadiusKm = 6371
+
+func (u *ProductService) CreateWarehouse(warehouse *proto.Warehouse) (*proto.Warehouse, error) {
+	if err := normalizeWarehouse(warehouse); err != nil {
+		return nil, err
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	created, err := u.warehouseRepo.Create(u.ctx, tx, warehouse)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Warehouse %s created", created.Code)
+	return created, nil
+}
+
+// UpdateWarehouse replaces a warehouse's details. The last active warehouse cannot
+// be deactivated, since stock changes without a warehouse go to the active one.
+func (u *ProductService) UpdateWarehouse(warehouse *proto.Warehouse) (*proto.Warehouse, error) {
+	if err := normalizeWarehouse(warehouse); err != nil {
+		return nil, err
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	warehouses, err := u.warehouseRepo.GetAll(u.ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	found, othersActive := false, false
+	for _, existing := range warehouses {
+		if existing.Id == warehouse.Id {
+			found = true
+		} else if existing.Active {
+			othersActive = true
+		}
+	}
+	if !found {
+		return nil, repository.ErrWarehouseNotFound
+	}
+	if !warehouse.Active && !othersActive {
+		return nil, errors.New("at least one warehouse must stay active")
+	}
+
+	updated, err := u.warehouseRepo.Update(u.ctx, tx, warehouse)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	// Warehouse codes are embedded in the stock levels of cached products
+	u.invalidateCatalogCache()
+	return updated, nil
+}
+
+// ListWarehouses returns every warehouse in allocation order
+func (u *ProductService) ListWarehouses() ([]*proto.Warehouse, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	return u.warehouseRepo.GetAll(u.ctx, tx)
+}
+
+// TransferStock moves stock of a variant from one warehouse to another. The two
+// sides are recorded as transfer movements in one transaction, so the variant's
+// total stock does not change.
+func (u *ProductService) TransferStock(req *proto.TransferStockRequest) (*proto.ProductVariant, error) {
+	if req.Quantity <= 0 {
+		return nil, errors.New("quantity must be positive")
+	}
+	if req.FromWarehouseId == req.ToWarehouseId {
+		return nil, errors.New("source and destination warehouse must differ")
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	from, err := u.warehouseRepo.GetByID(u.ctx, tx, int(req.FromWarehouseId))
+	if err != nil {
+		return nil, err
+	}
+	to, err := u.warehouseRepo.GetByID(u.ctx, tx, int(req.ToWarehouseId))
+	if err != nil {
+		return nil, err
+	}
+	if !to.Active {
+		return nil, fmt.Errorf("warehouse %s is not active", to.Code)
+	}
+
+	moves := []*proto.AdjustVariantStockRequest{
+		{WarehouseId: from.Id, Delta: -req.Quantity, ReferenceId: to.Code},
+		{WarehouseId: to.Id, Delta: req.Quantity, ReferenceId: from.Code},
+	}
+	// Levels are changed in warehouse ID order, so opposite transfers cannot deadlock
+	if to.Id < from.Id {
+		moves[0], moves[1] = moves[1], moves[0]
+	}
+
+	var variant *proto.ProductVariant
+	for _, move := range moves {
+		move.VariantId = req.VariantId
+		move.Livemode = req.Livemode
+		move.Reason = MovementTransfer
+		move.ReferenceType = transferReference
+		move.Actor = req.Actor
+		move.Note = req.Note
+		if variant, err = u.moveStock(tx, move); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.attachWarehouseStock(tx, []*proto.ProductVariant{variant}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("Transferred %d of %s from %s to %s", req.Quantity, variant.Sku, from.Code, to.Code)
+	u.invalidateProductCache(int(variant.ProductId))
+	return variant, nil
+}
+
+// allocationPart is the quantity of a line taken from one warehouse
+type allocationPart struct {
+	warehouse *proto.Warehouse
+	quantity  int32
+}
+
+// AllocateStock picks the warehouses each line ships from with the request's
+// strategy and takes the stock as sales. Every line is allocated or none is.
+func (u *ProductService) AllocateStock(req *proto.AllocateStockRequest) ([]*proto.StockAllocation, error) {
+	strategy := req.Strategy
+	if strategy == "" {
+		strategy = AllocatePriority
+	}
+	if strategy != AllocatePriority && strategy != AllocateNearest && strategy != AllocateSplit {
+		return nil, fmt.Errorf("unknown allocation strategy %q", strategy)
+	}
+	if len(req.Lines) == 0 {
+		return nil, errors.New("lines are required")
+	}
+	for _, line := range req.Lines {
+		if line.Quantity <= 0 {
+			return nil, errors.New("quantity must be positive")
+		}
+	}
+
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	warehouses, err := u.warehouseRepo.GetAll(u.ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int32]*proto.Warehouse, len(warehouses))
+	for _, warehouse := range warehouses {
+		byID[warehouse.Id] = warehouse
+	}
+
+	// Bundles take the stock of their components. Variants are allocated in ID order,
+	// like AdjustStockBatch.
+	items, err := u.expandBundles(tx, req.Lines)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(items, func(a, b int) bool {
+		return items[a].variantID < items[b].variantID
+	})
+
+	var allocations []*proto.StockAllocation
+	touched := make(map[int32]bool)
+	for _, item := range items {
+		levels, err := u.warehouseRepo.LockVariantLevels(u.ctx, tx, int(item.variantID))
+		if err != nil {
+			return nil, err
+		}
+
+		parts, err := planAllocation(strategy, levels, byID, req, item.quantity)
+		if err != nil {
+			return nil, fmt.Errorf("variant %d: %w", item.variantID, err)
+		}
+
+		for _, part := range parts {
+			variant, err := u.moveStock(tx, &proto.AdjustVariantStockRequest{
+				VariantId:     item.variantID,
+				Delta:         -part.quantity,
+				Livemode:      req.Livemode,
+				Reason:        MovementSale,
+				ReferenceType: req.ReferenceType,
+				ReferenceId:   req.ReferenceId,
+				Actor:         req.Actor,
+				WarehouseId:   part.warehouse.Id,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("variant %d: %w", item.variantID, err)
+			}
+			touched[variant.ProductId] = true
+
+			allocations = append(allocations, &proto.StockAllocation{
+				Line:            int32(item.line),
+				VariantId:       item.variantID,
+				WarehouseId:     part.warehouse.Id,
+				WarehouseCode:   part.warehouse.Code,
+				Quantity:        part.quantity,
+				BundleVariantId: item.bundleVariantID,
+			})
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	for productID := range touched {
+		u.invalidateProductCache(int(productID))
+	}
+
+	sort.SliceStable(allocations, func(a, b int) bool {
+		return allocations[a].Line < allocations[b].Line
+	})
+	return allocations, nil
+}
+
+// planAllocation decides which warehouses a line of quantity ships from. Without a
+// destination, nearest and split rank warehouses by priority. Priority and nearest
+// keep a line in one warehouse when one has all of it.
+func planAllocation(strategy string, levels []*proto.WarehouseStock, warehouses map[int32]*proto.Warehouse, req *proto.AllocateStockRequest, quantity int32) ([]allocationPart, error) {
+	var candidates []allocationPart
+	var total int32
+	for _, level := range levels {
+		available := level.Stock
+		if !req.Livemode {
+			available = level.TestStock
+		}
+		warehouse := warehouses[level.WarehouseId]
+		if available <= 0 || warehouse == nil {
+			continue
+		}
+		candidates = append(candidates, allocationPart{warehouse: warehouse, quantity: available})
+		total += available
+	}
+
+	byDistance := strategy != AllocatePriority && hasLocation(req.Latitude, req.Longitude)
+	sort.SliceStable(candidates, func(a, b int) bool {
+		x, y := candidates[a].warehouse, candidates[b].warehouse
+		if byDistance {
+			dx, dy := warehouseDistance(x, req), warehouseDistance(y, req)
+			if dx != dy {
+				return dx < dy
+			}
+		}
+		if x.Priority != y.Priority {
+			return x.Priority < y.Priority
+		}
+		return x.Id < y.Id
+	})
+
+	if total < quantity {
+		return nil, repository.ErrInsufficientStock
+	}
+
+	if strategy != AllocateSplit {
+		for _, candidate := range candidates {
+			if candidate.quantity >= quantity {
+				return []allocationPart{{warehouse: candidate.warehouse, quantity: quantity}}, nil
+			}
+		}
+	}
+
+	var parts []allocationPart
+	remaining := quantity
+	for _, candidate := range candidates {
+		take := candidate.quantity
+		if take > remaining {
+			take = remaining
+		}
+		parts = append(parts, allocationPart{warehouse: candidate.warehouse, quantity: take})
+		if remaining -= take; remaining == 0 {
+			break
+		}
+	}
+	return parts, nil
+}
+
+// warehouseDistance is the distance in km from a warehouse to the request's
+// destination. Warehouses without a location rank after every located one.
+func warehouseDistance(warehouse *proto.Warehouse, req *proto.AllocateStockRequest) float64 {
+	if !hasLocation(warehouse.Latitude, warehouse.Longitude) {
+		return math.Inf(1)
+	}
+	return distanceKm(warehouse.Latitude, warehouse.Longitude, req.Latitude, req.Longitude)
+}
+
+// distanceKm is the great-circle distance between two points
+func distanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+func hasLocation(latitude, longitude float64) bool {
+	return latitude != 0 || longitude != 0
+}
+
+// attachWarehouseStock fills in the per-warehouse stock of variants
+func (u *ProductService) attachWarehouseStock(tx *sql.Tx, variants []*proto.ProductVariant) error {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	IDs := make([]int32, len(variants))
+	byID := make(map[int32]*proto.ProductVariant, len(variants))
+	for i, variant := range variants {
+		IDs[i] = variant.Id
+		byID[variant.Id] = variant
+	}
+
+	levels, err := u.warehouseRepo.GetLevelsForVariants(u.ctx, tx, IDs)
+	if err != nil {
+		return err
+	}
+	for _, level := range levels {
+		variant := byID[level.VariantId]
+		variant.WarehouseStock = append(variant.WarehouseStock, level)
+	}
+	return nil
+}
+
+func normalizeWarehouse(warehouse *proto.Warehouse) error {
+	warehouse.Code = strings.ToUpper(strings.TrimSpace(warehouse.Code))
+	warehouse.Name = strings.TrimSpace(warehouse.Name)
+
+	if warehouse.Code == "" || len(warehouse.Code) > 20 {
+		return errors.New("code must be 1 to 20 characters")
+	}
+	if warehouse.Name == "" || len(warehouse.Name) > 100 {
+		return errors.New("name must be 1 to 100 characters")
+	}
+	if warehouse.Latitude < -90 || warehouse.Latitude > 90 {
+		return errors.New("latitude must be between -90 and 90")
+	}
+	if warehouse.Longitude < -180 || warehouse.Longitude > 180 {
+		return errors.New("longitude must be between -180 and 180")
+	}
+	return nil
+}
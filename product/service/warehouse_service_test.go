@@ -0,0 +1,69 @@
+package service
+
+import (
+	"errors"
+	"product_service/proto"
+	"product_service/repository"
+	"testing"
+)
+
+func TestPlanAllocation(t *testing.T) {
+	warehouses := map[int32]*proto.Warehouse{
+		1: {Id: 1, Code: "MAIN", Priority: 1, Latitude: 52.52, Longitude: 13.40},
+		2: {Id: 2, Code: "EAST", Priority: 2, Latitude: 52.23, Longitude: 21.01},
+		3: {Id: 3, Code: "WEST", Priority: 3, Latitude: 48.86, Longitude: 2.35},
+	}
+	levels := []*proto.WarehouseStock{
+		{VariantId: 9, WarehouseId: 1, Stock: 3},
+		{VariantId: 9, WarehouseId: 2, Stock: 5},
+		{VariantId: 9, WarehouseId: 3, Stock: 4},
+	}
+	nearWarsaw := &proto.AllocateStockRequest{Livemode: true, Latitude: 52.20, Longitude: 21.00}
+	anywhere := &proto.AllocateStockRequest{Livemode: true}
+
+	type part struct {
+		code     string
+		quantity int32
+	}
+
+	tests := []struct {
+		name     string
+		strategy string
+		req      *proto.AllocateStockRequest
+		quantity int32
+		want     []part
+		wantErr  error
+	}{
+		{"priority takes the first warehouse with the whole line", AllocatePriority, anywhere, 4, []part{{"EAST", 4}}, nil},
+		{"priority ignores the destination", AllocatePriority, nearWarsaw, 2, []part{{"MAIN", 2}}, nil},
+		{"priority splits in priority order when no warehouse has the line", AllocatePriority, anywhere, 10, []part{{"MAIN", 3}, {"EAST", 5}, {"WEST", 2}}, nil},
+		{"nearest takes the closest warehouse with the whole line", AllocateNearest, nearWarsaw, 2, []part{{"EAST", 2}}, nil},
+		{"nearest splits by distance when no warehouse has the line", AllocateNearest, nearWarsaw, 7, []part{{"EAST", 5}, {"MAIN", 2}}, nil},
+		{"split fills from the first warehouses even when one has the line", AllocateSplit, anywhere, 4, []part{{"MAIN", 3}, {"EAST", 1}}, nil},
+		{"more than the total stock", AllocatePriority, anywhere, 13, nil, repository.ErrInsufficientStock},
+		{"test mode uses test stock", AllocatePriority, &proto.AllocateStockRequest{}, 1, nil, repository.ErrInsufficientStock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := planAllocation(tt.strategy, levels, warehouses, tt.req, tt.quantity)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("planAllocation() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var got []part
+			for _, p := range parts {
+				got = append(got, part{p.warehouse.Code, p.quantity})
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("planAllocation() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("planAllocation() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
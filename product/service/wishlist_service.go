@@ -0,0 +1,254 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"product_service/helper"
+	"product_service/proto"
+	"product_service/transport/kafka"
+	"product_service/types"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// restockPollInterval is how often the publisher looks for new restocks
+	restockPollInterval = 10 * time.Second
+	restockBatchSize    = 50
+)
+
+func (u *ProductService) AddToWishlist(req *proto.WishlistRequest) error {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	product, err := u.repo.GetProductByID(u.ctx, tx, int(req.ProductId))
+	if err != nil {
+		return err
+	}
+	if product.ArchivedAt != "" {
+		return errors.New("archived products cannot be added to a wishlist")
+	}
+
+	if err := u.wishlistRepo.AddItem(u.ctx, tx, int(req.UserId), int(req.ProductId)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rollback = false
+
+	return nil
+}
+
+func (u *ProductService) RemoveFromWishlist(req *proto.WishlistRequest) error {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	return u.wishlistRepo.RemoveItem(u.ctx, tx, int(req.UserId), int(req.ProductId))
+}
+
+// GetWishlist returns a user's saved products with their current price and stock
+func (u *ProductService) GetWishlist(req *proto.GetWishlistRequest) (*proto.Wishlist, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	items, productIDs, err := u.wishlistRepo.GetItems(u.ctx, tx, int(req.UserId))
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return &proto.Wishlist{}, nil
+	}
+
+	products, err := u.repo.GetProductsByIDs(u.ctx, tx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.attachImages(products); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint32]*proto.Product, len(products))
+	for _, product := range products {
+		byID[product.Id] = product
+	}
+	for i, item := range items {
+		item.Product = byID[uint32(productIDs[i])]
+	}
+
+	return &proto.Wishlist{Items: items}, nil
+}
+
+// SubscribeBackInStock asks for an event each time an out-of-stock product comes back
+func (u *ProductService) SubscribeBackInStock(req *proto.WishlistRequest) (*proto.StockSubscription, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	product, err := u.repo.GetProductByID(u.ctx, tx, int(req.ProductId))
+	if err != nil {
+		return nil, err
+	}
+	if product.ArchivedAt != "" {
+		return nil, errors.New("product is archived")
+	}
+	if product.Stock > 0 {
+		return nil, errors.New("product is in stock")
+	}
+
+	subscription, err := u.wishlistRepo.Subscribe(u.ctx, tx, int(req.UserId), int(req.ProductId))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	rollback = false
+
+	logrus.Infof("User %d subscribed to restocks of product %d", req.UserId, req.ProductId)
+	return subscription, nil
+}
+
+func (u *ProductService) UnsubscribeBackInStock(req *proto.WishlistRequest) error {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	return u.wishlistRepo.Unsubscribe(u.ctx, tx, int(req.UserId), int(req.ProductId))
+}
+
+func (u *ProductService) ListStockSubscriptions(req *proto.GetWishlistRequest) ([]*proto.StockSubscription, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	return u.wishlistRepo.GetSubscriptions(u.ctx, tx, int(req.UserId))
+}
+
+// RunRestockPublisher sends a back-in-stock event to every subscriber of each recorded
+// restock. A restock is marked published in the transaction that claimed it, so when
+// sending fails the whole restock is retried; consumers drop the events they already
+// handled by event ID. It runs for the life of the service.
+func (u *ProductService) RunRestockPublisher(topic string) {
+	logrus.Info("Restock publisher started")
+	ticker := time.NewTicker(restockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			published, err := u.publishRestocks(topic)
+			if err != nil {
+				logrus.Errorf("Failed to publish restocks: %v", err)
+				break
+			}
+			if published < restockBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-u.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishRestocks publishes one batch of restocks and returns how many it handled
+func (u *ProductService) publishRestocks(topic string) (int, error) {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	rollback := true
+	defer func() {
+		if rollback {
+			if rErr := tx.Rollback(); rErr != nil {
+				logrus.Errorf("Rollback error: %v", rErr)
+			}
+		}
+	}()
+
+	restocks, err := u.wishlistRepo.ClaimRestocks(u.ctx, tx, restockBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, restock := range restocks {
+		userIDs, err := u.wishlistRepo.GetRestockSubscribers(u.ctx, tx, restock)
+		if err != nil {
+			return 0, err
+		}
+
+		if len(userIDs) > 0 {
+			product, err := u.repo.GetProductByID(u.ctx, tx, restock.ProductID)
+			if err != nil {
+				return 0, err
+			}
+
+			for _, userID := range userIDs {
+				if _, _, err := kafka.SendBackInStock(topic, &types.BackInStockEvent{
+					EventID:     fmt.Sprintf("restock-%d-user-%d", restock.ID, userID),
+					RestockID:   int32(restock.ID),
+					UserID:      userID,
+					ProductID:   int32(restock.ProductID),
+					ProductName: product.Name,
+					Price:       product.Price,
+					Stock:       int32(restock.Stock),
+					RestockedAt: restock.CreatedAt,
+				}); err != nil {
+					return 0, err
+				}
+			}
+		}
+
+		if err := u.wishlistRepo.MarkRestockPublished(u.ctx, tx, restock, userIDs); err != nil {
+			return 0, err
+		}
+		if len(userIDs) > 0 {
+			logrus.Infof("Restock %d of product %d sent to %d subscribers", restock.ID, restock.ProductID, len(userIDs))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	rollback = false
+
+	return len(restocks), nil
+}
@@ -1,104 +1,777 @@
-package grpc
-
-import (
-	"context"
-	"net"
-	"product_service/cmd/db"
-	"product_service/proto"
-	"product_service/repository"
-	"product_service/service"
-
-	"github.com/sirupsen/logrus"
-	"google.golang.org/grpc"
-)
-
-type ProductGRPCServer struct {
-	service *service.ProductService
-	proto.UnimplementedProductServiceServer
-}
-
-func NewProductGRPCServer(service *service.ProductService) *ProductGRPCServer {
-	return &ProductGRPCServer{
-		service: service,
-	}
-}
-
-func (u *ProductGRPCServer) CreateProduct(ctx context.Context, req *proto.ProductRequest) (*proto.Empty, error) {
-	logrus.Info(req.Payload.Name)
-	logrus.Info("create product handler")
-	if err := u.service.Create(req); err != nil {
-		return nil, err
-	}
-
-	return nil, nil
-}
-
-func (u *ProductGRPCServer) GetProduct(ctx context.Context, req *proto.GetProductRequest) (*proto.Product, error) {
-	product, err := u.service.GetProductByID(int(req.Id))
-	if err != nil {
-		return nil, err
-	}
-
-	return product, nil
-}
-
-func (u *ProductGRPCServer) ListProducts(ctx context.Context, req *proto.Offset) (*proto.ProductList, error) {
-	products, totalProducts, totalPages, page, err := u.service.GetAll(int(req.Id))
-	if err != nil {
-		return nil, err
-	}
-	logrus.Info("proto Listing products")
-
-	return &proto.ProductList{
-		Products:  products,
-		TotalPage: int32(totalPages),
-		Total:     int64(totalProducts),
-		Page:      int32(page),
-	}, nil
-}
-
-func (u *ProductGRPCServer) UpdateProduct(ctx context.Context, req *proto.Product) (*proto.Product, error) {
-	productResult, err := u.service.Update(req)
-	if err != nil {
-		return nil, err
-	}
-
-	return productResult, nil
-}
-
-func (u *ProductGRPCServer) DeleteProduct(ctx context.Context, req *proto.GetProductRequest) (*proto.Empty, error) {
-	if err := u.service.Delete(int(req.Id)); err != nil {
-		return nil, err
-	}
-
-	return nil, nil
-}
-
-func GRPCListen() {
-	DB, err := db.Connect()
-	if err != nil {
-		logrus.Fatalf("failed to connect to database: %v", err)
-	}
-
-	if err := db.InitRedis(); err != nil {
-		logrus.Warnf("Redis initialization failed: %v. Continuing without cache.", err)
-	}
-
-	ctx := context.Background()
-	repo := repository.NewProductRepositoryImpl()
-	service := service.NewProductService(repo, DB, ctx)
-	connection := NewProductGRPCServer(service)
-
-	lis, err := net.Listen("tcp", ":40001")
-	if err != nil {
-		logrus.Fatalf("Failed to listen for gRPC: %v", err)
-	}
-
-	srv := grpc.NewServer()
-	proto.RegisterProductServiceServer(srv, connection)
-	logrus.Infof("gRPC Server started on port 40001")
-
-	if err := srv.Serve(lis); err != nil {
-		logrus.Fatalf("error when connect to gRPC Server  %v", err)
-	}
-}
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"product_service/cmd/db"
+	"product_service/proto"
+	"product_service/repository"
+	"product_service/service"
+	"product_service/transport/kafka"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+type ProductGRPCServer struct {
+	service *service.ProductService
+	proto.UnimplementedProductServiceServer
+}
+
+func NewProductGRPCServer(service *service.ProductService) *ProductGRPCServer {
+	return &ProductGRPCServer{
+		service: service,
+	}
+}
+
+func (u *ProductGRPCServer) CreateProduct(ctx context.Context, req *proto.ProductRequest) (*proto.Empty, error) {
+	logrus.Info(req.Payload.Name)
+	logrus.Info("create product handler")
+	if err := u.service.Create(req); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (u *ProductGRPCServer) GetProduct(ctx context.Context, req *proto.GetProductRequest) (*proto.Product, error) {
+	product, err := u.service.GetProductByID(int(req.Id), req.AcceptLanguage)
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (u *ProductGRPCServer) GetProductBySlug(ctx context.Context, req *proto.GetProductBySlugRequest) (*proto.Product, error) {
+	product, err := u.service.GetProductBySlug(req.Slug, req.AcceptLanguage)
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (u *ProductGRPCServer) BatchGetProducts(ctx context.Context, req *proto.BatchGetProductsRequest) (*proto.BatchGetProductsResponse, error) {
+	products, err := u.service.BatchGetProducts(req.Ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.BatchGetProductsResponse{Products: products}, nil
+}
+
+func (u *ProductGRPCServer) ListProducts(ctx context.Context, req *proto.Offset) (*proto.ProductList, error) {
+	products, totalProducts, totalPages, page, err := u.service.GetAll(int(req.Id), req.AcceptLanguage)
+	if err != nil {
+		return nil, err
+	}
+	logrus.Info("proto Listing products")
+
+	return &proto.ProductList{
+		Products:  products,
+		TotalPage: int32(totalPages),
+		Total:     int64(totalProducts),
+		Page:      int32(page),
+	}, nil
+}
+
+func (u *ProductGRPCServer) UpdateProduct(ctx context.Context, req *proto.Product) (*proto.Product, error) {
+	productResult, err := u.service.Update(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return productResult, nil
+}
+
+// DeleteProduct archives the product; PurgeProduct removes it for good
+func (u *ProductGRPCServer) DeleteProduct(ctx context.Context, req *proto.GetProductRequest) (*proto.Empty, error) {
+	if err := u.service.ArchiveProduct(int(req.Id)); err != nil {
+		return nil, err
+	}
+
+	return &proto.Empty{}, nil
+}
+
+func (u *ProductGRPCServer) UnarchiveProduct(ctx context.Context, req *proto.GetProductRequest) (*proto.Product, error) {
+	product, err := u.service.UnarchiveProduct(int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (u *ProductGRPCServer) PurgeProduct(ctx context.Context, req *proto.GetProductRequest) (*proto.Empty, error) {
+	if err := u.service.PurgeProduct(int(req.Id)); err != nil {
+		return nil, err
+	}
+
+	return &proto.Empty{}, nil
+}
+
+func (u *ProductGRPCServer) UpdateTestStock(ctx context.Context, req *proto.UpdateTestStockRequest) (*proto.Empty, error) {
+	if err := u.service.UpdateTestStock(req); err != nil {
+		return nil, err
+	}
+
+	return &proto.Empty{}, nil
+}
+
+func (u *ProductGRPCServer) SearchProducts(ctx context.Context, req *proto.SearchProductsRequest) (*proto.ProductList, error) {
+	productList, err := u.service.Search(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return productList, nil
+}
+
+func (u *ProductGRPCServer) SetProductTranslation(ctx context.Context, req *proto.ProductTranslation) (*proto.ProductTranslation, error) {
+	translation, err := u.service.SetProductTranslation(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return translation, nil
+}
+
+func (u *ProductGRPCServer) DeleteProductTranslation(ctx context.Context, req *proto.GetProductTranslationRequest) (*proto.Empty, error) {
+	if err := u.service.DeleteProductTranslation(int(req.ProductId), req.Locale); err != nil {
+		return nil, err
+	}
+
+	return &proto.Empty{}, nil
+}
+
+func (u *ProductGRPCServer) ListProductTranslations(ctx context.Context, req *proto.GetProductRequest) (*proto.ProductTranslationList, error) {
+	translations, err := u.service.ListProductTranslations(int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ProductTranslationList{Translations: translations}, nil
+}
+
+func (u *ProductGRPCServer) CreateCategory(ctx context.Context, req *proto.Category) (*proto.Category, error) {
+	category, err := u.service.CreateCategory(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (u *ProductGRPCServer) UpdateCategory(ctx context.Context, req *proto.Category) (*proto.Category, error) {
+	category, err := u.service.UpdateCategory(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (u *ProductGRPCServer) DeleteCategory(ctx context.Context, req *proto.GetCategoryRequest) (*proto.Empty, error) {
+	if err := u.service.DeleteCategory(int(req.Id)); err != nil {
+		return nil, err
+	}
+
+	return &proto.Empty{}, nil
+}
+
+func (u *ProductGRPCServer) ListCategories(ctx context.Context, req *proto.Empty) (*proto.CategoryList, error) {
+	categories, err := u.service.ListCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.CategoryList{Categories: categories}, nil
+}
+
+func (u *ProductGRPCServer) ListCategoryProducts(ctx context.Context, req *proto.ListCategoryProductsRequest) (*proto.CategoryProductList, error) {
+	response, err := u.service.ListCategoryProducts(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (u *ProductGRPCServer) SetProductCategories(ctx context.Context, req *proto.SetProductCategoriesRequest) (*proto.Product, error) {
+	product, err := u.service.SetProductCategories(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (u *ProductGRPCServer) CreateTag(ctx context.Context, req *proto.Tag) (*proto.Tag, error) {
+	tag, err := u.service.CreateTag(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+func (u *ProductGRPCServer) DeleteTag(ctx context.Context, req *proto.GetTagRequest) (*proto.Empty, error) {
+	if err := u.service.DeleteTag(int(req.Id)); err != nil {
+		return nil, err
+	}
+
+	return &proto.Empty{}, nil
+}
+
+func (u *ProductGRPCServer) ListTags(ctx context.Context, req *proto.Empty) (*proto.TagList, error) {
+	tags, err := u.service.ListTags()
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.TagList{Tags: tags}, nil
+}
+
+func (u *ProductGRPCServer) SetProductTags(ctx context.Context, req *proto.SetProductTagsRequest) (*proto.Product, error) {
+	product, err := u.service.SetProductTags(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (u *ProductGRPCServer) SetProductOptions(ctx context.Context, req *proto.SetProductOptionsRequest) (*proto.Product, error) {
+	product, err := u.service.SetProductOptions(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (u *ProductGRPCServer) CreateVariant(ctx context.Context, req *proto.ProductVariant) (*proto.ProductVariant, error) {
+	variant, err := u.service.CreateVariant(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+func (u *ProductGRPCServer) UpdateVariant(ctx context.Context, req *proto.ProductVariant) (*proto.ProductVariant, error) {
+	variant, err := u.service.UpdateVariant(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+func (u *ProductGRPCServer) DeleteVariant(ctx context.Context, req *proto.GetVariantRequest) (*proto.Empty, error) {
+	if err := u.service.DeleteVariant(int(req.Id)); err != nil {
+		return nil, err
+	}
+
+	return &proto.Empty{}, nil
+}
+
+func (u *ProductGRPCServer) GetVariant(ctx context.Context, req *proto.GetVariantRequest) (*proto.ProductVariant, error) {
+	variant, err := u.service.GetVariant(int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+func (u *ProductGRPCServer) AdjustVariantStock(ctx context.Context, req *proto.AdjustVariantStockRequest) (*proto.ProductVariant, error) {
+	variant, err := u.service.AdjustVariantStock(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+func (u *ProductGRPCServer) AdjustStockBatch(ctx context.Context, req *proto.AdjustStockBatchRequest) (*proto.ProductVariantList, error) {
+	variants, err := u.service.AdjustStockBatch(req.Adjustments)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ProductVariantList{Variants: variants}, nil
+}
+
+func (u *ProductGRPCServer) SetBundleComponents(ctx context.Context, req *proto.SetBundleComponentsRequest) (*proto.Product, error) {
+	return u.service.SetBundleComponents(req)
+}
+
+func (u *ProductGRPCServer) SetDigitalDelivery(ctx context.Context, req *proto.DigitalDelivery) (*proto.Product, error) {
+	product, err := u.service.SetDigitalDelivery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (u *ProductGRPCServer) AddLicenseKeys(ctx context.Context, req *proto.AddLicenseKeysRequest) (*proto.LicenseKeyPool, error) {
+	pool, err := u.service.AddLicenseKeys(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+func (u *ProductGRPCServer) DeliverDigitalItems(ctx context.Context, req *proto.DeliverDigitalItemsRequest) (*proto.DigitalItemDeliveryList, error) {
+	deliveries, err := u.service.DeliverDigitalItems(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.DigitalItemDeliveryList{Deliveries: deliveries}, nil
+}
+
+func (u *ProductGRPCServer) ListInventoryMovements(ctx context.Context, req *proto.ListInventoryMovementsRequest) (*proto.InventoryMovementList, error) {
+	movements, err := u.service.ListInventoryMovements(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+func (u *ProductGRPCServer) CreateWarehouse(ctx context.Context, req *proto.Warehouse) (*proto.Warehouse, error) {
+	warehouse, err := u.service.CreateWarehouse(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return warehouse, nil
+}
+
+func (u *ProductGRPCServer) UpdateWarehouse(ctx context.Context, req *proto.Warehouse) (*proto.Warehouse, error) {
+	warehouse, err := u.service.UpdateWarehouse(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return warehouse, nil
+}
+
+func (u *ProductGRPCServer) ListWarehouses(ctx context.Context, req *proto.Empty) (*proto.WarehouseList, error) {
+	warehouses, err := u.service.ListWarehouses()
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.WarehouseList{Warehouses: warehouses}, nil
+}
+
+func (u *ProductGRPCServer) TransferStock(ctx context.Context, req *proto.TransferStockRequest) (*proto.ProductVariant, error) {
+	variant, err := u.service.TransferStock(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+func (u *ProductGRPCServer) AllocateStock(ctx context.Context, req *proto.AllocateStockRequest) (*proto.AllocateStockResponse, error) {
+	allocations, err := u.service.AllocateStock(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.AllocateStockResponse{Allocations: allocations}, nil
+}
+
+func (u *ProductGRPCServer) SchedulePrice(ctx context.Context, req *proto.ProductPrice) (*proto.ProductPrice, error) {
+	price, err := u.service.SchedulePrice(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return price, nil
+}
+
+func (u *ProductGRPCServer) EndScheduledPrice(ctx context.Context, req *proto.GetProductPriceRequest) (*proto.ProductPrice, error) {
+	price, err := u.service.EndScheduledPrice(int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return price, nil
+}
+
+func (u *ProductGRPCServer) GetPriceHistory(ctx context.Context, req *proto.PriceHistoryRequest) (*proto.PriceHistory, error) {
+	history, err := u.service.GetPriceHistory(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+func (u *ProductGRPCServer) CreateSupplier(ctx context.Context, req *proto.Supplier) (*proto.Supplier, error) {
+	supplier, err := u.service.CreateSupplier(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return supplier, nil
+}
+
+func (u *ProductGRPCServer) ListSuppliers(ctx context.Context, req *proto.Empty) (*proto.SupplierList, error) {
+	suppliers, err := u.service.ListSuppliers()
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.SupplierList{Suppliers: suppliers}, nil
+}
+
+func (u *ProductGRPCServer) CreatePurchaseOrder(ctx context.Context, req *proto.PurchaseOrder) (*proto.PurchaseOrder, error) {
+	order, err := u.service.CreatePurchaseOrder(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (u *ProductGRPCServer) GetPurchaseOrder(ctx context.Context, req *proto.GetPurchaseOrderRequest) (*proto.PurchaseOrder, error) {
+	order, err := u.service.GetPurchaseOrder(int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (u *ProductGRPCServer) ListPurchaseOrders(ctx context.Context, req *proto.ListPurchaseOrdersRequest) (*proto.PurchaseOrderList, error) {
+	orders, err := u.service.ListPurchaseOrders(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (u *ProductGRPCServer) SendPurchaseOrder(ctx context.Context, req *proto.GetPurchaseOrderRequest) (*proto.PurchaseOrder, error) {
+	order, err := u.service.SendPurchaseOrder(int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (u *ProductGRPCServer) ReceivePurchaseOrder(ctx context.Context, req *proto.ReceivePurchaseOrderRequest) (*proto.PurchaseOrder, error) {
+	order, err := u.service.ReceivePurchaseOrder(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (u *ProductGRPCServer) CancelPurchaseOrder(ctx context.Context, req *proto.GetPurchaseOrderRequest) (*proto.PurchaseOrder, error) {
+	order, err := u.service.CancelPurchaseOrder(int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (u *ProductGRPCServer) SetReorderThreshold(ctx context.Context, req *proto.ReorderThreshold) (*proto.ReorderThreshold, error) {
+	threshold, err := u.service.SetReorderThreshold(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return threshold, nil
+}
+
+func (u *ProductGRPCServer) GetReorderReport(ctx context.Context, req *proto.ReorderReportRequest) (*proto.ReorderReport, error) {
+	report, err := u.service.GetReorderReport(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (u *ProductGRPCServer) CreatePriceRule(ctx context.Context, req *proto.PriceRule) (*proto.PriceRule, error) {
+	rule, err := u.service.CreatePriceRule(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (u *ProductGRPCServer) DeletePriceRule(ctx context.Context, req *proto.GetPriceRuleRequest) (*proto.Empty, error) {
+	if err := u.service.DeletePriceRule(int(req.Id)); err != nil {
+		return nil, err
+	}
+
+	return &proto.Empty{}, nil
+}
+
+func (u *ProductGRPCServer) ListPriceRules(ctx context.Context, req *proto.GetProductRequest) (*proto.PriceRuleList, error) {
+	rules, err := u.service.ListPriceRules(int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.PriceRuleList{Rules: rules}, nil
+}
+
+func (u *ProductGRPCServer) QuotePrices(ctx context.Context, req *proto.QuotePricesRequest) (*proto.PriceQuote, error) {
+	quote, err := u.service.QuotePrices(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+func (u *ProductGRPCServer) CreateReview(ctx context.Context, req *proto.ProductReview) (*proto.ProductReview, error) {
+	review, err := u.service.CreateReview(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+func (u *ProductGRPCServer) DeleteReview(ctx context.Context, req *proto.DeleteReviewRequest) (*proto.ProductReview, error) {
+	review, err := u.service.DeleteReview(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+func (u *ProductGRPCServer) ModerateReview(ctx context.Context, req *proto.ModerateReviewRequest) (*proto.ProductReview, error) {
+	review, err := u.service.ModerateReview(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+func (u *ProductGRPCServer) ListReviews(ctx context.Context, req *proto.ListReviewsRequest) (*proto.ReviewList, error) {
+	reviews, err := u.service.ListReviews(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+func (u *ProductGRPCServer) AddToWishlist(ctx context.Context, req *proto.WishlistRequest) (*proto.Empty, error) {
+	if err := u.service.AddToWishlist(req); err != nil {
+		return nil, err
+	}
+
+	return &proto.Empty{}, nil
+}
+
+func (u *ProductGRPCServer) RemoveFromWishlist(ctx context.Context, req *proto.WishlistRequest) (*proto.Empty, error) {
+	if err := u.service.RemoveFromWishlist(req); err != nil {
+		return nil, err
+	}
+
+	return &proto.Empty{}, nil
+}
+
+func (u *ProductGRPCServer) GetWishlist(ctx context.Context, req *proto.GetWishlistRequest) (*proto.Wishlist, error) {
+	wishlist, err := u.service.GetWishlist(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return wishlist, nil
+}
+
+func (u *ProductGRPCServer) SubscribeBackInStock(ctx context.Context, req *proto.WishlistRequest) (*proto.StockSubscription, error) {
+	subscription, err := u.service.SubscribeBackInStock(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (u *ProductGRPCServer) UnsubscribeBackInStock(ctx context.Context, req *proto.WishlistRequest) (*proto.Empty, error) {
+	if err := u.service.UnsubscribeBackInStock(req); err != nil {
+		return nil, err
+	}
+
+	return &proto.Empty{}, nil
+}
+
+func (u *ProductGRPCServer) ListStockSubscriptions(ctx context.Context, req *proto.GetWishlistRequest) (*proto.StockSubscriptionList, error) {
+	subscriptions, err := u.service.ListStockSubscriptions(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.StockSubscriptionList{Subscriptions: subscriptions}, nil
+}
+
+func (u *ProductGRPCServer) GetRecommendations(ctx context.Context, req *proto.GetRecommendationsRequest) (*proto.RecommendationList, error) {
+	return u.service.GetRecommendations(req)
+}
+
+func (u *ProductGRPCServer) AddProductImage(ctx context.Context, req *proto.ProductImage) (*proto.ProductImage, error) {
+	image, err := u.service.AddProductImage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
+
+func (u *ProductGRPCServer) UpdateProductImage(ctx context.Context, req *proto.ProductImage) (*proto.ProductImage, error) {
+	image, err := u.service.UpdateProductImage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
+
+func (u *ProductGRPCServer) DeleteProductImage(ctx context.Context, req *proto.GetProductImageRequest) (*proto.ProductImage, error) {
+	image, err := u.service.DeleteProductImage(int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
+
+func (u *ProductGRPCServer) ListProductImages(ctx context.Context, req *proto.GetProductRequest) (*proto.ProductImageList, error) {
+	images, err := u.service.ListProductImages(int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ProductImageList{Images: images}, nil
+}
+
+func (u *ProductGRPCServer) ReorderProductImages(ctx context.Context, req *proto.ReorderProductImagesRequest) (*proto.ProductImageList, error) {
+	images, err := u.service.ReorderProductImages(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ProductImageList{Images: images}, nil
+}
+
+// ImportProducts reads the import options from the first message and rows from the rest
+func (u *ProductGRPCServer) ImportProducts(stream grpc.ClientStreamingServer[proto.ImportProductsRequest, proto.ImportReport]) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	options := first.GetOptions()
+	if options == nil {
+		return errors.New("the first message of an import must carry its options")
+	}
+
+	report, err := u.service.ImportProducts(options, func() (*proto.ProductRow, error) {
+		req, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if req.GetRow() == nil {
+			return nil, errors.New("expected a product row")
+		}
+		return req.GetRow(), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(report)
+}
+
+func (u *ProductGRPCServer) ExportProducts(req *proto.Empty, stream grpc.ServerStreamingServer[proto.ProductRow]) error {
+	return u.service.ExportProducts(stream.Send)
+}
+
+func GRPCListen() {
+	DB, err := db.Connect()
+	if err != nil {
+		logrus.Fatalf("failed to connect to database: %v", err)
+	}
+
+	if err := db.InitRedis(); err != nil {
+		logrus.Warnf("Redis initialization failed: %v. Continuing without cache.", err)
+	}
+
+	ctx := context.Background()
+	repo := repository.NewProductRepositoryImpl()
+	categoryRepo := repository.NewCategoryRepositoryImpl()
+	tagRepo := repository.NewTagRepositoryImpl()
+	variantRepo := repository.NewVariantRepositoryImpl()
+	imageRepo := repository.NewImageRepositoryImpl()
+	inventoryRepo := repository.NewInventoryRepositoryImpl()
+	warehouseRepo := repository.NewWarehouseRepositoryImpl()
+	priceRepo := repository.NewPriceRepositoryImpl()
+	reviewRepo := repository.NewReviewRepositoryImpl()
+	wishlistRepo := repository.NewWishlistRepositoryImpl()
+	outboxRepo := repository.NewOutboxRepositoryImpl()
+	recommendationRepo := repository.NewRecommendationRepositoryImpl()
+	bundleRepo := repository.NewBundleRepositoryImpl()
+	priceRuleRepo := repository.NewPriceRuleRepositoryImpl()
+	reorderRepo := repository.NewReorderRepositoryImpl()
+	purchaseOrderRepo := repository.NewPurchaseOrderRepositoryImpl()
+	translationRepo := repository.NewTranslationRepositoryImpl()
+	digitalRepo := repository.NewDigitalRepositoryImpl()
+	orderRepo := repository.NewOrderRepositoryImpl()
+	service := service.NewProductService(repo, DB, ctx, categoryRepo, tagRepo, variantRepo, imageRepo, inventoryRepo, warehouseRepo, priceRepo, reviewRepo, wishlistRepo, outboxRepo, recommendationRepo, bundleRepo, priceRuleRepo, reorderRepo, purchaseOrderRepo, translationRepo, digitalRepo, orderRepo)
+	service.SetDefaultLocale(os.Getenv("DEFAULT_LOCALE"))
+	connection := NewProductGRPCServer(service)
+
+	go service.RunPriceScheduler()
+
+	// Recommendations are counted from the orders the order service reports paid
+	if topic := os.Getenv("KAFKA_ORDER_PAID_TOPIC"); topic != "" {
+		go kafka.ProcessOrderEvents([]string{os.Getenv("KAFKA_BROKER_URL")}, []string{topic}, os.Getenv("KAFKA_GROUP_ID"), service.CountPaidOrder)
+	} else {
+		logrus.Warn("KAFKA_ORDER_PAID_TOPIC is not set, recommendations will not count new orders")
+	}
+
+	// Restocks and product events stay queued in the database until Kafka is reachable
+	if err := kafka.ConnectProducer([]string{os.Getenv("KAFKA_BROKER_URL")}); err != nil {
+		logrus.Warnf("Kafka connection failed: %v. Back-in-stock and product events will not be published.", err)
+	} else {
+		go service.RunRestockPublisher(os.Getenv("KAFKA_BACK_IN_STOCK_TOPIC"))
+		go service.RunOutboxPublisher(os.Getenv("KAFKA_PRODUCT_TOPIC"))
+	}
+
+	lis, err := net.Listen("tcp", ":40001")
+	if err != nil {
+		logrus.Fatalf("Failed to listen for gRPC: %v", err)
+	}
+
+	srv := grpc.NewServer()
+	proto.RegisterProductServiceServer(srv, connection)
+	logrus.Infof("gRPC Server started on port 40001")
+
+	if err := srv.Serve(lis); err != nil {
+		logrus.Fatalf("error when connect to gRPC Server  %v", err)
+	}
+}
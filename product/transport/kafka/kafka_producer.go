@@ -0,0 +1,59 @@
+package kafka
+
+import (
+	"encoding/json"
+	"errors"
+	"product_service/types"
+	"strconv"
+
+	"github.com/IBM/sarama"
+	"github.com/sirupsen/logrus"
+)
+
+var producer sarama.SyncProducer
+
+func ConnectProducer(addr []string) error {
+	config := sarama.NewConfig()
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Retry.Max = 5
+	config.Producer.Return.Successes = true
+
+	p, err := sarama.NewSyncProducer(addr, config)
+	if err != nil {
+		return err
+	}
+	producer = p
+
+	logrus.Info("success connect producer")
+
+	return nil
+}
+
+// SendBackInStock publishes a back-in-stock event keyed by product, so the events of
+// one product stay in order
+func SendBackInStock(topic string, event *types.BackInStockEvent) (int32, int64, error) {
+	return send(topic, int(event.ProductID), event)
+}
+
+// SendProductEvent publishes a product change keyed by product, so consumers see the
+// changes of one product in the order they were made
+func SendProductEvent(topic string, event *types.ProductEvent) (int32, int64, error) {
+	return send(topic, int(event.ProductID), event)
+}
+
+func send(topic string, productID int, event interface{}) (int32, int64, error) {
+	if producer == nil {
+		return 0, 0, errors.New("kafka producer is not initialized")
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return producer.SendMessage(&sarama.ProducerMessage{
+		Topic: topic,
+		Key:   sarama.StringEncoder(strconv.Itoa(productID)),
+		Value: sarama.ByteEncoder(data),
+	})
+}
@@ -0,0 +1,101 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"product_service/proto"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/sirupsen/logrus"
+)
+
+// orderRetryDelay is how long a paid order that could not be counted waits before it
+// is tried again
+const orderRetryDelay = 5 * time.Second
+
+// OrderConsumerHandler hands each order event to handle; the service is not imported
+// here, as it publishes its own events through this package
+type OrderConsumerHandler struct {
+	handle func(order *proto.Order) error
+}
+
+func connectConsumer(addr []string, groupID string) (sarama.ConsumerGroup, error) {
+	config := sarama.NewConfig()
+	config.Consumer.Group.Rebalance.Strategy = sarama.NewBalanceStrategyRange()
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	config.Consumer.Return.Errors = true
+
+	var CGError error
+
+	for i := 0; i < 5; i++ {
+		worker, err := sarama.NewConsumerGroup(addr, groupID, config)
+		if err == nil {
+			logrus.Info("Connected to kafka")
+			return worker, nil
+		}
+
+		CGError = err
+		logrus.Warnf("failed connect to kafka, retrying...(%d/5)", i+1)
+		time.Sleep(5 * time.Second)
+	}
+
+	return nil, CGError
+}
+
+// ProcessOrderEvents consumes order.paid events for the life of the service. Without
+// Kafka the product service keeps serving; only recommendations stop learning.
+func ProcessOrderEvents(addr []string, topic []string, groupID string, handle func(order *proto.Order) error) {
+	consumerGroup, err := connectConsumer(addr, groupID)
+	if err != nil {
+		logrus.Errorf("error when connect to kafka, recommendations will not count new orders: %v", err)
+		return
+	}
+	defer consumerGroup.Close()
+
+	handler := &OrderConsumerHandler{handle: handle}
+
+	logrus.Infof("addr: %s topic: %s groupID: %s", addr, topic, groupID)
+	for {
+		if err := consumerGroup.Consume(context.Background(), topic, handler); err != nil {
+			logrus.Errorf("failed when consume partition, retrying: %v", err)
+			time.Sleep(2 * time.Second)
+		}
+	}
+}
+
+func (h *OrderConsumerHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
+func (h *OrderConsumerHandler) Cleanup(_ sarama.ConsumerGroupSession) error { return nil }
+
+func (h *OrderConsumerHandler) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for msg := range claim.Messages() {
+		order := new(proto.Order)
+		if err := json.Unmarshal(msg.Value, order); err != nil {
+			logrus.Errorf("Error parsing message: %s. Raw data: %s", err, string(msg.Value))
+			// A malformed event will never parse, so it is skipped for good
+			sess.MarkMessage(msg, "")
+			continue
+		}
+
+		// Marking a later message would commit past this one, so it is retried until
+		// counted; counting an order twice is a no-op
+		for {
+			err := h.handle(order)
+			if err == nil {
+				break
+			}
+			logrus.Errorf("Error counting paid order %d for recommendations, retrying: %v", order.Id, err)
+
+			select {
+			case <-sess.Context().Done():
+				// Rebalanced away; the next owner of the partition gets the event again
+				return nil
+			case <-time.After(orderRetryDelay):
+			}
+		}
+
+		sess.MarkMessage(msg, "")
+	}
+
+	return nil
+}
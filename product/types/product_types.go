@@ -1,22 +1,55 @@
-package types
-
-// import (
-// 	"time"
-// )
-
-// type Product struct {
-// 	ID          uint      `gorm:"primaryKey" json:"id"`
-// 	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
-// 	Description string    `gorm:"type:text" json:"description"`
-// 	Price       float64   `gorm:"not null" json:"price"`
-// 	Stock       int       `gorm:"not null" json:"stock"`
-// 	CreatedAt   time.Time `json:"created_at"`
-// 	UpdatedAt   time.Time `json:"updated_at"`
-// }
-
-// type ProductPayload struct {
-// 	Name        string  `json:"name" binding:"required"`
-// 	Description string  `json:"description"`
-// 	Price       float64 `json:"price" binding:"required,gt=0"`
-// 	Stock       int     `json:"stock" binding:"required,gte=0"`
-// }
+package types
+
+import "encoding/json"
+
+// ProductEventSchemaVersion is bumped whenever ProductEvent changes incompatibly
+const ProductEventSchemaVersion = 1
+
+// BackInStockEvent tells a subscriber that a product is back in stock. EventID is
+// unique per restock and user, so consumers can drop redelivered events.
+type BackInStockEvent struct {
+	EventID     string  `json:"event_id"`
+	RestockID   int32   `json:"restock_id"`
+	UserID      int32   `json:"user_id"`
+	ProductID   int32   `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Price       float64 `json:"price"`
+	Stock       int32   `json:"stock"`
+	RestockedAt string  `json:"restocked_at"`
+}
+
+// ProductEvent tells other services that a product changed. Product is the whole
+// product after the change, in the product proto's JSON form, and is null once the
+// product is purged. ProductVersion grows by one with every event of a product, so
+// consumers can drop redelivered and stale events.
+type ProductEvent struct {
+	EventID        string          `json:"event_id"`
+	Type           string          `json:"type"`
+	SchemaVersion  int             `json:"schema_version"`
+	ProductID      int32           `json:"product_id"`
+	ProductVersion int64           `json:"product_version"`
+	ChangedFields  []string        `json:"changed_fields"`
+	Product        json.RawMessage `json:"product"`
+	OccurredAt     string          `json:"occurred_at"`
+}
+
+// import (
+// 	"time"
+// )
+
+// type Product struct {
+// 	ID          uint      `gorm:"primaryKey" json:"id"`
+// 	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
+// 	Description string    `gorm:"type:text" json:"description"`
+// 	Price       float64   `gorm:"not null" json:"price"`
+// 	Stock       int       `gorm:"not null" json:"stock"`
+// 	CreatedAt   time.Time `json:"created_at"`
+// 	UpdatedAt   time.Time `json:"updated_at"`
+// }
+
+// type ProductPayload struct {
+// 	Name        string  `json:"name" binding:"required"`
+// 	Description string  `json:"description"`
+// 	Price       float64 `json:"price" binding:"required,gt=0"`
+// 	Stock       int     `json:"stock" binding:"required,gte=0"`
+// }
@@ -0,0 +1,4 @@
+-- Rollback: Remove unit price from order lines
+
+ALTER TABLE order_items
+    DROP COLUMN IF EXISTS price;
@@ -0,0 +1,15 @@
+-- Migration: Store the unit price on each order line so receipts and
+-- payment gateway item details reflect the price at checkout time
+
+-- Step 1: Add price column
+ALTER TABLE order_items
+    ADD COLUMN IF NOT EXISTS price DOUBLE PRECISION;
+
+-- Step 2: Backfill existing lines from the current product price
+UPDATE order_items oi
+SET price = p.price
+FROM products p
+WHERE oi.product_id = p.id AND oi.price IS NULL;
+
+-- Step 3: Make price NOT NULL after backfill
+ALTER TABLE order_items ALTER COLUMN price SET NOT NULL;
@@ -0,0 +1,18 @@
+-- Rollback: Remove payment intents
+
+-- Drop payment_refunds table
+DROP INDEX IF EXISTS idx_payment_refunds_order_id;
+DROP INDEX IF EXISTS idx_payment_refunds_payment_intent_id;
+DROP TABLE IF EXISTS payment_refunds;
+
+-- Unlink payments from intents
+DROP INDEX IF EXISTS idx_payments_payment_intent_id;
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS fk_payments_payment_intent_id;
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS payment_intent_id,
+    DROP COLUMN IF EXISTS refunded_amount;
+
+-- Drop payment_intents table
+DROP INDEX IF EXISTS idx_payment_intents_gateway_order_id;
+DROP INDEX IF EXISTS idx_payment_intents_user_id;
+DROP TABLE IF EXISTS payment_intents;
@@ -0,0 +1,53 @@
+-- Migration: Payment intents group several orders under one gateway transaction
+
+-- Step 1: Create payment_intents table
+CREATE TABLE IF NOT EXISTS payment_intents (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    amount DOUBLE PRECISION NOT NULL,
+    refunded_amount DOUBLE PRECISION NOT NULL DEFAULT 0,
+    currency VARCHAR(3) DEFAULT 'IDR',
+    payment_method VARCHAR(50),
+    payment_channel VARCHAR(50),
+    gateway_name VARCHAR(50) DEFAULT 'midtrans',
+    gateway_transaction_id VARCHAR(100),
+    gateway_order_id VARCHAR(100),
+    gateway_token VARCHAR(255),
+    gateway_redirect_url TEXT,
+    status VARCHAR(50) DEFAULT 'pending',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    paid_at TIMESTAMP,
+    expired_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_intents_user_id ON payment_intents(user_id);
+CREATE INDEX IF NOT EXISTS idx_payment_intents_gateway_order_id ON payment_intents(gateway_order_id);
+
+-- Step 2: Link per-order payments to the intent that paid them
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS payment_intent_id INTEGER,
+    ADD COLUMN IF NOT EXISTS refunded_amount DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+ALTER TABLE payments
+    ADD CONSTRAINT fk_payments_payment_intent_id FOREIGN KEY (payment_intent_id) REFERENCES payment_intents(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_payments_payment_intent_id ON payments(payment_intent_id);
+
+-- Step 3: Refunds are allocated to a single order within an intent
+CREATE TABLE IF NOT EXISTS payment_refunds (
+    id SERIAL PRIMARY KEY,
+    payment_intent_id INTEGER NOT NULL,
+    payment_id INTEGER NOT NULL,
+    order_id INTEGER NOT NULL,
+    amount DOUBLE PRECISION NOT NULL,
+    reason TEXT,
+    refund_key VARCHAR(100) NOT NULL,
+    status VARCHAR(50) DEFAULT 'pending',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_payment_refunds_payment_intent_id FOREIGN KEY (payment_intent_id) REFERENCES payment_intents(id) ON DELETE CASCADE,
+    CONSTRAINT fk_payment_refunds_payment_id FOREIGN KEY (payment_id) REFERENCES payments(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_refunds_payment_intent_id ON payment_refunds(payment_intent_id);
+CREATE INDEX IF NOT EXISTS idx_payment_refunds_order_id ON payment_refunds(order_id);
@@ -0,0 +1,5 @@
+-- Rollback: Drop saved payment methods
+
+DROP INDEX IF EXISTS idx_saved_payment_methods_user_default;
+DROP INDEX IF EXISTS idx_saved_payment_methods_user_id;
+DROP TABLE IF EXISTS saved_payment_methods;
@@ -0,0 +1,23 @@
+-- Migration: Saved cards hold gateway-issued tokens only, never raw card numbers
+
+-- Step 1: Create saved_payment_methods table
+CREATE TABLE IF NOT EXISTS saved_payment_methods (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    gateway_name VARCHAR(50) NOT NULL DEFAULT 'midtrans',
+    gateway_token VARCHAR(255) NOT NULL,
+    masked_pan VARCHAR(32) NOT NULL,
+    brand VARCHAR(32) NOT NULL,
+    expiry_month INTEGER NOT NULL,
+    expiry_year INTEGER NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_saved_payment_methods_user_token UNIQUE (user_id, gateway_token)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_payment_methods_user_id ON saved_payment_methods(user_id);
+
+-- Step 2: At most one default card per user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_saved_payment_methods_user_default
+    ON saved_payment_methods(user_id) WHERE is_default;
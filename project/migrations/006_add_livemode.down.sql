@@ -0,0 +1,12 @@
+-- Rollback: Remove test-mode flags and the test stock pool
+
+ALTER TABLE products DROP COLUMN IF EXISTS test_stock;
+
+DROP INDEX IF EXISTS idx_orders_user_id_livemode;
+DROP INDEX IF EXISTS idx_saved_payment_methods_user_default;
+ALTER TABLE saved_payment_methods DROP COLUMN IF EXISTS livemode;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_saved_payment_methods_user_default
+    ON saved_payment_methods(user_id) WHERE is_default;
+ALTER TABLE payment_intents DROP COLUMN IF EXISTS livemode;
+ALTER TABLE payments DROP COLUMN IF EXISTS livemode;
+ALTER TABLE orders DROP COLUMN IF EXISTS livemode;
@@ -0,0 +1,27 @@
+-- Migration: Test-mode credentials work on sandbox data kept apart from live records
+
+-- Step 1: Flag orders and payment records with the mode they were created in
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS livemode BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS livemode BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE payment_intents
+    ADD COLUMN IF NOT EXISTS livemode BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE saved_payment_methods
+    ADD COLUMN IF NOT EXISTS livemode BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE INDEX IF NOT EXISTS idx_orders_user_id_livemode ON orders(user_id, livemode);
+
+-- Step 2: Test-mode orders draw from a separate stock pool, seeded from live stock
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS test_stock INTEGER NOT NULL DEFAULT 0;
+
+UPDATE products SET test_stock = stock;
+
+-- Step 3: Saved cards keep one default per mode
+DROP INDEX IF EXISTS idx_saved_payment_methods_user_default;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_saved_payment_methods_user_default
+    ON saved_payment_methods(user_id, livemode) WHERE is_default;
@@ -0,0 +1,13 @@
+-- Rollback: Remove wallets
+
+ALTER TABLE payments DROP COLUMN IF EXISTS wallet_amount;
+
+DROP INDEX IF EXISTS idx_wallet_topups_gateway_order_id;
+DROP TABLE IF EXISTS wallet_topups;
+
+DROP TRIGGER IF EXISTS trg_wallet_transactions_append_only ON wallet_transactions;
+DROP FUNCTION IF EXISTS wallet_transactions_append_only();
+DROP INDEX IF EXISTS idx_wallet_transactions_wallet_id;
+DROP TABLE IF EXISTS wallet_transactions;
+
+DROP TABLE IF EXISTS wallets;
@@ -0,0 +1,71 @@
+-- Migration: Store credit wallets with an append-only transaction log
+
+-- Step 1: One wallet per user and mode
+CREATE TABLE IF NOT EXISTS wallets (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    livemode BOOLEAN NOT NULL DEFAULT TRUE,
+    balance DOUBLE PRECISION NOT NULL DEFAULT 0,
+    currency VARCHAR(3) DEFAULT 'IDR',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_wallets_user_livemode UNIQUE (user_id, livemode),
+    CONSTRAINT chk_wallets_balance CHECK (balance >= 0)
+);
+
+-- Step 2: Every balance change is logged; rows are never updated or deleted
+CREATE TABLE IF NOT EXISTS wallet_transactions (
+    id SERIAL PRIMARY KEY,
+    wallet_id INTEGER NOT NULL,
+    livemode BOOLEAN NOT NULL DEFAULT TRUE,
+    type VARCHAR(50) NOT NULL,             -- topup, payment, payment_reversal, refund_credit
+    amount DOUBLE PRECISION NOT NULL,      -- positive credits, negative debits
+    balance_after DOUBLE PRECISION NOT NULL,
+    reference_type VARCHAR(50),            -- wallet_topup, payment, payment_refund
+    reference_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_wallet_transactions_wallet_id FOREIGN KEY (wallet_id) REFERENCES wallets(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_wallet_transactions_wallet_id ON wallet_transactions(wallet_id);
+
+-- Only test-mode rows may be deleted, so purging sandbox data keeps working
+CREATE OR REPLACE FUNCTION wallet_transactions_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' AND NOT OLD.livemode THEN
+        RETURN OLD;
+    END IF;
+    RAISE EXCEPTION 'wallet_transactions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_wallet_transactions_append_only
+    BEFORE UPDATE OR DELETE ON wallet_transactions
+    FOR EACH ROW EXECUTE FUNCTION wallet_transactions_append_only();
+
+-- Step 3: Top-ups are paid through the gateway before the wallet is credited
+CREATE TABLE IF NOT EXISTS wallet_topups (
+    id SERIAL PRIMARY KEY,
+    wallet_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    livemode BOOLEAN NOT NULL DEFAULT TRUE,
+    amount DOUBLE PRECISION NOT NULL,
+    gateway_transaction_id VARCHAR(100),
+    gateway_order_id VARCHAR(100),
+    gateway_token VARCHAR(255),
+    gateway_redirect_url TEXT,
+    status VARCHAR(50) DEFAULT 'pending',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    paid_at TIMESTAMP,
+    expired_at TIMESTAMP,
+
+    CONSTRAINT fk_wallet_topups_wallet_id FOREIGN KEY (wallet_id) REFERENCES wallets(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_wallet_topups_gateway_order_id ON wallet_topups(gateway_order_id);
+
+-- Step 4: Part of a payment may be covered from the wallet
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS wallet_amount DOUBLE PRECISION NOT NULL DEFAULT 0;
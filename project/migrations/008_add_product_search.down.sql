@@ -0,0 +1,6 @@
+-- Rollback: Remove full-text product search
+
+DROP INDEX IF EXISTS idx_products_created_at;
+DROP INDEX IF EXISTS idx_products_name;
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
+CREATE INDEX IF NOT EXISTS idx_products_name ON products(name);
@@ -0,0 +1,17 @@
+-- Migration: Full-text product search
+
+-- Step 1: Searchable document built from the name (weighted higher) and description.
+-- The 'simple' configuration avoids stemming, since names mix Indonesian and English.
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', COALESCE(name, '')), 'A') ||
+        setweight(to_tsvector('simple', COALESCE(description, '')), 'B')
+    ) STORED;
+
+-- Step 2: Upgrade the name index to a GIN index over the search document
+DROP INDEX IF EXISTS idx_products_name;
+CREATE INDEX IF NOT EXISTS idx_products_name ON products USING GIN(search_vector);
+
+-- Step 3: Support sorting by newest
+CREATE INDEX IF NOT EXISTS idx_products_created_at ON products(created_at);
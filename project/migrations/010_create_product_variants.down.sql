@@ -0,0 +1,8 @@
+-- Rollback: Remove product options and variants
+
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS fk_order_items_variant_id;
+DROP INDEX IF EXISTS idx_order_items_variant_id;
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_id;
+
+DROP TABLE IF EXISTS product_variants;
+DROP TABLE IF EXISTS product_options;
@@ -0,0 +1,58 @@
+-- Migration: Option types and per-SKU variants for products, with order lines
+-- pointing at the variant that was bought
+
+-- Step 1: Option types such as size or colour, with their allowed values
+CREATE TABLE IF NOT EXISTS product_options (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    "values" TEXT[] NOT NULL DEFAULT '{}',
+    position INTEGER NOT NULL DEFAULT 0,
+
+    CONSTRAINT uq_product_options_name UNIQUE (product_id, name),
+    CONSTRAINT fk_product_options_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+-- Step 2: Variants carry their own SKU, stock and an optional price override.
+-- products.stock and products.test_stock are kept as the sum over variants.
+CREATE TABLE IF NOT EXISTS product_variants (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL,
+    sku VARCHAR(64) NOT NULL,
+    price DOUBLE PRECISION,
+    stock INTEGER NOT NULL DEFAULT 0,
+    test_stock INTEGER NOT NULL DEFAULT 0,
+    attributes JSONB NOT NULL DEFAULT '{}',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_product_variants_sku UNIQUE (sku),
+    CONSTRAINT chk_product_variants_stock CHECK (stock >= 0 AND test_stock >= 0),
+    CONSTRAINT chk_product_variants_price CHECK (price IS NULL OR price >= 0),
+    CONSTRAINT fk_product_variants_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_variants_product_id ON product_variants(product_id);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_product_variants_attributes ON product_variants(product_id, attributes);
+
+-- Step 3: Every existing product becomes a single default variant
+INSERT INTO product_variants (product_id, sku, stock, test_stock, is_default)
+SELECT p.id, 'SKU-' || p.id, p.stock, p.test_stock, TRUE
+FROM products p
+WHERE NOT EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = p.id);
+
+-- Step 4: Order lines reference the variant, backfilled to the default variant
+ALTER TABLE order_items
+    ADD COLUMN IF NOT EXISTS variant_id INTEGER;
+
+UPDATE order_items oi
+SET variant_id = v.id
+FROM product_variants v
+WHERE v.product_id = oi.product_id AND v.is_default AND oi.variant_id IS NULL;
+
+ALTER TABLE order_items
+    ADD CONSTRAINT fk_order_items_variant_id FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_order_items_variant_id ON order_items(variant_id);
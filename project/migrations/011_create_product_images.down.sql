@@ -0,0 +1,3 @@
+-- Rollback: Remove product images
+
+DROP TABLE IF EXISTS product_images;
@@ -0,0 +1,23 @@
+-- Migration: Product images. The files live in the blob store; rows keep the
+-- storage keys, ordering and alt text.
+
+-- Step 1: Create product_images table
+CREATE TABLE IF NOT EXISTS product_images (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    thumbnail_key VARCHAR(255) NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    width INTEGER NOT NULL DEFAULT 0,
+    height INTEGER NOT NULL DEFAULT 0,
+    alt_text VARCHAR(255) NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_product_images_storage_key UNIQUE (storage_key),
+    CONSTRAINT fk_product_images_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+-- Step 2: Images are always read per product in display order
+CREATE INDEX IF NOT EXISTS idx_product_images_product_id ON product_images(product_id, position);
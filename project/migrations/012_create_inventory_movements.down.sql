@@ -0,0 +1,3 @@
+-- Rollback: Remove the inventory ledger
+
+DROP TABLE IF EXISTS inventory_movements;
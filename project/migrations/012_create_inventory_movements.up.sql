@@ -0,0 +1,39 @@
+-- Migration: Inventory ledger. Every stock change of a variant is recorded with
+-- its reason, what caused it and who made it.
+
+-- Step 1: Create inventory_movements table
+CREATE TABLE IF NOT EXISTS inventory_movements (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL,
+    variant_id INTEGER,
+    sku VARCHAR(64) NOT NULL,
+    delta INTEGER NOT NULL,
+    stock_after INTEGER NOT NULL,
+    livemode BOOLEAN NOT NULL DEFAULT TRUE,
+    reason VARCHAR(30) NOT NULL,
+    reference_type VARCHAR(30) NOT NULL DEFAULT '',
+    reference_id VARCHAR(64) NOT NULL DEFAULT '',
+    actor VARCHAR(100) NOT NULL DEFAULT '',
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_inventory_movements_reason CHECK (reason IN ('sale', 'restock', 'reservation_release', 'manual_adjustment', 'return')),
+    CONSTRAINT chk_inventory_movements_delta CHECK (delta <> 0),
+    CONSTRAINT fk_inventory_movements_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    -- The SKU is kept on the row so history survives deleting the variant
+    CONSTRAINT fk_inventory_movements_variant_id FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE SET NULL
+);
+
+-- Step 2: History is read per product, newest first
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_product_id ON inventory_movements(product_id, livemode, created_at DESC);
+
+-- Step 3: Record the current stock as an opening balance so the ledger adds up
+INSERT INTO inventory_movements (product_id, variant_id, sku, delta, stock_after, livemode, reason, actor, note)
+SELECT product_id, id, sku, stock, stock, TRUE, 'manual_adjustment', 'migration', 'Opening balance'
+FROM product_variants
+WHERE stock > 0;
+
+INSERT INTO inventory_movements (product_id, variant_id, sku, delta, stock_after, livemode, reason, actor, note)
+SELECT product_id, id, sku, test_stock, test_stock, FALSE, 'manual_adjustment', 'migration', 'Opening balance'
+FROM product_variants
+WHERE test_stock > 0;
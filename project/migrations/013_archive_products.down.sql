@@ -0,0 +1,8 @@
+-- Rollback: Remove product archival
+
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS fk_order_items_product_id;
+ALTER TABLE order_items
+    ADD CONSTRAINT fk_order_items_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS idx_products_active;
+ALTER TABLE products DROP COLUMN IF EXISTS archived_at;
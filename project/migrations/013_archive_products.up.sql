@@ -0,0 +1,13 @@
+-- Migration: Archive products instead of deleting them. Order lines keep pointing
+-- at their product, so a product can no longer be deleted while orders use it.
+
+-- Step 1: Add archived_at to products
+ALTER TABLE products ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+
+-- Step 2: Listings and search only read active products
+CREATE INDEX IF NOT EXISTS idx_products_active ON products(id) WHERE archived_at IS NULL;
+
+-- Step 3: Stop deletes of products from cascading into order history
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS fk_order_items_product_id;
+ALTER TABLE order_items
+    ADD CONSTRAINT fk_order_items_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE RESTRICT;
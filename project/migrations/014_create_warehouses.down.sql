@@ -0,0 +1,15 @@
+-- Rollback: Remove multi-warehouse inventory. Variant stock already holds the
+-- totals over all warehouses, so nothing has to be copied back.
+
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS fk_order_items_warehouse_id;
+ALTER TABLE order_items DROP COLUMN IF EXISTS warehouse_id;
+
+DELETE FROM inventory_movements WHERE reason = 'transfer';
+ALTER TABLE inventory_movements DROP CONSTRAINT IF EXISTS chk_inventory_movements_reason;
+ALTER TABLE inventory_movements
+    ADD CONSTRAINT chk_inventory_movements_reason CHECK (reason IN ('sale', 'restock', 'reservation_release', 'manual_adjustment', 'return'));
+ALTER TABLE inventory_movements DROP CONSTRAINT IF EXISTS fk_inventory_movements_warehouse_id;
+ALTER TABLE inventory_movements DROP COLUMN IF EXISTS warehouse_id;
+
+DROP TABLE IF EXISTS variant_stock_levels;
+DROP TABLE IF EXISTS warehouses;
@@ -0,0 +1,61 @@
+-- Migration: Multi-warehouse inventory. Stock is held per variant and warehouse;
+-- the stock of a variant and of a product become totals over the warehouses.
+
+-- Step 1: Create warehouses table
+CREATE TABLE IF NOT EXISTS warehouses (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    -- Lower ships first when allocating by priority
+    priority INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Step 2: Create the warehouse every existing stock level moves into
+INSERT INTO warehouses (code, name, priority)
+VALUES ('MAIN', 'Main warehouse', 0)
+ON CONFLICT (code) DO NOTHING;
+
+-- Step 3: Create variant_stock_levels table
+CREATE TABLE IF NOT EXISTS variant_stock_levels (
+    variant_id INTEGER NOT NULL,
+    warehouse_id INTEGER NOT NULL,
+    stock INTEGER NOT NULL DEFAULT 0,
+    test_stock INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (variant_id, warehouse_id),
+    CONSTRAINT chk_variant_stock_levels_stock CHECK (stock >= 0 AND test_stock >= 0),
+    CONSTRAINT fk_variant_stock_levels_variant_id FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_variant_stock_levels_warehouse_id FOREIGN KEY (warehouse_id) REFERENCES warehouses(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_variant_stock_levels_warehouse_id ON variant_stock_levels(warehouse_id);
+
+-- Step 4: Move the current stock into the main warehouse
+INSERT INTO variant_stock_levels (variant_id, warehouse_id, stock, test_stock)
+SELECT v.id, w.id, v.stock, v.test_stock
+FROM product_variants v
+CROSS JOIN warehouses w
+WHERE w.code = 'MAIN'
+ON CONFLICT (variant_id, warehouse_id) DO NOTHING;
+
+-- Step 5: Record the warehouse of every movement; transfers move stock between two
+ALTER TABLE inventory_movements ADD COLUMN IF NOT EXISTS warehouse_id INTEGER;
+ALTER TABLE inventory_movements
+    ADD CONSTRAINT fk_inventory_movements_warehouse_id FOREIGN KEY (warehouse_id) REFERENCES warehouses(id) ON DELETE SET NULL;
+UPDATE inventory_movements SET warehouse_id = (SELECT id FROM warehouses WHERE code = 'MAIN') WHERE warehouse_id IS NULL;
+
+ALTER TABLE inventory_movements DROP CONSTRAINT IF EXISTS chk_inventory_movements_reason;
+ALTER TABLE inventory_movements
+    ADD CONSTRAINT chk_inventory_movements_reason CHECK (reason IN ('sale', 'restock', 'reservation_release', 'manual_adjustment', 'return', 'transfer'));
+
+-- Step 6: Record the warehouse every order line ships from
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS warehouse_id INTEGER;
+ALTER TABLE order_items
+    ADD CONSTRAINT fk_order_items_warehouse_id FOREIGN KEY (warehouse_id) REFERENCES warehouses(id) ON DELETE RESTRICT;
+UPDATE order_items SET warehouse_id = (SELECT id FROM warehouses WHERE code = 'MAIN') WHERE warehouse_id IS NULL;
@@ -0,0 +1,3 @@
+-- Rollback: Remove price history and scheduled prices
+
+DROP TABLE IF EXISTS product_prices;
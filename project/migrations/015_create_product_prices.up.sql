@@ -0,0 +1,31 @@
+-- Migration: Price history and scheduled prices. List prices are kept with the
+-- period they applied in; a scheduled price overrides the list price while active.
+
+-- Step 1: Create product_prices table
+CREATE TABLE IF NOT EXISTS product_prices (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    price DOUBLE PRECISION NOT NULL,
+    effective_from TIMESTAMP NOT NULL,
+    -- NULL while the price applies indefinitely
+    effective_to TIMESTAMP,
+    note VARCHAR(200) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_product_prices_kind CHECK (kind IN ('list', 'scheduled')),
+    CONSTRAINT chk_product_prices_price CHECK (price >= 0),
+    CONSTRAINT chk_product_prices_period CHECK (effective_to IS NULL OR effective_to > effective_from),
+    CONSTRAINT fk_product_prices_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+-- Step 2: Prices are resolved per product by period
+CREATE INDEX IF NOT EXISTS idx_product_prices_product_id ON product_prices(product_id, kind, effective_from DESC);
+
+-- Step 3: A product has one open list price
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_prices_open_list ON product_prices(product_id) WHERE kind = 'list' AND effective_to IS NULL;
+
+-- Step 4: The current price of every product has applied since it was created
+INSERT INTO product_prices (product_id, kind, price, effective_from, note)
+SELECT id, 'list', price, COALESCE(created_at, CURRENT_TIMESTAMP), 'Opening price'
+FROM products;
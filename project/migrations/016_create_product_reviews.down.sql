@@ -0,0 +1,7 @@
+-- Rollback: Remove product reviews
+
+ALTER TABLE products DROP COLUMN IF EXISTS rating_count;
+ALTER TABLE products DROP COLUMN IF EXISTS rating_average;
+
+DROP TABLE IF EXISTS product_review_images;
+DROP TABLE IF EXISTS product_reviews;
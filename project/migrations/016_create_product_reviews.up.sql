@@ -0,0 +1,47 @@
+-- Migration: Product reviews. Only buyers with a paid or fulfilled order of the
+-- product may review it; reviews are published once a moderator approves them,
+-- and products keep the rating of their approved reviews.
+
+-- Step 1: Create product_reviews table, one review per buyer and product
+CREATE TABLE IF NOT EXISTS product_reviews (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    rating SMALLINT NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    moderation_note VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_product_reviews_product_user UNIQUE (product_id, user_id),
+    CONSTRAINT chk_product_reviews_rating CHECK (rating BETWEEN 1 AND 5),
+    CONSTRAINT chk_product_reviews_status CHECK (status IN ('pending', 'approved', 'rejected')),
+    CONSTRAINT fk_product_reviews_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+-- Step 2: Reviews are listed per product and status, newest first
+CREATE INDEX IF NOT EXISTS idx_product_reviews_product_status ON product_reviews(product_id, status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_product_reviews_status ON product_reviews(status, created_at);
+
+-- Step 3: Create product_review_images table; files live in the blob store
+CREATE TABLE IF NOT EXISTS product_review_images (
+    id SERIAL PRIMARY KEY,
+    review_id INTEGER NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    thumbnail_key VARCHAR(255) NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    width INTEGER NOT NULL DEFAULT 0,
+    height INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_product_review_images_storage_key UNIQUE (storage_key),
+    CONSTRAINT fk_product_review_images_review_id FOREIGN KEY (review_id) REFERENCES product_reviews(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_review_images_review_id ON product_review_images(review_id);
+
+-- Step 4: Aggregate rating of the approved reviews
+ALTER TABLE products ADD COLUMN IF NOT EXISTS rating_average NUMERIC(3,2) NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS rating_count INTEGER NOT NULL DEFAULT 0;
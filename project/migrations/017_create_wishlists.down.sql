@@ -0,0 +1,5 @@
+-- Rollback: Remove wishlists and back-in-stock subscriptions
+
+DROP TABLE IF EXISTS product_restocks;
+DROP TABLE IF EXISTS stock_subscriptions;
+DROP TABLE IF EXISTS wishlist_items;
@@ -0,0 +1,42 @@
+-- Migration: Wishlists and back-in-stock subscriptions. Every time a product's live
+-- stock goes from zero to positive a restock is recorded; a publisher sends one
+-- event per subscriber to Kafka and marks the restock published.
+
+-- Step 1: Create wishlist_items table
+CREATE TABLE IF NOT EXISTS wishlist_items (
+    user_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (user_id, product_id),
+    CONSTRAINT fk_wishlist_items_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+-- Step 2: Create stock_subscriptions table; last_notified_restock_id keeps a
+-- subscriber from being notified twice for the same restock
+CREATE TABLE IF NOT EXISTS stock_subscriptions (
+    user_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    last_notified_restock_id INTEGER,
+    last_notified_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (user_id, product_id),
+    CONSTRAINT fk_stock_subscriptions_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_subscriptions_product_id ON stock_subscriptions(product_id);
+
+-- Step 3: Create product_restocks table
+CREATE TABLE IF NOT EXISTS product_restocks (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL,
+    stock INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMP,
+
+    CONSTRAINT fk_product_restocks_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+-- Step 4: The publisher only reads restocks it has not sent yet
+CREATE INDEX IF NOT EXISTS idx_product_restocks_unpublished ON product_restocks(id) WHERE published_at IS NULL;
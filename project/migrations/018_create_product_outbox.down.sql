@@ -0,0 +1,4 @@
+-- Rollback: Remove product change events
+
+DROP TABLE IF EXISTS product_outbox;
+ALTER TABLE products DROP COLUMN IF EXISTS version;
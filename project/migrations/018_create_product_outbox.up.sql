@@ -0,0 +1,26 @@
+-- Migration: Product change events. Every product mutation bumps the product's
+-- version and writes an event with the product's after-image to product_outbox in
+-- the same transaction; a publisher sends the events to Kafka in order.
+
+-- Step 1: Add a version to products, bumped by every recorded change. Existing
+-- products start at 1 so only products created from now on are announced as new.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE products ALTER COLUMN version SET DEFAULT 0;
+
+-- Step 2: Create product_outbox table; product_id has no foreign key so the event
+-- of a purged product outlives it
+CREATE TABLE IF NOT EXISTS product_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL,
+    product_version BIGINT NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    changed_fields TEXT[] NOT NULL DEFAULT '{}',
+    product JSONB,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMP,
+
+    CONSTRAINT uq_product_outbox_version UNIQUE (product_id, product_version)
+);
+
+-- Step 3: The publisher only reads events it has not sent yet
+CREATE INDEX IF NOT EXISTS idx_product_outbox_unpublished ON product_outbox(id) WHERE published_at IS NULL;
@@ -0,0 +1,5 @@
+-- Rollback: Remove product recommendations
+
+DROP TABLE IF EXISTS recommendation_orders;
+DROP TABLE IF EXISTS product_popularity;
+DROP TABLE IF EXISTS product_copurchases;
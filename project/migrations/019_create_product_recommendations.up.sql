@@ -0,0 +1,36 @@
+-- Migration: "Frequently bought together" recommendations. A job counts the
+-- products bought together in paid live orders, each order once, and how many
+-- such orders every product is in for the popularity fallback.
+
+-- Step 1: Create product_copurchases table; each pair is stored both ways so a
+-- product's recommendations are read from one index
+CREATE TABLE IF NOT EXISTS product_copurchases (
+    product_id INTEGER NOT NULL,
+    related_product_id INTEGER NOT NULL,
+    order_count INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (product_id, related_product_id),
+    CONSTRAINT fk_product_copurchases_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_product_copurchases_related_product_id FOREIGN KEY (related_product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_copurchases_ranking ON product_copurchases(product_id, order_count DESC);
+
+-- Step 2: Create product_popularity table
+CREATE TABLE IF NOT EXISTS product_popularity (
+    product_id INTEGER PRIMARY KEY,
+    order_count INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_product_popularity_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_popularity_order_count ON product_popularity(order_count DESC);
+
+-- Step 3: Create recommendation_orders table; the orders already counted, so each
+-- refresh only reads orders paid since the last one
+CREATE TABLE IF NOT EXISTS recommendation_orders (
+    order_id INTEGER PRIMARY KEY,
+    counted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
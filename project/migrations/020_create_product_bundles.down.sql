@@ -0,0 +1,15 @@
+-- Rollback: Remove product bundles
+
+DROP INDEX IF EXISTS idx_order_items_parent_item_id;
+
+ALTER TABLE order_items
+    DROP CONSTRAINT IF EXISTS chk_order_items_returned_quantity,
+    DROP CONSTRAINT IF EXISTS fk_order_items_parent_item_id,
+    DROP COLUMN IF EXISTS returned_quantity,
+    DROP COLUMN IF EXISTS parent_item_id;
+
+DROP TABLE IF EXISTS bundle_components;
+
+ALTER TABLE products
+    DROP CONSTRAINT IF EXISTS chk_products_kind,
+    DROP COLUMN IF EXISTS kind;
@@ -0,0 +1,40 @@
+-- Migration: Product bundles. A bundle is sold as one product but holds no stock of
+-- its own; its availability is computed from the variants it is made of, which are
+-- taken from their warehouses when a bundle is ordered. Order items of a bundle
+-- list its components as child items, which can be returned on their own.
+
+-- Step 1: Add kind to products
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS kind VARCHAR(20) NOT NULL DEFAULT 'standard';
+
+ALTER TABLE products
+    ADD CONSTRAINT chk_products_kind CHECK (kind IN ('standard', 'bundle'));
+
+-- Step 2: Create bundle_components table; a component variant cannot be deleted
+-- while a bundle is made of it
+CREATE TABLE IF NOT EXISTS bundle_components (
+    bundle_product_id INTEGER NOT NULL,
+    variant_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (bundle_product_id, variant_id),
+    CONSTRAINT chk_bundle_components_quantity CHECK (quantity > 0),
+    CONSTRAINT fk_bundle_components_bundle_product_id FOREIGN KEY (bundle_product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_bundle_components_variant_id FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_bundle_components_variant_id ON bundle_components(variant_id);
+
+-- Step 3: Link the component items of an order to their bundle item and count
+-- what was returned of every item
+ALTER TABLE order_items
+    ADD COLUMN IF NOT EXISTS parent_item_id INTEGER,
+    ADD COLUMN IF NOT EXISTS returned_quantity INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE order_items
+    ADD CONSTRAINT fk_order_items_parent_item_id FOREIGN KEY (parent_item_id) REFERENCES order_items(id) ON DELETE CASCADE,
+    ADD CONSTRAINT chk_order_items_returned_quantity CHECK (returned_quantity >= 0 AND returned_quantity <= quantity);
+
+CREATE INDEX IF NOT EXISTS idx_order_items_parent_item_id ON order_items(parent_item_id);
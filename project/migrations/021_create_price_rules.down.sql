@@ -0,0 +1,8 @@
+-- Rollback: Remove customer groups and price rules
+
+ALTER TABLE order_items
+    DROP COLUMN IF EXISTS price_rule_id;
+
+DROP TABLE IF EXISTS price_rules;
+DROP TABLE IF EXISTS customer_group_members;
+DROP TABLE IF EXISTS customer_groups;
@@ -0,0 +1,52 @@
+-- Migration: Customer groups and price rules. Users can belong to customer
+-- groups, and price rules lower a product's price from a minimum quantity for one
+-- group or for every customer. Order items record the rule that priced them.
+
+-- Step 1: Create customer_groups table
+CREATE TABLE IF NOT EXISTS customer_groups (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Step 2: Create customer_group_members table; like orders.user_id, user_id has
+-- no foreign key since the user service creates the users table
+CREATE TABLE IF NOT EXISTS customer_group_members (
+    group_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (group_id, user_id),
+    CONSTRAINT fk_customer_group_members_group_id FOREIGN KEY (group_id) REFERENCES customer_groups(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_group_members_user_id ON customer_group_members(user_id);
+
+-- Step 3: Create price_rules table; a rule either sets the unit price or takes a
+-- percentage off the price the product sells for
+CREATE TABLE IF NOT EXISTS price_rules (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL,
+    customer_group_id INTEGER,
+    min_quantity INTEGER NOT NULL DEFAULT 1,
+    price DOUBLE PRECISION,
+    discount_percent DOUBLE PRECISION,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_price_rules_min_quantity CHECK (min_quantity >= 1),
+    CONSTRAINT chk_price_rules_amount CHECK (
+        (price IS NOT NULL AND price >= 0 AND discount_percent IS NULL)
+        OR (price IS NULL AND discount_percent > 0 AND discount_percent <= 100)
+    ),
+    CONSTRAINT fk_price_rules_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_price_rules_customer_group_id FOREIGN KEY (customer_group_id) REFERENCES customer_groups(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_rules_product_id ON price_rules(product_id, min_quantity);
+
+-- Step 4: Record the rule that priced each order item. There is no foreign key,
+-- so order history keeps the rule's ID after the rule is deleted.
+ALTER TABLE order_items
+    ADD COLUMN IF NOT EXISTS price_rule_id INTEGER;
@@ -0,0 +1,7 @@
+-- Rollback: Remove reorder thresholds
+
+DROP INDEX IF EXISTS idx_orders_created_at;
+
+ALTER TABLE products
+    DROP CONSTRAINT IF EXISTS chk_products_reorder_threshold,
+    DROP COLUMN IF EXISTS reorder_threshold;
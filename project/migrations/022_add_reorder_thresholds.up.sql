@@ -0,0 +1,11 @@
+-- Migration: Low-stock alerts and reorder suggestions. A product's reorder
+-- threshold is the live stock at or below which it is announced as low on stock;
+-- the reorder report reads sales velocity from recent paid orders.
+
+-- Step 1: Add reorder_threshold to products; 0 turns low-stock alerts off
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS reorder_threshold INTEGER NOT NULL DEFAULT 0,
+    ADD CONSTRAINT chk_products_reorder_threshold CHECK (reorder_threshold >= 0);
+
+-- Step 2: Index orders by creation time for the report's rolling window
+CREATE INDEX IF NOT EXISTS idx_orders_created_at ON orders(created_at);
@@ -0,0 +1,62 @@
+-- Migration: Suppliers and purchase orders. A purchase order is drafted with lines,
+-- sent to its supplier and received into one warehouse, fully or in parts; every
+-- receipt is a restock in the inventory ledger.
+
+-- Step 1: Create suppliers table
+CREATE TABLE IF NOT EXISTS suppliers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    phone VARCHAR(30) NOT NULL DEFAULT '',
+    lead_time_days INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_suppliers_lead_time_days CHECK (lead_time_days >= 0)
+);
+
+-- Step 2: Create purchase_orders table; freight and duty are what the order costs
+-- on top of its lines, which together make its landed cost
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id SERIAL PRIMARY KEY,
+    supplier_id INTEGER NOT NULL,
+    warehouse_id INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    expected_at TIMESTAMP,
+    freight_cost DOUBLE PRECISION NOT NULL DEFAULT 0,
+    duty_cost DOUBLE PRECISION NOT NULL DEFAULT 0,
+    note TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(100) NOT NULL DEFAULT '',
+    sent_at TIMESTAMP,
+    received_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_purchase_orders_status CHECK (status IN ('draft', 'sent', 'partially_received', 'received', 'cancelled')),
+    CONSTRAINT chk_purchase_orders_costs CHECK (freight_cost >= 0 AND duty_cost >= 0),
+    CONSTRAINT fk_purchase_orders_supplier_id FOREIGN KEY (supplier_id) REFERENCES suppliers(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_purchase_orders_warehouse_id FOREIGN KEY (warehouse_id) REFERENCES warehouses(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_status ON purchase_orders(status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_supplier_id ON purchase_orders(supplier_id);
+
+-- Step 3: Create purchase_order_lines table; like order items, a line keeps its SKU
+-- when its variant is deleted
+CREATE TABLE IF NOT EXISTS purchase_order_lines (
+    id SERIAL PRIMARY KEY,
+    purchase_order_id INTEGER NOT NULL,
+    variant_id INTEGER,
+    sku VARCHAR(64) NOT NULL,
+    quantity_ordered INTEGER NOT NULL,
+    quantity_received INTEGER NOT NULL DEFAULT 0,
+    unit_cost DOUBLE PRECISION NOT NULL,
+
+    CONSTRAINT chk_purchase_order_lines_quantity CHECK (quantity_ordered > 0 AND quantity_received >= 0 AND quantity_received <= quantity_ordered),
+    CONSTRAINT chk_purchase_order_lines_unit_cost CHECK (unit_cost >= 0),
+    CONSTRAINT uq_purchase_order_lines_variant UNIQUE (purchase_order_id, variant_id),
+    CONSTRAINT fk_purchase_order_lines_purchase_order_id FOREIGN KEY (purchase_order_id) REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    CONSTRAINT fk_purchase_order_lines_variant_id FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_order_lines_variant_id ON purchase_order_lines(variant_id);
@@ -0,0 +1,8 @@
+-- Rollback: Remove product slugs and translations
+
+DROP TABLE IF EXISTS product_translations;
+DROP TABLE IF EXISTS product_slug_history;
+
+ALTER TABLE products
+    DROP CONSTRAINT IF EXISTS uq_products_slug,
+    DROP COLUMN IF EXISTS slug;
@@ -0,0 +1,53 @@
+-- Migration: SEO slugs and localized product content. A product keeps the slugs it
+-- used to have so old links can be redirected; names and descriptions can be
+-- translated per locale, with the products row holding the default locale.
+
+-- Step 1: Add slug to products, backfilled from the name. Names that clash, and
+-- names without any letter or digit, get the product id appended.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS slug VARCHAR(160);
+
+WITH slugs AS (
+    SELECT id, TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(name), '[^[:alnum:]]+', '-', 'g')) AS base
+    FROM products
+), ranked AS (
+    SELECT id, base, ROW_NUMBER() OVER (PARTITION BY base ORDER BY id) AS position
+    FROM slugs
+)
+UPDATE products p
+SET slug = CASE
+        WHEN r.base = '' THEN 'product-' || p.id
+        WHEN r.position > 1 THEN r.base || '-' || p.id
+        ELSE r.base
+    END
+FROM ranked r
+WHERE r.id = p.id AND p.slug IS NULL;
+
+ALTER TABLE products
+    ALTER COLUMN slug SET NOT NULL,
+    ADD CONSTRAINT uq_products_slug UNIQUE (slug);
+
+-- Step 2: Create product_slug_history table; a slug stays reserved for the product
+-- that used it so its old links keep resolving
+CREATE TABLE IF NOT EXISTS product_slug_history (
+    slug VARCHAR(160) PRIMARY KEY,
+    product_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_product_slug_history_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_slug_history_product_id ON product_slug_history(product_id);
+
+-- Step 3: Create product_translations table; locales are lowercase language tags
+-- such as de or pt-br
+CREATE TABLE IF NOT EXISTS product_translations (
+    product_id INTEGER NOT NULL,
+    locale VARCHAR(20) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (product_id, locale),
+    CONSTRAINT fk_product_translations_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
@@ -0,0 +1,11 @@
+-- Rollback: Remove digital products
+
+DROP TABLE IF EXISTS order_deliveries;
+DROP TABLE IF EXISTS license_keys;
+DROP TABLE IF EXISTS digital_products;
+
+UPDATE products SET kind = 'standard' WHERE kind = 'digital';
+
+ALTER TABLE products
+    DROP CONSTRAINT IF EXISTS chk_products_kind,
+    ADD CONSTRAINT chk_products_kind CHECK (kind IN ('standard', 'bundle'));
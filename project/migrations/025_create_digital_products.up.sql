@@ -0,0 +1,76 @@
+-- Migration: Digital products. A digital product holds no stock; it is delivered
+-- once its order is paid, either as a file downloaded through time-limited signed
+-- links or as licence keys assigned from a pool. Deliveries are recorded on the
+-- order with the downloads made.
+
+-- Step 1: Allow digital as a kind of product
+ALTER TABLE products
+    DROP CONSTRAINT IF EXISTS chk_products_kind,
+    ADD CONSTRAINT chk_products_kind CHECK (kind IN ('standard', 'bundle', 'digital'));
+
+-- Step 2: Create digital_products table; file_key is the file's key in the
+-- gateway's private download store
+CREATE TABLE IF NOT EXISTS digital_products (
+    product_id INTEGER PRIMARY KEY,
+    delivery VARCHAR(20) NOT NULL,
+    file_key VARCHAR(255) NOT NULL DEFAULT '',
+    file_name VARCHAR(255) NOT NULL DEFAULT '',
+    content_type VARCHAR(100) NOT NULL DEFAULT '',
+    download_limit INTEGER NOT NULL DEFAULT 5,
+    link_ttl_hours INTEGER NOT NULL DEFAULT 72,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_digital_products_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT chk_digital_products_delivery CHECK (delivery IN ('download', 'license_key')),
+    CONSTRAINT chk_digital_products_file_key CHECK (delivery <> 'download' OR file_key <> ''),
+    CONSTRAINT chk_digital_products_download_limit CHECK (download_limit > 0),
+    CONSTRAINT chk_digital_products_link_ttl_hours CHECK (link_ttl_hours > 0)
+);
+
+-- Step 3: Create license_keys table; a key belongs to the order item it was
+-- assigned to for good
+CREATE TABLE IF NOT EXISTS license_keys (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL,
+    license_key VARCHAR(255) NOT NULL,
+    order_id INTEGER,
+    order_item_id INTEGER,
+    assigned_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_license_keys_product_key UNIQUE (product_id, license_key),
+    CONSTRAINT fk_license_keys_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_license_keys_order_id FOREIGN KEY (order_id) REFERENCES orders(id),
+    CONSTRAINT fk_license_keys_order_item_id FOREIGN KEY (order_item_id) REFERENCES order_items(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_license_keys_available ON license_keys(product_id, id) WHERE order_item_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_license_keys_order_item_id ON license_keys(order_item_id);
+
+-- Step 4: Create order_deliveries table; one delivery per order item, holding a
+-- copy of what was delivered so later product changes do not affect it
+CREATE TABLE IF NOT EXISTS order_deliveries (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL,
+    order_item_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    delivery VARCHAR(20) NOT NULL,
+    file_key VARCHAR(255) NOT NULL DEFAULT '',
+    file_name VARCHAR(255) NOT NULL DEFAULT '',
+    content_type VARCHAR(100) NOT NULL DEFAULT '',
+    license_keys TEXT[] NOT NULL DEFAULT '{}',
+    download_limit INTEGER NOT NULL DEFAULT 0,
+    download_count INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP,
+    last_downloaded_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_order_deliveries_order_item_id UNIQUE (order_item_id),
+    CONSTRAINT fk_order_deliveries_order_id FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    CONSTRAINT fk_order_deliveries_order_item_id FOREIGN KEY (order_item_id) REFERENCES order_items(id) ON DELETE CASCADE,
+    CONSTRAINT chk_order_deliveries_delivery CHECK (delivery IN ('download', 'license_key')),
+    CONSTRAINT chk_order_deliveries_download_count CHECK (download_count >= 0 AND download_count <= download_limit)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_deliveries_order_id ON order_deliveries(order_id);
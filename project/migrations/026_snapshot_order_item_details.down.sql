@@ -0,0 +1,3 @@
+-- Rollback: Remove the product details snapshot from order items
+
+ALTER TABLE order_items DROP COLUMN IF EXISTS product_name;
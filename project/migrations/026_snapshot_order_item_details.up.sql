@@ -0,0 +1,14 @@
+-- Migration: Snapshot product details onto order items. An order item keeps the
+-- product name it was ordered with, so the order service reads its items without
+-- the product service's tables and later catalog changes do not rewrite past
+-- orders. Later migrations snapshot the details later features add.
+
+-- Step 1: Add the snapshot column
+ALTER TABLE order_items
+    ADD COLUMN IF NOT EXISTS product_name VARCHAR(100) NOT NULL DEFAULT '';
+
+-- Step 2: Backfill items not snapshotted yet from the catalog as it is now
+UPDATE order_items oi
+SET product_name = p.name
+FROM products p
+WHERE p.id = oi.product_id AND oi.product_name = '';
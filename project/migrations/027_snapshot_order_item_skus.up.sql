@@ -0,0 +1,12 @@
+-- Migration: Snapshot the SKU onto order items, so an order keeps the SKU its
+-- variant had when it was ordered
+
+-- Step 1: Add the snapshot column
+ALTER TABLE order_items
+    ADD COLUMN IF NOT EXISTS sku VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Step 2: Backfill items not snapshotted yet from the variants as they are now
+UPDATE order_items oi
+SET sku = v.sku
+FROM product_variants v
+WHERE v.id = oi.variant_id AND oi.sku = '';
@@ -0,0 +1,3 @@
+-- Rollback: Remove the warehouse code snapshot from order items
+
+ALTER TABLE order_items DROP COLUMN IF EXISTS warehouse_code;
@@ -0,0 +1,12 @@
+-- Migration: Snapshot the warehouse code onto order items, so an order keeps the
+-- code of the warehouse it was allocated from
+
+-- Step 1: Add the snapshot column
+ALTER TABLE order_items
+    ADD COLUMN IF NOT EXISTS warehouse_code VARCHAR(20) NOT NULL DEFAULT '';
+
+-- Step 2: Backfill items not snapshotted yet from the warehouses as they are now
+UPDATE order_items oi
+SET warehouse_code = w.code
+FROM warehouses w
+WHERE w.id = oi.warehouse_id AND oi.warehouse_code = '';
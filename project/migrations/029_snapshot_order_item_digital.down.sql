@@ -0,0 +1,3 @@
+-- Rollback: Remove the digital flag from order items
+
+ALTER TABLE order_items DROP COLUMN IF EXISTS digital;
@@ -0,0 +1,13 @@
+-- Migration: Record on order items whether they are delivered digitally, so the
+-- order service delivers them without reading the product service's tables
+
+-- Step 1: Add the snapshot column
+ALTER TABLE order_items
+    ADD COLUMN IF NOT EXISTS digital BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Step 2: Backfill items of digital products; a product that has been sold cannot
+-- become digital, so these were digital when ordered
+UPDATE order_items oi
+SET digital = TRUE
+FROM products p
+WHERE p.id = oi.product_id AND p.kind = 'digital' AND NOT oi.digital;
@@ -0,0 +1,5 @@
+-- Rollback: Tie licence keys to orders and order items again
+
+ALTER TABLE license_keys
+    ADD CONSTRAINT fk_license_keys_order_id FOREIGN KEY (order_id) REFERENCES orders(id),
+    ADD CONSTRAINT fk_license_keys_order_item_id FOREIGN KEY (order_item_id) REFERENCES order_items(id);
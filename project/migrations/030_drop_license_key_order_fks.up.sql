@@ -0,0 +1,7 @@
+-- Migration: Licence keys belong to the product service, which must not depend on
+-- the order service's tables; the order and order item a key was assigned to are
+-- kept as plain IDs
+
+ALTER TABLE license_keys
+    DROP CONSTRAINT IF EXISTS fk_license_keys_order_id,
+    DROP CONSTRAINT IF EXISTS fk_license_keys_order_item_id;
@@ -0,0 +1,12 @@
+-- Rollback: Refunds belong to payment intents again
+
+-- Drop refunds of orders paid on their own
+DROP INDEX IF EXISTS idx_payment_refunds_payment_id;
+DELETE FROM payment_refunds WHERE payment_intent_id IS NULL;
+
+ALTER TABLE payment_refunds
+    ALTER COLUMN payment_intent_id SET NOT NULL;
+
+-- Payments no longer record the customer
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS user_id;
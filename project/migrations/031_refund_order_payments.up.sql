@@ -0,0 +1,16 @@
+-- Migration: Orders paid on their own, by Snap, card or wallet, are refunded like
+-- orders of a payment intent
+
+-- Step 1: Payments record the customer, whose wallet store credit refunds go to
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS user_id INTEGER;
+
+UPDATE payments SET user_id = orders.user_id
+    FROM orders
+    WHERE orders.id = payments.order_id AND payments.user_id IS NULL;
+
+-- Step 2: Refunds of orders paid on their own have no intent
+ALTER TABLE payment_refunds
+    ALTER COLUMN payment_intent_id DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_payment_refunds_payment_id ON payment_refunds(payment_id);
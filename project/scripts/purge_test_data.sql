@@ -0,0 +1,38 @@
+-- Purge all test-mode data and reset the test stock pool.
+-- Live records are never touched: every delete is scoped to livemode = FALSE.
+
+BEGIN;
+
+-- Step 1: Payment side; refunds go before the payments and intents they refund
+DELETE FROM payment_refunds
+    WHERE payment_intent_id IN (SELECT id FROM payment_intents WHERE NOT livemode)
+       OR payment_id IN (SELECT id FROM payments WHERE NOT livemode);
+DELETE FROM payments WHERE NOT livemode;
+DELETE FROM payment_intents WHERE NOT livemode;
+DELETE FROM saved_payment_methods WHERE NOT livemode;
+DELETE FROM wallet_topups WHERE NOT livemode;
+DELETE FROM wallet_transactions WHERE NOT livemode;
+DELETE FROM wallets WHERE NOT livemode;
+
+-- Step 2: Orders and their items. Digital deliveries, with their download counts,
+-- go first; licence keys assigned to test orders go back to the product's pool, as
+-- their foreign keys would otherwise keep the orders from being deleted.
+DELETE FROM order_deliveries
+    WHERE order_id IN (SELECT id FROM orders WHERE NOT livemode);
+UPDATE license_keys SET order_id = NULL, order_item_id = NULL, assigned_at = NULL
+    WHERE order_id IN (SELECT id FROM orders WHERE NOT livemode);
+DELETE FROM order_items
+    WHERE order_id IN (SELECT id FROM orders WHERE NOT livemode);
+DELETE FROM orders WHERE NOT livemode;
+
+-- Price rules and purchase orders have no test-mode rows: rules apply in both modes
+-- and receipts restock live stock only, which step 3 copies to the test pool. Test
+-- order items only keep the ID of the rule that priced them, and went with step 2.
+
+-- Step 3: Refill the test stock pool of every warehouse from live stock
+DELETE FROM inventory_movements WHERE NOT livemode;
+UPDATE variant_stock_levels SET test_stock = stock;
+UPDATE product_variants SET test_stock = stock;
+UPDATE products SET test_stock = stock;
+
+COMMIT;
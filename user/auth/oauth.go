@@ -1,42 +1,42 @@
-package auth
-
-import (
-	"os"
-
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/facebook"
-	"golang.org/x/oauth2/github"
-	"golang.org/x/oauth2/google"
-)
-
-var (
-	OauthGithubConfig   = &oauth2.Config{}
-	OauthGoogleConfig   = &oauth2.Config{}
-	OauthFacebookConfig = &oauth2.Config{}
-)
-
-func InitOauth() {
-	OauthGoogleConfig = &oauth2.Config{
-		ClientID:     os.Getenv("GOOGLE_CLIENT_ID"),
-		ClientSecret: os.Getenv("GOOGLE_CLIENT_SECRET"),
-		RedirectURL:  os.Getenv("GOOGLE_REDIRECT_URL"),
-		Scopes:       []string{"email", "profile"},
-		Endpoint:     google.Endpoint,
-	}
-
-	OauthGithubConfig = &oauth2.Config{
-		ClientID:     os.Getenv("GITHUB_CLIENT_ID"),
-		ClientSecret: os.Getenv("GITHUB_CLIENT_SECRET"),
-		RedirectURL:  os.Getenv("GITHUB_REDIRECT_URL"),
-		Scopes:       []string{"user", "user:email"},
-		Endpoint:     github.Endpoint,
-	}
-
-	OauthFacebookConfig = &oauth2.Config{
-		ClientID:     os.Getenv("FACEBOOK_CLIENT_ID"),
-		ClientSecret: os.Getenv("FACEBOOK_CLIENT_SECRET"),
-		RedirectURL:  os.Getenv("FACEBOOK_REDIRECT_URL"),
-		Scopes:       []string{"public_profile", "email"},
-		Endpoint:     facebook.Endpoint,
-	}
-}
+package auth
+
+import (
+	"os"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/facebook"
+	"golang.org/x/oauth2/github"
+	"golang.org/x/oauth2/google"
+)
+
+var (
+	OauthGithubConfig   = &oauth2.Config{}
+	OauthGoogleConfig   = &oauth2.Config{}
+	OauthFacebookConfig = &oauth2.Config{}
+)
+
+func InitOauth() {
+	OauthGoogleConfig = &oauth2.Config{
+		ClientID:     os.Getenv("GOOGLE_CLIENT_ID"),
+		ClientSecret: os.Getenv("GOOGLE_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("GOOGLE_REDIRECT_URL"),
+		Scopes:       []string{"email", "profile"},
+		Endpoint:     google.Endpoint,
+	}
+
+	OauthGithubConfig = &oauth2.Config{
+		ClientID:     os.Getenv("GITHUB_CLIENT_ID"),
+		ClientSecret: os.Getenv("GITHUB_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("GITHUB_REDIRECT_URL"),
+		Scopes:       []string{"user", "user:email"},
+		Endpoint:     github.Endpoint,
+	}
+
+	OauthFacebookConfig = &oauth2.Config{
+		ClientID:     os.Getenv("FACEBOOK_CLIENT_ID"),
+		ClientSecret: os.Getenv("FACEBOOK_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("FACEBOOK_REDIRECT_URL"),
+		Scopes:       []string{"public_profile", "email"},
+		Endpoint:     facebook.Endpoint,
+	}
+}
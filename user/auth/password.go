@@ -1,20 +1,20 @@
-package auth
-
-import "golang.org/x/crypto/bcrypt"
-
-func GeneratePasswordHash(password string) (string, error) {
-	hashPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return "", err
-
-	}
-	return string(hashPassword), nil
-}
-
-func ComparePasswordHash(hashedPassword string, plainPassword []byte) error {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), plainPassword)
-	if err != nil {
-		return err
-	}
-	return nil
-}
+package auth
+
+import "golang.org/x/crypto/bcrypt"
+
+func GeneratePasswordHash(password string) (string, error) {
+	hashPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+
+	}
+	return string(hashPassword), nil
+}
+
+func ComparePasswordHash(hashedPassword string, plainPassword []byte) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), plainPassword)
+	if err != nil {
+		return err
+	}
+	return nil
+}
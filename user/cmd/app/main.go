@@ -1,26 +1,31 @@
-package main
-
-import (
-	"sync"
-
-	"user_service/transport/grpc"
-
-	"github.com/sirupsen/logrus"
-)
-
-func main() {
-	logrus.SetFormatter(&logrus.JSONFormatter{})
-	logrus.Info("Starting Application")
-
-	var wg sync.WaitGroup
-	wg.Add(1)
-
-	go func() {
-		defer wg.Done()
-		logrus.Info("Calling GRPCListen()")
-		grpc.GRPCListen()
-		logrus.Info("GRPCListen() exited")
-	}()
-
-	wg.Wait()
-}
+package main
+
+import (
+	"os"
+	"sync"
+
+	"user_service/transport/grpc"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+	logrus.Info("Starting Application")
+
+	addr := []string{os.Getenv("KAFKA_BROKER_URL")}
+	topic := []string{os.Getenv("KAFKA_BACK_IN_STOCK_TOPIC")}
+	groupID := os.Getenv("KAFKA_GROUP_ID")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		logrus.Info("Calling GRPCListen()")
+		grpc.GRPCListen(addr, topic, groupID)
+		logrus.Info("GRPCListen() exited")
+	}()
+
+	wg.Wait()
+}
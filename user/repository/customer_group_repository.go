@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"user_service/types"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CustomerGroupRepository interface {
+	CreateGroup(group *types.CustomerGroup) error
+	GetGroupByID(ID int) (*types.CustomerGroup, error)
+	ListGroups() ([]*types.CustomerGroupWithCount, error)
+	AddMember(groupID int, userID int) error
+	RemoveMember(groupID int, userID int) (bool, error)
+}
+
+type CustomerGroupRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewCustomerGroupRepository(DB *gorm.DB) *CustomerGroupRepositoryImpl {
+	return &CustomerGroupRepositoryImpl{
+		DB: DB,
+	}
+}
+
+func (u *CustomerGroupRepositoryImpl) CreateGroup(group *types.CustomerGroup) error {
+	return u.DB.Create(group).Error
+}
+
+func (u *CustomerGroupRepositoryImpl) GetGroupByID(ID int) (*types.CustomerGroup, error) {
+	var group types.CustomerGroup
+	if err := u.DB.Where("id = ?", ID).First(&group).Error; err != nil {
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+// ListGroups returns every group by name with its member count
+func (u *CustomerGroupRepositoryImpl) ListGroups() ([]*types.CustomerGroupWithCount, error) {
+	var groups []*types.CustomerGroupWithCount
+	if err := u.DB.Model(&types.CustomerGroup{}).
+		Select("customer_groups.*, (SELECT COUNT(*) FROM customer_group_members m WHERE m.group_id = customer_groups.id) AS member_count").
+		Order("name ASC").
+		Scan(&groups).Error; err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// AddMember puts a user in a group; adding a member again changes nothing
+func (u *CustomerGroupRepositoryImpl) AddMember(groupID int, userID int) error {
+	return u.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&types.CustomerGroupMember{
+		GroupID: groupID,
+		UserID:  userID,
+	}).Error
+}
+
+// RemoveMember takes a user out of a group and returns false when they were not in it
+func (u *CustomerGroupRepositoryImpl) RemoveMember(groupID int, userID int) (bool, error) {
+	result := u.DB.Where("group_id = ? AND user_id = ?", groupID, userID).Delete(&types.CustomerGroupMember{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"user_service/types"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository interface {
+	RecordStockNotification(event *types.BackInStockEvent) (bool, error)
+}
+
+type NotificationRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewNotificationRepository(DB *gorm.DB) *NotificationRepositoryImpl {
+	return &NotificationRepositoryImpl{
+		DB: DB,
+	}
+}
+
+// RecordStockNotification claims an event for sending. It returns false when the
+// event was already handled.
+func (u *NotificationRepositoryImpl) RecordStockNotification(event *types.BackInStockEvent) (bool, error) {
+	result := u.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&types.StockNotification{
+		EventID:   event.EventID,
+		UserID:    int(event.UserID),
+		ProductID: int(event.ProductID),
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
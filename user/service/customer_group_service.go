@@ -0,0 +1,102 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"user_service/proto"
+	"user_service/repository"
+	"user_service/types"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type CustomerGroupService struct {
+	repo     repository.CustomerGroupRepository
+	userRepo repository.UserRepository
+}
+
+func NewCustomerGroupService(repo repository.CustomerGroupRepository, userRepo repository.UserRepository) *CustomerGroupService {
+	return &CustomerGroupService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+func (u *CustomerGroupService) CreateGroup(req *proto.CustomerGroup) (*proto.CustomerGroup, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	group := &types.CustomerGroup{
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+	}
+	if err := u.repo.CreateGroup(group); err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Customer group %s created", group.Name)
+	return toCustomerGroupProto(group, 0), nil
+}
+
+func (u *CustomerGroupService) ListGroups() ([]*proto.CustomerGroup, error) {
+	groups, err := u.repo.ListGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*proto.CustomerGroup, 0, len(groups))
+	for _, group := range groups {
+		list = append(list, toCustomerGroupProto(&group.CustomerGroup, group.MemberCount))
+	}
+	return list, nil
+}
+
+// AddMember puts a user in a group, so the group's price rules apply to their orders
+func (u *CustomerGroupService) AddMember(req *proto.GroupMemberRequest) error {
+	if _, err := u.repo.GetGroupByID(int(req.GroupId)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("customer group not found")
+		}
+		return err
+	}
+	if _, err := u.userRepo.GetUserByID(int(req.UserId)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found")
+		}
+		return err
+	}
+
+	if err := u.repo.AddMember(int(req.GroupId), int(req.UserId)); err != nil {
+		return err
+	}
+
+	logrus.Infof("User %d added to customer group %d", req.UserId, req.GroupId)
+	return nil
+}
+
+func (u *CustomerGroupService) RemoveMember(req *proto.GroupMemberRequest) error {
+	removed, err := u.repo.RemoveMember(int(req.GroupId), int(req.UserId))
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errors.New("user is not a member of the group")
+	}
+
+	logrus.Infof("User %d removed from customer group %d", req.UserId, req.GroupId)
+	return nil
+}
+
+func toCustomerGroupProto(group *types.CustomerGroup, memberCount int) *proto.CustomerGroup {
+	return &proto.CustomerGroup{
+		Id:          int32(group.ID),
+		Name:        group.Name,
+		Description: group.Description,
+		MemberCount: int32(memberCount),
+		CreatedAt:   group.CreatedAt.Format(time.RFC3339),
+	}
+}
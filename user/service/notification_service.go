@@ -0,0 +1,53 @@
+package service
+
+import (
+	"errors"
+	"user_service/repository"
+	"user_service/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+type NotificationService struct {
+	repo     repository.NotificationRepository
+	userRepo repository.UserRepository
+}
+
+func NewNotificationService(repo repository.NotificationRepository, userRepo repository.UserRepository) *NotificationService {
+	return &NotificationService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+// NotifyBackInStock tells a subscriber that a product is available again. Each event
+// is sent at most once, however often Kafka delivers it. There is no mail provider
+// yet, so the notification is written to the log.
+func (u *NotificationService) NotifyBackInStock(event *types.BackInStockEvent) error {
+	if event.EventID == "" || event.UserID == 0 {
+		return errors.New("event_id and user_id are required")
+	}
+
+	user, err := u.userRepo.GetUserByID(int(event.UserID))
+	if err != nil {
+		return err
+	}
+
+	claimed, err := u.repo.RecordStockNotification(event)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		logrus.Infof("Back-in-stock event %s already sent, skipping", event.EventID)
+		return nil
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"event_id":   event.EventID,
+		"user_id":    user.ID,
+		"email":      user.Email,
+		"product_id": event.ProductID,
+		"stock":      event.Stock,
+	}).Infof("Back in stock: %s is available again at %.2f", event.ProductName, event.Price)
+	return nil
+}
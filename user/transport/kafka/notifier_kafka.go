@@ -0,0 +1,84 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"user_service/service"
+	"user_service/types"
+
+	"github.com/IBM/sarama"
+	"github.com/sirupsen/logrus"
+)
+
+type ConsumerHandler struct {
+	service *service.NotificationService
+}
+
+func connectKafka(addr []string, groupID string) (sarama.ConsumerGroup, error) {
+	config := sarama.NewConfig()
+	config.Consumer.Group.Rebalance.Strategy = sarama.NewBalanceStrategyRange()
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	config.Consumer.Return.Errors = true
+
+	var CGError error
+
+	for i := 0; i < 5; i++ {
+		worker, err := sarama.NewConsumerGroup(addr, groupID, config)
+		if err == nil {
+			logrus.Info("Connected to kafka")
+			return worker, nil
+		}
+
+		CGError = err
+		logrus.Warnf("failed connect to kafka, retrying...(%d/5)", i+1)
+		time.Sleep(5 * time.Second)
+	}
+
+	return nil, CGError
+}
+
+// ProcessMessage consumes back-in-stock events for the life of the service. Without
+// Kafka the user service keeps serving; only notifications stop.
+func ProcessMessage(addr []string, topic []string, groupID string, service *service.NotificationService) {
+	consumerGroup, err := connectKafka(addr, groupID)
+	if err != nil {
+		logrus.Errorf("error when connect to kafka, notifications are disabled: %v", err)
+		return
+	}
+	defer consumerGroup.Close()
+
+	handler := &ConsumerHandler{service: service}
+
+	logrus.Infof("addr: %s topic: %s groupID: %s", addr, topic, groupID)
+	for {
+		if err := consumerGroup.Consume(context.Background(), topic, handler); err != nil {
+			logrus.Errorf("failed when consume partition, retrying: %v", err)
+			time.Sleep(2 * time.Second)
+		}
+	}
+}
+
+func (h *ConsumerHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
+func (h *ConsumerHandler) Cleanup(_ sarama.ConsumerGroupSession) error { return nil }
+
+func (h *ConsumerHandler) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for msg := range claim.Messages() {
+		event := new(types.BackInStockEvent)
+		if err := json.Unmarshal(msg.Value, event); err != nil {
+			logrus.Errorf("Error parsing message: %s. Raw data: %s", err, string(msg.Value))
+			// A malformed event will never parse, so it is skipped for good
+			sess.MarkMessage(msg, "")
+			continue
+		}
+
+		if err := h.service.NotifyBackInStock(event); err != nil {
+			logrus.Errorf("Error sending back-in-stock notification %s: %v", event.EventID, err)
+			continue
+		}
+
+		sess.MarkMessage(msg, "")
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package types
+
+import "time"
+
+// CustomerGroup is a set of users that price rules of the product service can apply
+// to. Its tables are created by the shared migrations, since price rules refer to them.
+type CustomerGroup struct {
+	ID          int       `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"name"`
+	Description string    `gorm:"not null" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CustomerGroupMember struct {
+	GroupID   int       `gorm:"primaryKey" json:"group_id"`
+	UserID    int       `gorm:"primaryKey" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CustomerGroupWithCount is a group with how many users belong to it
+type CustomerGroupWithCount struct {
+	CustomerGroup
+	MemberCount int
+}
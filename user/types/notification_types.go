@@ -0,0 +1,26 @@
+package types
+
+import "time"
+
+// BackInStockEvent is published by the product service when a product a user
+// subscribed to comes back in stock. EventID is unique per restock and user.
+type BackInStockEvent struct {
+	EventID     string  `json:"event_id"`
+	RestockID   int32   `json:"restock_id"`
+	UserID      int32   `json:"user_id"`
+	ProductID   int32   `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Price       float64 `json:"price"`
+	Stock       int32   `json:"stock"`
+	RestockedAt string  `json:"restocked_at"`
+}
+
+// StockNotification records a back-in-stock notification that was sent, so a
+// redelivered event is not sent twice
+type StockNotification struct {
+	ID        int       `gorm:"primaryKey" json:"id"`
+	EventID   string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"event_id"`
+	UserID    int       `gorm:"index;not null" json:"user_id"`
+	ProductID int       `gorm:"not null" json:"product_id"`
+	CreatedAt time.Time `json:"created_at"`
+}